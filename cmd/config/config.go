@@ -14,9 +14,19 @@ import (
 	"github.com/lmittmann/tint"
 )
 
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
 type Config struct {
 	Debug bool `default:"false" envconfig:"DEBUG"`
 
+	// Storage — хранилище: postgres, sqlite (один файл SQLITE_PATH) или memory
+	// (данные теряются при перезапуске, для локальной разработки).
+	Storage string `envconfig:"STORAGE" default:"postgres"`
+
 	Server struct {
 		Port uint16 `envconfig:"PORT" default:"8000"`
 		Host string `envconfig:"HOST" default:"0.0.0.0"`
@@ -24,15 +34,123 @@ type Config struct {
 
 	DB struct {
 		User     string `envconfig:"POSTGRES_USER"`
-		Password string `envconfig:"POSTGRES_PASSWORD"`
+		Password string `envconfig:"POSTGRES_PASSWORD" json:"-"`
 		Host     string `envconfig:"POSTGRES_HOST"`
 		Port     uint16 `envconfig:"POSTGRES_PORT" default:"5432"`
 		Database string `envconfig:"POSTGRES_NAME" default:"postgres"`
 	}
 
+	Migrate struct {
+		// Auto — накатывать миграции Postgres при старте сервера.
+		Auto bool `envconfig:"MIGRATE_AUTO" default:"false"`
+	}
+
+	SQLite struct {
+		// Path — файл базы; ":memory:" — база в памяти процесса.
+		Path string `envconfig:"SQLITE_PATH" default:"gopr.db"`
+	}
+
 	Log struct {
 		Handler string `envconfig:"LOG_HANDLER" default:"tint"`
 	}
+
+	Webhooks struct {
+		// Secret — секрет вебхука код-хостинга; пустой секрет отключает эндпоинт.
+		// Logins — логины провайдера в id пользователей gopr: "octocat:u1,hubot:u2".
+		GitHub struct {
+			Secret string            `envconfig:"WEBHOOK_GITHUB_SECRET" json:"-"`
+			Logins map[string]string `envconfig:"WEBHOOK_GITHUB_LOGINS"`
+		}
+		GitLab struct {
+			Secret string            `envconfig:"WEBHOOK_GITLAB_SECRET" json:"-"`
+			Logins map[string]string `envconfig:"WEBHOOK_GITLAB_LOGINS"`
+		}
+	}
+
+	ChatOps struct {
+		// Slack — slash-команды Slack. SigningSecret — секрет подписи запросов
+		// приложения; пустой секрет отключает эндпоинт.
+		// Logins — id участников Slack в id пользователей gopr: "U024BE7LH:u1".
+		Slack struct {
+			SigningSecret string            `envconfig:"CHATOPS_SLACK_SIGNING_SECRET" json:"-"`
+			Logins        map[string]string `envconfig:"CHATOPS_SLACK_LOGINS"`
+		}
+		// AwayInterval — как часто отсутствующие, чья дата возвращения наступила,
+		// снова становятся активными; 0 — не возвращать.
+		AwayInterval time.Duration `envconfig:"CHATOPS_AWAY_INTERVAL" default:"5m"`
+	}
+
+	Audit struct {
+		// Retention — сколько хранятся записи журнала аудита; 0 — хранить всегда.
+		Retention time.Duration `envconfig:"AUDIT_RETENTION" default:"2160h"`
+		// PruneInterval — как часто удаляются записи старше Retention.
+		PruneInterval time.Duration `envconfig:"AUDIT_PRUNE_INTERVAL" default:"1h"`
+	}
+
+	CodeHost struct {
+		// Token — токен GitHub для запроса ревью; пустой токен отключает выгрузку ревьюверов.
+		// URL — адрес REST API, для GitHub Enterprise — https://host/api/v3.
+		GitHub struct {
+			Token string `envconfig:"CODEHOST_GITHUB_TOKEN" json:"-"`
+			URL   string `envconfig:"CODEHOST_GITHUB_URL" default:"https://api.github.com"`
+		}
+		// RetryFor — сколько повторять неудачную выгрузку, прежде чем пометить её FAILED.
+		RetryFor time.Duration `envconfig:"CODEHOST_RETRY_FOR" default:"5m"`
+	}
+
+	Subscriptions struct {
+		// MaxAttempts — сколько раз пробуется доставка события подписчику, прежде чем стать DEAD.
+		MaxAttempts int `envconfig:"SUBSCRIPTION_MAX_ATTEMPTS" default:"5"`
+		// RetryInterval — задержка перед первым повтором доставки, дальше она растёт экспоненциально.
+		RetryInterval time.Duration `envconfig:"SUBSCRIPTION_RETRY_INTERVAL" default:"10s"`
+		// PollInterval — как часто ищутся доставки, чей повтор наступил.
+		PollInterval time.Duration `envconfig:"SUBSCRIPTION_POLL_INTERVAL" default:"5s"`
+	}
+
+	Outbox struct {
+		// Relay — публиковать события из outbox в этом инстансе. В Postgres
+		// проходы relay разных инстансов не пересекаются, поэтому его можно
+		// включать на всех; SQLite и memory обслуживают один инстанс.
+		Relay bool `envconfig:"OUTBOX_RELAY" default:"true"`
+		// Interval — как часто outbox проверяется на новые события.
+		Interval time.Duration `envconfig:"OUTBOX_INTERVAL" default:"1s"`
+		// Batch — сколько событий публикуется за один проход.
+		Batch int `envconfig:"OUTBOX_BATCH" default:"100"`
+		// MaxAttempts — после скольких неудачных проходов событие снимается с публикации.
+		MaxAttempts int `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"100"`
+		// LogFile — файл, в который опубликованные события дописываются строками JSON; пусто — не писать.
+		LogFile string `envconfig:"OUTBOX_LOG_FILE"`
+	}
+
+	Notify struct {
+		// SlackWebhookURL и HTTPURL — адреса каналов slack и http для
+		// пользователей, не задавших свой. Адрес Slack-вебхука сам служит
+		// секретом и в Print не выводится.
+		SlackWebhookURL string `envconfig:"NOTIFY_SLACK_WEBHOOK_URL" json:"-"`
+		HTTPURL         string `envconfig:"NOTIFY_HTTP_URL"`
+		// SMTP — почтовый сервер host:port; пустой адрес отключает канал email.
+		SMTP struct {
+			Addr     string `envconfig:"NOTIFY_SMTP_ADDR"`
+			From     string `envconfig:"NOTIFY_SMTP_FROM" default:"gopr@localhost"`
+			Username string `envconfig:"NOTIFY_SMTP_USERNAME"`
+			Password string `envconfig:"NOTIFY_SMTP_PASSWORD" json:"-"`
+		}
+		// ReviewSLA — сколько PR ждёт ревью, прежде чем ревьюверы получат
+		// предупреждение; 0 — не предупреждать.
+		ReviewSLA time.Duration `envconfig:"NOTIFY_REVIEW_SLA" default:"24h"`
+		// SLAInterval — как часто открытые PR проверяются на SLA.
+		SLAInterval time.Duration `envconfig:"NOTIFY_SLA_INTERVAL" default:"5m"`
+		// DigestInterval — как часто проверяется, кому пора отправить ежедневную
+		// сводку; 0 — не отправлять сводки.
+		DigestInterval time.Duration `envconfig:"NOTIFY_DIGEST_INTERVAL" default:"5m"`
+	}
+
+	Assign struct {
+		// Strategy — стратегия выбора ревьюверов: random, least_loaded, weighted_random.
+		Strategy string `envconfig:"ASSIGN_STRATEGY" default:"random"`
+		// Reviewers — сколько ревьюверов назначается на новый PR.
+		Reviewers int `envconfig:"ASSIGN_REVIEWERS" default:"2"`
+	}
 }
 
 func Load(envFile string) *Config {
@@ -48,6 +166,7 @@ func Load(envFile string) *Config {
 	return c
 }
 
+// Print в debug-режиме выводит конфиг целиком, кроме секретов с тегом json:"-".
 func (c *Config) Print() {
 	if c.Debug {
 		slog.Info("Launched in debug mode")
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"gopr/cmd/config"
+	"gopr/internal/gateways/codehost"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+	"gopr/pkg/slogx"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// setupReviewSync включает выгрузку ревьюверов в GitHub, если задан токен.
+// Возвращённая функция прерывает повторы и дожидается запущенных выгрузок.
+func setupReviewSync(ctx context.Context, cfg *config.Config, repos repo.Factory, cases usecase.Cases) func() {
+	gh := cfg.CodeHost.GitHub
+	if gh.Token == "" {
+		return func() {}
+	}
+
+	retryFor := cfg.CodeHost.RetryFor
+	sync := usecase.NewReviewSync(
+		repos.PullRequest(),
+		codehost.NewGitHub(gh.URL, gh.Token, nil),
+		cases.Identity,
+		func() backoff.BackOff {
+			return backoff.NewExponentialBackOff(backoff.WithMaxElapsedTime(retryFor))
+		},
+	)
+	cases.PullRequest.SetReviewSync(sync)
+
+	slogx.Info(ctx, "review sync to GitHub enabled", "url", gh.URL)
+	return sync.Stop
+}
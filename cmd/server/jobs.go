@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"gopr/cmd/config"
+	"gopr/internal/usecase"
+	"gopr/pkg/slogx"
+	"sync"
+	"time"
+)
+
+// jobs запускает фоновые циклы сервера и останавливает их разом.
+type jobs struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newJobs(ctx context.Context) *jobs {
+	ctx, cancel := context.WithCancel(ctx)
+	return &jobs{ctx: ctx, cancel: cancel}
+}
+
+// run запускает fn в фоне до остановки.
+func (j *jobs) run(fn func(ctx context.Context)) {
+	j.wg.Add(1)
+	go func() {
+		defer j.wg.Done()
+		fn(j.ctx)
+	}()
+}
+
+// every запускает цикл с интервалом interval; interval <= 0 — цикл выключен,
+// и в лог пишется disabled.
+func (j *jobs) every(interval time.Duration, loop func(ctx context.Context, interval time.Duration), disabled string) {
+	if interval <= 0 {
+		slogx.Info(j.ctx, disabled)
+		return
+	}
+	j.run(func(ctx context.Context) { loop(ctx, interval) })
+}
+
+// stop отменяет циклы и дожидается их выхода.
+func (j *jobs) stop() {
+	j.cancel()
+	j.wg.Wait()
+}
+
+// setupJobs запускает возвращение пользователей, отметивших в чате отсутствие
+// до даты, и удаление записей журнала аудита старше срока хранения. closeFn
+// останавливает обе проверки.
+func setupJobs(ctx context.Context, cfg *config.Config, cases usecase.Cases) func() {
+	j := newJobs(ctx)
+
+	j.every(cfg.ChatOps.AwayInterval, cases.ChatOps.RunReturns, "returning away users disabled")
+
+	pruneInterval := cfg.Audit.PruneInterval
+	if cfg.Audit.Retention <= 0 {
+		pruneInterval = 0
+	}
+	j.every(pruneInterval, cases.Audit.RunRetention, "audit log retention disabled")
+
+	return j.stop
+}
@@ -15,8 +15,6 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
-
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 func main() {
@@ -25,21 +23,46 @@ func main() {
 
 	log := cfg.Logger()
 	slog.SetDefault(log)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(cfg, os.Args[2:]); err != nil {
+			log.Error("migrate failed", slogx.Err(err))
+			os.Exit(1)
+		}
+		return
+	}
+
 	log.Info("Hello from gopr server!")
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer cancel()
 	ctx = slogx.NewCtx(ctx, log)
 
-	pgConfig := cfg.PGXConfig()
-	pool, err := pgxpool.NewWithConfig(ctx, pgConfig)
+	repos, closeStorage, err := openStorage(ctx, cfg)
+	if err != nil {
+		log.Error("can't open storage", slogx.Err(err))
+		os.Exit(1)
+	}
+	defer closeStorage()
+
+	cases := usecase.Setup(ctx, cfg, repos)
+	stopReviewSync := setupReviewSync(ctx, cfg, repos, cases)
+	defer stopReviewSync()
+	closeSubscriptions := setupSubscriptions(ctx, cfg, repos, &cases)
+	defer closeSubscriptions()
+	closeNotifications := setupNotifications(ctx, cfg, repos, &cases)
+	defer closeNotifications()
+	closeJobs := setupJobs(ctx, cfg, cases)
+	defer closeJobs()
+
+	closeOutbox, err := setupOutbox(ctx, cfg, repos, cases)
 	if err != nil {
-		log.Error("can't create new database pool", slogx.Err(err))
+		log.Error("can't start outbox relay", slogx.Err(err))
 		os.Exit(1)
 	}
-	defer pool.Close()
+	defer closeOutbox()
 
-	s := rest.NewServer(ctx, cfg, usecase.Setup(ctx, cfg, pool))
+	s := rest.NewServer(ctx, cfg, cases)
 	if err := s.Run(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slogx.WithErr(log, err).Error("error during server shutdown")
 	}
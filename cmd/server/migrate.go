@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"gopr/cmd/config"
+	"gopr/internal/repo/pg"
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+var errMigrateUsage = errors.New("usage: migrate up [N] | down [N] | version | force VERSION")
+
+// runMigrate выполняет подкоманду migrate над базой Postgres из конфига.
+// down без N откатывает одну миграцию, up без N — накатывает все.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 || !slices.Contains([]string{"up", "down", "version", "force"}, args[0]) {
+		return errMigrateUsage
+	}
+
+	m, err := pg.NewMigrator(cfg.DBUrl())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	switch args[0] {
+	case "up":
+		steps, err := migrateSteps(args[1:], 0)
+		if err != nil {
+			return err
+		}
+		if err := m.Up(steps); err != nil {
+			return err
+		}
+	case "down":
+		steps, err := migrateSteps(args[1:], 1)
+		if err != nil {
+			return err
+		}
+		if err := m.Down(steps); err != nil {
+			return err
+		}
+	case "force":
+		if len(args) != 2 {
+			return errMigrateUsage
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		if err := m.Force(version); err != nil {
+			return err
+		}
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	slog.Info("schema version",
+		slog.Uint64("version", uint64(version)),
+		slog.Bool("dirty", dirty),
+		slog.Uint64("latest", uint64(m.Latest())),
+	)
+
+	return nil
+}
+
+func migrateSteps(args []string, def int) (int, error) {
+	if len(args) == 0 {
+		return def, nil
+	}
+	if len(args) > 1 {
+		return 0, errMigrateUsage
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid steps %q: must be a positive number", args[0])
+	}
+	return n, nil
+}
+
+// prepareSchema при MIGRATE_AUTO накатывает миграции и не даёт запустить
+// сервер, если схема отстаёт от бинарника.
+func prepareSchema(cfg *config.Config) error {
+	m, err := pg.NewMigrator(cfg.DBUrl())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if cfg.Migrate.Auto {
+		if err := m.Up(0); err != nil {
+			return err
+		}
+	}
+
+	if err := m.Check(); err != nil {
+		return fmt.Errorf("%w; run `migrate up` or set MIGRATE_AUTO=true", err)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"gopr/cmd/config"
+	"gopr/internal/gateways/notify"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+
+	// часовые пояса сводок не зависят от tzdata системы
+	_ "time/tzdata"
+)
+
+// setupNotifications включает оповещения пользователей, проверку SLA ревью и
+// ежедневные сводки. Вызывается до setupOutbox: оповещения получают события
+// через relay. closeFn останавливает фоновые проверки и дожидается отправки
+// оповещений.
+func setupNotifications(ctx context.Context, cfg *config.Config, repos repo.Factory, cases *usecase.Cases) func() {
+	notifiers := []usecase.Notifier{
+		notify.NewSlack(nil, cfg.Notify.SlackWebhookURL),
+		notify.NewHTTP(nil, cfg.Notify.HTTPURL),
+	}
+	if smtp := cfg.Notify.SMTP; smtp.Addr != "" {
+		notifiers = append(notifiers, notify.NewEmail(smtp.Addr, smtp.From, smtp.Username, smtp.Password))
+	}
+
+	cases.Notification = usecase.NewNotification(
+		repos.Notification(),
+		repos.User(),
+		repos.PullRequest(),
+		repos.Identity(),
+		notifiers,
+		cfg.Notify.ReviewSLA,
+	)
+
+	slaInterval := cfg.Notify.SLAInterval
+	if cfg.Notify.ReviewSLA <= 0 {
+		slaInterval = 0
+	}
+
+	j := newJobs(ctx)
+	j.every(slaInterval, cases.Notification.RunSLA, "review SLA warnings disabled")
+	j.every(cfg.Notify.DigestInterval, cases.Notification.RunDigests, "review digests disabled")
+
+	return func() {
+		j.stop()
+		cases.Notification.Wait()
+	}
+}
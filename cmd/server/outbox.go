@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"gopr/cmd/config"
+	"gopr/internal/gateways/eventlog"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+	"gopr/pkg/slogx"
+)
+
+// setupOutbox запускает раздачу опубликованных событий потокам и, если relay
+// включён, публикацию событий из outbox в подписки, оповещения и файл событий. closeFn
+// останавливает обе.
+func setupOutbox(ctx context.Context, cfg *config.Config, repos repo.Factory, cases usecase.Cases) (func(), error) {
+	// без LISTEN/NOTIFY поток узнаёт о публикациях по таймеру
+	var wake <-chan struct{}
+	j := newJobs(ctx)
+	if l, ok := repos.(repo.OutboxListener); ok {
+		ch, err := l.ListenOutbox(j.ctx)
+		if err != nil {
+			j.stop()
+			return nil, err
+		}
+		wake = ch
+	}
+
+	var sinks []usecase.EventSink
+	var logFile *eventlog.File
+	if cfg.Outbox.Relay {
+		sinks = append(sinks, cases.Subscription, cases.Notification)
+
+		if cfg.Outbox.LogFile != "" {
+			f, err := eventlog.Open(cfg.Outbox.LogFile)
+			if err != nil {
+				j.stop()
+				return nil, err
+			}
+			logFile = f
+			sinks = append(sinks, logFile)
+		}
+	}
+
+	j.run(func(ctx context.Context) {
+		cases.Events.Run(ctx, cfg.Outbox.Interval, wake)
+	})
+
+	if cfg.Outbox.Relay {
+		relay := usecase.NewRelay(repos.Outbox(), sinks, cfg.Outbox.Batch, cfg.Outbox.MaxAttempts)
+		if l, ok := repos.(repo.OutboxLocker); ok {
+			relay.SetLocker(l)
+		}
+
+		j.every(cfg.Outbox.Interval, relay.Run, "outbox relay disabled")
+	} else {
+		slogx.Info(ctx, "outbox relay disabled")
+	}
+
+	return func() {
+		j.stop()
+		if logFile != nil {
+			_ = logFile.Close()
+		}
+	}, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gopr/cmd/config"
+	"gopr/internal/repo"
+	"gopr/internal/repo/memory"
+	"gopr/internal/repo/pg"
+	"gopr/internal/repo/sqlite"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// openStorage подключает хранилище из конфига; closeFn освобождает соединения.
+func openStorage(ctx context.Context, cfg *config.Config) (repo.Factory, func(), error) {
+	switch cfg.Storage {
+	case config.StoragePostgres:
+		if err := prepareSchema(cfg); err != nil {
+			return nil, nil, err
+		}
+		pool, err := pgxpool.NewWithConfig(ctx, cfg.PGXConfig())
+		if err != nil {
+			return nil, nil, fmt.Errorf("create database pool: %w", err)
+		}
+		return pg.NewFactory(pool), pool.Close, nil
+	case config.StorageSQLite:
+		db, err := sqlite.Open(ctx, cfg.SQLite.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sqlite.NewFactory(db), func() { _ = db.Close() }, nil
+	case config.StorageMemory:
+		return memory.NewFactory(memory.NewDB()), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
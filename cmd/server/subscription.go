@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"gopr/cmd/config"
+	"gopr/internal/gateways/delivery"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// setupSubscriptions включает подписки на события PR и их доставку. Повторы
+// доставок опрашиваются в каждом инстансе: доставки забираются атомарно.
+// closeFn останавливает опрос и дожидается начатых попыток.
+func setupSubscriptions(ctx context.Context, cfg *config.Config, repos repo.Factory, cases *usecase.Cases) func() {
+	retryInterval := cfg.Subscriptions.RetryInterval
+
+	cases.Subscription = usecase.NewSubscription(
+		repos.Subscription(),
+		delivery.NewSender(nil),
+		cfg.Subscriptions.MaxAttempts,
+		func() backoff.BackOff {
+			return backoff.NewExponentialBackOff(
+				backoff.WithInitialInterval(retryInterval),
+				backoff.WithMaxElapsedTime(0),
+			)
+		},
+	)
+
+	j := newJobs(ctx)
+	j.every(cfg.Subscriptions.PollInterval, cases.Subscription.RunDeliveries, "delivery retries disabled")
+
+	return func() {
+		j.stop()
+		cases.Subscription.Wait()
+	}
+}
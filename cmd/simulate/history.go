@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// history — команды и PR, которые прогоняются через назначение.
+type history struct {
+	Teams        []teamSpec `json:"teams"`
+	PullRequests []prSpec   `json:"pull_requests"`
+}
+
+// teamSpec — команда; родитель должен идти в списке раньше подкоманды.
+type teamSpec struct {
+	Name       string       `json:"team_name"`
+	ParentName string       `json:"parent_name"`
+	Members    []memberSpec `json:"members"`
+}
+
+// memberSpec — участник команды; без JoinedAt он состоит в ней с начала истории.
+type memberSpec struct {
+	UserID   string     `json:"user_id"`
+	Username string     `json:"username"`
+	JoinedAt *time.Time `json:"joined_at"`
+}
+
+// prSpec — PR из истории; TeamName пуст — ревьюит основная команда автора.
+type prSpec struct {
+	ID        string     `json:"pull_request_id"`
+	AuthorID  string     `json:"author_id"`
+	TeamName  string     `json:"team_name"`
+	CreatedAt time.Time  `json:"created_at"`
+	MergedAt  *time.Time `json:"merged_at"`
+}
+
+var csvHeader = []string{"event", "time", "team_name", "user_id", "username", "pull_request_id"}
+
+func loadHistory(path, format string) (*history, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input: %w", err)
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	var h *history
+	switch format {
+	case "json":
+		h = &history{}
+		err = json.NewDecoder(f).Decode(h)
+	case "csv":
+		h, err = decodeCSV(f)
+	default:
+		return nil, fmt.Errorf("unknown input format %q, expected json or csv", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s input: %w", format, err)
+	}
+
+	if len(h.PullRequests) == 0 {
+		return nil, errors.New("input contains no pull requests")
+	}
+
+	return h, nil
+}
+
+// decodeCSV читает журнал событий с заголовком csvHeader:
+//
+//	member,<joined_at|пусто>,<team_name>,<user_id>,<username>,
+//	pr,<created_at>,<team_name|пусто>,<author_id>,,<pull_request_id>
+//	merge,<merged_at>,,,,<pull_request_id>
+//
+// Команды создаются по первому упоминанию в строке member, иерархия в CSV не задаётся.
+func decodeCSV(r io.Reader) (*history, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	for i, col := range csvHeader {
+		if strings.TrimSpace(header[i]) != col {
+			return nil, fmt.Errorf("unexpected header %v, expected %v", header, csvHeader)
+		}
+	}
+
+	h := &history{}
+	teams := make(map[string]int)
+	prs := make(map[string]int)
+
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		event, ts, teamName, userID, username, prID := rec[0], rec[1], rec[2], rec[3], rec[4], rec[5]
+
+		var at *time.Time
+		if ts != "" {
+			t, err := time.Parse(time.RFC3339, ts)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid time: %w", line, err)
+			}
+			at = &t
+		}
+
+		switch event {
+		case "member":
+			i, ok := teams[teamName]
+			if !ok {
+				i = len(h.Teams)
+				teams[teamName] = i
+				h.Teams = append(h.Teams, teamSpec{Name: teamName})
+			}
+			h.Teams[i].Members = append(h.Teams[i].Members, memberSpec{UserID: userID, Username: username, JoinedAt: at})
+		case "pr":
+			if at == nil {
+				return nil, fmt.Errorf("line %d: pr event requires time", line)
+			}
+			prs[prID] = len(h.PullRequests)
+			h.PullRequests = append(h.PullRequests, prSpec{ID: prID, AuthorID: userID, TeamName: teamName, CreatedAt: *at})
+		case "merge":
+			i, ok := prs[prID]
+			if !ok || at == nil {
+				return nil, fmt.Errorf("line %d: merge of unknown PR %q or without time", line, prID)
+			}
+			h.PullRequests[i].MergedAt = at
+		default:
+			return nil, fmt.Errorf("line %d: unknown event %q", line, event)
+		}
+	}
+
+	return h, nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadHistory_JSONAndCSVMatch(t *testing.T) {
+	fromJSON, err := loadHistory("testdata/history.json", "")
+	require.NoError(t, err)
+	fromCSV, err := loadHistory("testdata/history.csv", "")
+	require.NoError(t, err)
+
+	require.Equal(t, fromJSON, fromCSV)
+
+	require.Len(t, fromJSON.Teams, 2)
+	require.Equal(t, "core", fromJSON.Teams[0].Name)
+	require.Len(t, fromJSON.Teams[0].Members, 4)
+	require.Nil(t, fromJSON.Teams[0].Members[0].JoinedAt)
+	require.Equal(t, time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), *fromJSON.Teams[0].Members[3].JoinedAt)
+
+	require.Len(t, fromJSON.PullRequests, 5)
+	require.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), *fromJSON.PullRequests[0].MergedAt)
+	require.Nil(t, fromJSON.PullRequests[1].MergedAt)
+	require.Equal(t, "solo", fromJSON.PullRequests[3].TeamName)
+}
+
+func TestLoadHistory_Errors(t *testing.T) {
+	const header = "event,time,team_name,user_id,username,pull_request_id\n"
+
+	cases := []struct {
+		name    string
+		file    string
+		format  string
+		content string
+		err     string
+	}{
+		{
+			name:    "format by flag",
+			file:    "history.log",
+			format:  "csv",
+			content: header + "pr,2026-10-01T09:00:00Z,,a,,p1\n",
+		},
+		{
+			name:    "unknown extension",
+			file:    "history.txt",
+			content: header,
+			err:     "unknown input format",
+		},
+		{
+			name:    "no pull requests",
+			file:    "history.json",
+			content: `{"teams": [{"team_name": "core"}]}`,
+			err:     "no pull requests",
+		},
+		{
+			name:    "bad header",
+			file:    "history.csv",
+			content: "kind,time,team_name,user_id,username,pull_request_id\n",
+			err:     "unexpected header",
+		},
+		{
+			name:    "pr without time",
+			file:    "history.csv",
+			content: header + "pr,,,a,,p1\n",
+			err:     "line 2: pr event requires time",
+		},
+		{
+			name:    "merge of unknown pr",
+			file:    "history.csv",
+			content: header + "pr,2026-10-01T09:00:00Z,,a,,p1\nmerge,2026-10-01T10:00:00Z,,,,p2\n",
+			err:     "line 3: merge of unknown PR",
+		},
+		{
+			name:    "unknown event",
+			file:    "history.csv",
+			content: header + "close,2026-10-01T09:00:00Z,,,,p1\n",
+			err:     "unknown event",
+		},
+		{
+			name:    "invalid time",
+			file:    "history.csv",
+			content: header + "pr,yesterday,,a,,p1\n",
+			err:     "invalid time",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.file)
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			h, err := loadHistory(path, tc.format)
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.NotEmpty(t, h.PullRequests)
+				return
+			}
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Command simulate прогоняет историю команд и PR через назначение ревьюверов
+// с разными стратегиями и числом ревьюверов и печатает метрики равномерности.
+//
+//	go run ./cmd/simulate -input history.json -reviewers 1,2,3
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gopr/internal/usecase"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	var (
+		input       = flag.String("input", "", "path to history file (.json or .csv)")
+		inputFormat = flag.String("input-format", "", "json or csv, by default taken from file extension")
+		strategies  = flag.String("strategies", strings.Join(usecase.StrategyNames, ","), "comma-separated assignment strategies")
+		reviewers   = flag.String("reviewers", "1,2,3", "comma-separated reviewer counts per PR")
+		seed        = flag.Int64("seed", 1, "random seed, the same for every run")
+		output      = flag.String("output", "text", "text or json")
+	)
+	flag.Parse()
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	if err := run(*input, *inputFormat, *strategies, *reviewers, *seed, *output); err != nil {
+		fmt.Fprintln(os.Stderr, "simulate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, inputFormat, strategies, reviewers string, seed int64, output string) error {
+	if input == "" {
+		return fmt.Errorf("-input is required")
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unknown output %q, expected text or json", output)
+	}
+
+	h, err := loadHistory(input, inputFormat)
+	if err != nil {
+		return err
+	}
+
+	counts, err := parseCounts(reviewers)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	var results []*runResult
+	for _, name := range strings.Split(strategies, ",") {
+		for _, n := range counts {
+			// у каждого прогона свой генератор с тем же seed, чтобы прогоны были воспроизводимы
+			strategy, err := usecase.NewStrategy(strings.TrimSpace(name), rand.New(rand.NewSource(seed)))
+			if err != nil {
+				return err
+			}
+
+			res, err := replay(ctx, h, strategy, n)
+			if err != nil {
+				return fmt.Errorf("%s, %d reviewers: %w", strategy.Name(), n, err)
+			}
+			results = append(results, res)
+		}
+	}
+
+	if output == "json" {
+		return writeJSON(os.Stdout, results)
+	}
+	return writeText(os.Stdout, results)
+}
+
+func parseCounts(s string) ([]int, error) {
+	var res []int
+	for _, part := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid reviewer count %q", part)
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+	"log/slog"
+	"sort"
+	"time"
+)
+
+type eventKind int
+
+// порядок важен при равном времени: участник вступает и PR мержится раньше,
+// чем создаётся следующий PR
+const (
+	eventJoin eventKind = iota
+	eventMerge
+	eventOpen
+)
+
+type event struct {
+	at     time.Time
+	kind   eventKind
+	team   string
+	member memberSpec
+	pr     prSpec
+}
+
+// runResult — итог прогона истории с одной стратегией и числом ревьюверов.
+type runResult struct {
+	Strategy     string
+	Reviewers    int
+	PullRequests int
+	// Unfilled — сколько мест ревьюверов осталось пустыми из-за нехватки кандидатов.
+	Unfilled int
+	// Skipped — PR, которые не удалось создать, например автор не в команде ревью.
+	Skipped int
+	From    time.Time
+	To      time.Time
+	Teams   []*teamResult
+}
+
+type teamResult struct {
+	TeamName string
+	Result   *domain.FairnessResult
+}
+
+// replay прогоняет историю через usecase.PullRequest поверх хранилища в
+// памяти, время которого сдвигается вслед за событиями.
+func replay(ctx context.Context, h *history, strategy usecase.Strategy, reviewers int) (*runResult, error) {
+	events := timeline(h)
+	from, to := events[0].at, events[len(events)-1].at
+	if !to.After(from) {
+		to = from.Add(24 * time.Hour)
+	}
+
+	now := from
+	db := memory.NewDB()
+	db.SetClock(func() time.Time { return now })
+
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, strategy, reviewers)
+
+	for _, t := range h.Teams {
+		if _, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{TeamName: t.Name, ParentName: t.ParentName}); err != nil {
+			return nil, fmt.Errorf("failed to create team %q: %w", t.Name, err)
+		}
+	}
+
+	res := &runResult{
+		Strategy:  strategy.Name(),
+		Reviewers: reviewers,
+		From:      from,
+		To:        to,
+	}
+	created := make(map[string]struct{}, len(h.PullRequests))
+
+	for _, ev := range events {
+		now = ev.at
+
+		switch ev.kind {
+		case eventJoin:
+			_, err := teamCase.AddMember(ctx, &domain.TeamMemberInput{
+				TeamName: ev.team,
+				UserID:   ev.member.UserID,
+				Username: ev.member.Username,
+				IsActive: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to add %q to team %q: %w", ev.member.UserID, ev.team, err)
+			}
+		case eventOpen:
+			pr, err := prCase.Create(ctx, &domain.CreatePullRequest{
+				Id:       ev.pr.ID,
+				AuthorId: ev.pr.AuthorID,
+				Name:     ev.pr.ID,
+				TeamName: ev.pr.TeamName,
+			})
+			if err != nil {
+				slog.Warn("skip pull request", slog.String("id", ev.pr.ID), slog.String("err", err.Error()))
+				res.Skipped++
+				continue
+			}
+			created[ev.pr.ID] = struct{}{}
+			res.PullRequests++
+			res.Unfilled += reviewers - len(pr.Reviewers)
+		case eventMerge:
+			if _, ok := created[ev.pr.ID]; !ok {
+				continue
+			}
+			if _, err := prCase.Merge(ctx, &domain.MergePullRequest{Id: ev.pr.ID}); err != nil {
+				return nil, fmt.Errorf("failed to merge %q: %w", ev.pr.ID, err)
+			}
+		}
+	}
+
+	for _, t := range h.Teams {
+		tr, err := teamFairness(ctx, teamRepo, prRepo, t.Name, strategy.Name(), from, to)
+		if err != nil {
+			return nil, err
+		}
+		if len(tr.Result.Members) == 0 {
+			continue
+		}
+		res.Teams = append(res.Teams, tr)
+	}
+
+	return res, nil
+}
+
+// teamFairness считает назначения на PR команды среди её активных участников.
+func teamFairness(
+	ctx context.Context,
+	teamRepo *memory.TeamRepo,
+	prRepo *memory.PullRequestRepo,
+	teamName, strategy string,
+	from, to time.Time,
+) (*teamResult, error) {
+	team, err := teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team: %w", err)
+	}
+
+	all, err := teamRepo.ListMembers(ctx, team.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team members: %w", err)
+	}
+
+	members := make([]*domain.TeamMember, 0, len(all))
+	for _, m := range all {
+		if m.User.IsActive && m.JoinedAt.Before(to) {
+			members = append(members, m)
+		}
+	}
+
+	prs, err := prRepo.List(ctx, &domain.PullRequestFilter{TeamName: team.Name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.Id)
+	}
+
+	reviewers, err := prRepo.ListReviewersByPRs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviewers: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, revs := range reviewers {
+		for _, id := range revs {
+			counts[id]++
+		}
+	}
+
+	loads := usecase.MemberLoads(members, counts, from, to)
+
+	return &teamResult{
+		TeamName: team.Name,
+		Result: &domain.FairnessResult{
+			Strategy: strategy,
+			Metrics:  usecase.EvaluateFairness(loads),
+			Members:  loads,
+		},
+	}, nil
+}
+
+// timeline раскладывает историю в события по времени.
+func timeline(h *history) []event {
+	start := h.PullRequests[0].CreatedAt
+	for _, pr := range h.PullRequests {
+		if pr.CreatedAt.Before(start) {
+			start = pr.CreatedAt
+		}
+	}
+	for _, t := range h.Teams {
+		for _, m := range t.Members {
+			if m.JoinedAt != nil && m.JoinedAt.Before(start) {
+				start = *m.JoinedAt
+			}
+		}
+	}
+
+	var events []event
+	for _, t := range h.Teams {
+		for _, m := range t.Members {
+			at := start
+			if m.JoinedAt != nil {
+				at = *m.JoinedAt
+			}
+			events = append(events, event{at: at, kind: eventJoin, team: t.Name, member: m})
+		}
+	}
+	for _, pr := range h.PullRequests {
+		events = append(events, event{at: pr.CreatedAt, kind: eventOpen, pr: pr})
+		if pr.MergedAt != nil {
+			events = append(events, event{at: *pr.MergedAt, kind: eventMerge, pr: pr})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].kind < events[j].kind
+	})
+
+	return events
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+)
+
+// byIDStrategy — детерминированная least_loaded: при равной нагрузке берёт
+// меньший id, чтобы назначения в тестах считались вручную.
+type byIDStrategy struct{}
+
+func (byIDStrategy) Name() string { return "by_id" }
+
+func (byIDStrategy) Pick(candidates []*domain.User, load map[string]int, n int) []*domain.User {
+	res := append([]*domain.User(nil), candidates...)
+	sort.Slice(res, func(i, j int) bool {
+		if load[res[i].Id] != load[res[j].Id] {
+			return load[res[i].Id] < load[res[j].Id]
+		}
+		return res[i].Id < res[j].Id
+	})
+	return res[:min(n, len(res))]
+}
+
+func TestTimeline_Order(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 10, 1, h, 0, 0, 0, time.UTC) }
+	ptr := func(v time.Time) *time.Time { return &v }
+
+	h := &history{
+		Teams: []teamSpec{{
+			Name: "core",
+			Members: []memberSpec{
+				{UserID: "a"},
+				{UserID: "late", JoinedAt: ptr(at(12))},
+			},
+		}},
+		PullRequests: []prSpec{
+			{ID: "p2", AuthorID: "a", CreatedAt: at(12)},
+			{ID: "p1", AuthorID: "a", CreatedAt: at(10), MergedAt: ptr(at(12))},
+		},
+	}
+
+	type step struct {
+		at   time.Time
+		kind eventKind
+		id   string
+	}
+	var got []step
+	for _, ev := range timeline(h) {
+		id := ev.pr.ID
+		if ev.kind == eventJoin {
+			id = ev.member.UserID
+		}
+		got = append(got, step{at: ev.at, kind: ev.kind, id: id})
+	}
+
+	// участник без joined_at вступает в начале истории; при равном времени
+	// вступление и мерж идут раньше создания PR
+	require.Equal(t, []step{
+		{at: at(10), kind: eventJoin, id: "a"},
+		{at: at(10), kind: eventOpen, id: "p1"},
+		{at: at(12), kind: eventJoin, id: "late"},
+		{at: at(12), kind: eventMerge, id: "p1"},
+		{at: at(12), kind: eventOpen, id: "p2"},
+	}, got)
+}
+
+func TestReplay(t *testing.T) {
+	h, err := loadHistory("testdata/history.json", "")
+	require.NoError(t, err)
+
+	type member struct {
+		assignments int
+		days        float64
+		flag        domain.FairnessFlag
+	}
+
+	cases := []struct {
+		name      string
+		reviewers int
+		unfilled  int
+		core      map[string]member
+		mean      float64
+		stddev    float64
+		gini      float64
+		maxMin    float64
+	}{
+		{
+			// p1 → b, p2 → b (p1 уже смержен), p3 → a; c и d без назначений
+			name:      "one reviewer",
+			reviewers: 1,
+			unfilled:  1,
+			core: map[string]member{
+				"a": {assignments: 1, days: 2},
+				"b": {assignments: 2, days: 2, flag: domain.FairnessOverloaded},
+				"c": {assignments: 0, days: 2, flag: domain.FairnessUnderloaded},
+				"d": {assignments: 0, days: 1.375, flag: domain.FairnessUnderloaded},
+			},
+			mean:   0.375,
+			stddev: math.Sqrt(0.171875),
+			gini:   7.0 / 12,
+			maxMin: math.Inf(1),
+		},
+		{
+			// p1 → b,c; p2 → b,c; p3 → a,d (у c открыт p2)
+			name:      "two reviewers",
+			reviewers: 2,
+			unfilled:  2,
+			core: map[string]member{
+				"a": {assignments: 1, days: 2},
+				"b": {assignments: 2, days: 2},
+				"c": {assignments: 2, days: 2},
+				"d": {assignments: 1, days: 1.375},
+			},
+			// rate: a 0.5, d 8/11, b и c по 1
+			mean:   71.0 / 88,
+			stddev: math.Sqrt(0.0437758),
+			gini:   0.1373239,
+			maxMin: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := replay(context.Background(), h, byIDStrategy{}, tc.reviewers)
+			require.NoError(t, err)
+
+			require.Equal(t, "by_id", res.Strategy)
+			require.Equal(t, 4, res.PullRequests)
+			require.Equal(t, 1, res.Skipped, "автора x нет в командах")
+			require.Equal(t, tc.unfilled, res.Unfilled, "у единственного участника solo нет ревьюверов")
+			require.Equal(t, time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC), res.From)
+			require.Equal(t, time.Date(2026, 10, 3, 9, 0, 0, 0, time.UTC), res.To)
+
+			require.Len(t, res.Teams, 2)
+			require.Equal(t, "core", res.Teams[0].TeamName)
+			require.Equal(t, "solo", res.Teams[1].TeamName)
+
+			core := res.Teams[0].Result
+			require.Len(t, core.Members, len(tc.core))
+			for _, l := range core.Members {
+				want, ok := tc.core[l.UserId]
+				require.True(t, ok, l.UserId)
+				require.Equal(t, want.assignments, l.Assignments, l.UserId)
+				require.InDelta(t, want.days, l.DaysActive, 1e-9, l.UserId)
+				require.Equal(t, want.flag, l.Flag, l.UserId)
+			}
+
+			m := core.Metrics
+			require.InDelta(t, tc.mean, m.Mean, 1e-6)
+			require.InDelta(t, tc.stddev, m.StdDev, 1e-6)
+			require.InDelta(t, tc.gini, m.Gini, 1e-6)
+			if math.IsInf(tc.maxMin, 1) {
+				require.True(t, math.IsInf(m.MaxMinRatio, 1))
+			} else {
+				require.InDelta(t, tc.maxMin, m.MaxMinRatio, 1e-9)
+			}
+
+			// без назначений у всех неравномерности нет
+			solo := res.Teams[1].Result
+			require.Len(t, solo.Members, 1)
+			require.Zero(t, solo.Members[0].Assignments)
+			require.InDelta(t, 1, solo.Metrics.MaxMinRatio, 1e-9)
+		})
+	}
+}
+
+func TestWriteJSON_InfiniteRatio(t *testing.T) {
+	h, err := loadHistory("testdata/history.csv", "")
+	require.NoError(t, err)
+
+	res, err := replay(context.Background(), h, byIDStrategy{}, 1)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeJSON(&buf, []*runResult{res}))
+
+	var out []struct {
+		Strategy string `json:"strategy"`
+		Skipped  int    `json:"skipped"`
+		Teams    []struct {
+			TeamName    string   `json:"team_name"`
+			Gini        float64  `json:"gini"`
+			MaxMinRatio *float64 `json:"max_min_ratio"`
+		} `json:"teams"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+	require.Len(t, out, 1)
+	require.Equal(t, "by_id", out[0].Strategy)
+	require.Equal(t, 1, out[0].Skipped)
+	require.Len(t, out[0].Teams, 2)
+	require.Nil(t, out[0].Teams[0].MaxMinRatio, "у c и d нет назначений")
+	require.InDelta(t, 7.0/12, out[0].Teams[0].Gini, 1e-6)
+	require.NotNil(t, out[0].Teams[1].MaxMinRatio)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopr/internal/domain"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+)
+
+func writeText(w io.Writer, results []*runResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "STRATEGY\tREVIEWERS\tTEAM\tPRS\tUNFILLED\tSKIPPED\tMEAN/DAY\tGINI\tSTDDEV\tMAX/MIN\tOVER\tUNDER")
+	for _, r := range results {
+		for _, t := range r.Teams {
+			m := t.Result.Metrics
+			over, under := flagged(t)
+			fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%d\t%d\t%.3f\t%.3f\t%.3f\t%s\t%d\t%d\n",
+				r.Strategy, r.Reviewers, t.TeamName, r.PullRequests, r.Unfilled, r.Skipped,
+				m.Mean, m.Gini, m.StdDev, ratio(m.MaxMinRatio), over, under)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(w, "\n%s, %d reviewers\n", r.Strategy, r.Reviewers)
+
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "  TEAM\tUSER\tASSIGNMENTS\tDAYS\tRATE\tFLAG")
+		for _, t := range r.Teams {
+			for _, l := range t.Result.Members {
+				fmt.Fprintf(tw, "  %s\t%s\t%d\t%.1f\t%.3f\t%s\n",
+					t.TeamName, l.Username, l.Assignments, l.DaysActive, l.Rate, l.Flag)
+			}
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type jsonMember struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	Assignments int     `json:"assignments"`
+	DaysActive  float64 `json:"days_active"`
+	Rate        float64 `json:"rate"`
+	Flag        string  `json:"flag,omitempty"`
+}
+
+type jsonTeam struct {
+	TeamName    string       `json:"team_name"`
+	Mean        float64      `json:"mean"`
+	Gini        float64      `json:"gini"`
+	StdDev      float64      `json:"stddev"`
+	MaxMinRatio *float64     `json:"max_min_ratio"`
+	Members     []jsonMember `json:"members"`
+}
+
+type jsonRun struct {
+	Strategy     string     `json:"strategy"`
+	Reviewers    int        `json:"reviewers"`
+	From         time.Time  `json:"from"`
+	To           time.Time  `json:"to"`
+	PullRequests int        `json:"pull_requests"`
+	Unfilled     int        `json:"unfilled"`
+	Skipped      int        `json:"skipped"`
+	Teams        []jsonTeam `json:"teams"`
+}
+
+// writeJSON печатает результаты; бесконечный max/min (кто-то без назначений) становится null.
+func writeJSON(w io.Writer, results []*runResult) error {
+	runs := make([]jsonRun, 0, len(results))
+	for _, r := range results {
+		run := jsonRun{
+			Strategy:     r.Strategy,
+			Reviewers:    r.Reviewers,
+			From:         r.From,
+			To:           r.To,
+			PullRequests: r.PullRequests,
+			Unfilled:     r.Unfilled,
+			Skipped:      r.Skipped,
+		}
+
+		for _, t := range r.Teams {
+			m := t.Result.Metrics
+			team := jsonTeam{
+				TeamName: t.TeamName,
+				Mean:     m.Mean,
+				Gini:     m.Gini,
+				StdDev:   m.StdDev,
+			}
+			if !math.IsInf(m.MaxMinRatio, 0) {
+				v := m.MaxMinRatio
+				team.MaxMinRatio = &v
+			}
+			for _, l := range t.Result.Members {
+				team.Members = append(team.Members, jsonMember{
+					UserID:      l.UserId,
+					Username:    l.Username,
+					Assignments: l.Assignments,
+					DaysActive:  l.DaysActive,
+					Rate:        l.Rate,
+					Flag:        string(l.Flag),
+				})
+			}
+			run.Teams = append(run.Teams, team)
+		}
+
+		runs = append(runs, run)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(runs)
+}
+
+func flagged(t *teamResult) (over, under int) {
+	for _, l := range t.Result.Members {
+		switch l.Flag {
+		case domain.FairnessOverloaded:
+			over++
+		case domain.FairnessUnderloaded:
+			under++
+		}
+	}
+	return over, under
+}
+
+func ratio(v float64) string {
+	if math.IsInf(v, 0) {
+		return "inf"
+	}
+	return fmt.Sprintf("%.2f", v)
+}
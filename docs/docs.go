@@ -15,47 +15,146 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/pullRequest/create": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
+        "/audit": {
+            "get": {
+                "description": "Кто и когда менял пользователей, команды и PR, со снимками цели до и после.\nПо умолчанию новые записи первыми; from и to (RFC3339) ограничивают время записи: [from, to).\nrequest_id — значение заголовка X-Request-Id запроса, сделавшего изменение.\nactor — подтверждённый инициатор (отправитель вебхука, автор slash-команды) или system;\nclaimed_actor — непроверенный X-Actor-Id запроса.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "Audit"
                 ],
-                "summary": "Создать PR и автоматически назначить до 2 ревьюверов из команды автора",
+                "summary": "Журнал аудита",
                 "parameters": [
                     {
-                        "description": "PR create payload",
-                        "name": "pr",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/domain.CreatePullRequest"
-                        }
+                        "maxLength": 64,
+                        "enum": [
+                            "user.set_active",
+                            "user.update",
+                            "user.move",
+                            "team.add",
+                            "team.rename",
+                            "team.set_parent",
+                            "team.delete",
+                            "team.add_member",
+                            "team.remove_member",
+                            "team.set_member_role",
+                            "pull_request.create",
+                            "pull_request.merge",
+                            "pull_request.reassign",
+                            "pull_request.unassign"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "AuditUserSetActive",
+                            "AuditUserUpdate",
+                            "AuditUserMove",
+                            "AuditTeamAdd",
+                            "AuditTeamRename",
+                            "AuditTeamSetParent",
+                            "AuditTeamDelete",
+                            "AuditTeamAddMember",
+                            "AuditTeamRemoveMember",
+                            "AuditTeamSetMemberRole",
+                            "AuditPullRequestCreate",
+                            "AuditPullRequestMerge",
+                            "AuditPullRequestReassign",
+                            "AuditPullRequestUnassign"
+                        ],
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "claimed_actor",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 512,
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "oldest",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SortOldest",
+                            "SortNewest"
+                        ],
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "team",
+                            "pull_request"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "AuditTargetUser",
+                            "AuditTargetTeam",
+                            "AuditTargetPullRequest"
+                        ],
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/dto.PullRequest"
-                            }
+                            "$ref": "#/definitions/dto.AuditList"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -63,41 +162,122 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/merge": {
+        "/chatops/slack": {
             "post": {
+                "description": "Подпись X-Slack-Signature проверяется секретом CHATOPS_SLACK_SIGNING_SECRET, запросы старше 5 минут отклоняются.\nКоманды: reviews [@user], reassign \u003cpull request id\u003e @reviewer, away [until YYYY-MM-DD], back, help.\nАвтор команды сопоставляется с пользователем gopr по учётной записи slack или CHATOPS_SLACK_LOGINS.\nОшибки команды возвращаются текстом ответа со статусом 200, чтобы Slack показал их автору.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "ChatOps"
                 ],
-                "summary": "Пометить PR как MERGED (идемпотентная операция)",
+                "summary": "Выполнить slash-команду Slack",
                 "parameters": [
                     {
-                        "description": "Merge request",
-                        "name": "pr",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Время запроса, unix-секунды",
+                        "name": "X-Slack-Request-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "v0=\u003chex HMAC-SHA256 строки v0:\u003ctimestamp\u003e:\u003cтело\u003e\u003e",
+                        "name": "X-Slack-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Имя команды, например /gopr",
+                        "name": "command",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Текст после имени команды",
+                        "name": "text",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Id автора в Slack",
+                        "name": "user_id",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/domain.MergePullRequest"
+                            "$ref": "#/definitions/dto.SlashReply"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/stream": {
+            "get": {
+                "description": "События reviewers.assigned (создание PR с назначенными ревьюверами), reviewer.reassigned и\npull_request.merged в формате text/event-stream: id — номер события, event — тип, data — событие в JSON.\nteam_name оставляет события PR команды, user_id — события, где пользователь автор или ревьювер.\nПереподключение с заголовком Last-Event-ID (или last_event_id) сначала отдаёт пропущенные события.\nОтставший клиент отключается и продолжает с Last-Event-ID.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Поток событий PR (Server-Sent Events)",
+                "parameters": [
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "id последнего полученного события",
+                        "name": "Last-Event-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "поток событий",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/dto.PullRequest"
-                            }
+                            "type": "string"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -105,8 +285,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/reassign": {
+        "/identities/add": {
             "post": {
+                "description": "Логин сохраняется в нижнем регистре. У пользователя может быть несколько\nучётных записей, в том числе у одного провайдера.",
                 "consumes": [
                     "application/json"
                 ],
@@ -114,25 +295,34 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "Identities"
                 ],
-                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "summary": "Привязать учётную запись провайдера к пользователю",
                 "parameters": [
                     {
-                        "description": "Reassign payload",
-                        "name": "reassign",
+                        "description": "Пользователь, провайдер, логин и числовой id",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.ReassignPullRequest"
+                            "$ref": "#/definitions/domain.IdentityAddInput"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/dto.PullRequestReassignResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Identity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
@@ -146,11 +336,17 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/team/add": {
+        "/identities/delete": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -159,27 +355,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "Identities"
                 ],
-                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "summary": "Отвязать учётную запись провайдера",
                 "parameters": [
                     {
-                        "description": "Team object",
-                        "name": "team",
+                        "description": "Провайдер и логин",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.TeamAddInput"
+                            "$ref": "#/definitions/domain.IdentityDeleteInput"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/dto.Team"
+                                "$ref": "#/definitions/dto.Identity"
                             }
                         }
                     },
@@ -188,24 +384,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/team/get": {
+        "/identities/list": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "Identities"
                 ],
-                "summary": "Получить команду с участниками",
+                "summary": "Учётные записи пользователя",
                 "parameters": [
                     {
+                        "maxLength": 128,
                         "type": "string",
-                        "description": "Team name",
-                        "name": "team_name",
+                        "name": "user_id",
                         "in": "query",
                         "required": true
                     }
@@ -214,7 +422,86 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.Team"
+                            "$ref": "#/definitions/dto.IdentityList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identities/lookup": {
+            "get": {
+                "description": "Кроме привязанных учётных записей учитываются логины из WEBHOOK_*_LOGINS.\nНеизвестная учётная запись — 404 UNKNOWN_IDENTITY.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Identities"
+                ],
+                "summary": "Найти пользователя по логину или числовому id у провайдера",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "external_id",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "login",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "github",
+                            "gitlab",
+                            "email",
+                            "slack"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "IdentityGitHub",
+                            "IdentityGitLab",
+                            "IdentityEmail",
+                            "IdentitySlack"
+                        ],
+                        "name": "provider",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Identity"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "404": {
@@ -222,12 +509,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/users/setIsActive": {
+        "/identities/update": {
             "post": {
+                "description": "external_id 0 или отсутствующий сбрасывает id.",
                 "consumes": [
                     "application/json"
                 ],
@@ -235,17 +529,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Identities"
                 ],
-                "summary": "Установить флаг активности пользователя",
+                "summary": "Изменить числовой id учётной записи",
                 "parameters": [
                     {
-                        "description": "User ID and active flag",
+                        "description": "Провайдер, логин и новый числовой id",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.setActiveInput"
+                            "$ref": "#/definitions/domain.IdentityUpdateInput"
                         }
                     }
                 ],
@@ -255,61 +549,3406 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/dto.User"
+                                "$ref": "#/definitions/dto.Identity"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/digest/get": {
+            "get": {
+                "description": "Без сохранённых настроек сводка выключена, час отправки — 9 по UTC.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Настройки ежедневной сводки пользователя",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.DigestSettings"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/digest/preview": {
+            "get": {
+                "description": "Отрисовывает сводку на текущий момент, не отправляя её, даже если сводка выключена.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Предпросмотр сводки",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Digest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/digest/set": {
+            "post": {
+                "description": "Сводка уходит по каналам оповещений пользователя раз в сутки, начиная с hour\nпо его часовому поясу, но не в тихие часы [quiet_hours_start, quiet_hours_end).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Включить или настроить ежедневную сводку",
+                "parameters": [
+                    {
+                        "description": "Настройки сводки",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.DigestSettingsInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.DigestSettings"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences/delete": {
+            "post": {
+                "description": "Возвращает оставшиеся каналы пользователя.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Отключить канал оповещений",
+                "parameters": [
+                    {
+                        "description": "Пользователь и канал",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.NotificationPreferenceDeleteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationPreferenceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Каналы оповещений пользователя",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.NotificationPreferenceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/preferences/set": {
+            "post": {
+                "description": "Адрес — почта для email и URL для slack и http. Без адреса письма уходят\nна почту из учётной записи email пользователя, а slack и http — на адреса\nиз настроек сервера.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Notifications"
+                ],
+                "summary": "Включить канал оповещений или изменить его адрес",
+                "parameters": [
+                    {
+                        "description": "Пользователь, канал и адрес",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.NotificationPreferenceSetInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.NotificationPreference"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/create": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Создать PR и автоматически назначить до 2 ревьюверов из команды автора",
+                "parameters": [
+                    {
+                        "description": "PR create payload",
+                        "name": "pr",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CreatePullRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.PullRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/get": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Получить PR с ревьюверами и историей назначений",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "pull_request_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PullRequestDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Поиск PR по организации с keyset-пагинацией",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "author_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 512,
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "merged_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "merged_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "oldest",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SortOldest",
+                            "SortNewest"
+                        ],
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "reviewer_id",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 10,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "reviewers_lt",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "OPEN",
+                            "MERGED"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PullRequestList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/merge": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Пометить PR как MERGED (идемпотентная операция)",
+                "parameters": [
+                    {
+                        "description": "Merge request",
+                        "name": "pr",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MergePullRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.PullRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переназначить конкретного ревьювера на другого из его команды",
+                "parameters": [
+                    {
+                        "description": "Reassign payload",
+                        "name": "reassign",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReassignPullRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PullRequestReassignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/fairness": {
+            "get": {
+                "description": "Gini, стандартное отклонение и max/min по назначениям в день среди активных участников.\nФактическое распределение сравнивается с прогоном PR окна через каждую стратегию.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Равномерность назначений в команде",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.FairnessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/mergeTime": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Перцентили времени до merge за период",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MergeTimeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/teams": {
+            "get": {
+                "description": "Считаются PR, созданные в [from, to) и ревьюемые командой.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Итоги по командам за период",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/users": {
+            "get": {
+                "description": "Считаются PR, созданные в [from, to); по умолчанию последние 30 дней.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Нагрузка ревьюверов за период",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/add": {
+            "post": {
+                "description": "Пустой events — все события. Тело доставки подписывается секретом:\nзаголовок X-Gopr-Signature-256 содержит sha256=\u003cHMAC-SHA256 тела в hex\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Подписать внешнюю систему на события PR",
+                "parameters": [
+                    {
+                        "description": "URL, фильтр событий и секрет",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SubscriptionAddInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/delete": {
+            "post": {
+                "description": "Журнал доставок подписки удаляется вместе с ней.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "description": "Subscription id",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SubscriptionDeleteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/deliveries": {
+            "get": {
+                "description": "По умолчанию новые доставки первыми. Доставка, исчерпавшая попытки, — DEAD.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Журнал доставок подписки",
+                "parameters": [
+                    {
+                        "maxLength": 512,
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "oldest",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SortOldest",
+                            "SortNewest"
+                        ],
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "PENDING",
+                            "DELIVERED",
+                            "DEAD"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "DeliveryPending",
+                            "DeliveryDelivered",
+                            "DeliveryDead"
+                        ],
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "subscription_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeliveryList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Список подписок",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubscriptionList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/subscriptions/redeliver": {
+            "post": {
+                "description": "Событие отправляется заново новой доставкой с тем же event_id и телом.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Subscriptions"
+                ],
+                "summary": "Повторить доставку",
+                "parameters": [
+                    {
+                        "description": "Delivery id",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RedeliverInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Delivery"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/add": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Создать команду с участниками (создаёт/обновляет пользователей)",
+                "parameters": [
+                    {
+                        "description": "Team object",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamAddInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/addMember": {
+            "post": {
+                "description": "Пользователь может состоять в нескольких командах.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Добавить участника в команду (создаёт пользователя, если его нет)",
+                "parameters": [
+                    {
+                        "description": "Участник",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamMemberInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/delete": {
+            "post": {
+                "description": "Если участники назначены на открытые PR, без force возвращается TEAM_HAS_OPEN_REVIEWS,\nс force они снимаются с этих PR.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Удалить команду (участники остаются без команды)",
+                "parameters": [
+                    {
+                        "description": "Команда и флаг force",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamDeleteInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamDeleteResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/get": {
+            "get": {
+                "description": "С subtree=true в ответ добавляются подкоманды и участники всего поддерева.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить команду с участниками",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Subtree добавляет в ответ подкоманды и участников всего поддерева.",
+                        "name": "subtree",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Список команд с количеством участников",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamList"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/removeMember": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Вывести участника из команды",
+                "parameters": [
+                    {
+                        "description": "Команда и пользователь",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamRemoveMemberInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/rename": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Переименовать команду",
+                "parameters": [
+                    {
+                        "description": "Текущее и новое имя",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamRenameInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/setMemberRole": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Изменить роль участника команды",
+                "parameters": [
+                    {
+                        "description": "Команда, пользователь и роль",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamMemberRoleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/setParent": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Перенести команду в другую или сделать корневой",
+                "parameters": [
+                    {
+                        "description": "Команда и новый родитель",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.TeamSetParentInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/get": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить пользователя с командой и текущей нагрузкой",
+                "parameters": [
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 512,
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "oldest",
+                            "newest"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "SortOldest",
+                            "SortNewest"
+                        ],
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "OPEN",
+                            "MERGED"
+                        ],
+                        "type": "string",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 128,
+                        "type": "string",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserReviews"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/list": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Список пользователей с поиском по префиксу имени",
+                "parameters": [
+                    {
+                        "maxLength": 512,
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 255,
+                        "type": "string",
+                        "name": "username_prefix",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/moveToTeam": {
+            "post": {
+                "description": "Открытые ревью пользователя передаются другим участникам старой команды.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Перевести пользователя в другую команду",
+                "parameters": [
+                    {
+                        "description": "User id и новая команда",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserMoveInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserMoveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Установить флаг активности пользователя",
+                "parameters": [
+                    {
+                        "description": "User ID and active flag",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.setActiveInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/update": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Изменить имя пользователя",
+                "parameters": [
+                    {
+                        "description": "User id и новое имя",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UserUpdateInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/dto.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/github": {
+            "post": {
+                "description": "Подпись X-Hub-Signature-256 проверяется секретом WEBHOOK_GITHUB_SECRET.\nopened, reopened и ready_for_review создают PR, closed с merged=true мержит его, closed без merge снимает с него ревьюверов; остальные события игнорируются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Принять вебхук GitHub о pull request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип события",
+                        "name": "X-GitHub-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "sha256=\u003chex HMAC-SHA256 тела\u003e",
+                        "name": "X-Hub-Signature-256",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/gitlab": {
+            "post": {
+                "description": "Заголовок X-Gitlab-Token сравнивается с секретом WEBHOOK_GITLAB_SECRET.\nopen, reopen и снятие draft создают PR, merge мержит его, close снимает с него ревьюверов; остальные события игнорируются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Принять вебхук GitLab о merge request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Тип события",
+                        "name": "X-Gitlab-Event",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Секрет вебхука",
+                        "name": "X-Gitlab-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.AuditAction": {
+            "type": "string",
+            "enum": [
+                "user.set_active",
+                "user.update",
+                "user.move",
+                "team.add",
+                "team.rename",
+                "team.set_parent",
+                "team.delete",
+                "team.add_member",
+                "team.remove_member",
+                "team.set_member_role",
+                "pull_request.create",
+                "pull_request.merge",
+                "pull_request.reassign",
+                "pull_request.unassign"
+            ],
+            "x-enum-varnames": [
+                "AuditUserSetActive",
+                "AuditUserUpdate",
+                "AuditUserMove",
+                "AuditTeamAdd",
+                "AuditTeamRename",
+                "AuditTeamSetParent",
+                "AuditTeamDelete",
+                "AuditTeamAddMember",
+                "AuditTeamRemoveMember",
+                "AuditTeamSetMemberRole",
+                "AuditPullRequestCreate",
+                "AuditPullRequestMerge",
+                "AuditPullRequestReassign",
+                "AuditPullRequestUnassign"
+            ]
+        },
+        "domain.AuditTargetType": {
+            "type": "string",
+            "enum": [
+                "user",
+                "team",
+                "pull_request"
+            ],
+            "x-enum-varnames": [
+                "AuditTargetUser",
+                "AuditTargetTeam",
+                "AuditTargetPullRequest"
+            ]
+        },
         "domain.CreatePullRequest": {
+            "type": "object",
+            "required": [
+                "author_id",
+                "pull_request_name"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "pull_request_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "team_name": {
+                    "description": "TeamName — команда ревью; по умолчанию основная команда автора.",
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "DELIVERED",
+                "DEAD"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliveryDelivered",
+                "DeliveryDead"
+            ]
+        },
+        "domain.DigestSettingsInput": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hour": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "quiet_hours_end": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "quiet_hours_start": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "timezone": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.EventType": {
+            "type": "string",
+            "enum": [
+                "reviewers.assigned",
+                "reviewer.reassigned",
+                "pull_request.merged"
+            ],
+            "x-enum-varnames": [
+                "EventReviewersAssigned",
+                "EventReviewerReassigned",
+                "EventPullRequestMerged"
+            ]
+        },
+        "domain.IdentityAddInput": {
+            "type": "object",
+            "required": [
+                "login",
+                "provider",
+                "user_id"
+            ],
+            "properties": {
+                "external_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "login": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "provider": {
+                    "enum": [
+                        "github",
+                        "gitlab",
+                        "email",
+                        "slack"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IdentityProvider"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.IdentityDeleteInput": {
+            "type": "object",
+            "required": [
+                "login",
+                "provider"
+            ],
+            "properties": {
+                "login": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "provider": {
+                    "enum": [
+                        "github",
+                        "gitlab",
+                        "email",
+                        "slack"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IdentityProvider"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.IdentityProvider": {
+            "type": "string",
+            "enum": [
+                "github",
+                "gitlab",
+                "email",
+                "slack"
+            ],
+            "x-enum-varnames": [
+                "IdentityGitHub",
+                "IdentityGitLab",
+                "IdentityEmail",
+                "IdentitySlack"
+            ]
+        },
+        "domain.IdentityUpdateInput": {
+            "type": "object",
+            "required": [
+                "login",
+                "provider"
+            ],
+            "properties": {
+                "external_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "login": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "provider": {
+                    "enum": [
+                        "github",
+                        "gitlab",
+                        "email",
+                        "slack"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.IdentityProvider"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.MembershipRole": {
+            "type": "string",
+            "enum": [
+                "member",
+                "lead"
+            ],
+            "x-enum-varnames": [
+                "RoleMember",
+                "RoleLead"
+            ]
+        },
+        "domain.MergePullRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.NotificationChannel": {
+            "type": "string",
+            "enum": [
+                "slack",
+                "email",
+                "http"
+            ],
+            "x-enum-varnames": [
+                "NotificationSlack",
+                "NotificationEmail",
+                "NotificationHTTP"
+            ]
+        },
+        "domain.NotificationPreferenceDeleteInput": {
+            "type": "object",
+            "required": [
+                "channel",
+                "user_id"
+            ],
+            "properties": {
+                "channel": {
+                    "enum": [
+                        "slack",
+                        "email",
+                        "http"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.NotificationChannel"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.NotificationPreferenceSetInput": {
+            "type": "object",
+            "required": [
+                "channel",
+                "user_id"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "maxLength": 2048
+                },
+                "channel": {
+                    "enum": [
+                        "slack",
+                        "email",
+                        "http"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.NotificationChannel"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.ReassignPullRequest": {
+            "type": "object",
+            "required": [
+                "old_reviewer_id",
+                "pull_request_id"
+            ],
+            "properties": {
+                "old_reviewer_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "pull_request_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.RedeliverInput": {
+            "type": "object",
+            "required": [
+                "delivery_id"
+            ],
+            "properties": {
+                "delivery_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.SortOrder": {
+            "type": "string",
+            "enum": [
+                "oldest",
+                "newest"
+            ],
+            "x-enum-varnames": [
+                "SortOldest",
+                "SortNewest"
+            ]
+        },
+        "domain.SubscriptionAddInput": {
+            "type": "object",
+            "required": [
+                "secret",
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/domain.EventType"
+                    }
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "domain.SubscriptionDeleteInput": {
+            "type": "object",
+            "required": [
+                "subscription_id"
+            ],
+            "properties": {
+                "subscription_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.TeamAddInput": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/domain.TeamAddMemberInput"
+                    }
+                },
+                "parent_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.TeamAddMemberInput": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "username"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "enum": [
+                        "member",
+                        "lead"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MembershipRole"
+                        }
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.TeamDeleteInput": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "force": {
+                    "description": "Force снимает членов команды с открытых ревью вместо отказа в удалении.",
+                    "type": "boolean"
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.TeamMemberInput": {
+            "type": "object",
+            "required": [
+                "team_name",
+                "user_id",
+                "username"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "enum": [
+                        "member",
+                        "lead"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MembershipRole"
+                        }
+                    ]
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.TeamMemberRoleInput": {
+            "type": "object",
+            "required": [
+                "role",
+                "team_name",
+                "user_id"
+            ],
+            "properties": {
+                "role": {
+                    "enum": [
+                        "member",
+                        "lead"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.MembershipRole"
+                        }
+                    ]
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.TeamRemoveMemberInput": {
+            "type": "object",
+            "required": [
+                "team_name",
+                "user_id"
+            ],
+            "properties": {
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.TeamRenameInput": {
+            "type": "object",
+            "required": [
+                "new_team_name",
+                "team_name"
+            ],
+            "properties": {
+                "new_team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.TeamSetParentInput": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "parent_name": {
+                    "description": "ParentName пуст — команда становится корневой.",
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "domain.UserMoveInput": {
+            "type": "object",
+            "required": [
+                "team_name",
+                "user_id"
+            ],
+            "properties": {
+                "team_name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                }
+            }
+        },
+        "domain.UserUpdateInput": {
+            "type": "object",
+            "required": [
+                "user_id",
+                "username"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "string",
+                    "maxLength": 128
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "dto.AuditList": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditRecord"
+                    }
+                }
+            }
+        },
+        "dto.AuditRecord": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "audit_id": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "claimed_actor": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "team",
+                        "pull_request"
+                    ]
+                }
+            }
+        },
+        "dto.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string",
+                    "enum": [
+                        "reviewers.assigned",
+                        "reviewer.reassigned",
+                        "pull_request.merged"
+                    ]
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_code": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "DELIVERED",
+                        "DEAD"
+                    ]
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeliveryList": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Delivery"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Digest": {
+            "type": "object",
+            "properties": {
+                "generated_at": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DigestItem"
+                    }
+                },
+                "overdue": {
+                    "type": "integer"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DigestItem": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DigestSettings": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "hour": {
+                    "type": "integer"
+                },
+                "last_sent_at": {
+                    "type": "string"
+                },
+                "quiet_hours_end": {
+                    "type": "integer"
+                },
+                "quiet_hours_start": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ErrorObject": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/dto.ErrorObject"
+                }
+            }
+        },
+        "dto.FairnessResponse": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "$ref": "#/definitions/dto.FairnessResult"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "integer"
+                },
+                "strategies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FairnessResult"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FairnessResult": {
+            "type": "object",
+            "properties": {
+                "gini": {
+                    "type": "number"
+                },
+                "max_min_ratio": {
+                    "type": "number"
+                },
+                "mean": {
+                    "type": "number"
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MemberLoad"
+                    }
+                },
+                "stddev": {
+                    "type": "number"
+                },
+                "strategy": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Identity": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "integer"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "enum": [
+                        "github",
+                        "gitlab",
+                        "email",
+                        "slack"
+                    ]
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.IdentityList": {
+            "type": "object",
+            "properties": {
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Identity"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MemberLoad": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "integer"
+                },
+                "days_active": {
+                    "type": "number"
+                },
+                "flag": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MergeTimeResponse": {
+            "type": "object",
+            "properties": {
+                "avg_seconds": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "merged": {
+                    "type": "integer"
+                },
+                "p50_seconds": {
+                    "type": "number"
+                },
+                "p90_seconds": {
+                    "type": "number"
+                },
+                "p95_seconds": {
+                    "type": "number"
+                },
+                "p99_seconds": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotificationPreference": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "slack",
+                        "email",
+                        "http"
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.NotificationPreferenceList": {
+            "type": "object",
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.NotificationPreference"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PullRequest": {
+            "type": "object",
+            "properties": {
+                "assigned_reviewers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "mergedAt": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PullRequestDetails": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/dto.PullRequest"
+                },
+                "review_sync": {
+                    "$ref": "#/definitions/dto.ReviewSync"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimelineEvent"
+                    }
+                }
+            }
+        },
+        "dto.PullRequestList": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PullRequest"
+                    }
+                }
+            }
+        },
+        "dto.PullRequestReassignResponse": {
+            "type": "object",
+            "properties": {
+                "pr": {
+                    "$ref": "#/definitions/dto.PullRequest"
+                },
+                "replaced_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PullRequestShort": {
             "type": "object",
             "properties": {
+                "assigned_reviewers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "author_id": {
                     "type": "string"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
                 },
                 "pull_request_name": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "domain.MergePullRequest": {
+        "dto.Reassignment": {
             "type": "object",
             "properties": {
+                "new_reviewer_id": {
+                    "type": "string"
+                },
+                "old_reviewer_id": {
+                    "type": "string"
+                },
                 "pull_request_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.ReassignPullRequest": {
+        "dto.ReviewAssignment": {
             "type": "object",
             "properties": {
-                "old_reviewer_id": {
+                "pull_request_id": {
                     "type": "string"
                 },
-                "pull_request_id": {
+                "reviewer_id": {
                     "type": "string"
                 }
             }
         },
-        "domain.TeamAddInput": {
+        "dto.ReviewSync": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "PENDING",
+                        "SYNCED",
+                        "FAILED"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SlashReply": {
+            "type": "object",
+            "properties": {
+                "response_type": {
+                    "type": "string",
+                    "enum": [
+                        "ephemeral",
+                        "in_channel"
+                    ]
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Subscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "subscription_id": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SubscriptionList": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Subscription"
+                    }
+                }
+            }
+        },
+        "dto.Team": {
             "type": "object",
             "properties": {
+                "aggregate_members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TeamMember"
+                    }
+                },
                 "members": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/domain.TeamAddMemberInput"
+                        "$ref": "#/definitions/dto.TeamMember"
+                    }
+                },
+                "parent_name": {
+                    "type": "string"
+                },
+                "subteams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Team"
                     }
                 },
                 "team_name": {
@@ -317,12 +3956,40 @@ const docTemplate = `{
                 }
             }
         },
-        "domain.TeamAddMemberInput": {
+        "dto.TeamDeleteResponse": {
+            "type": "object",
+            "properties": {
+                "removed_reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReviewAssignment"
+                    }
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TeamList": {
+            "type": "object",
+            "properties": {
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TeamSummary"
+                    }
+                }
+            }
+        },
+        "dto.TeamMember": {
             "type": "object",
             "properties": {
                 "is_active": {
                     "type": "boolean"
                 },
+                "role": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -331,84 +3998,171 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.ErrorObject": {
+        "dto.TeamStats": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "string"
+                "assignments": {
+                    "type": "integer"
                 },
-                "message": {
+                "merged": {
+                    "type": "integer"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "pull_requests": {
+                    "type": "integer"
+                },
+                "reassignments": {
+                    "type": "integer"
+                },
+                "team_name": {
                     "type": "string"
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.TeamStatsResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "$ref": "#/definitions/dto.ErrorObject"
+                "from": {
+                    "type": "string"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TeamStats"
+                    }
+                },
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "dto.PullRequest": {
+        "dto.TeamSummary": {
             "type": "object",
             "properties": {
-                "assigned_reviewers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "active_count": {
+                    "type": "integer"
                 },
-                "author_id": {
+                "members_count": {
+                    "type": "integer"
+                },
+                "parent_name": {
                     "type": "string"
                 },
-                "createdAt": {
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TimelineEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
                     "type": "string"
                 },
-                "mergedAt": {
+                "at": {
                     "type": "string"
                 },
-                "pull_request_id": {
+                "reason": {
                     "type": "string"
                 },
-                "pull_request_name": {
+                "reviewer_id": {
                     "type": "string"
                 },
-                "status": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "dto.PullRequestReassignResponse": {
+        "dto.User": {
             "type": "object",
             "properties": {
-                "pr": {
-                    "$ref": "#/definitions/dto.PullRequest"
+                "is_active": {
+                    "type": "boolean"
                 },
-                "replaced_by": {
+                "is_available": {
+                    "type": "boolean"
+                },
+                "review_load": {
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserTeam"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "dto.Team": {
+        "dto.UserList": {
             "type": "object",
             "properties": {
-                "members": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "users": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.TeamMember"
+                        "$ref": "#/definitions/dto.User"
+                    }
+                }
+            }
+        },
+        "dto.UserMoveResponse": {
+            "type": "object",
+            "properties": {
+                "reassigned": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.Reassignment"
                     }
                 },
-                "team_name": {
+                "user": {
+                    "$ref": "#/definitions/dto.User"
+                }
+            }
+        },
+        "dto.UserReviews": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "pull_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PullRequestShort"
+                    }
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.TeamMember": {
+        "dto.UserStats": {
             "type": "object",
             "properties": {
-                "is_active": {
-                    "type": "boolean"
+                "assigned": {
+                    "type": "integer"
+                },
+                "merged": {
+                    "type": "integer"
+                },
+                "open": {
+                    "type": "integer"
+                },
+                "reassigned_away": {
+                    "type": "integer"
                 },
                 "user_id": {
                     "type": "string"
@@ -418,19 +4172,50 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.User": {
+        "dto.UserStatsResponse": {
             "type": "object",
             "properties": {
-                "is_active": {
-                    "type": "boolean"
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.UserStats"
+                    }
+                }
+            }
+        },
+        "dto.UserTeam": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
                 },
                 "team_name": {
                     "type": "string"
+                }
+            }
+        },
+        "dto.WebhookResult": {
+            "type": "object",
+            "properties": {
+                "outcome": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "merged",
+                        "closed",
+                        "ignored"
+                    ]
                 },
-                "user_id": {
+                "pull_request_id": {
                     "type": "string"
                 },
-                "username": {
+                "reason": {
                     "type": "string"
                 }
             }
@@ -446,7 +4231,8 @@ const docTemplate = `{
                     "type": "boolean"
                 },
                 "user_id": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 128
                 }
             }
         }
@@ -0,0 +1,58 @@
+package domain
+
+import "context"
+
+// ActorCtxKey — ключ, под которым в контексте лежит id инициатора действия.
+// Строковый, чтобы значение было видно и через gin.Context.
+const ActorCtxKey = "gopr_actor"
+
+// ClaimedActorCtxKey — ключ id инициатора, заявленного клиентом в X-Actor-Id.
+// Заявка ничем не подтверждена, поэтому хранится отдельно от ActorCtxKey.
+const ClaimedActorCtxKey = "gopr_claimed_actor"
+
+// ActorSystem — инициатор автоматических действий (автоназначение и т.п.).
+const ActorSystem = "system"
+
+// IsReservedActor сообщает, что id занят самим gopr и не может быть заявлен клиентом.
+func IsReservedActor(id string) bool {
+	return id == ActorSystem
+}
+
+// WithActor задаёт подтверждённого инициатора: отправителя подписанного
+// вебхука, автора slash-команды и т.п.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ActorCtxKey, actor)
+}
+
+// WithClaimedActor задаёт инициатора, которого клиент назвал сам.
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ClaimedActorCtxKey, actor)
+}
+
+// ActorFromCtx возвращает инициатора действия для истории PR и событий:
+// подтверждённого, иначе заявленного, иначе ActorSystem.
+func ActorFromCtx(ctx context.Context) string {
+	if actor := VerifiedActorFromCtx(ctx); actor != ActorSystem {
+		return actor
+	}
+	if claimed := ClaimedActorFromCtx(ctx); claimed != "" {
+		return claimed
+	}
+	return ActorSystem
+}
+
+// VerifiedActorFromCtx возвращает подтверждённого инициатора или ActorSystem,
+// если его нет; заявленный клиентом инициатор не учитывается.
+func VerifiedActorFromCtx(ctx context.Context) string {
+	actor, ok := ctx.Value(ActorCtxKey).(string)
+	if !ok || actor == "" {
+		return ActorSystem
+	}
+	return actor
+}
+
+// ClaimedActorFromCtx возвращает инициатора, заявленного клиентом, или "".
+func ClaimedActorFromCtx(ctx context.Context) string {
+	actor, _ := ctx.Value(ClaimedActorCtxKey).(string)
+	return actor
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction — изменение, записанное в журнал аудита.
+type AuditAction string
+
+const (
+	AuditUserSetActive       AuditAction = "user.set_active"
+	AuditUserUpdate          AuditAction = "user.update"
+	AuditUserMove            AuditAction = "user.move"
+	AuditTeamAdd             AuditAction = "team.add"
+	AuditTeamRename          AuditAction = "team.rename"
+	AuditTeamSetParent       AuditAction = "team.set_parent"
+	AuditTeamDelete          AuditAction = "team.delete"
+	AuditTeamAddMember       AuditAction = "team.add_member"
+	AuditTeamRemoveMember    AuditAction = "team.remove_member"
+	AuditTeamSetMemberRole   AuditAction = "team.set_member_role"
+	AuditPullRequestCreate   AuditAction = "pull_request.create"
+	AuditPullRequestMerge    AuditAction = "pull_request.merge"
+	AuditPullRequestReassign AuditAction = "pull_request.reassign"
+	AuditPullRequestUnassign AuditAction = "pull_request.unassign"
+)
+
+// AuditTargetType — вид объекта, который меняет действие.
+type AuditTargetType string
+
+const (
+	AuditTargetUser        AuditTargetType = "user"
+	AuditTargetTeam        AuditTargetType = "team"
+	AuditTargetPullRequest AuditTargetType = "pull_request"
+)
+
+// AuditRecord — запись журнала аудита. Actor — подтверждённый инициатор или
+// system, ClaimedActor — инициатор из X-Actor-Id, который никто не проверял.
+// TargetId — id пользователя, команды или PR; имя команды на момент действия — в снимках. Before и After — снимки цели
+// в JSON до и после изменения, пустые, если цели не было (создание) или не
+// стало (удаление). Записи только добавляются и удаляются по сроку хранения.
+type AuditRecord struct {
+	Id           string          `json:"id"`
+	Actor        string          `json:"actor"`
+	ClaimedActor string          `json:"claimed_actor"`
+	Action       AuditAction     `json:"action"`
+	TargetType   AuditTargetType `json:"target_type"`
+	TargetId     string          `json:"target_id"`
+	Before       json.RawMessage `json:"before"`
+	After        json.RawMessage `json:"after"`
+	RequestId    string          `json:"request_id"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	Actor        string
+	ClaimedActor string
+	Action       AuditAction
+	TargetType   AuditTargetType
+	TargetId     string
+	RequestId    string
+	From         time.Time
+	To           time.Time
+	Order        SortOrder
+	After        *Cursor
+	Limit        int
+}
+
+// AuditListQuery — журнал аудита, по умолчанию новые записи первыми.
+// From и To ограничивают время записи: [From, To).
+type AuditListQuery struct {
+	Actor        string          `form:"actor" binding:"omitempty,notblank,max=128"`
+	ClaimedActor string          `form:"claimed_actor" binding:"omitempty,notblank,max=128"`
+	Action       AuditAction     `form:"action" binding:"omitempty,notblank,max=64"`
+	TargetType   AuditTargetType `form:"target_type" binding:"omitempty,oneof=user team pull_request"`
+	TargetId     string          `form:"target_id" binding:"omitempty,notblank,max=255"`
+	RequestId    string          `form:"request_id" binding:"omitempty,notblank,max=128"`
+	From         time.Time       `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To           time.Time       `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Order        SortOrder       `form:"order" binding:"omitempty,oneof=oldest newest"`
+	Cursor       string          `form:"cursor" binding:"omitempty,max=512"`
+	Limit        int             `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type AuditPage struct {
+	Records    []*AuditRecord `json:"records"`
+	NextCursor string         `json:"next_cursor"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// SlashCommand — slash-команда из чата. Command — имя команды вида /gopr,
+// UserId — id автора у провайдера, Text — всё, что написано после имени команды.
+type SlashCommand struct {
+	Provider IdentityProvider
+	Command  string
+	UserId   string
+	Text     string
+}
+
+// SlashReply — ответ на slash-команду; InChannel — ответ виден всему каналу,
+// иначе только автору команды.
+type SlashReply struct {
+	Text      string
+	InChannel bool
+}
+
+// Away — отсутствие пользователя: до Until он неактивен, после снова
+// становится активным.
+type Away struct {
+	UserId    string    `json:"user_id"`
+	Until     time.Time `json:"until"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CodeHostRef — PR на код-хостинге: провайдер, полное имя репозитория и номер.
+type CodeHostRef struct {
+	Provider   IdentityProvider
+	Repository string
+	Number     int
+}
+
+// ParseCodeHostRef разбирает id PR, заведённого вебхуком: acme/api#42 — PR
+// GitHub, acme/web!7 — merge request GitLab.
+// ok == false — PR создан не с код-хостинга, синхронизировать нечего.
+func ParseCodeHostRef(id string) (ref CodeHostRef, ok bool) {
+	i := strings.LastIndexAny(id, "#!")
+	if i <= 0 {
+		return CodeHostRef{}, false
+	}
+
+	provider := IdentityGitHub
+	if id[i] == '!' {
+		provider = IdentityGitLab
+	}
+
+	repository := id[:i]
+	if !strings.Contains(repository, "/") {
+		return CodeHostRef{}, false
+	}
+
+	number, err := strconv.Atoi(id[i+1:])
+	if err != nil || number <= 0 {
+		return CodeHostRef{}, false
+	}
+
+	return CodeHostRef{Provider: provider, Repository: repository, Number: number}, true
+}
+
+// ReviewSyncStatus — состояние выгрузки ревьюверов PR на код-хостинг.
+type ReviewSyncStatus string
+
+const (
+	ReviewSyncPending ReviewSyncStatus = "PENDING"
+	ReviewSyncSynced  ReviewSyncStatus = "SYNCED"
+	ReviewSyncFailed  ReviewSyncStatus = "FAILED"
+)
+
+// ReviewSync — последняя попытка выгрузить ревьюверов PR на код-хостинг.
+type ReviewSync struct {
+	PullRequestId string           `json:"pull_request_id"`
+	Status        ReviewSyncStatus `json:"status"`
+	Attempts      int              `json:"attempts"`
+	LastError     string           `json:"last_error"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
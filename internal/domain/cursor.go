@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = NewError(ErrCodeBadRequest, "invalid cursor")
+
+// Cursor — позиция keyset-пагинации по паре (created_at, id).
+type Cursor struct {
+	CreatedAt time.Time
+	Id        string
+}
+
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.Id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCursor, err)
+	}
+
+	ts, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCursor, err)
+	}
+
+	return &Cursor{CreatedAt: createdAt, Id: id}, nil
+}
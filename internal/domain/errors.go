@@ -1,6 +1,79 @@
 package domain
 
-type ErrorResponse struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+import "errors"
+
+// ErrorCode — машинный код ошибки. Набор кодов совпадает с enum
+// ErrorResponse.error.code в docs/openapi_original.yml.
+type ErrorCode string
+
+const (
+	ErrCodeTeamExists      ErrorCode = "TEAM_EXISTS"
+	ErrCodePRExists        ErrorCode = "PR_EXISTS"
+	ErrCodePRMerged        ErrorCode = "PR_MERGED"
+	ErrCodeNotAssigned     ErrorCode = "NOT_ASSIGNED"
+	ErrCodeNoCandidate     ErrorCode = "NO_CANDIDATE"
+	ErrCodeNotFound        ErrorCode = "NOT_FOUND"
+	ErrCodeAlreadyExists   ErrorCode = "ALREADY_EXISTS"
+	ErrCodeOpenReviews     ErrorCode = "TEAM_HAS_OPEN_REVIEWS"
+	ErrCodeUserInTeam      ErrorCode = "USER_IN_TEAM"
+	ErrCodeNotMember       ErrorCode = "NOT_TEAM_MEMBER"
+	ErrCodeTeamCycle       ErrorCode = "TEAM_CYCLE"
+	ErrCodeUnknownIdentity ErrorCode = "UNKNOWN_IDENTITY"
+	ErrCodeBadRequest      ErrorCode = "BAD_REQUEST"
+	ErrCodeValidation      ErrorCode = "VALIDATION_ERROR"
+	ErrCodeUnauthorized    ErrorCode = "UNAUTHORIZED"
+	ErrCodeInternal        ErrorCode = "INTERNAL"
+)
+
+// ErrorCodes перечисляет все известные коды ошибок.
+var ErrorCodes = []ErrorCode{
+	ErrCodeTeamExists,
+	ErrCodePRExists,
+	ErrCodePRMerged,
+	ErrCodeNotAssigned,
+	ErrCodeNoCandidate,
+	ErrCodeNotFound,
+	ErrCodeAlreadyExists,
+	ErrCodeOpenReviews,
+	ErrCodeUserInTeam,
+	ErrCodeNotMember,
+	ErrCodeTeamCycle,
+	ErrCodeUnknownIdentity,
+	ErrCodeBadRequest,
+	ErrCodeValidation,
+	ErrCodeUnauthorized,
+	ErrCodeInternal,
+}
+
+// FieldViolation описывает нарушение правила валидации одного поля.
+type FieldViolation struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Error — доменная ошибка с машинным кодом и сообщением для клиента.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Details []FieldViolation
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// AsError достаёт доменную ошибку из цепочки err.
+func AsError(err error) (*Error, bool) {
+	var de *Error
+	if errors.As(err, &de) {
+		return de, true
+	}
+	return nil, false
 }
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// EventType — изменение PR, о котором gopr оповещает внешние системы.
+type EventType string
+
+const (
+	EventReviewersAssigned  EventType = "reviewers.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventPullRequestMerged  EventType = "pull_request.merged"
+)
+
+// EventTypes перечисляет все типы событий.
+var EventTypes = []EventType{
+	EventReviewersAssigned,
+	EventReviewerReassigned,
+	EventPullRequestMerged,
+}
+
+// Event — событие PR в том виде, в котором оно уходит подписчикам.
+type Event struct {
+	Id          string       `json:"id"`
+	Type        EventType    `json:"type"`
+	PullRequest *PullRequest `json:"pull_request"`
+	Reviewers   []string     `json:"assigned_reviewers"`
+	// OldReviewerId и NewReviewerId заполняются для reviewer.reassigned;
+	// NewReviewerId пуст, если замены в команде не нашлось.
+	OldReviewerId string    `json:"old_reviewer_id,omitempty"`
+	NewReviewerId string    `json:"new_reviewer_id,omitempty"`
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// Seq — номер публикации события (OutboxMessage.PublishedSeq); по нему
+	// поток событий возобновляется после обрыва.
+	Seq int64 `json:"-"`
+}
+
+// Involves сообщает, касается ли событие пользователя: автор, ревьюверы PR
+// или участник переназначения.
+func (e *Event) Involves(userID string) bool {
+	if e.PullRequest != nil && e.PullRequest.AuthorId == userID {
+		return true
+	}
+	return e.OldReviewerId == userID || e.NewReviewerId == userID || slices.Contains(e.Reviewers, userID)
+}
+
+// EventFilter отбирает события для потока; пустые поля не ограничивают.
+type EventFilter struct {
+	TeamName string
+	UserId   string
+}
+
+func (f *EventFilter) Match(e *Event) bool {
+	if f.TeamName != "" && (e.PullRequest == nil || e.PullRequest.TeamName != f.TeamName) {
+		return false
+	}
+	return f.UserId == "" || e.Involves(f.UserId)
+}
+
+// EventStreamQuery — параметры потока событий. LastEventId — Seq последнего
+// полученного события: поток начнётся с пропущенных после него.
+type EventStreamQuery struct {
+	TeamName    string `form:"team_name" binding:"omitempty,max=255"`
+	UserId      string `form:"user_id" binding:"omitempty,max=255"`
+	LastEventId *int64 `form:"last_event_id" binding:"omitempty,min=0"`
+}
+
+// OutboxMessage — событие в outbox: оно записано в одной транзакции с
+// изменением PR и ждёт публикации. Id растёт в порядке записи и задаёт порядок
+// публикации; PublishedAt пуст, пока событие не принято всеми получателями.
+// AcceptedBy — имена получателей, уже принявших событие. PublishedSeq растёт в
+// порядке публикации, который может отличаться от порядка записи, если событие
+// одного PR задержалось. DeadAt задан, если попытки кончились и событие снято с
+// публикации.
+type OutboxMessage struct {
+	Id            int64
+	PullRequestId string
+	EventType     EventType
+	Payload       json.RawMessage
+	Attempts      int
+	LastError     string
+	AcceptedBy    []string
+	CreatedAt     time.Time
+	PublishedAt   *time.Time
+	PublishedSeq  int64
+	DeadAt        *time.Time
+}
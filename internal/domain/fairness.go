@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+type FairnessFlag string
+
+const (
+	FairnessOverloaded  FairnessFlag = "overloaded"
+	FairnessUnderloaded FairnessFlag = "underloaded"
+)
+
+type FairnessQuery struct {
+	TeamName string    `form:"team_name" binding:"required,notblank,max=255"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// MemberLoad — назначения активного участника за окно. Rate — назначений
+// в день с учётом того, сколько дней окна участник состоял в команде.
+type MemberLoad struct {
+	UserId      string       `json:"user_id"`
+	Username    string       `json:"username"`
+	Assignments int          `json:"assignments"`
+	DaysActive  float64      `json:"days_active"`
+	Rate        float64      `json:"rate"`
+	Flag        FairnessFlag `json:"flag"`
+}
+
+// FairnessMetrics считаются по Rate участников. MaxMinRatio равен +Inf, если
+// кто-то из участников не получил ни одного назначения.
+type FairnessMetrics struct {
+	Mean        float64 `json:"mean"`
+	Gini        float64 `json:"gini"`
+	StdDev      float64 `json:"stddev"`
+	MaxMinRatio float64 `json:"max_min_ratio"`
+}
+
+// FairnessResult — распределение назначений: фактическое или полученное
+// прогоном PR окна через стратегию.
+type FairnessResult struct {
+	Strategy string          `json:"strategy"`
+	Metrics  FairnessMetrics `json:"metrics"`
+	Members  []*MemberLoad   `json:"members"`
+}
+
+type FairnessReport struct {
+	Team         *Team             `json:"team"`
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"`
+	PullRequests int               `json:"pull_requests"`
+	Actual       *FairnessResult   `json:"actual"`
+	Strategies   []*FairnessResult `json:"strategies"`
+}
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+type PullRequestEventType string
+
+const (
+	PullRequestEventCreated          PullRequestEventType = "CREATED"
+	PullRequestEventReviewerAssigned PullRequestEventType = "REVIEWER_ASSIGNED"
+	PullRequestEventReviewerRemoved  PullRequestEventType = "REVIEWER_REMOVED"
+	PullRequestEventMerged           PullRequestEventType = "MERGED"
+)
+
+const (
+	ReasonAutoAssign  = "auto_assign"
+	ReasonReassign    = "reassign"
+	ReasonNoCandidate = "no_candidate"
+	ReasonTeamDeleted = "team_deleted"
+	ReasonUserMoved   = "user_moved"
+	ReasonClosed      = "closed"
+)
+
+// PullRequestEvent — запись истории PR: создание, назначение или снятие
+// ревьювера, merge.
+type PullRequestEvent struct {
+	Id            int64                `json:"id"`
+	PullRequestId string               `json:"pull_request_id"`
+	Type          PullRequestEventType `json:"type"`
+	ReviewerId    string               `json:"reviewer_id"`
+	Actor         string               `json:"actor"`
+	Reason        string               `json:"reason"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// IdentityProvider — внешняя система, в которой у пользователя есть учётная запись.
+type IdentityProvider string
+
+const (
+	IdentityGitHub IdentityProvider = "github"
+	IdentityGitLab IdentityProvider = "gitlab"
+	IdentityEmail  IdentityProvider = "email"
+	// IdentitySlack — учётная запись Slack, логин — id участника вида U024BE7LH.
+	IdentitySlack IdentityProvider = "slack"
+)
+
+// Identity — учётная запись пользователя gopr у провайдера. Login — логин или
+// адрес почты, ExternalId — числовой id у провайдера, 0 — неизвестен.
+// У пользователя может быть сколько угодно учётных записей, в том числе
+// несколько у одного провайдера.
+type Identity struct {
+	UserId     string           `json:"user_id"`
+	Provider   IdentityProvider `json:"provider"`
+	Login      string           `json:"login"`
+	ExternalId int64            `json:"external_id"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// NormalizeLogin приводит логин к виду, в котором он хранится: логины GitHub,
+// GitLab, адреса почты и id Slack сравниваются без учёта регистра.
+func NormalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+type IdentityAddInput struct {
+	UserID     string           `json:"user_id" binding:"required,notblank,max=128"`
+	Provider   IdentityProvider `json:"provider" binding:"required,oneof=github gitlab email slack"`
+	Login      string           `json:"login" binding:"required,notblank,max=255"`
+	ExternalID int64            `json:"external_id" binding:"omitempty,min=1"`
+}
+
+// IdentityUpdateInput меняет числовой id учётной записи; 0 — сбросить.
+type IdentityUpdateInput struct {
+	Provider   IdentityProvider `json:"provider" binding:"required,oneof=github gitlab email slack"`
+	Login      string           `json:"login" binding:"required,notblank,max=255"`
+	ExternalID int64            `json:"external_id" binding:"omitempty,min=1"`
+}
+
+type IdentityDeleteInput struct {
+	Provider IdentityProvider `json:"provider" binding:"required,oneof=github gitlab email slack"`
+	Login    string           `json:"login" binding:"required,notblank,max=255"`
+}
+
+type IdentityListQuery struct {
+	UserId string `form:"user_id" binding:"required,notblank,max=128"`
+}
+
+// IdentityLookupQuery ищет пользователя по логину или по числовому id у провайдера.
+type IdentityLookupQuery struct {
+	Provider   IdentityProvider `form:"provider" binding:"required,oneof=github gitlab email slack"`
+	Login      string           `form:"login" binding:"required_without=ExternalID,omitempty,notblank,max=255"`
+	ExternalID int64            `form:"external_id" binding:"omitempty,min=1"`
+}
+
+// UnknownIdentityError — учётной записи провайдера не сопоставлен пользователь gopr
+// или, при поиске в обратную сторону, у пользователя нет учётной записи провайдера.
+type UnknownIdentityError struct {
+	Provider   IdentityProvider
+	Login      string
+	ExternalId int64
+	UserId     string
+}
+
+func (e *UnknownIdentityError) Error() string {
+	switch {
+	case e.UserId != "":
+		return fmt.Sprintf("user %s has no %s identity", e.UserId, e.Provider)
+	case e.ExternalId != 0:
+		return fmt.Sprintf("unknown %s identity with id %d", e.Provider, e.ExternalId)
+	default:
+		return fmt.Sprintf("unknown %s identity %q", e.Provider, e.Login)
+	}
+}
+
+// Unwrap отдаёт доменную ошибку, по которой REST отвечает кодом UNKNOWN_IDENTITY.
+func (e *UnknownIdentityError) Unwrap() error {
+	return NewError(ErrCodeUnknownIdentity, e.Error())
+}
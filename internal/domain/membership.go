@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type MembershipRole string
+
+const (
+	RoleMember MembershipRole = "member"
+	RoleLead   MembershipRole = "lead"
+)
+
+// Membership — членство пользователя в команде. Пользователь может состоять в
+// нескольких командах; User.TeamId при этом указывает основную из них.
+type Membership struct {
+	TeamId    string         `json:"team_id"`
+	UserId    string         `json:"user_id"`
+	Role      MembershipRole `json:"role"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type TeamMember struct {
+	User     *User          `json:"user"`
+	Role     MembershipRole `json:"role"`
+	JoinedAt time.Time      `json:"joined_at"`
+}
+
+type UserTeam struct {
+	Team *Team          `json:"team"`
+	Role MembershipRole `json:"role"`
+}
+
+type TeamMemberRoleInput struct {
+	TeamName string         `json:"team_name" binding:"required,notblank,max=255"`
+	UserID   string         `json:"user_id" binding:"required,notblank,max=128"`
+	Role     MembershipRole `json:"role" binding:"required,oneof=member lead"`
+}
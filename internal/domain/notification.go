@@ -0,0 +1,144 @@
+package domain
+
+import "time"
+
+// NotificationChannel — канал, по которому пользователь получает оповещения.
+type NotificationChannel string
+
+const (
+	NotificationSlack NotificationChannel = "slack"
+	NotificationEmail NotificationChannel = "email"
+	NotificationHTTP  NotificationChannel = "http"
+)
+
+// NotificationKind — повод оповещения; у каждого свой шаблон.
+type NotificationKind string
+
+const (
+	NotificationAssigned   NotificationKind = "assigned"
+	NotificationReassigned NotificationKind = "reassigned"
+	NotificationSLAWarning NotificationKind = "sla_warning"
+	NotificationMerged     NotificationKind = "merged"
+	NotificationDigest     NotificationKind = "digest"
+)
+
+// NotificationPreference — канал оповещений пользователя. Address — куда
+// слать: адрес почты, URL входящего вебхука Slack или URL для HTTP. Пустой
+// Address — адрес по умолчанию: почта из учётной записи email, вебхук Slack и
+// URL HTTP из настроек сервера.
+type NotificationPreference struct {
+	UserId    string              `json:"user_id"`
+	Channel   NotificationChannel `json:"channel"`
+	Address   string              `json:"address"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+// Notification — отрисованное оповещение одному пользователю по одному каналу.
+type Notification struct {
+	Kind          NotificationKind    `json:"kind"`
+	Channel       NotificationChannel `json:"channel"`
+	UserId        string              `json:"user_id"`
+	Username      string              `json:"username"`
+	Address       string              `json:"-"`
+	PullRequestId string              `json:"pull_request_id,omitempty"`
+	Subject       string              `json:"subject"`
+	Text          string              `json:"text"`
+}
+
+type NotificationPreferenceSetInput struct {
+	UserID  string              `json:"user_id" binding:"required,notblank,max=128"`
+	Channel NotificationChannel `json:"channel" binding:"required,oneof=slack email http"`
+	Address string              `json:"address" binding:"omitempty,max=2048"`
+}
+
+type NotificationPreferenceDeleteInput struct {
+	UserID  string              `json:"user_id" binding:"required,notblank,max=128"`
+	Channel NotificationChannel `json:"channel" binding:"required,oneof=slack email http"`
+}
+
+type NotificationPreferenceListQuery struct {
+	UserId string `form:"user_id" binding:"required,notblank,max=128"`
+}
+
+// DigestSettings — подписка пользователя на ежедневную сводку ожидающих ревью.
+// Сводка уходит раз в местные сутки, начиная с Hour по часовому поясу Timezone,
+// и не уходит в тихие часы [QuietStart, QuietEnd), которые могут переходить
+// через полночь.
+type DigestSettings struct {
+	UserId     string     `json:"user_id"`
+	Enabled    bool       `json:"enabled"`
+	Timezone   string     `json:"timezone"`
+	Hour       int        `json:"hour"`
+	QuietStart *int       `json:"quiet_hours_start"`
+	QuietEnd   *int       `json:"quiet_hours_end"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// Quiet сообщает, попадает ли местный час в тихие часы.
+func (s *DigestSettings) Quiet(hour int) bool {
+	if s.QuietStart == nil || s.QuietEnd == nil {
+		return false
+	}
+	start, end := *s.QuietStart, *s.QuietEnd
+	if start <= end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// Due сообщает, пора ли отправить сводку в момент now по местному времени loc:
+// наступил час отправки, сейчас не тихие часы и сегодня сводки ещё не было.
+func (s *DigestSettings) Due(now time.Time, loc *time.Location) bool {
+	local := now.In(loc)
+	if !s.Enabled || local.Hour() < s.Hour || s.Quiet(local.Hour()) {
+		return false
+	}
+	if s.LastSentAt == nil {
+		return true
+	}
+
+	y, m, d := local.Date()
+	ly, lm, ld := s.LastSentAt.In(loc).Date()
+	return y != ly || m != lm || d != ld
+}
+
+// Digest — сводка открытых PR на ревью у пользователя, старые первыми.
+type Digest struct {
+	UserId      string        `json:"user_id"`
+	Username    string        `json:"username"`
+	Timezone    string        `json:"timezone"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Items       []*DigestItem `json:"items"`
+	// Overdue — сколько PR ждут ревью дольше SLA.
+	Overdue int    `json:"overdue"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+}
+
+type DigestItem struct {
+	PullRequestId string    `json:"pull_request_id"`
+	Name          string    `json:"name"`
+	AuthorId      string    `json:"author_id"`
+	Author        string    `json:"author"`
+	TeamName      string    `json:"team_name"`
+	CreatedAt     time.Time `json:"created_at"`
+	AgeSeconds    int64     `json:"age_seconds"`
+	Overdue       bool      `json:"overdue"`
+}
+
+// DigestSettingsInput заменяет настройки сводки целиком; без timezone — UTC,
+// без hour — 9 утра. Тихие часы задаются обе границы или ни одной.
+type DigestSettingsInput struct {
+	UserID     string `json:"user_id" binding:"required,notblank,max=128"`
+	Enabled    bool   `json:"enabled"`
+	Timezone   string `json:"timezone" binding:"omitempty,max=64"`
+	Hour       *int   `json:"hour" binding:"omitempty,min=0,max=23"`
+	QuietStart *int   `json:"quiet_hours_start" binding:"omitempty,min=0,max=23"`
+	QuietEnd   *int   `json:"quiet_hours_end" binding:"omitempty,min=0,max=23"`
+}
+
+type DigestQuery struct {
+	UserId string `form:"user_id" binding:"required,notblank,max=128"`
+}
@@ -14,6 +14,10 @@ type PullRequest struct {
 	AuthorId string `json:"author_id"`
 	Name     string `json:"name"`
 
+	// TeamId — команда, из которой выбираются ревьюверы; TeamName заполняется при чтении.
+	TeamId   string `json:"team_id"`
+	TeamName string `json:"team_name"`
+
 	Status string `json:"status"`
 
 	CreatedAt time.Time  `json:"created_at"`
@@ -22,18 +26,91 @@ type PullRequest struct {
 }
 
 type CreatePullRequest struct {
-	Id       string `json:"pull_request_id"`
-	AuthorId string `json:"author_id"`
-	Name     string `json:"pull_request_name"`
+	Id       string `json:"pull_request_id" binding:"omitempty,notblank,max=128"`
+	AuthorId string `json:"author_id" binding:"required,notblank,max=128"`
+	Name     string `json:"pull_request_name" binding:"required,notblank,max=255"`
+	// TeamName — команда ревью; по умолчанию основная команда автора.
+	TeamName string `json:"team_name" binding:"omitempty,notblank,max=255"`
 }
 
 type ReassignPullRequest struct {
-	Id            string `json:"pull_request_id"`
-	OldReviewerId string `json:"old_reviewer_id"`
+	Id            string `json:"pull_request_id" binding:"required,notblank,max=128"`
+	OldReviewerId string `json:"old_reviewer_id" binding:"required,notblank,max=128"`
+}
+
+type GetPullRequestQuery struct {
+	Id string `form:"pull_request_id" binding:"required,notblank,max=128"`
 }
 
 type MergePullRequest struct {
-	Id string `json:"pull_request_id"`
+	Id string `json:"pull_request_id" binding:"required,notblank,max=128"`
+}
+
+type SortOrder string
+
+const (
+	SortOldest SortOrder = "oldest"
+	SortNewest SortOrder = "newest"
+)
+
+type UserReviewsQuery struct {
+	UserId      string            `form:"user_id" binding:"required,notblank,max=128"`
+	Status      PullRequestStatus `form:"status" binding:"omitempty,oneof=OPEN MERGED"`
+	TeamName    string            `form:"team_name" binding:"omitempty,notblank,max=255"`
+	CreatedFrom time.Time         `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   time.Time         `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Order       SortOrder         `form:"order" binding:"omitempty,oneof=oldest newest"`
+	Cursor      string            `form:"cursor" binding:"omitempty,max=512"`
+	Limit       int               `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type PullRequestListQuery struct {
+	Status             PullRequestStatus `form:"status" binding:"omitempty,oneof=OPEN MERGED"`
+	AuthorId           string            `form:"author_id" binding:"omitempty,notblank,max=128"`
+	TeamName           string            `form:"team_name" binding:"omitempty,notblank,max=255"`
+	ReviewerId         string            `form:"reviewer_id" binding:"omitempty,notblank,max=128"`
+	Name               string            `form:"name" binding:"omitempty,notblank,max=255"`
+	CreatedFrom        time.Time         `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo          time.Time         `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedFrom         time.Time         `form:"merged_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedTo           time.Time         `form:"merged_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	FewerReviewersThan int               `form:"reviewers_lt" binding:"omitempty,min=1,max=10"`
+	Order              SortOrder         `form:"order" binding:"omitempty,oneof=oldest newest"`
+	Cursor             string            `form:"cursor" binding:"omitempty,max=512"`
+	Limit              int               `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type PullRequestFilter struct {
+	Status             PullRequestStatus
+	AuthorId           string
+	TeamName           string
+	ReviewerId         string
+	NameContains       string
+	CreatedFrom        time.Time
+	CreatedTo          time.Time
+	MergedFrom         time.Time
+	MergedTo           time.Time
+	FewerReviewersThan int
+	Order              SortOrder
+	After              *Cursor
+	Limit              int
+}
+
+type ReviewerPRFilter struct {
+	ReviewerId  string
+	Status      PullRequestStatus
+	TeamId      string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Order       SortOrder
+	After       *Cursor
+	Limit       int
+}
+
+// ReviewAssignment — назначение ревьювера на PR.
+type ReviewAssignment struct {
+	PullRequestId string `json:"pull_request_id"`
+	ReviewerId    string `json:"reviewer_id"`
 }
 
 type PullRequestReassignResponse struct {
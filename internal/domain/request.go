@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// RequestIDCtxKey — ключ, под которым в контексте лежит id запроса.
+// Строковый, чтобы значение было видно и через gin.Context.
+const RequestIDCtxKey = "gopr_request_id"
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDCtxKey, id)
+}
+
+// RequestIDFromCtx возвращает id запроса; пусто — действие не из запроса.
+func RequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDCtxKey).(string)
+	return id
+}
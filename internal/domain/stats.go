@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+var ErrInvalidRange = NewError(ErrCodeBadRequest, "invalid date range: from must be before to")
+
+// StatsQuery ограничивает статистику PR, созданными в [From, To). По умолчанию
+// берутся последние 30 дней.
+type StatsQuery struct {
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	TeamName string    `form:"team_name" binding:"omitempty,notblank,max=255"`
+}
+
+type StatsFilter struct {
+	From   time.Time
+	To     time.Time
+	TeamId string
+}
+
+// UserStats — нагрузка ревьювера. Assigned считает все назначения, включая
+// снятые; Open и Merged — текущие назначения по статусу PR; ReassignedAway —
+// сколько раз ревьювера сняли с PR.
+type UserStats struct {
+	UserId         string `json:"user_id"`
+	Username       string `json:"username"`
+	Assigned       int    `json:"assigned"`
+	Open           int    `json:"open"`
+	Merged         int    `json:"merged"`
+	ReassignedAway int    `json:"reassigned_away"`
+}
+
+type TeamStats struct {
+	Team          *Team `json:"team"`
+	PullRequests  int   `json:"pull_requests"`
+	Open          int   `json:"open"`
+	Merged        int   `json:"merged"`
+	Assignments   int   `json:"assignments"`
+	Reassignments int   `json:"reassignments"`
+}
+
+type MergeTimeStats struct {
+	Merged int           `json:"merged"`
+	Avg    time.Duration `json:"avg"`
+	P50    time.Duration `json:"p50"`
+	P90    time.Duration `json:"p90"`
+	P95    time.Duration `json:"p95"`
+	P99    time.Duration `json:"p99"`
+}
+
+type UserStatsReport struct {
+	From  time.Time    `json:"from"`
+	To    time.Time    `json:"to"`
+	Users []*UserStats `json:"users"`
+}
+
+type TeamStatsReport struct {
+	From  time.Time    `json:"from"`
+	To    time.Time    `json:"to"`
+	Teams []*TeamStats `json:"teams"`
+}
+
+type MergeTimeReport struct {
+	From  time.Time       `json:"from"`
+	To    time.Time       `json:"to"`
+	Stats *MergeTimeStats `json:"stats"`
+}
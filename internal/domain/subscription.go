@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// Subscription — подписка внешней системы на события PR. Events пуст — все события.
+// Secret подписывает тело доставки (HMAC-SHA256) и наружу не отдаётся.
+type Subscription struct {
+	Id        string      `json:"id"`
+	URL       string      `json:"url"`
+	Events    []EventType `json:"events"`
+	Secret    string      `json:"-"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// Accepts — нужно ли доставлять подписке событие типа t.
+func (s *Subscription) Accepts(t EventType) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, t)
+}
+
+// DeliveryStatus — состояние доставки события подписчику.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "PENDING"
+	DeliveryDelivered DeliveryStatus = "DELIVERED"
+	// DeliveryDead — попытки исчерпаны, доставка больше не повторяется сама.
+	DeliveryDead DeliveryStatus = "DEAD"
+)
+
+// Delivery — доставка одного события одной подписке. Повторная доставка
+// создаёт новую запись с тем же EventId и телом.
+type Delivery struct {
+	Id             string          `json:"id"`
+	SubscriptionId string          `json:"subscription_id"`
+	EventId        string          `json:"event_id"`
+	EventType      EventType       `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	// ResponseCode — HTTP-код последней попытки, 0 — ответа не было.
+	ResponseCode int    `json:"response_code"`
+	LastError    string `json:"last_error"`
+	// NextAttemptAt — когда доставку PENDING попробовать снова; у завершённых — nil.
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type DeliveryFilter struct {
+	SubscriptionId string
+	Status         DeliveryStatus
+	Order          SortOrder
+	After          *Cursor
+	Limit          int
+}
+
+type DeliveryPage struct {
+	Deliveries []*Delivery `json:"deliveries"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+type SubscriptionAddInput struct {
+	URL    string      `json:"url" binding:"required,http_url,max=2048"`
+	Events []EventType `json:"events" binding:"omitempty,unique,dive,oneof=reviewers.assigned reviewer.reassigned pull_request.merged"`
+	Secret string      `json:"secret" binding:"required,notblank,min=16,max=255"`
+}
+
+type SubscriptionDeleteInput struct {
+	Id string `json:"subscription_id" binding:"required,notblank,max=128"`
+}
+
+// DeliveryListQuery — журнал доставок подписки, по умолчанию новые первыми.
+type DeliveryListQuery struct {
+	SubscriptionId string         `form:"subscription_id" binding:"required,notblank,max=128"`
+	Status         DeliveryStatus `form:"status" binding:"omitempty,oneof=PENDING DELIVERED DEAD"`
+	Order          SortOrder      `form:"order" binding:"omitempty,oneof=oldest newest"`
+	Cursor         string         `form:"cursor" binding:"omitempty,max=512"`
+	Limit          int            `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type RedeliverInput struct {
+	DeliveryId string `json:"delivery_id" binding:"required,notblank,max=128"`
+}
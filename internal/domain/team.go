@@ -3,15 +3,67 @@ package domain
 type Team struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
+	// ParentId — родительская команда в дереве отдел → команда → подкоманда.
+	ParentId string `json:"parent_id"`
 }
 
 type TeamAddInput struct {
-	TeamName string               `json:"team_name"`
-	Members  []TeamAddMemberInput `json:"members"`
+	TeamName   string               `json:"team_name" binding:"required,notblank,max=255"`
+	ParentName string               `json:"parent_name" binding:"omitempty,notblank,max=255,nefield=TeamName"`
+	Members    []TeamAddMemberInput `json:"members" binding:"unique=UserID,dive"`
 }
 
 type TeamAddMemberInput struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID   string         `json:"user_id" binding:"required,notblank,max=128"`
+	Username string         `json:"username" binding:"required,notblank,max=255"`
+	IsActive bool           `json:"is_active"`
+	Role     MembershipRole `json:"role" binding:"omitempty,oneof=member lead"`
+}
+
+type TeamSummary struct {
+	Team         *Team  `json:"team"`
+	ParentName   string `json:"parent_name"`
+	MembersCount int    `json:"members_count"`
+	ActiveCount  int    `json:"active_count"`
+}
+
+type GetTeamQuery struct {
+	TeamName string `form:"team_name" binding:"required,notblank,max=255"`
+	// Subtree добавляет в ответ подкоманды и участников всего поддерева.
+	Subtree bool `form:"subtree"`
+}
+
+type TeamSetParentInput struct {
+	TeamName string `json:"team_name" binding:"required,notblank,max=255"`
+	// ParentName пуст — команда становится корневой.
+	ParentName string `json:"parent_name" binding:"omitempty,notblank,max=255,nefield=TeamName"`
+}
+
+type TeamRenameInput struct {
+	TeamName    string `json:"team_name" binding:"required,notblank,max=255"`
+	NewTeamName string `json:"new_team_name" binding:"required,notblank,max=255,nefield=TeamName"`
+}
+
+type TeamDeleteInput struct {
+	TeamName string `json:"team_name" binding:"required,notblank,max=255"`
+	// Force снимает членов команды с открытых ревью вместо отказа в удалении.
+	Force bool `json:"force"`
+}
+
+type TeamMemberInput struct {
+	TeamName string         `json:"team_name" binding:"required,notblank,max=255"`
+	UserID   string         `json:"user_id" binding:"required,notblank,max=128"`
+	Username string         `json:"username" binding:"required,notblank,max=255"`
+	IsActive bool           `json:"is_active"`
+	Role     MembershipRole `json:"role" binding:"omitempty,oneof=member lead"`
+}
+
+type TeamRemoveMemberInput struct {
+	TeamName string `json:"team_name" binding:"required,notblank,max=255"`
+	UserID   string `json:"user_id" binding:"required,notblank,max=128"`
+}
+
+type TeamDeleteResult struct {
+	Team           *Team               `json:"team"`
+	RemovedReviews []*ReviewAssignment `json:"removed_reviews"`
 }
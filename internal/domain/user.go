@@ -7,6 +7,61 @@ type User struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Username  string    `json:"username"`
-	TeamId    string    `json:"team_id"`
-	IsActive  bool      `json:"is_active"`
+	// TeamId — основная команда, в ней по умолчанию ревьюятся PR пользователя.
+	TeamId   string `json:"team_id"`
+	IsActive bool   `json:"is_active"`
+}
+
+// UserDetails — пользователь с названием команды и текущей нагрузкой.
+type UserDetails struct {
+	User        *User       `json:"user"`
+	TeamName    string      `json:"team_name"`
+	Teams       []*UserTeam `json:"teams"`
+	ReviewLoad  int         `json:"review_load"`
+	IsAvailable bool        `json:"is_available"`
+}
+
+type GetUserQuery struct {
+	UserId string `form:"user_id" binding:"required,notblank,max=128"`
+}
+
+type UserListQuery struct {
+	UsernamePrefix string `form:"username_prefix" binding:"omitempty,notblank,max=255"`
+	TeamName       string `form:"team_name" binding:"omitempty,notblank,max=255"`
+	Cursor         string `form:"cursor" binding:"omitempty,max=512"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type UserFilter struct {
+	UsernamePrefix string
+	TeamId         string
+	After          *Cursor
+	Limit          int
+}
+
+type UserUpdateInput struct {
+	UserID   string `json:"user_id" binding:"required,notblank,max=128"`
+	Username string `json:"username" binding:"required,notblank,max=255"`
+}
+
+type UserMoveInput struct {
+	UserID   string `json:"user_id" binding:"required,notblank,max=128"`
+	TeamName string `json:"team_name" binding:"required,notblank,max=255"`
+}
+
+// Reassignment — замена ревьювера на PR; NewReviewerId пуст, если замены не нашлось.
+type Reassignment struct {
+	PullRequestId string `json:"pull_request_id"`
+	OldReviewerId string `json:"old_reviewer_id"`
+	NewReviewerId string `json:"new_reviewer_id"`
+}
+
+type UserMoveResult struct {
+	User       *UserDetails    `json:"user"`
+	Reassigned []*Reassignment `json:"reassigned"`
+}
+
+type UserPage struct {
+	Users      []*UserDetails `json:"users"`
+	NextCursor string         `json:"next_cursor"`
 }
@@ -1,8 +1,13 @@
 package domain
 
 type TeamWithMembers struct {
-	Team    *Team   `json:"team"`
-	Members []*User `json:"members"`
+	Team    *Team         `json:"team"`
+	Parent  *Team         `json:"parent"`
+	Members []*TeamMember `json:"members"`
+
+	// Subteams и AggregateMembers заполняются только при запросе поддерева.
+	Subteams         []*TeamWithMembers `json:"subteams"`
+	AggregateMembers []*TeamMember      `json:"aggregate_members"`
 }
 
 type PullRequestWithReviewers struct {
@@ -10,7 +15,21 @@ type PullRequestWithReviewers struct {
 	Reviewers []string     `json:"assigned_reviewers"`
 }
 
+type PullRequestDetails struct {
+	PR        *PullRequest        `json:"pr"`
+	Reviewers []string            `json:"assigned_reviewers"`
+	Timeline  []*PullRequestEvent `json:"timeline"`
+	// ReviewSync — nil, если ревьюверы PR на код-хостинг не выгружались.
+	ReviewSync *ReviewSync `json:"review_sync"`
+}
+
 type UserReviews struct {
-	UserID string         `json:"user_id"`
-	PRs    []*PullRequest `json:"pull_requests"`
+	UserID     string                      `json:"user_id"`
+	PRs        []*PullRequestWithReviewers `json:"pull_requests"`
+	NextCursor string                      `json:"next_cursor"`
+}
+
+type PullRequestPage struct {
+	PRs        []*PullRequestWithReviewers `json:"pull_requests"`
+	NextCursor string                      `json:"next_cursor"`
 }
@@ -0,0 +1,61 @@
+package domain
+
+import "fmt"
+
+// HookProvider — код-хостинг, приславший вебхук.
+type HookProvider string
+
+const (
+	HookProviderGitHub HookProvider = "github"
+	HookProviderGitLab HookProvider = "gitlab"
+)
+
+// HookAction — событие PR на код-хостинге, на которое реагирует gopr.
+type HookAction string
+
+const (
+	HookActionOpened   HookAction = "opened"
+	HookActionReady    HookAction = "ready_for_review"
+	HookActionReopened HookAction = "reopened"
+	HookActionMerged   HookAction = "merged"
+	// HookActionClosed — PR закрыт без merge.
+	HookActionClosed HookAction = "closed"
+)
+
+// PullRequestHook — событие PR, приведённое к общему для провайдеров виду.
+type PullRequestHook struct {
+	Provider    HookProvider
+	Action      HookAction
+	Repository  string
+	Number      int
+	Title       string
+	AuthorLogin string
+	SenderLogin string
+	Draft       bool
+}
+
+// PullRequestId — id PR в gopr: полное имя репозитория и номер в нотации
+// провайдера, например acme/api#42 для GitHub и acme/web!7 для GitLab.
+func (h *PullRequestHook) PullRequestId() string {
+	if h.Provider == HookProviderGitLab {
+		return fmt.Sprintf("%s!%d", h.Repository, h.Number)
+	}
+	return fmt.Sprintf("%s#%d", h.Repository, h.Number)
+}
+
+// HookOutcome — что gopr сделал в ответ на вебхук.
+type HookOutcome string
+
+const (
+	HookOutcomeCreated HookOutcome = "created"
+	HookOutcomeMerged  HookOutcome = "merged"
+	// HookOutcomeClosed — с PR, закрытого без merge, сняты ревьюверы.
+	HookOutcomeClosed  HookOutcome = "closed"
+	HookOutcomeIgnored HookOutcome = "ignored"
+)
+
+type HookResult struct {
+	Outcome       HookOutcome
+	PullRequestId string
+	Reason        string
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "encoding/json"
+
+// AuditRecord — запись журнала аудита; before и after — снимки цели до и после
+// изменения, null при создании и удалении.
+type AuditRecord struct {
+	AuditID      string          `json:"audit_id"`
+	Actor        string          `json:"actor"`
+	ClaimedActor string          `json:"claimed_actor,omitempty"`
+	Action       string          `json:"action"`
+	TargetType   string          `json:"target_type" enums:"user,team,pull_request"`
+	TargetID     string          `json:"target_id"`
+	Before       json.RawMessage `json:"before" swaggertype:"object"`
+	After        json.RawMessage `json:"after" swaggertype:"object"`
+	RequestID    string          `json:"request_id,omitempty"`
+	CreatedAt    string          `json:"created_at"`
+}
+
+type AuditList struct {
+	Records    []AuditRecord `json:"records"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// SlashReply — ответ на slash-команду в формате Slack; ephemeral виден только
+// автору команды, in_channel — всему каналу.
+type SlashReply struct {
+	ResponseType string `json:"response_type" enums:"ephemeral,in_channel"`
+	Text         string `json:"text"`
+}
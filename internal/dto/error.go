@@ -1,10 +1,26 @@
 package dto
 
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
 type ErrorObject struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
 	Error ErrorObject `json:"error"`
 }
+
+// Problem — тело ответа об ошибке в формате RFC 7807 (application/problem+json).
+type Problem struct {
+	Type    string       `json:"type"`
+	Title   string       `json:"title"`
+	Status  int          `json:"status"`
+	Detail  string       `json:"detail"`
+	Code    string       `json:"code"`
+	Details []FieldError `json:"details,omitempty"`
+}
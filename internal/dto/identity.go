@@ -0,0 +1,14 @@
+package dto
+
+type Identity struct {
+	UserID     string `json:"user_id"`
+	Provider   string `json:"provider" enums:"github,gitlab,email,slack"`
+	Login      string `json:"login"`
+	ExternalID int64  `json:"external_id,omitempty"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
+type IdentityList struct {
+	UserID     string     `json:"user_id"`
+	Identities []Identity `json:"identities"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+type NotificationPreference struct {
+	Channel   string `json:"channel" enums:"slack,email,http"`
+	Address   string `json:"address"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type NotificationPreferenceList struct {
+	UserID      string                   `json:"user_id"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type DigestSettings struct {
+	UserID     string `json:"user_id"`
+	Enabled    bool   `json:"enabled"`
+	Timezone   string `json:"timezone"`
+	Hour       int    `json:"hour"`
+	QuietStart *int   `json:"quiet_hours_start"`
+	QuietEnd   *int   `json:"quiet_hours_end"`
+	LastSentAt string `json:"last_sent_at,omitempty"`
+}
+
+type DigestItem struct {
+	PullRequestID string `json:"pull_request_id"`
+	Name          string `json:"pull_request_name"`
+	AuthorID      string `json:"author_id"`
+	Author        string `json:"author"`
+	TeamName      string `json:"team_name"`
+	CreatedAt     string `json:"created_at"`
+	AgeSeconds    int64  `json:"age_seconds"`
+	Overdue       bool   `json:"overdue"`
+}
+
+type Digest struct {
+	UserID      string       `json:"user_id"`
+	Username    string       `json:"username"`
+	Timezone    string       `json:"timezone"`
+	GeneratedAt string       `json:"generated_at"`
+	Items       []DigestItem `json:"items"`
+	Overdue     int          `json:"overdue"`
+	Subject     string       `json:"subject"`
+	Text        string       `json:"text"`
+}
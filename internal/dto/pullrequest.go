@@ -4,6 +4,7 @@ type PullRequest struct {
 	PullRequestID     string   `json:"pull_request_id"`
 	PullRequestName   string   `json:"pull_request_name"`
 	AuthorID          string   `json:"author_id"`
+	TeamName          string   `json:"team_name,omitempty"`
 	Status            string   `json:"status"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
 
@@ -12,13 +13,42 @@ type PullRequest struct {
 }
 
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+
+	CreatedAt *string `json:"createdAt,omitempty"`
 }
 
 type PullRequestReassignResponse struct {
 	PR         PullRequest `json:"pr"`
 	ReplacedBy string      `json:"replaced_by"`
 }
+
+type PullRequestList struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+type TimelineEvent struct {
+	Type       string `json:"type"`
+	ReviewerID string `json:"reviewer_id,omitempty"`
+	Actor      string `json:"actor"`
+	Reason     string `json:"reason,omitempty"`
+	At         string `json:"at"`
+}
+
+type ReviewSync struct {
+	Status    string `json:"status" enums:"PENDING,SYNCED,FAILED"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+type PullRequestDetails struct {
+	PR         PullRequest     `json:"pr"`
+	Timeline   []TimelineEvent `json:"timeline"`
+	ReviewSync *ReviewSync     `json:"review_sync,omitempty"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+type UserStats struct {
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	Assigned       int    `json:"assigned"`
+	Open           int    `json:"open"`
+	Merged         int    `json:"merged"`
+	ReassignedAway int    `json:"reassigned_away"`
+}
+
+type UserStatsResponse struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Users []UserStats `json:"users"`
+}
+
+type TeamStats struct {
+	TeamName      string `json:"team_name"`
+	PullRequests  int    `json:"pull_requests"`
+	Open          int    `json:"open"`
+	Merged        int    `json:"merged"`
+	Assignments   int    `json:"assignments"`
+	Reassignments int    `json:"reassignments"`
+}
+
+type TeamStatsResponse struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Teams []TeamStats `json:"teams"`
+}
+
+// MergeTimeResponse — время от создания до merge в секундах.
+type MergeTimeResponse struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Merged int     `json:"merged"`
+	Avg    float64 `json:"avg_seconds"`
+	P50    float64 `json:"p50_seconds"`
+	P90    float64 `json:"p90_seconds"`
+	P95    float64 `json:"p95_seconds"`
+	P99    float64 `json:"p99_seconds"`
+}
+
+type MemberLoad struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	Assignments int     `json:"assignments"`
+	DaysActive  float64 `json:"days_active"`
+	Rate        float64 `json:"rate"`
+	Flag        string  `json:"flag,omitempty"`
+}
+
+// FairnessResult — MaxMinRatio равен null, если кто-то не получил назначений.
+type FairnessResult struct {
+	Strategy    string       `json:"strategy"`
+	Mean        float64      `json:"mean"`
+	Gini        float64      `json:"gini"`
+	StdDev      float64      `json:"stddev"`
+	MaxMinRatio *float64     `json:"max_min_ratio"`
+	Members     []MemberLoad `json:"members"`
+}
+
+type FairnessResponse struct {
+	TeamName     string           `json:"team_name"`
+	From         string           `json:"from"`
+	To           string           `json:"to"`
+	PullRequests int              `json:"pull_requests"`
+	Actual       FairnessResult   `json:"actual"`
+	Strategies   []FairnessResult `json:"strategies"`
+}
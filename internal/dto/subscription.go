@@ -0,0 +1,35 @@
+package dto
+
+import "encoding/json"
+
+type Subscription struct {
+	SubscriptionID string   `json:"subscription_id"`
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+type SubscriptionList struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+type Delivery struct {
+	DeliveryID     string          `json:"delivery_id"`
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type" enums:"reviewers.assigned,reviewer.reassigned,pull_request.merged"`
+	Status         string          `json:"status" enums:"PENDING,DELIVERED,DEAD"`
+	Attempts       int             `json:"attempts"`
+	ResponseCode   int             `json:"response_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	NextAttemptAt  string          `json:"next_attempt_at,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      string          `json:"created_at"`
+	UpdatedAt      string          `json:"updated_at"`
+}
+
+type DeliveryList struct {
+	SubscriptionID string     `json:"subscription_id"`
+	Deliveries     []Delivery `json:"deliveries"`
+	NextCursor     string     `json:"next_cursor,omitempty"`
+}
@@ -4,9 +4,35 @@ type TeamMember struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
 }
 
 type Team struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName   string       `json:"team_name"`
+	ParentName string       `json:"parent_name,omitempty"`
+	Members    []TeamMember `json:"members"`
+
+	Subteams         []Team       `json:"subteams,omitempty"`
+	AggregateMembers []TeamMember `json:"aggregate_members,omitempty"`
+}
+
+type TeamSummary struct {
+	TeamName     string `json:"team_name"`
+	ParentName   string `json:"parent_name,omitempty"`
+	MembersCount int    `json:"members_count"`
+	ActiveCount  int    `json:"active_count"`
+}
+
+type TeamList struct {
+	Teams []TeamSummary `json:"teams"`
+}
+
+type ReviewAssignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+}
+
+type TeamDeleteResponse struct {
+	TeamName       string             `json:"team_name"`
+	RemovedReviews []ReviewAssignment `json:"removed_reviews"`
 }
@@ -1,8 +1,33 @@
 package dto
 
 type User struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
+	UserID      string `json:"user_id"`
+	Username    string `json:"username"`
+	TeamName    string `json:"team_name"`
+	IsActive    bool   `json:"is_active"`
+	ReviewLoad  int    `json:"review_load"`
+	IsAvailable bool   `json:"is_available"`
+
+	Teams []UserTeam `json:"teams"`
+}
+
+type UserTeam struct {
 	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
+}
+
+type UserList struct {
+	Users      []User `json:"users"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type Reassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+type UserMoveResponse struct {
+	User       User           `json:"user"`
+	Reassigned []Reassignment `json:"reassigned"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+type WebhookResult struct {
+	Outcome       string `json:"outcome" enums:"created,merged,closed,ignored"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
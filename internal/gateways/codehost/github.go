@@ -0,0 +1,152 @@
+// Package codehost — клиенты REST API код-хостингов, на которые gopr выгружает
+// назначенных ревьюверов.
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// GitHubURL — адрес публичного API GitHub.
+const GitHubURL = "https://api.github.com"
+
+const requestTimeout = 10 * time.Second
+
+var _ usecase.CodeHost = &GitHub{}
+
+// StatusError — ответ код-хостинга с кодом не из 2xx.
+type StatusError struct {
+	Method  string
+	Path    string
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.Path, e.Code, e.Message)
+}
+
+// GitHub — клиент REST API GitHub. baseURL настраивается для GitHub Enterprise
+// (https://host/api/v3) и тестов.
+type GitHub struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewGitHub создаёт клиент; пустой baseURL — публичный GitHub, client == nil —
+// http.Client с таймаутом запроса.
+func NewGitHub(baseURL, token string, client *http.Client) *GitHub {
+	if baseURL == "" {
+		baseURL = GitHubURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+
+	return &GitHub{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  client,
+	}
+}
+
+func (g *GitHub) Provider() domain.IdentityProvider {
+	return domain.IdentityGitHub
+}
+
+// RequestReviewers запрашивает ревью у logins; уже запрошенные логины GitHub пропускает.
+func (g *GitHub) RequestReviewers(ctx context.Context, ref domain.CodeHostRef, logins []string) error {
+	return g.requestedReviewers(ctx, http.MethodPost, ref, logins)
+}
+
+// RemoveReviewers отзывает запрос ревью у logins.
+func (g *GitHub) RemoveReviewers(ctx context.Context, ref domain.CodeHostRef, logins []string) error {
+	return g.requestedReviewers(ctx, http.MethodDelete, ref, logins)
+}
+
+func (g *GitHub) requestedReviewers(ctx context.Context, method string, ref domain.CodeHostRef, logins []string) error {
+	body, err := json.Marshal(map[string][]string{"reviewers": logins})
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("marshal reviewers: %w", err))
+	}
+
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", escapeRepository(ref.Repository), ref.Number)
+
+	req, err := http.NewRequestWithContext(ctx, method, g.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return backoff.Permanent(fmt.Errorf("build request: %w", err))
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	statusErr := &StatusError{
+		Method:  method,
+		Path:    path,
+		Code:    resp.StatusCode,
+		Message: errorMessage(resp.Body),
+	}
+	if retryable(resp) {
+		return statusErr
+	}
+	return backoff.Permanent(statusErr)
+}
+
+// retryable — стоит ли повторять запрос: сбои GitHub и исчерпанный лимит запросов.
+// Остальные 4xx (нет доступа, логин не коллаборатор) повтор не исправит.
+func retryable(resp *http.Response) bool {
+	switch {
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode == http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0"
+	default:
+		return false
+	}
+}
+
+func errorMessage(body io.Reader) string {
+	raw, _ := io.ReadAll(io.LimitReader(body, 4<<10))
+
+	var payload struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &payload); err == nil && payload.Message != "" {
+		return payload.Message
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+func escapeRepository(repository string) string {
+	parts := strings.Split(repository, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
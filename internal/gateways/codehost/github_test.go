@@ -0,0 +1,265 @@
+package codehost_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/codehost"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+const token = "gh-token"
+
+type call struct {
+	Method    string
+	Path      string
+	Reviewers []string
+}
+
+// stub — GitHub, отвечающий кодами из очереди (по умолчанию 201) и записывающий вызовы.
+type stub struct {
+	mu     sync.Mutex
+	codes  []int
+	calls  []call
+	server *httptest.Server
+}
+
+func newStub(t *testing.T, codes ...int) *stub {
+	s := &stub{codes: codes}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		s.mu.Lock()
+		s.calls = append(s.calls, call{Method: r.Method, Path: r.URL.Path, Reviewers: body.Reviewers})
+		code := http.StatusCreated
+		if len(s.codes) > 0 {
+			code, s.codes = s.codes[0], s.codes[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(`{"message":"stub says ` + http.StatusText(code) + `"}`))
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *stub) Calls() []call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]call(nil), s.calls...)
+}
+
+type env struct {
+	prCase *usecase.PullRequest
+	sync   *usecase.ReviewSync
+}
+
+func setup(t *testing.T, baseURL string) *env {
+	t.Helper()
+	return setupWithBackOff(t, baseURL, func() backoff.BackOff {
+		return backoff.WithMaxRetries(&backoff.ZeroBackOff{}, 2)
+	})
+}
+
+func setupWithBackOff(t *testing.T, baseURL string, newBackOff func() backoff.BackOff) *env {
+	t.Helper()
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "author", IsActive: true},
+			{UserID: "u2", Username: "reviewer", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	identities := usecase.NewIdentity(memory.NewIdentityRepo(db), userRepo, nil)
+	_, err = identities.Add(ctx, &domain.IdentityAddInput{UserID: "u2", Provider: domain.IdentityGitHub, Login: "OctoCat"})
+	require.NoError(t, err)
+
+	rs := usecase.NewReviewSync(prRepo, codehost.NewGitHub(baseURL, token, nil), identities, newBackOff)
+
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1)
+	prCase.SetReviewSync(rs)
+
+	return &env{prCase: prCase, sync: rs}
+}
+
+func (e *env) create(t *testing.T, id string) *domain.PullRequestDetails {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: id, AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+	e.sync.Wait()
+
+	details, err := e.prCase.Get(ctx, id)
+	require.NoError(t, err)
+	return details
+}
+
+func TestGitHub_RequestReviewersOnCreate(t *testing.T) {
+	gh := newStub(t)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "acme/api#42")
+
+	require.Equal(t, []call{{
+		Method:    http.MethodPost,
+		Path:      "/repos/acme/api/pulls/42/requested_reviewers",
+		Reviewers: []string{"octocat"},
+	}}, gh.Calls())
+
+	require.NotNil(t, details.ReviewSync)
+	require.Equal(t, domain.ReviewSyncSynced, details.ReviewSync.Status)
+	require.Equal(t, 1, details.ReviewSync.Attempts)
+	require.Empty(t, details.ReviewSync.LastError)
+}
+
+func TestGitHub_RetriesServerErrors(t *testing.T) {
+	gh := newStub(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "acme/api#43")
+
+	require.Len(t, gh.Calls(), 3)
+	require.Equal(t, domain.ReviewSyncSynced, details.ReviewSync.Status)
+	require.Equal(t, 3, details.ReviewSync.Attempts)
+}
+
+func TestGitHub_GivesUpAfterRetries(t *testing.T) {
+	gh := newStub(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "acme/api#44")
+
+	require.Len(t, gh.Calls(), 3)
+	require.Equal(t, domain.ReviewSyncFailed, details.ReviewSync.Status)
+	require.Contains(t, details.ReviewSync.LastError, "status 502")
+}
+
+func TestGitHub_ClientErrorIsNotRetried(t *testing.T) {
+	gh := newStub(t, http.StatusUnprocessableEntity)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "acme/api#45")
+
+	require.Len(t, gh.Calls(), 1)
+	require.Equal(t, domain.ReviewSyncFailed, details.ReviewSync.Status)
+	require.Equal(t, 1, details.ReviewSync.Attempts)
+	require.Contains(t, details.ReviewSync.LastError, "stub says Unprocessable Entity")
+}
+
+func TestGitHub_ReassignRemovesReviewer(t *testing.T) {
+	gh := newStub(t)
+	e := setup(t, gh.server.URL)
+	ctx := context.Background()
+
+	e.create(t, "acme/api#46")
+
+	// единственный кандидат уже назначен — Reassign снимает ревьювера без замены
+	_, _, err := e.prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: "acme/api#46", OldReviewerId: "u2"})
+	require.ErrorIs(t, err, usecase.ErrNoCandidate)
+	e.sync.Wait()
+
+	calls := gh.Calls()
+	require.Len(t, calls, 2)
+	require.Equal(t, call{
+		Method:    http.MethodDelete,
+		Path:      "/repos/acme/api/pulls/46/requested_reviewers",
+		Reviewers: []string{"octocat"},
+	}, calls[1])
+}
+
+func TestGitHub_SkipsLocalPullRequests(t *testing.T) {
+	gh := newStub(t)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "local-pr")
+
+	require.Empty(t, gh.Calls())
+	require.Nil(t, details.ReviewSync)
+}
+
+func TestGitHub_SkipsGitLabMergeRequests(t *testing.T) {
+	gh := newStub(t)
+	e := setup(t, gh.server.URL)
+
+	details := e.create(t, "acme/web!7")
+
+	require.Empty(t, gh.Calls())
+	require.Nil(t, details.ReviewSync)
+}
+
+func TestGitHub_PushesOfOnePullRequestRunInOrder(t *testing.T) {
+	gh := newStub(t)
+	e := setup(t, gh.server.URL)
+	ctx := context.Background()
+
+	// Reassign не ждёт выгрузки Create, но на GitHub попадает после неё
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "acme/api#47", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+	_, _, err = e.prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: "acme/api#47", OldReviewerId: "u2"})
+	require.ErrorIs(t, err, usecase.ErrNoCandidate)
+	e.sync.Wait()
+
+	calls := gh.Calls()
+	require.Len(t, calls, 2)
+	require.Equal(t, http.MethodPost, calls[0].Method)
+	require.Equal(t, http.MethodDelete, calls[1].Method)
+}
+
+func TestGitHub_StopInterruptsRetries(t *testing.T) {
+	gh := newStub(t, http.StatusBadGateway, http.StatusBadGateway)
+	e := setupWithBackOff(t, gh.server.URL, func() backoff.BackOff {
+		return backoff.NewConstantBackOff(time.Hour)
+	})
+	ctx := context.Background()
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "acme/api#42", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(gh.Calls()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	stopped := make(chan struct{})
+	go func() {
+		e.sync.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop is blocked by retries")
+	}
+
+	details, err := e.prCase.Get(ctx, "acme/api#42")
+	require.NoError(t, err)
+	require.NotNil(t, details.ReviewSync)
+	require.Equal(t, domain.ReviewSyncPending, details.ReviewSync.Status)
+	require.Equal(t, 1, details.ReviewSync.Attempts)
+	require.Contains(t, details.ReviewSync.LastError, "interrupted by shutdown")
+}
@@ -0,0 +1,79 @@
+// Package delivery отправляет события PR на URL подписок внешних систем.
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Заголовки доставки. Подпись — HMAC-SHA256 тела секретом подписки в виде
+// "sha256=<hex>", как у вебхуков GitHub.
+const (
+	HeaderEvent     = "X-Gopr-Event"
+	HeaderDelivery  = "X-Gopr-Delivery"
+	HeaderSignature = "X-Gopr-Signature-256"
+)
+
+const requestTimeout = 10 * time.Second
+
+var _ usecase.DeliverySender = &Sender{}
+
+// Sender — HTTP-клиент доставок.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender создаёт отправителя; client == nil — http.Client с таймаутом запроса.
+func NewSender(client *http.Client) *Sender {
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	return &Sender{client: client}
+}
+
+// Send отправляет тело доставки POST-запросом. Любой ответ не из 2xx — ошибка,
+// доставка повторяется; не повторяются только запросы, которые нельзя собрать.
+func (s *Sender) Send(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, backoff.Permanent(fmt.Errorf("build request: %w", err))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gopr-webhook")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, d.Id)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("POST %s: %w", sub.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode, fmt.Errorf("POST %s: status %d: %s", sub.URL, resp.StatusCode, strings.TrimSpace(string(raw)))
+}
+
+// Sign возвращает значение заголовка подписи тела body секретом secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
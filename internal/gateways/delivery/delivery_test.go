@@ -0,0 +1,303 @@
+package delivery_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/delivery"
+	"gopr/internal/repo"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+const secret = "subscriber-secret-0123"
+
+type request struct {
+	Event    string
+	Delivery string
+	Body     domain.Event
+}
+
+// receiver — подписчик, отвечающий кодами из очереди (по умолчанию 204) и
+// записывающий доставки с верной подписью.
+type receiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []request
+	server   *httptest.Server
+}
+
+func newReceiver(t *testing.T, codes ...int) *receiver {
+	rc := &receiver{codes: codes}
+	rc.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		if r.Header.Get(delivery.HeaderSignature) != delivery.Sign(secret, raw) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body domain.Event
+		_ = json.Unmarshal(raw, &body)
+
+		rc.mu.Lock()
+		rc.requests = append(rc.requests, request{
+			Event:    r.Header.Get(delivery.HeaderEvent),
+			Delivery: r.Header.Get(delivery.HeaderDelivery),
+			Body:     body,
+		})
+		code := http.StatusNoContent
+		if len(rc.codes) > 0 {
+			code, rc.codes = rc.codes[0], rc.codes[1:]
+		}
+		rc.mu.Unlock()
+
+		w.WriteHeader(code)
+	}))
+	t.Cleanup(rc.server.Close)
+	return rc
+}
+
+func (rc *receiver) Requests() []request {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]request(nil), rc.requests...)
+}
+
+type env struct {
+	prCase  *usecase.PullRequest
+	subRepo repo.Subscription
+	subCase *usecase.Subscription
+	relay   *usecase.Relay
+}
+
+func setup(t *testing.T) *env {
+	t.Helper()
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "author", IsActive: true},
+			{UserID: "u2", Username: "reviewer", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	subRepo := memory.NewSubscriptionRepo(db)
+	subCase := usecase.NewSubscription(subRepo, delivery.NewSender(nil), 3,
+		func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+	)
+
+	outbox := memory.NewOutboxRepo(db)
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1)
+	prCase.SetOutbox(memory.NewTransactor(db), outbox)
+
+	return &env{
+		prCase:  prCase,
+		subRepo: subRepo,
+		subCase: subCase,
+		relay:   usecase.NewRelay(outbox, []usecase.EventSink{subCase}, 0, 0),
+	}
+}
+
+func (e *env) subscribe(t *testing.T, url string, events ...domain.EventType) *domain.Subscription {
+	t.Helper()
+
+	sub, err := e.subCase.Add(context.Background(), &domain.SubscriptionAddInput{URL: url, Events: events, Secret: secret})
+	require.NoError(t, err)
+	return sub
+}
+
+func (e *env) deliveries(t *testing.T, subID string) []*domain.Delivery {
+	t.Helper()
+
+	_, err := e.relay.Flush(context.Background())
+	require.NoError(t, err)
+	e.subCase.Wait()
+
+	// повторы без задержки наступают сразу
+	for {
+		n, err := e.subCase.DeliverDue(context.Background())
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	page, err := e.subCase.Deliveries(context.Background(), &domain.DeliveryListQuery{SubscriptionId: subID})
+	require.NoError(t, err)
+	return page.Deliveries
+}
+
+func TestDelivery_SignedEventOnCreate(t *testing.T) {
+	rc := newReceiver(t)
+	e := setup(t)
+	sub := e.subscribe(t, rc.server.URL)
+
+	_, err := e.prCase.Create(context.Background(), &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+
+	deliveries := e.deliveries(t, sub.Id)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+	require.Equal(t, 1, deliveries[0].Attempts)
+	require.Equal(t, http.StatusNoContent, deliveries[0].ResponseCode)
+
+	requests := rc.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, "reviewers.assigned", requests[0].Event)
+	require.Equal(t, deliveries[0].Id, requests[0].Delivery)
+	require.Equal(t, deliveries[0].EventId, requests[0].Body.Id)
+	require.Equal(t, "pr-1", requests[0].Body.PullRequest.Id)
+	require.Equal(t, []string{"u2"}, requests[0].Body.Reviewers)
+}
+
+func TestDelivery_EventFilter(t *testing.T) {
+	rc := newReceiver(t)
+	e := setup(t)
+	ctx := context.Background()
+	merged := e.subscribe(t, rc.server.URL, domain.EventPullRequestMerged)
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+	_, err = e.prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+	// повторный merge ничего не меняет и события не порождает
+	_, err = e.prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+
+	require.Len(t, e.deliveries(t, merged.Id), 1)
+
+	requests := rc.Requests()
+	require.Len(t, requests, 1)
+	require.Equal(t, "pull_request.merged", requests[0].Event)
+	require.Equal(t, "MERGED", requests[0].Body.PullRequest.Status)
+}
+
+func TestDelivery_RetriesThenDead(t *testing.T) {
+	rc := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable)
+	e := setup(t)
+	sub := e.subscribe(t, rc.server.URL)
+
+	_, err := e.prCase.Create(context.Background(), &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+
+	deliveries := e.deliveries(t, sub.Id)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, domain.DeliveryDead, deliveries[0].Status)
+	require.Equal(t, 3, deliveries[0].Attempts)
+	require.Equal(t, http.StatusServiceUnavailable, deliveries[0].ResponseCode)
+	require.Contains(t, deliveries[0].LastError, "status 503")
+	require.Len(t, rc.Requests(), 3)
+}
+
+func TestDelivery_RetrySucceeds(t *testing.T) {
+	rc := newReceiver(t, http.StatusBadGateway)
+	e := setup(t)
+	sub := e.subscribe(t, rc.server.URL)
+
+	_, err := e.prCase.Create(context.Background(), &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+
+	deliveries := e.deliveries(t, sub.Id)
+	require.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+	require.Equal(t, 2, deliveries[0].Attempts)
+	require.Empty(t, deliveries[0].LastError)
+}
+
+func TestDelivery_Redeliver(t *testing.T) {
+	rc := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	e := setup(t)
+	ctx := context.Background()
+	sub := e.subscribe(t, rc.server.URL)
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+
+	dead := e.deliveries(t, sub.Id)[0]
+	require.Equal(t, domain.DeliveryDead, dead.Status)
+
+	again, err := e.subCase.Redeliver(ctx, &domain.RedeliverInput{DeliveryId: dead.Id})
+	require.NoError(t, err)
+	require.NotEqual(t, dead.Id, again.Id)
+	require.Equal(t, domain.DeliveryPending, again.Status)
+
+	deliveries := e.deliveries(t, sub.Id)
+	require.Len(t, deliveries, 2)
+	require.Equal(t, again.Id, deliveries[0].Id)
+	require.Equal(t, domain.DeliveryDelivered, deliveries[0].Status)
+	require.Equal(t, dead.EventId, deliveries[0].EventId)
+
+	requests := rc.Requests()
+	require.Len(t, requests, 4)
+	require.Equal(t, requests[0].Body, requests[3].Body)
+
+	_, err = e.subCase.Redeliver(ctx, &domain.RedeliverInput{DeliveryId: "missing"})
+	require.ErrorIs(t, err, repo.ErrNotFound)
+}
+
+func TestDelivery_ResumesAfterRestart(t *testing.T) {
+	rc := newReceiver(t, http.StatusBadGateway)
+	e := setup(t)
+	ctx := context.Background()
+	sub := e.subscribe(t, rc.server.URL)
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "feature"})
+	require.NoError(t, err)
+	_, err = e.relay.Flush(ctx)
+	require.NoError(t, err)
+	e.subCase.Wait()
+
+	// первая попытка не удалась, повтор запланирован в базе
+	page, err := e.subCase.Deliveries(ctx, &domain.DeliveryListQuery{SubscriptionId: sub.Id})
+	require.NoError(t, err)
+	require.Len(t, page.Deliveries, 1)
+	require.Equal(t, domain.DeliveryPending, page.Deliveries[0].Status)
+	require.Equal(t, 1, page.Deliveries[0].Attempts)
+	require.NotNil(t, page.Deliveries[0].NextAttemptAt)
+
+	// доставка, взятая в работу упавшим инстансом, ждёт окончания его аренды
+	now := time.Now()
+	claimed := now.Add(time.Minute)
+	require.NoError(t, e.subRepo.CreateDelivery(ctx, &domain.Delivery{
+		Id: "orphan", SubscriptionId: sub.Id, EventId: "e-orphan", EventType: domain.EventPullRequestMerged,
+		Payload: []byte(`{"id":"e-orphan"}`), Status: domain.DeliveryPending, NextAttemptAt: &claimed,
+	}))
+
+	restarted := usecase.NewSubscription(e.subRepo, delivery.NewSender(nil), 3,
+		func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+	)
+	restarted.SetClock(func() time.Time { return now })
+	n, err := restarted.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	restarted.SetClock(func() time.Time { return now.Add(2 * time.Minute) })
+	n, err = restarted.DeliverDue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	page, err = e.subCase.Deliveries(ctx, &domain.DeliveryListQuery{SubscriptionId: sub.Id})
+	require.NoError(t, err)
+	require.Len(t, page.Deliveries, 2)
+	for _, d := range page.Deliveries {
+		require.Equal(t, domain.DeliveryDelivered, d.Status, d.Id)
+		require.Nil(t, d.NextAttemptAt)
+	}
+	require.Len(t, rc.Requests(), 3)
+}
@@ -0,0 +1,51 @@
+// Package eventlog дописывает события PR в файл строками JSON.
+package eventlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+	"os"
+	"sync"
+)
+
+var _ usecase.EventSink = &File{}
+
+// File — получатель событий, пишущий каждое событие отдельной строкой.
+type File struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open открывает файл path на дозапись, создавая его при необходимости.
+func Open(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open event log: %w", err)
+	}
+	return &File{f: f}, nil
+}
+
+func (l *File) Name() string { return "log_file" }
+
+func (l *File) Publish(_ context.Context, event *domain.Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.f.Write(line); err != nil {
+		return fmt.Errorf("write event log: %w", err)
+	}
+	return nil
+}
+
+func (l *File) Close() error {
+	return l.f.Close()
+}
@@ -0,0 +1,73 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Email отправляет оповещение письмом через SMTP-сервер. Если сервер
+// поддерживает STARTTLS, соединение шифруется; пароль net/smtp отправляет только
+// по зашифрованному соединению или на localhost.
+type Email struct {
+	addr     string
+	from     string
+	username string
+	password string
+}
+
+// NewEmail создаёт канал почты; addr — host:port SMTP-сервера. Пустой username
+// — без аутентификации.
+func NewEmail(addr, from, username, password string) *Email {
+	return &Email{addr: addr, from: from, username: username, password: password}
+}
+
+func (e *Email) Channel() domain.NotificationChannel { return domain.NotificationEmail }
+
+func (e *Email) Notify(ctx context.Context, n *domain.Notification) error {
+	if n.Address == "" {
+		return errNoAddress
+	}
+
+	var auth smtp.Auth
+	if e.username != "" {
+		host, _, _ := net.SplitHostPort(e.addr)
+		auth = smtp.PlainAuth("", e.username, e.password, host)
+	}
+
+	// net/smtp не принимает контекст: отправка выполняется в горутине, а отмена
+	// только прекращает ожидание
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(e.addr, auth, e.from, []string{n.Address}, e.message(n))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("send mail to %s: %w", n.Address, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Email) message(n *domain.Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", n.Address)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Text, "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
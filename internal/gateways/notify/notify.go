@@ -0,0 +1,111 @@
+// Package notify отправляет оповещения пользователям: во входящий вебхук
+// Slack, письмом по SMTP и JSON-запросом на произвольный URL.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 10 * time.Second
+
+var (
+	_ usecase.Notifier = &Slack{}
+	_ usecase.Notifier = &HTTP{}
+	_ usecase.Notifier = &Email{}
+)
+
+var errNoAddress = errors.New("no address configured")
+
+// Slack пишет текст оповещения во входящий вебхук: адрес пользователя или
+// вебхук по умолчанию.
+type Slack struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// NewSlack создаёт канал Slack; client == nil — http.Client с таймаутом запроса.
+func NewSlack(client *http.Client, webhookURL string) *Slack {
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	return &Slack{client: client, webhookURL: webhookURL}
+}
+
+func (s *Slack) Channel() domain.NotificationChannel { return domain.NotificationSlack }
+
+func (s *Slack) Notify(ctx context.Context, n *domain.Notification) error {
+	body, err := json.Marshal(map[string]string{"text": n.Text})
+	if err != nil {
+		return fmt.Errorf("marshal slack message: %w", err)
+	}
+	return post(ctx, s.client, address(n, s.webhookURL), body)
+}
+
+// HTTP отправляет оповещение целиком JSON-запросом на адрес пользователя или
+// URL по умолчанию.
+type HTTP struct {
+	client *http.Client
+	url    string
+}
+
+// NewHTTP создаёт канал HTTP; client == nil — http.Client с таймаутом запроса.
+func NewHTTP(client *http.Client, url string) *HTTP {
+	if client == nil {
+		client = &http.Client{Timeout: requestTimeout}
+	}
+	return &HTTP{client: client, url: url}
+}
+
+func (h *HTTP) Channel() domain.NotificationChannel { return domain.NotificationHTTP }
+
+func (h *HTTP) Notify(ctx context.Context, n *domain.Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+	return post(ctx, h.client, address(n, h.url), body)
+}
+
+func address(n *domain.Notification, fallback string) string {
+	if n.Address != "" {
+		return n.Address
+	}
+	return fallback
+}
+
+func post(ctx context.Context, client *http.Client, url string, body []byte) error {
+	if url == "" {
+		return errNoAddress
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gopr-notify")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("POST %s: status %d: %s", url, resp.StatusCode, strings.TrimSpace(string(raw)))
+}
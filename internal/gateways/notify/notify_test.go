@@ -0,0 +1,145 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/notify"
+)
+
+func notification(address string) *domain.Notification {
+	return &domain.Notification{
+		Kind:          domain.NotificationAssigned,
+		UserId:        "u2",
+		Username:      "bob",
+		Address:       address,
+		PullRequestId: "pr-1",
+		Subject:       "Review requested: Fix",
+		Text:          "bob, you are assigned",
+	}
+}
+
+// recorder — стенд вебхука, запоминающий тела запросов.
+func recorder(t *testing.T, status int) (*httptest.Server, <-chan []byte) {
+	t.Helper()
+
+	bodies := make(chan []byte, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		bodies <- raw
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, bodies
+}
+
+func TestSlack(t *testing.T) {
+	ctx := context.Background()
+	def, defBodies := recorder(t, http.StatusOK)
+	own, ownBodies := recorder(t, http.StatusOK)
+	slack := notify.NewSlack(nil, def.URL)
+
+	require.NoError(t, slack.Notify(ctx, notification("")))
+	require.JSONEq(t, `{"text":"bob, you are assigned"}`, string(<-defBodies))
+
+	require.NoError(t, slack.Notify(ctx, notification(own.URL)))
+	require.JSONEq(t, `{"text":"bob, you are assigned"}`, string(<-ownBodies))
+
+	require.Error(t, notify.NewSlack(nil, "").Notify(ctx, notification("")))
+
+	down, _ := recorder(t, http.StatusInternalServerError)
+	require.ErrorContains(t, slack.Notify(ctx, notification(down.URL)), "status 500")
+}
+
+func TestHTTP(t *testing.T) {
+	server, bodies := recorder(t, http.StatusAccepted)
+
+	require.NoError(t, notify.NewHTTP(nil, server.URL).Notify(context.Background(), notification("")))
+
+	var got domain.Notification
+	require.NoError(t, json.Unmarshal(<-bodies, &got))
+	require.Equal(t, domain.NotificationAssigned, got.Kind)
+	require.Equal(t, "pr-1", got.PullRequestId)
+	require.Equal(t, "bob, you are assigned", got.Text)
+}
+
+// smtpServer — минимальный SMTP-сервер: принимает одно письмо и отдаёт его текст.
+func smtpServer(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	mails := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ready")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					mails <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), mails
+}
+
+func TestEmail(t *testing.T) {
+	addr, mails := smtpServer(t)
+	email := notify.NewEmail(addr, "gopr@example.com", "", "")
+
+	require.Error(t, email.Notify(context.Background(), notification("")))
+	require.NoError(t, email.Notify(context.Background(), notification("bob@example.com")))
+
+	mail := <-mails
+	require.Contains(t, mail, "From: gopr@example.com\r\n")
+	require.Contains(t, mail, "To: bob@example.com\r\n")
+	require.Contains(t, mail, "Subject: Review requested: Fix\r\n")
+	require.True(t, strings.HasSuffix(mail, "\r\nbob, you are assigned\r\n"))
+}
@@ -0,0 +1,146 @@
+package apierr
+
+import (
+	"errors"
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
+
+// Сообщения для ошибок репозитория постоянные: цепочка обёрток из usecase
+// остаётся только в логе.
+const (
+	notFoundMessage      = "resource not found"
+	alreadyExistsMessage = "resource already exists"
+)
+
+var statuses = map[domain.ErrorCode]int{
+	domain.ErrCodeTeamExists:      http.StatusBadRequest,
+	domain.ErrCodePRExists:        http.StatusConflict,
+	domain.ErrCodePRMerged:        http.StatusConflict,
+	domain.ErrCodeNotAssigned:     http.StatusConflict,
+	domain.ErrCodeNoCandidate:     http.StatusConflict,
+	domain.ErrCodeNotFound:        http.StatusNotFound,
+	domain.ErrCodeAlreadyExists:   http.StatusConflict,
+	domain.ErrCodeOpenReviews:     http.StatusConflict,
+	domain.ErrCodeUserInTeam:      http.StatusConflict,
+	domain.ErrCodeNotMember:       http.StatusConflict,
+	domain.ErrCodeTeamCycle:       http.StatusConflict,
+	domain.ErrCodeUnknownIdentity: http.StatusNotFound,
+	domain.ErrCodeBadRequest:      http.StatusBadRequest,
+	domain.ErrCodeValidation:      http.StatusBadRequest,
+	domain.ErrCodeUnauthorized:    http.StatusUnauthorized,
+	domain.ErrCodeInternal:        http.StatusInternalServerError,
+}
+
+// Status возвращает HTTP-статус для кода ошибки.
+func Status(code domain.ErrorCode) int {
+	if s, ok := statuses[code]; ok {
+		return s
+	}
+	return http.StatusInternalServerError
+}
+
+// Classify приводит произвольную ошибку usecase/repo к доменной ошибке.
+func Classify(err error) *domain.Error {
+	if de, ok := domain.AsError(err); ok {
+		return de
+	}
+
+	if violations, ok := validation.Violations(err); ok {
+		return &domain.Error{
+			Code:    domain.ErrCodeValidation,
+			Message: "validation failed",
+			Details: violations,
+		}
+	}
+
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return domain.NewError(domain.ErrCodeNotFound, notFoundMessage)
+	case errors.Is(err, repo.ErrAlreadyExists):
+		return domain.NewError(domain.ErrCodeAlreadyExists, alreadyExistsMessage)
+	default:
+		return domain.NewError(domain.ErrCodeInternal, "internal error")
+	}
+}
+
+// Render пишет ошибку в ответ. Клиент, приславший
+// Accept: application/problem+json, получает ответ в формате RFC 7807.
+func Render(c *gin.Context, err error) {
+	de := Classify(err)
+	status := Status(de.Code)
+
+	if status >= http.StatusInternalServerError {
+		slogx.FromCtxWithErr(c, err).Error("request failed")
+	} else {
+		slogx.FromCtxWithErr(c, err).Info("request rejected", "code", de.Code)
+	}
+
+	write(c, status, de)
+}
+
+// BadRequest пишет ответ 400 с кодом BAD_REQUEST.
+func BadRequest(c *gin.Context, message string) {
+	write(c, http.StatusBadRequest, domain.NewError(domain.ErrCodeBadRequest, message))
+}
+
+// RenderBind пишет ошибку привязки запроса: нарушения правил валидации
+// отдаются списком по полям, остальное — как BAD_REQUEST с message.
+func RenderBind(c *gin.Context, err error, message string) {
+	if _, ok := validation.Violations(err); ok {
+		Render(c, err)
+		return
+	}
+	BadRequest(c, message)
+}
+
+func write(c *gin.Context, status int, de *domain.Error) {
+	if wantsProblem(c) {
+		c.Header("Content-Type", problemContentType)
+		c.AbortWithStatusJSON(status, dto.Problem{
+			Type:    "about:blank",
+			Title:   http.StatusText(status),
+			Status:  status,
+			Detail:  de.Message,
+			Code:    string(de.Code),
+			Details: details(de),
+		})
+		return
+	}
+
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{
+		Error: dto.ErrorObject{
+			Code:    string(de.Code),
+			Message: de.Message,
+			Details: details(de),
+		},
+	})
+}
+
+func details(de *domain.Error) []dto.FieldError {
+	if len(de.Details) == 0 {
+		return nil
+	}
+
+	res := make([]dto.FieldError, 0, len(de.Details))
+	for _, d := range de.Details {
+		res = append(res, dto.FieldError{
+			Field:  d.Field,
+			Reason: d.Reason,
+		})
+	}
+	return res
+}
+
+func wantsProblem(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), problemContentType)
+}
@@ -0,0 +1,101 @@
+package apierr_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+)
+
+func TestErrorCodesMatchOpenAPI(t *testing.T) {
+	raw, err := os.ReadFile("../../../../docs/openapi_original.yml")
+	require.NoError(t, err)
+
+	var spec struct {
+		Components struct {
+			Schemas struct {
+				ErrorResponse struct {
+					Properties struct {
+						Error struct {
+							Properties struct {
+								Code struct {
+									Enum []string `yaml:"enum"`
+								} `yaml:"code"`
+							} `yaml:"properties"`
+						} `yaml:"error"`
+					} `yaml:"properties"`
+				} `yaml:"ErrorResponse"`
+			} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	require.NoError(t, yaml.Unmarshal(raw, &spec))
+
+	codes := make([]string, 0, len(domain.ErrorCodes))
+	for _, c := range domain.ErrorCodes {
+		codes = append(codes, string(c))
+	}
+
+	require.ElementsMatch(t, spec.Components.Schemas.ErrorResponse.Properties.Error.Properties.Code.Enum, codes)
+}
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		err    error
+		code   domain.ErrorCode
+		status int
+	}{
+		{fmt.Errorf("load author: %w", repo.ErrNotFound), domain.ErrCodeNotFound, http.StatusNotFound},
+		{fmt.Errorf("insert user: %w", repo.ErrAlreadyExists), domain.ErrCodeAlreadyExists, http.StatusConflict},
+		{fmt.Errorf("%w: %w", usecase.ErrTeamExists, repo.ErrAlreadyExists), domain.ErrCodeTeamExists, http.StatusBadRequest},
+		{fmt.Errorf("%w: %w", usecase.ErrPRExists, repo.ErrAlreadyExists), domain.ErrCodePRExists, http.StatusConflict},
+		{usecase.ErrPRMerged, domain.ErrCodePRMerged, http.StatusConflict},
+		{usecase.ErrNoCandidate, domain.ErrCodeNoCandidate, http.StatusConflict},
+		{fmt.Errorf("resolve author: %w", &domain.UnknownIdentityError{Provider: domain.IdentityGitHub, Login: "octocat"}), domain.ErrCodeUnknownIdentity, http.StatusNotFound},
+		{fmt.Errorf("connection reset"), domain.ErrCodeInternal, http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		de := apierr.Classify(tc.err)
+		require.Equal(t, tc.code, de.Code, tc.err.Error())
+		require.Equal(t, tc.status, apierr.Status(de.Code), tc.err.Error())
+	}
+}
+
+func TestClassify_HidesWrapChain(t *testing.T) {
+	de := apierr.Classify(fmt.Errorf("failed to load author: %w", repo.ErrNotFound))
+	require.Equal(t, "resource not found", de.Message)
+
+	de = apierr.Classify(fmt.Errorf("failed to create user u1: %w", repo.ErrAlreadyExists))
+	require.Equal(t, "resource already exists", de.Message)
+}
+
+func TestRenderProblemJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept", "application/problem+json")
+
+	apierr.Render(c, usecase.ErrNotAssigned)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var p dto.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, "NOT_ASSIGNED", p.Code)
+	require.Equal(t, http.StatusConflict, p.Status)
+}
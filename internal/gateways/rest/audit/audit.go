@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	v1.GET("/audit", listAudit(cases.Audit))
+}
+
+// @Summary Журнал аудита
+// @Description Кто и когда менял пользователей, команды и PR, со снимками цели до и после.
+// @Description По умолчанию новые записи первыми; from и to (RFC3339) ограничивают время записи: [from, to).
+// @Description request_id — значение заголовка X-Request-Id запроса, сделавшего изменение.
+// @Description actor — подтверждённый инициатор (отправитель вебхука, автор slash-команды) или system;
+// @Description claimed_actor — непроверенный X-Actor-Id запроса.
+// @Tags Audit
+// @Produce json
+// @Param query query domain.AuditListQuery false "Фильтры и пагинация"
+// @Success 200 {object} dto.AuditList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /audit [get]
+func listAudit(auditCase *usecase.Audit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.AuditListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		page, err := auditCase.List(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		res := make([]dto.AuditRecord, 0, len(page.Records))
+		for _, r := range page.Records {
+			res = append(res, convertRecord(r))
+		}
+
+		c.JSON(http.StatusOK, dto.AuditList{
+			Records:    res,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
+
+func convertRecord(r *domain.AuditRecord) dto.AuditRecord {
+	return dto.AuditRecord{
+		AuditID:      r.Id,
+		Actor:        r.Actor,
+		ClaimedActor: r.ClaimedActor,
+		Action:       string(r.Action),
+		TargetType:   string(r.TargetType),
+		TargetID:     r.TargetId,
+		Before:       r.Before,
+		After:        r.After,
+		RequestID:    r.RequestId,
+		CreatedAt:    r.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,135 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/audit"
+	"gopr/internal/gateways/rest/middlewares"
+	"gopr/internal/gateways/rest/user"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+func setup(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	db := memory.NewDB()
+	// записи не совпадают по времени, порядок выдачи однозначен
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	db.SetClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+	userRepo := memory.NewUserRepo(db)
+	require.NoError(t, userRepo.Create(context.Background(), &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+
+	auditCase := usecase.NewAudit(memory.NewAuditRepo(db), memory.NewTransactor(db), 0)
+	userCase := usecase.NewUser(userRepo, memory.NewTeamRepo(db), memory.NewPullRequestRepo(db), nil)
+	userCase.SetAudit(auditCase)
+	cases := usecase.Cases{User: userCase, Audit: auditCase}
+
+	r := gin.New()
+	r.Use(middlewares.RequestID(), middlewares.Actor())
+	v1 := r.Group("/api/v1")
+	user.Setup(v1, cases)
+	audit.Setup(v1, cases)
+	return r
+}
+
+func do(t *testing.T, r *gin.Engine, req *http.Request) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res), w.Body.String())
+	return w, res
+}
+
+func TestAudit_SetIsActive(t *testing.T) {
+	r := setup(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/setIsActive", strings.NewReader(`{"user_id":"u1","is_active":false}`))
+	req.Header.Set("X-Actor-Id", "admin")
+	req.Header.Set("X-Request-Id", "req-42")
+	w, _ := do(t, r, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "req-42", w.Header().Get("X-Request-Id"))
+
+	// без заголовка id запроса генерируется
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/users/setIsActive", strings.NewReader(`{"user_id":"u1","is_active":true}`))
+	w, _ = do(t, r, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	generated := w.Header().Get("X-Request-Id")
+	require.NotEmpty(t, generated)
+
+	w, res := do(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/audit?request_id=req-42", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var records []dto.AuditRecord
+	require.NoError(t, json.Unmarshal(res["records"], &records))
+	require.Len(t, records, 1)
+	// заголовок X-Actor-Id не проверяется и не становится инициатором
+	require.Equal(t, "system", records[0].Actor)
+	require.Equal(t, "admin", records[0].ClaimedActor)
+	require.Equal(t, "user.set_active", records[0].Action)
+	require.Equal(t, "user", records[0].TargetType)
+	require.Equal(t, "u1", records[0].TargetID)
+	require.Equal(t, "req-42", records[0].RequestID)
+	require.Contains(t, string(records[0].Before), `"is_active":true`)
+	require.Contains(t, string(records[0].After), `"is_active":false`)
+
+	w, res = do(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/audit?target_type=user&target_id=u1&order=oldest", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(res["records"], &records))
+	require.Len(t, records, 2)
+	require.Equal(t, "req-42", records[0].RequestID)
+	require.Equal(t, "system", records[1].Actor)
+	require.Empty(t, records[1].ClaimedActor)
+	require.Equal(t, generated, records[1].RequestID)
+
+	w, res = do(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/audit?claimed_actor=admin", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(res["records"], &records))
+	require.Len(t, records, 1)
+	require.Equal(t, "req-42", records[0].RequestID)
+}
+
+func TestAudit_ReservedActor(t *testing.T) {
+	r := setup(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/setIsActive", strings.NewReader(`{"user_id":"u1","is_active":false}`))
+	req.Header.Set("X-Actor-Id", "system")
+	w, _ := do(t, r, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, res := do(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/audit", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var records []dto.AuditRecord
+	require.NoError(t, json.Unmarshal(res["records"], &records))
+	require.Empty(t, records)
+}
+
+func TestAudit_InvalidQuery(t *testing.T) {
+	r := setup(t)
+
+	for _, query := range []string{"target_type=repo", "from=yesterday", "limit=500", "cursor=bad"} {
+		w, _ := do(t, r, httptest.NewRequest(http.MethodGet, "/api/v1/audit?"+query, nil))
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
@@ -0,0 +1,129 @@
+package chatops
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopr/cmd/config"
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+	"gopr/pkg/slogx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxPayload — предел тела slash-команды; Slack присылает несколько сотен байт.
+	maxPayload = 64 << 10
+	// maxSkew — насколько метка времени запроса может отличаться от текущей:
+	// старые подписанные запросы нельзя прислать повторно.
+	maxSkew = 5 * time.Minute
+)
+
+var errBadSignature = domain.NewError(domain.ErrCodeUnauthorized, "invalid slack signature")
+
+// Setup регистрирует эндпоинт Slack, только если задан секрет подписи.
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases, cfg *config.Config) {
+	if secret := cfg.ChatOps.Slack.SigningSecret; secret != "" {
+		v1.POST("/chatops/slack", slack(cases.ChatOps, secret))
+	}
+}
+
+// @Summary Выполнить slash-команду Slack
+// @Description Подпись X-Slack-Signature проверяется секретом CHATOPS_SLACK_SIGNING_SECRET, запросы старше 5 минут отклоняются.
+// @Description Команды: reviews [@user], reassign <pull request id> @reviewer, away [until YYYY-MM-DD], back, help.
+// @Description Автор команды сопоставляется с пользователем gopr по учётной записи slack или CHATOPS_SLACK_LOGINS.
+// @Description Ошибки команды возвращаются текстом ответа со статусом 200, чтобы Slack показал их автору.
+// @Tags ChatOps
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param X-Slack-Request-Timestamp header string true "Время запроса, unix-секунды"
+// @Param X-Slack-Signature header string true "v0=<hex HMAC-SHA256 строки v0:<timestamp>:<тело>>"
+// @Param command formData string true "Имя команды, например /gopr"
+// @Param text formData string false "Текст после имени команды"
+// @Param user_id formData string true "Id автора в Slack"
+// @Success 200 {object} dto.SlashReply
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /chatops/slack [post]
+func slack(chatCase *usecase.ChatOps, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPayload))
+		if err != nil {
+			apierr.BadRequest(c, "can't read payload")
+			return
+		}
+
+		if !validSlackSignature(secret, c.GetHeader("X-Slack-Request-Timestamp"), body, c.GetHeader("X-Slack-Signature"), time.Now()) {
+			apierr.Render(c, errBadSignature)
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil || form.Get("user_id") == "" {
+			apierr.BadRequest(c, "invalid payload")
+			return
+		}
+
+		res, err := chatCase.Handle(c, &domain.SlashCommand{
+			Provider: domain.IdentitySlack,
+			Command:  form.Get("command"),
+			UserId:   form.Get("user_id"),
+			Text:     form.Get("text"),
+		})
+		if err != nil {
+			c.JSON(http.StatusOK, dto.SlashReply{ResponseType: "ephemeral", Text: errorText(c, err)})
+			return
+		}
+
+		reply := dto.SlashReply{ResponseType: "ephemeral", Text: res.Text}
+		if res.InChannel {
+			reply.ResponseType = "in_channel"
+		}
+		c.JSON(http.StatusOK, reply)
+	}
+}
+
+// errorText — текст ошибки для автора команды; внутренние ошибки только логируются.
+func errorText(c *gin.Context, err error) string {
+	de := apierr.Classify(err)
+	if de.Code == domain.ErrCodeInternal {
+		slogx.FromCtxWithErr(c, err).Error("slash command failed")
+		return "Something went wrong, please try again later."
+	}
+	return "Error: " + de.Message
+}
+
+func validSlackSignature(secret, timestamp string, body []byte, header string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > maxSkew || skew < -maxSkew {
+		return false
+	}
+
+	sig, ok := strings.CutPrefix(header, "v0=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,153 @@
+package chatops_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/cmd/config"
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/chatops"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+const secret = "slack-secret"
+
+func setup(t *testing.T, signingSecret string) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1)
+	_, err = prCase.Create(ctx, &domain.CreatePullRequest{Id: "PR-123", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	identities := usecase.NewIdentity(memory.NewIdentityRepo(db), userRepo, map[domain.IdentityProvider]map[string]string{
+		domain.IdentitySlack: {"U01": "u1"},
+	})
+	cases := usecase.Cases{
+		ChatOps: usecase.NewChatOps(usecase.NewUser(userRepo, teamRepo, prRepo, nil), prCase, identities, userRepo),
+	}
+
+	cfg := &config.Config{}
+	cfg.ChatOps.Slack.SigningSecret = signingSecret
+
+	r := gin.New()
+	chatops.Setup(r.Group("/api/v1"), cases, cfg)
+	return r
+}
+
+func sign(timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func command(text string) string {
+	return url.Values{
+		"command":   {"/gopr"},
+		"text":      {text},
+		"user_id":   {"U01"},
+		"user_name": {"alice"},
+	}.Encode()
+}
+
+func do(t *testing.T, r *gin.Engine, timestamp, signature, body string) (int, dto.SlashReply) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/chatops/slack", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", signature)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res dto.SlashReply
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	}
+	return w.Code, res
+}
+
+func TestSlack_Commands(t *testing.T) {
+	r := setup(t, secret)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	body := command("reviews @bob")
+	code, res := do(t, r, ts, sign(ts, body), body)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ephemeral", res.ResponseType)
+	require.Contains(t, res.Text, "*bob* has 1 open review(s):\n• *Fix* (`PR-123`) by alice")
+
+	// ошибки команды Slack показывает автору, только если ответ 200
+	body = command("reassign PR-123 @alice")
+	code, res = do(t, r, ts, sign(ts, body), body)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.SlashReply{ResponseType: "ephemeral", Text: "Error: reviewer is not assigned to this PR"}, res)
+
+	body = command("reassign PR-123 @bob")
+	code, res = do(t, r, ts, sign(ts, body), body)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "in_channel", res.ResponseType)
+	require.Contains(t, res.Text, "*bob* is no longer a reviewer of *Fix*")
+}
+
+func TestSlack_Signature(t *testing.T) {
+	r := setup(t, secret)
+	body := command("reviews")
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	for name, tc := range map[string]struct{ timestamp, signature string }{
+		"wrong secret":      {now, "v0=" + strings.Repeat("0", 64)},
+		"no prefix":         {now, strings.TrimPrefix(sign(now, body), "v0=")},
+		"replayed":          {stale, sign(stale, body)},
+		"no timestamp":      {"", sign("", body)},
+		"tampered body":     {now, sign(now, command("away"))},
+		"timestamp swapped": {stale, sign(now, body)},
+	} {
+		code, _ := do(t, r, tc.timestamp, tc.signature, body)
+		require.Equal(t, http.StatusUnauthorized, code, name)
+	}
+
+	code, _ := do(t, r, now, sign(now, "text=reviews"), "text=reviews")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestSlack_DisabledWithoutSecret(t *testing.T) {
+	r := setup(t, "")
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	body := command("reviews")
+
+	code, _ := do(t, r, ts, sign(ts, body), body)
+	require.Equal(t, http.StatusNotFound, code)
+}
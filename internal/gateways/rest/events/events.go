@@ -0,0 +1,101 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keepAlive — как часто в простаивающий поток пишется комментарий, чтобы
+// прокси не закрывали соединение.
+const keepAlive = 15 * time.Second
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	g := v1.Group("/events")
+
+	g.GET("/stream", stream(cases.Events))
+}
+
+// @Summary Поток событий PR (Server-Sent Events)
+// @Description События reviewers.assigned (создание PR с назначенными ревьюверами), reviewer.reassigned и
+// @Description pull_request.merged в формате text/event-stream: id — номер события, event — тип, data — событие в JSON.
+// @Description team_name оставляет события PR команды, user_id — события, где пользователь автор или ревьювер.
+// @Description Переподключение с заголовком Last-Event-ID (или last_event_id) сначала отдаёт пропущенные события.
+// @Description Отставший клиент отключается и продолжает с Last-Event-ID.
+// @Tags Events
+// @Produce text/event-stream
+// @Param query query domain.EventStreamQuery false "Фильтры и место возобновления"
+// @Param Last-Event-ID header string false "id последнего полученного события"
+// @Success 200 {string} string "поток событий"
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /events/stream [get]
+func stream(feed *usecase.EventFeed) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.EventStreamQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		after := query.LastEventId
+		if h := c.GetHeader("Last-Event-ID"); h != "" {
+			id, err := strconv.ParseInt(h, 10, 64)
+			if err != nil || id < 0 {
+				apierr.BadRequest(c, "invalid Last-Event-ID")
+				return
+			}
+			after = &id
+		}
+
+		ctx := c.Request.Context()
+		events := feed.Watch(ctx, &domain.EventFilter{TeamName: query.TeamName, UserId: query.UserId}, after)
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		ping := time.NewTicker(keepAlive)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := writeEvent(c.Writer, event); err != nil {
+					return
+				}
+			case <-ping.C:
+				if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event *domain.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Seq, event.Type, data)
+	return err
+}
@@ -0,0 +1,234 @@
+package events_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/rest/events"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+type env struct {
+	server *httptest.Server
+	prCase *usecase.PullRequest
+	relay  *usecase.Relay
+	feed   *usecase.EventFeed
+}
+
+func setup(t *testing.T) *env {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+	outbox := memory.NewOutboxRepo(db)
+
+	teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+	for _, team := range []*domain.TeamAddInput{
+		{TeamName: "backend", Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		}},
+		{TeamName: "frontend", Members: []domain.TeamAddMemberInput{
+			{UserID: "u3", Username: "carol", IsActive: true},
+			{UserID: "u4", Username: "dave", IsActive: true},
+		}},
+	} {
+		_, err := teamCase.AddTeam(ctx, team)
+		require.NoError(t, err)
+	}
+
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1)
+	prCase.SetOutbox(memory.NewTransactor(db), outbox)
+	feed := usecase.NewEventFeed(outbox, 0)
+
+	r := gin.New()
+	events.Setup(r.Group("/api/v1"), usecase.Cases{Events: feed})
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &env{
+		server: server,
+		prCase: prCase,
+		relay:  usecase.NewRelay(outbox, nil, 0, 0),
+		feed:   feed,
+	}
+}
+
+// publish публикует накопившиеся события outbox и раздаёт их потокам.
+func (e *env) publish(t *testing.T) {
+	t.Helper()
+
+	ctx := context.Background()
+	_, err := e.relay.Flush(ctx)
+	require.NoError(t, err)
+	_, err = e.feed.Poll(ctx)
+	require.NoError(t, err)
+}
+
+type sseEvent struct {
+	Id    string
+	Type  string
+	Event domain.Event
+}
+
+// open подключается к потоку и возвращает канал разобранных событий. Заголовки
+// ответа приходят после подписки на раздачу, поэтому события, опубликованные
+// после open, поток не пропустит.
+func (e *env) open(t *testing.T, query string, header http.Header) <-chan sseEvent {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.server.URL+"/api/v1/events/stream"+query, nil)
+	require.NoError(t, err)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	out := make(chan sseEvent, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		var cur sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				cur.Id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				cur.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				_ = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &cur.Event)
+			case line == "" && cur.Id != "":
+				out <- cur
+				cur = sseEvent{}
+			}
+		}
+	}()
+
+	return out
+}
+
+func next(t *testing.T, ch <-chan sseEvent) sseEvent {
+	t.Helper()
+
+	select {
+	case e, ok := <-ch:
+		require.True(t, ok, "stream closed")
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+		return sseEvent{}
+	}
+}
+
+func (e *env) create(t *testing.T, id, author string) {
+	t.Helper()
+
+	_, err := e.prCase.Create(context.Background(), &domain.CreatePullRequest{Id: id, AuthorId: author, Name: id})
+	require.NoError(t, err)
+}
+
+func TestStream_LiveWithFilters(t *testing.T) {
+	e := setup(t)
+
+	all := e.open(t, "", nil)
+	frontend := e.open(t, "?team_name=frontend", nil)
+	bob := e.open(t, "?user_id=u2", nil)
+
+	e.create(t, "pr-1", "u1")
+	e.create(t, "pr-2", "u3")
+	_, err := e.prCase.Merge(context.Background(), &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+	e.publish(t)
+
+	got := next(t, all)
+	require.Equal(t, "1", got.Id)
+	require.Equal(t, "reviewers.assigned", got.Type)
+	require.Equal(t, "pr-1", got.Event.PullRequest.Id)
+	require.Equal(t, []string{"u2"}, got.Event.Reviewers)
+	require.Equal(t, "pr-2", next(t, all).Event.PullRequest.Id)
+	got = next(t, all)
+	require.Equal(t, "3", got.Id)
+	require.Equal(t, "pull_request.merged", got.Type)
+
+	got = next(t, frontend)
+	require.Equal(t, "pr-2", got.Event.PullRequest.Id)
+	require.Equal(t, "frontend", got.Event.PullRequest.TeamName)
+
+	// bob — ревьювер pr-1
+	require.Equal(t, "1", next(t, bob).Id)
+	require.Equal(t, "3", next(t, bob).Id)
+}
+
+func TestStream_ResumeFromLastEventID(t *testing.T) {
+	e := setup(t)
+
+	e.create(t, "pr-1", "u1")
+	e.create(t, "pr-2", "u1")
+	e.create(t, "pr-3", "u1")
+	e.publish(t)
+
+	resumed := e.open(t, "", http.Header{"Last-Event-Id": {"1"}})
+	require.Equal(t, "2", next(t, resumed).Id)
+	require.Equal(t, "3", next(t, resumed).Id)
+
+	// после пропущенных — новые события
+	e.create(t, "pr-4", "u1")
+	e.publish(t)
+	got := next(t, resumed)
+	require.Equal(t, "4", got.Id)
+	require.Equal(t, "pr-4", got.Event.PullRequest.Id)
+
+	byQuery := e.open(t, "?last_event_id=3&team_name=backend", nil)
+	require.Equal(t, "4", next(t, byQuery).Id)
+}
+
+func TestStream_BadRequest(t *testing.T) {
+	e := setup(t)
+
+	for _, tc := range []struct {
+		query  string
+		header string
+	}{
+		{query: "?last_event_id=-1"},
+		{query: "?last_event_id=abc"},
+		{header: "abc"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, e.server.URL+"/api/v1/events/stream"+tc.query, nil)
+		require.NoError(t, err)
+		if tc.header != "" {
+			req.Header.Set("Last-Event-ID", tc.header)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode, tc)
+	}
+}
@@ -0,0 +1,191 @@
+package identity
+
+import (
+	"net/http"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	g := v1.Group("/identities")
+
+	g.POST("/add", addIdentity(cases.Identity))
+	g.GET("/list", listIdentities(cases.Identity))
+	g.POST("/update", updateIdentity(cases.Identity))
+	g.POST("/delete", deleteIdentity(cases.Identity))
+	g.GET("/lookup", lookupIdentity(cases.Identity))
+}
+
+// @Summary Привязать учётную запись провайдера к пользователю
+// @Description Логин сохраняется в нижнем регистре. У пользователя может быть несколько
+// @Description учётных записей, в том числе у одного провайдера.
+// @Tags Identities
+// @Accept json
+// @Produce json
+// @Param body body domain.IdentityAddInput true "Пользователь, провайдер, логин и числовой id"
+// @Success 201 {object} map[string]dto.Identity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /identities/add [post]
+func addIdentity(identityCase *usecase.Identity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.IdentityAddInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		identity, err := identityCase.Add(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"identity": convertIdentity(identity)})
+	}
+}
+
+// @Summary Учётные записи пользователя
+// @Tags Identities
+// @Produce json
+// @Param query query domain.IdentityListQuery true "User id"
+// @Success 200 {object} dto.IdentityList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /identities/list [get]
+func listIdentities(identityCase *usecase.Identity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.IdentityListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		identities, err := identityCase.List(c, query.UserId)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		res := make([]dto.Identity, 0, len(identities))
+		for _, i := range identities {
+			res = append(res, convertIdentity(i))
+		}
+
+		c.JSON(http.StatusOK, dto.IdentityList{
+			UserID:     query.UserId,
+			Identities: res,
+		})
+	}
+}
+
+// @Summary Изменить числовой id учётной записи
+// @Description external_id 0 или отсутствующий сбрасывает id.
+// @Tags Identities
+// @Accept json
+// @Produce json
+// @Param body body domain.IdentityUpdateInput true "Провайдер, логин и новый числовой id"
+// @Success 200 {object} map[string]dto.Identity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /identities/update [post]
+func updateIdentity(identityCase *usecase.Identity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.IdentityUpdateInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		identity, err := identityCase.Update(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"identity": convertIdentity(identity)})
+	}
+}
+
+// @Summary Отвязать учётную запись провайдера
+// @Tags Identities
+// @Accept json
+// @Produce json
+// @Param body body domain.IdentityDeleteInput true "Провайдер и логин"
+// @Success 200 {object} map[string]dto.Identity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /identities/delete [post]
+func deleteIdentity(identityCase *usecase.Identity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.IdentityDeleteInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		identity, err := identityCase.Delete(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"identity": convertIdentity(identity)})
+	}
+}
+
+// @Summary Найти пользователя по логину или числовому id у провайдера
+// @Description Кроме привязанных учётных записей учитываются логины из WEBHOOK_*_LOGINS.
+// @Description Неизвестная учётная запись — 404 UNKNOWN_IDENTITY.
+// @Tags Identities
+// @Produce json
+// @Param query query domain.IdentityLookupQuery true "Провайдер и логин или числовой id"
+// @Success 200 {object} map[string]dto.Identity
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /identities/lookup [get]
+func lookupIdentity(identityCase *usecase.Identity) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.IdentityLookupQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		identity, err := identityCase.Lookup(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"identity": convertIdentity(identity)})
+	}
+}
+
+func convertIdentity(i *domain.Identity) dto.Identity {
+	var createdAt string
+	if !i.CreatedAt.IsZero() {
+		createdAt = i.CreatedAt.Format(time.RFC3339)
+	}
+
+	return dto.Identity{
+		UserID:     i.UserId,
+		Provider:   string(i.Provider),
+		Login:      i.Login,
+		ExternalID: i.ExternalId,
+		CreatedAt:  createdAt,
+	}
+}
@@ -0,0 +1,115 @@
+package identity_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/identity"
+	"gopr/internal/gateways/rest/testhelpers"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+func setup(t *testing.T) testhelpers.Client {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	require.NoError(t, userRepo.Create(context.Background(), &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+	require.NoError(t, userRepo.Create(context.Background(), &domain.User{Id: "u2", Username: "bob", IsActive: true}))
+
+	cases := usecase.Cases{
+		Identity: usecase.NewIdentity(memory.NewIdentityRepo(db), userRepo, map[domain.IdentityProvider]map[string]string{
+			domain.IdentityGitLab: {"Bobby": "u2"},
+		}),
+	}
+
+	r := gin.New()
+	identity.Setup(r.Group("/api/v1"), cases)
+	return testhelpers.Client{Handler: r, Prefix: "/api/v1/identities"}
+}
+
+func TestIdentities_CRUD(t *testing.T) {
+	r := setup(t)
+
+	code, res := r.Do(t, http.MethodPost, "/add", `{"user_id":"u1","provider":"github","login":"Alice-GH","external_id":583231}`)
+	require.Equal(t, http.StatusCreated, code)
+	added := testhelpers.Decode[dto.Identity](t, res["identity"])
+	require.Equal(t, "alice-gh", added.Login)
+	require.EqualValues(t, 583231, added.ExternalID)
+
+	code, _ = r.Do(t, http.MethodPost, "/add", `{"user_id":"u1","provider":"email","login":"alice@example.com"}`)
+	require.Equal(t, http.StatusCreated, code)
+
+	code, res = r.Do(t, http.MethodPost, "/add", `{"user_id":"u2","provider":"github","login":"alice-gh"}`)
+	require.Equal(t, http.StatusConflict, code)
+	require.Equal(t, "ALREADY_EXISTS", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodPost, "/add", `{"user_id":"missing","provider":"github","login":"ghost"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodPost, "/add", `{"user_id":"u1","provider":"bitbucket","login":"alice"}`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "VALIDATION_ERROR", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodGet, "/list?user_id=u1", "")
+	require.Equal(t, http.StatusOK, code)
+	list := testhelpers.Decode[[]dto.Identity](t, res["identities"])
+	require.Len(t, list, 2)
+	require.Equal(t, "github", list[0].Provider)
+	require.Equal(t, "email", list[1].Provider)
+
+	code, res = r.Do(t, http.MethodPost, "/update", `{"provider":"github","login":"ALICE-GH","external_id":42}`)
+	require.Equal(t, http.StatusOK, code)
+	require.EqualValues(t, 42, testhelpers.Decode[dto.Identity](t, res["identity"]).ExternalID)
+
+	code, res = r.Do(t, http.MethodPost, "/delete", `{"provider":"email","login":"Alice@Example.com"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "u1", testhelpers.Decode[dto.Identity](t, res["identity"]).UserID)
+
+	code, res = r.Do(t, http.MethodPost, "/delete", `{"provider":"email","login":"alice@example.com"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+}
+
+func TestIdentities_Lookup(t *testing.T) {
+	r := setup(t)
+
+	code, _ := r.Do(t, http.MethodPost, "/add", `{"user_id":"u1","provider":"github","login":"alice-gh","external_id":583231}`)
+	require.Equal(t, http.StatusCreated, code)
+
+	code, res := r.Do(t, http.MethodGet, "/lookup?provider=github&login=Alice-GH", "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "u1", testhelpers.Decode[dto.Identity](t, res["identity"]).UserID)
+
+	code, res = r.Do(t, http.MethodGet, "/lookup?provider=github&external_id=583231", "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "alice-gh", testhelpers.Decode[dto.Identity](t, res["identity"]).Login)
+
+	// логины из конфига находятся так же, как привязанные
+	code, res = r.Do(t, http.MethodGet, "/lookup?provider=gitlab&login=bobby", "")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "u2", testhelpers.Decode[dto.Identity](t, res["identity"]).UserID)
+
+	code, res = r.Do(t, http.MethodGet, "/lookup?provider=gitlab&login=alice-gh", "")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "UNKNOWN_IDENTITY", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodGet, "/lookup?provider=github&external_id=1", "")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "UNKNOWN_IDENTITY", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodGet, "/lookup?provider=github", "")
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "VALIDATION_ERROR", testhelpers.ErrorCode(t, res))
+}
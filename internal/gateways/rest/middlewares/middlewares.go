@@ -2,15 +2,18 @@ package middlewares
 
 import (
 	"context"
+	"gopr/internal/domain"
+	"gopr/internal/gateways/rest/apierr"
 	"gopr/pkg/slogx"
 	"log/slog"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func AllowOrigin() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		allowHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding"
+		allowHeaders := "Accept, Content-Type, Content-Length, Accept-Encoding, X-Actor-Id, X-Request-Id"
 
 		c.Header("Access-Control-Allow-Origin", c.GetHeader("Origin"))
 		c.Header("Access-Control-Allow-Credentials", "true")
@@ -28,8 +31,12 @@ func AllowOrigin() gin.HandlerFunc {
 }
 
 func Logger(ctx context.Context) gin.HandlerFunc {
-	log := slogx.FromCtx(ctx)
+	base := slogx.FromCtx(ctx)
 	return func(c *gin.Context) {
+		log := base
+		if id := c.GetString(domain.RequestIDCtxKey); id != "" {
+			log = log.With(slog.String("request_id", id))
+		}
 		slogx.InjectGin(c, log)
 		log.Info("Received request",
 			slog.String("method", c.Request.Method),
@@ -38,3 +45,41 @@ func Logger(ctx context.Context) gin.HandlerFunc {
 		)
 	}
 }
+
+const (
+	actorHeader     = "X-Actor-Id"
+	requestIDHeader = "X-Request-Id"
+	// maxRequestID — предел длины id запроса от клиента; длиннее — генерируется свой.
+	maxRequestID = 128
+)
+
+// Actor кладёт в контекст запроса инициатора из заголовка X-Actor-Id. Заголовок
+// ничем не подтверждён, поэтому это только заявка: в журнале аудита она пишется
+// отдельно от инициатора. Id, которые занял сам gopr, отклоняются.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := c.GetHeader(actorHeader)
+		if domain.IsReservedActor(actor) {
+			apierr.BadRequest(c, actorHeader+" "+actor+" is reserved")
+			return
+		}
+		if actor != "" {
+			c.Set(domain.ClaimedActorCtxKey, actor)
+		}
+		c.Next()
+	}
+}
+
+// RequestID кладёт в контекст запроса id из заголовка X-Request-Id или новый
+// и возвращает его в ответе тем же заголовком.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" || len(id) > maxRequestID {
+			id = uuid.NewString()
+		}
+		c.Set(domain.RequestIDCtxKey, id)
+		c.Header(requestIDHeader, id)
+		c.Next()
+	}
+}
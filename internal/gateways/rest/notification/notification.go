@@ -0,0 +1,265 @@
+package notification
+
+import (
+	"net/http"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	g := v1.Group("/notifications/preferences")
+
+	g.GET("/list", listPreferences(cases.Notification))
+	g.POST("/set", setPreference(cases.Notification))
+	g.POST("/delete", deletePreference(cases.Notification))
+
+	d := v1.Group("/notifications/digest")
+
+	d.GET("/get", getDigest(cases.Notification))
+	d.POST("/set", setDigest(cases.Notification))
+	d.GET("/preview", previewDigest(cases.Notification))
+}
+
+// @Summary Каналы оповещений пользователя
+// @Tags Notifications
+// @Produce json
+// @Param query query domain.NotificationPreferenceListQuery true "User id"
+// @Success 200 {object} dto.NotificationPreferenceList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/preferences/list [get]
+func listPreferences(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.NotificationPreferenceListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		prefs, err := notificationCase.ListPreferences(c, query.UserId)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, convertPreferences(query.UserId, prefs))
+	}
+}
+
+// @Summary Включить канал оповещений или изменить его адрес
+// @Description Адрес — почта для email и URL для slack и http. Без адреса письма уходят
+// @Description на почту из учётной записи email пользователя, а slack и http — на адреса
+// @Description из настроек сервера.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body domain.NotificationPreferenceSetInput true "Пользователь, канал и адрес"
+// @Success 200 {object} map[string]dto.NotificationPreference
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/preferences/set [post]
+func setPreference(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.NotificationPreferenceSetInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		pref, err := notificationCase.SetPreference(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"preference": convertPreference(pref)})
+	}
+}
+
+// @Summary Отключить канал оповещений
+// @Description Возвращает оставшиеся каналы пользователя.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body domain.NotificationPreferenceDeleteInput true "Пользователь и канал"
+// @Success 200 {object} dto.NotificationPreferenceList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/preferences/delete [post]
+func deletePreference(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.NotificationPreferenceDeleteInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		prefs, err := notificationCase.DeletePreference(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, convertPreferences(input.UserID, prefs))
+	}
+}
+
+// @Summary Настройки ежедневной сводки пользователя
+// @Description Без сохранённых настроек сводка выключена, час отправки — 9 по UTC.
+// @Tags Notifications
+// @Produce json
+// @Param query query domain.DigestQuery true "User id"
+// @Success 200 {object} map[string]dto.DigestSettings
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/digest/get [get]
+func getDigest(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.DigestQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		settings, err := notificationCase.GetDigest(c, query.UserId)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"digest": convertDigestSettings(settings)})
+	}
+}
+
+// @Summary Включить или настроить ежедневную сводку
+// @Description Сводка уходит по каналам оповещений пользователя раз в сутки, начиная с hour
+// @Description по его часовому поясу, но не в тихие часы [quiet_hours_start, quiet_hours_end).
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param body body domain.DigestSettingsInput true "Настройки сводки"
+// @Success 200 {object} map[string]dto.DigestSettings
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/digest/set [post]
+func setDigest(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.DigestSettingsInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		settings, err := notificationCase.SetDigest(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"digest": convertDigestSettings(settings)})
+	}
+}
+
+// @Summary Предпросмотр сводки
+// @Description Отрисовывает сводку на текущий момент, не отправляя её, даже если сводка выключена.
+// @Tags Notifications
+// @Produce json
+// @Param query query domain.DigestQuery true "User id"
+// @Success 200 {object} dto.Digest
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /notifications/digest/preview [get]
+func previewDigest(notificationCase *usecase.Notification) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.DigestQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		digest, err := notificationCase.PreviewDigest(c, query.UserId)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, convertDigest(digest))
+	}
+}
+
+func convertPreferences(userID string, prefs []*domain.NotificationPreference) dto.NotificationPreferenceList {
+	res := make([]dto.NotificationPreference, 0, len(prefs))
+	for _, p := range prefs {
+		res = append(res, convertPreference(p))
+	}
+
+	return dto.NotificationPreferenceList{
+		UserID:      userID,
+		Preferences: res,
+	}
+}
+
+func convertPreference(p *domain.NotificationPreference) dto.NotificationPreference {
+	return dto.NotificationPreference{
+		Channel:   string(p.Channel),
+		Address:   p.Address,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func convertDigestSettings(s *domain.DigestSettings) dto.DigestSettings {
+	var lastSentAt string
+	if s.LastSentAt != nil {
+		lastSentAt = s.LastSentAt.Format(time.RFC3339)
+	}
+
+	return dto.DigestSettings{
+		UserID:     s.UserId,
+		Enabled:    s.Enabled,
+		Timezone:   s.Timezone,
+		Hour:       s.Hour,
+		QuietStart: s.QuietStart,
+		QuietEnd:   s.QuietEnd,
+		LastSentAt: lastSentAt,
+	}
+}
+
+func convertDigest(d *domain.Digest) dto.Digest {
+	items := make([]dto.DigestItem, 0, len(d.Items))
+	for _, it := range d.Items {
+		items = append(items, dto.DigestItem{
+			PullRequestID: it.PullRequestId,
+			Name:          it.Name,
+			AuthorID:      it.AuthorId,
+			Author:        it.Author,
+			TeamName:      it.TeamName,
+			CreatedAt:     it.CreatedAt.Format(time.RFC3339),
+			AgeSeconds:    it.AgeSeconds,
+			Overdue:       it.Overdue,
+		})
+	}
+
+	return dto.Digest{
+		UserID:      d.UserId,
+		Username:    d.Username,
+		Timezone:    d.Timezone,
+		GeneratedAt: d.GeneratedAt.Format(time.RFC3339),
+		Items:       items,
+		Overdue:     d.Overdue,
+		Subject:     d.Subject,
+		Text:        d.Text,
+	}
+}
@@ -0,0 +1,138 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/notification"
+	"gopr/internal/gateways/rest/testhelpers"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+func setup(t *testing.T) testhelpers.Client {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	require.NoError(t, userRepo.Create(context.Background(), &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+
+	cases := usecase.Cases{
+		Notification: usecase.NewNotification(
+			memory.NewNotificationRepo(db),
+			userRepo,
+			memory.NewPullRequestRepo(db),
+			memory.NewIdentityRepo(db),
+			nil,
+			0,
+		),
+	}
+
+	r := gin.New()
+	notification.Setup(r.Group("/api/v1"), cases)
+	return testhelpers.Client{Handler: r, Prefix: "/api/v1/notifications"}
+}
+
+func TestPreferences_CRUD(t *testing.T) {
+	r := setup(t)
+
+	code, body := r.DoRaw(t, http.MethodPost, "/preferences/set", `{"user_id":"u1","channel":"slack","address":"https://hooks.slack.com/services/T/B/X"}`)
+	require.Equal(t, http.StatusOK, code, string(body))
+	var set map[string]dto.NotificationPreference
+	require.NoError(t, json.Unmarshal(body, &set))
+	require.Equal(t, "slack", set["preference"].Channel)
+	require.NotEmpty(t, set["preference"].CreatedAt)
+
+	code, _ = r.DoRaw(t, http.MethodPost, "/preferences/set", `{"user_id":"u1","channel":"email"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	code, body = r.DoRaw(t, http.MethodGet, "/preferences/list?user_id=u1", "")
+	require.Equal(t, http.StatusOK, code)
+	var list dto.NotificationPreferenceList
+	require.NoError(t, json.Unmarshal(body, &list))
+	require.Equal(t, "u1", list.UserID)
+	require.Len(t, list.Preferences, 2)
+	require.Equal(t, "email", list.Preferences[0].Channel)
+
+	code, body = r.DoRaw(t, http.MethodPost, "/preferences/delete", `{"user_id":"u1","channel":"email"}`)
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(body, &list))
+	require.Len(t, list.Preferences, 1)
+
+	code, res := r.Do(t, http.MethodPost, "/preferences/delete", `{"user_id":"u1","channel":"email"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+}
+
+func TestPreferences_Errors(t *testing.T) {
+	r := setup(t)
+
+	for _, tc := range []struct {
+		body   string
+		status int
+		code   string
+	}{
+		{`{"user_id":"u1","channel":"sms"}`, http.StatusBadRequest, "VALIDATION_ERROR"},
+		{`{"user_id":"u1","channel":"email","address":"nope"}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{`{"user_id":"u1","channel":"http","address":"ftp://example.com"}`, http.StatusBadRequest, "BAD_REQUEST"},
+		{`{"user_id":"missing","channel":"slack"}`, http.StatusNotFound, "NOT_FOUND"},
+	} {
+		code, res := r.Do(t, http.MethodPost, "/preferences/set", tc.body)
+		require.Equal(t, tc.status, code, tc.body)
+		require.Equal(t, tc.code, testhelpers.ErrorCode(t, res), tc.body)
+	}
+
+	code, _ := r.DoRaw(t, http.MethodGet, "/preferences/list", "")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestDigest_SetAndPreview(t *testing.T) {
+	r := setup(t)
+
+	code, body := r.DoRaw(t, http.MethodGet, "/digest/get?user_id=u1", "")
+	require.Equal(t, http.StatusOK, code, string(body))
+	var got map[string]dto.DigestSettings
+	require.NoError(t, json.Unmarshal(body, &got))
+	require.False(t, got["digest"].Enabled)
+	require.Equal(t, "UTC", got["digest"].Timezone)
+
+	code, body = r.DoRaw(t, http.MethodPost, "/digest/set", `{"user_id":"u1","enabled":true,"timezone":"Europe/Berlin","hour":8,"quiet_hours_start":22,"quiet_hours_end":7}`)
+	require.Equal(t, http.StatusOK, code, string(body))
+	require.NoError(t, json.Unmarshal(body, &got))
+	require.True(t, got["digest"].Enabled)
+	require.Equal(t, 22, *got["digest"].QuietStart)
+
+	for _, tc := range []struct {
+		body string
+		code string
+	}{
+		{`{"user_id":"u1","hour":24}`, "VALIDATION_ERROR"},
+		{`{"user_id":"u1","timezone":"Nowhere/City"}`, "BAD_REQUEST"},
+		{`{"user_id":"u1","quiet_hours_start":22}`, "BAD_REQUEST"},
+	} {
+		code, res := r.Do(t, http.MethodPost, "/digest/set", tc.body)
+		require.Equal(t, http.StatusBadRequest, code, tc.body)
+		require.Equal(t, tc.code, testhelpers.ErrorCode(t, res), tc.body)
+	}
+
+	code, body = r.DoRaw(t, http.MethodGet, "/digest/preview?user_id=u1", "")
+	require.Equal(t, http.StatusOK, code, string(body))
+	var digest dto.Digest
+	require.NoError(t, json.Unmarshal(body, &digest))
+	require.Equal(t, "Europe/Berlin", digest.Timezone)
+	require.Empty(t, digest.Items)
+	require.Equal(t, "alice, no pull requests are waiting for your review.", digest.Text)
+
+	code, _ = r.DoRaw(t, http.MethodGet, "/digest/preview?user_id=missing", "")
+	require.Equal(t, http.StatusNotFound, code)
+}
@@ -1,12 +1,12 @@
 package pullrequest
 
 import (
-	"errors"
 	"net/http"
 	"time"
 
 	"gopr/internal/domain"
 	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
 	"gopr/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -18,6 +18,8 @@ func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 	g.POST("/create", addPR(cases.PullRequest))
 	g.POST("/merge", mergePR(cases.PullRequest))
 	g.POST("/reassign", reassignPR(cases.PullRequest))
+	g.GET("/list", listPR(cases.PullRequest))
+	g.GET("/get", getPR(cases.PullRequest))
 }
 
 // @Summary Создать PR и автоматически назначить до 2 ревьюверов из команды автора
@@ -26,31 +28,22 @@ func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 // @Produce json
 // @Param pr body domain.CreatePullRequest true "PR create payload"
 // @Success 201 {object} map[string]dto.PullRequest
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /pullRequest/create [post]
 func addPR(prCase *usecase.PullRequest) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		input := &domain.CreatePullRequest{}
 		if err := c.ShouldBindJSON(input); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "invalid json",
-				},
-			})
+			apierr.RenderBind(c, err, "invalid json")
 			return
 		}
 
 		res, err := prCase.Create(c, input)
 		if err != nil {
-			// тут можно разрулить код ошибки, если будешь прокидывать из usecase
-			c.JSON(http.StatusConflict, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "PR_EXISTS",
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
 			return
 		}
 
@@ -64,29 +57,21 @@ func addPR(prCase *usecase.PullRequest) gin.HandlerFunc {
 // @Produce json
 // @Param pr body domain.MergePullRequest true "Merge request"
 // @Success 200 {object} map[string]dto.PullRequest
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /pullRequest/merge [post]
 func mergePR(prCase *usecase.PullRequest) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		input := &domain.MergePullRequest{}
 		if err := c.ShouldBindJSON(input); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "invalid json",
-				},
-			})
+			apierr.RenderBind(c, err, "invalid json")
 			return
 		}
 
 		res, err := prCase.Merge(c, input)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "NOT_FOUND",
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
 			return
 		}
 
@@ -100,43 +85,22 @@ func mergePR(prCase *usecase.PullRequest) gin.HandlerFunc {
 // @Produce json
 // @Param reassign body domain.ReassignPullRequest true "Reassign payload"
 // @Success 200 {object} dto.PullRequestReassignResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /pullRequest/reassign [post]
 func reassignPR(prCase *usecase.PullRequest) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		input := &domain.ReassignPullRequest{}
 		if err := c.ShouldBindJSON(input); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "invalid json",
-				},
-			})
+			apierr.RenderBind(c, err, "invalid json")
 			return
 		}
 
 		res, newReviewer, err := prCase.Reassign(c, input)
 		if err != nil {
-			code := "PR_ERROR"
-
-			switch {
-			case errors.Is(err, usecase.ErrPRMerged):
-				code = "PR_MERGED"
-			case errors.Is(err, usecase.ErrNotAssigned):
-				code = "NOT_ASSIGNED"
-			case errors.Is(err, usecase.ErrNoCandidate):
-				code = "NO_CANDIDATE"
-			default:
-				code = "REASSIGN_ERROR"
-			}
-
-			c.JSON(http.StatusConflict, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    code,
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
 			return
 		}
 
@@ -149,6 +113,95 @@ func reassignPR(prCase *usecase.PullRequest) gin.HandlerFunc {
 	}
 }
 
+// @Summary Поиск PR по организации с keyset-пагинацией
+// @Tags PullRequests
+// @Produce json
+// @Param query query domain.PullRequestListQuery false "Фильтры и пагинация"
+// @Success 200 {object} dto.PullRequestList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pullRequest/list [get]
+func listPR(prCase *usecase.PullRequest) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.PullRequestListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		page, err := prCase.List(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		prs := make([]dto.PullRequest, 0, len(page.PRs))
+		for _, p := range page.PRs {
+			prs = append(prs, convertPR(p))
+		}
+
+		c.JSON(http.StatusOK, dto.PullRequestList{
+			PullRequests: prs,
+			NextCursor:   page.NextCursor,
+		})
+	}
+}
+
+// @Summary Получить PR с ревьюверами и историей назначений
+// @Tags PullRequests
+// @Produce json
+// @Param query query domain.GetPullRequestQuery true "PR id"
+// @Success 200 {object} dto.PullRequestDetails
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pullRequest/get [get]
+func getPR(prCase *usecase.PullRequest) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.GetPullRequestQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		res, err := prCase.Get(c, query.Id)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		timeline := make([]dto.TimelineEvent, 0, len(res.Timeline))
+		for _, ev := range res.Timeline {
+			timeline = append(timeline, dto.TimelineEvent{
+				Type:       string(ev.Type),
+				ReviewerID: ev.ReviewerId,
+				Actor:      ev.Actor,
+				Reason:     ev.Reason,
+				At:         ev.CreatedAt.Format(time.RFC3339),
+			})
+		}
+
+		var sync *dto.ReviewSync
+		if res.ReviewSync != nil {
+			sync = &dto.ReviewSync{
+				Status:    string(res.ReviewSync.Status),
+				Attempts:  res.ReviewSync.Attempts,
+				LastError: res.ReviewSync.LastError,
+				UpdatedAt: res.ReviewSync.UpdatedAt.Format(time.RFC3339),
+			}
+		}
+
+		c.JSON(http.StatusOK, dto.PullRequestDetails{
+			PR: convertPR(&domain.PullRequestWithReviewers{
+				PR:        res.PR,
+				Reviewers: res.Reviewers,
+			}),
+			Timeline:   timeline,
+			ReviewSync: sync,
+		})
+	}
+}
+
 func convertPR(p *domain.PullRequestWithReviewers) dto.PullRequest {
 	var createdAt, mergedAt *string
 
@@ -162,12 +215,18 @@ func convertPR(p *domain.PullRequestWithReviewers) dto.PullRequest {
 		mergedAt = &s
 	}
 
+	reviewers := p.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
 	return dto.PullRequest{
 		PullRequestID:     p.PR.Id,
 		PullRequestName:   p.PR.Name,
 		AuthorID:          p.PR.AuthorId,
+		TeamName:          p.PR.TeamName,
 		Status:            p.PR.Status,
-		AssignedReviewers: p.Reviewers,
+		AssignedReviewers: reviewers,
 		CreatedAt:         createdAt,
 		MergedAt:          mergedAt,
 	}
@@ -2,11 +2,20 @@ package rest
 
 import (
 	"context"
+	"gopr/cmd/config"
 	"gopr/docs"
+	"gopr/internal/gateways/rest/audit"
+	"gopr/internal/gateways/rest/chatops"
+	"gopr/internal/gateways/rest/events"
+	"gopr/internal/gateways/rest/identity"
 	"gopr/internal/gateways/rest/middlewares"
+	"gopr/internal/gateways/rest/notification"
 	"gopr/internal/gateways/rest/pullrequest"
+	"gopr/internal/gateways/rest/stats"
+	"gopr/internal/gateways/rest/subscription"
 	"gopr/internal/gateways/rest/team"
 	"gopr/internal/gateways/rest/user"
+	"gopr/internal/gateways/rest/webhook"
 	"gopr/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -14,10 +23,12 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func setupRouter(ctx context.Context, r *gin.Engine, useCases usecase.Cases) {
+func setupRouter(ctx context.Context, r *gin.Engine, cfg *config.Config, useCases usecase.Cases) {
 	r.HandleMethodNotAllowed = true
 	r.Use(middlewares.AllowOrigin())
+	r.Use(middlewares.RequestID())
 	r.Use(middlewares.Logger(ctx))
+	r.Use(middlewares.Actor())
 
 	// Swagger
 	docs.SwaggerInfo.BasePath = "/api/v1"
@@ -28,4 +39,12 @@ func setupRouter(ctx context.Context, r *gin.Engine, useCases usecase.Cases) {
 	user.Setup(v1, useCases)
 	team.Setup(v1, useCases)
 	pullrequest.Setup(v1, useCases)
+	stats.Setup(v1, useCases)
+	identity.Setup(v1, useCases)
+	subscription.Setup(v1, useCases)
+	notification.Setup(v1, useCases)
+	events.Setup(v1, useCases)
+	webhook.Setup(v1, useCases, cfg)
+	chatops.Setup(v1, useCases, cfg)
+	audit.Setup(v1, useCases)
 }
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"gopr/cmd/config"
+	"gopr/internal/gateways/rest/validation"
 	"gopr/internal/usecase"
+	"gopr/pkg/slogx"
 	"net/http"
 	"time"
 
@@ -21,6 +23,10 @@ type Server struct {
 }
 
 func NewServer(ctx context.Context, cfg *config.Config, useCases usecase.Cases) *Server {
+	if err := validation.Setup(); err != nil {
+		slogx.Fatal(slogx.FromCtx(ctx), "can't setup request validation", slogx.Err(err))
+	}
+
 	r := gin.New()
 	r.Use(gin.Recovery())
 
@@ -32,7 +38,7 @@ func NewServer(ctx context.Context, cfg *config.Config, useCases usecase.Cases)
 		},
 	}
 
-	setupRouter(ctx, s.Router, useCases)
+	setupRouter(ctx, s.Router, cfg, useCases)
 
 	return s
 }
@@ -0,0 +1,220 @@
+package stats
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	g := v1.Group("/stats")
+
+	g.GET("/users", userStats(cases.Stats))
+	g.GET("/teams", teamStats(cases.Stats))
+	g.GET("/mergeTime", mergeTime(cases.Stats))
+	g.GET("/fairness", fairness(cases.Fairness))
+}
+
+// @Summary Нагрузка ревьюверов за период
+// @Description Считаются PR, созданные в [from, to); по умолчанию последние 30 дней.
+// @Tags Stats
+// @Produce json
+// @Param query query domain.StatsQuery false "Период и команда ревью"
+// @Success 200 {object} dto.UserStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /stats/users [get]
+func userStats(statsCase *usecase.Stats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.StatsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		report, err := statsCase.Users(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		users := make([]dto.UserStats, 0, len(report.Users))
+		for _, u := range report.Users {
+			users = append(users, dto.UserStats{
+				UserID:         u.UserId,
+				Username:       u.Username,
+				Assigned:       u.Assigned,
+				Open:           u.Open,
+				Merged:         u.Merged,
+				ReassignedAway: u.ReassignedAway,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.UserStatsResponse{
+			From:  formatTime(report.From),
+			To:    formatTime(report.To),
+			Users: users,
+		})
+	}
+}
+
+// @Summary Итоги по командам за период
+// @Description Считаются PR, созданные в [from, to) и ревьюемые командой.
+// @Tags Stats
+// @Produce json
+// @Param query query domain.StatsQuery false "Период и команда ревью"
+// @Success 200 {object} dto.TeamStatsResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /stats/teams [get]
+func teamStats(statsCase *usecase.Stats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.StatsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		report, err := statsCase.Teams(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		teams := make([]dto.TeamStats, 0, len(report.Teams))
+		for _, t := range report.Teams {
+			teams = append(teams, dto.TeamStats{
+				TeamName:      t.Team.Name,
+				PullRequests:  t.PullRequests,
+				Open:          t.Open,
+				Merged:        t.Merged,
+				Assignments:   t.Assignments,
+				Reassignments: t.Reassignments,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.TeamStatsResponse{
+			From:  formatTime(report.From),
+			To:    formatTime(report.To),
+			Teams: teams,
+		})
+	}
+}
+
+// @Summary Перцентили времени до merge за период
+// @Tags Stats
+// @Produce json
+// @Param query query domain.StatsQuery false "Период и команда ревью"
+// @Success 200 {object} dto.MergeTimeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /stats/mergeTime [get]
+func mergeTime(statsCase *usecase.Stats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.StatsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		report, err := statsCase.MergeTime(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.MergeTimeResponse{
+			From:   formatTime(report.From),
+			To:     formatTime(report.To),
+			Merged: report.Stats.Merged,
+			Avg:    report.Stats.Avg.Seconds(),
+			P50:    report.Stats.P50.Seconds(),
+			P90:    report.Stats.P90.Seconds(),
+			P95:    report.Stats.P95.Seconds(),
+			P99:    report.Stats.P99.Seconds(),
+		})
+	}
+}
+
+// @Summary Равномерность назначений в команде
+// @Description Gini, стандартное отклонение и max/min по назначениям в день среди активных участников.
+// @Description Фактическое распределение сравнивается с прогоном PR окна через каждую стратегию.
+// @Tags Stats
+// @Produce json
+// @Param query query domain.FairnessQuery true "Команда ревью и период"
+// @Success 200 {object} dto.FairnessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /stats/fairness [get]
+func fairness(fairnessCase *usecase.Fairness) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.FairnessQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		report, err := fairnessCase.Report(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		strategies := make([]dto.FairnessResult, 0, len(report.Strategies))
+		for _, s := range report.Strategies {
+			strategies = append(strategies, convertFairness(s))
+		}
+
+		c.JSON(http.StatusOK, dto.FairnessResponse{
+			TeamName:     report.Team.Name,
+			From:         formatTime(report.From),
+			To:           formatTime(report.To),
+			PullRequests: report.PullRequests,
+			Actual:       convertFairness(report.Actual),
+			Strategies:   strategies,
+		})
+	}
+}
+
+func convertFairness(r *domain.FairnessResult) dto.FairnessResult {
+	members := make([]dto.MemberLoad, 0, len(r.Members))
+	for _, m := range r.Members {
+		members = append(members, dto.MemberLoad{
+			UserID:      m.UserId,
+			Username:    m.Username,
+			Assignments: m.Assignments,
+			DaysActive:  m.DaysActive,
+			Rate:        m.Rate,
+			Flag:        string(m.Flag),
+		})
+	}
+
+	res := dto.FairnessResult{
+		Strategy: r.Strategy,
+		Mean:     r.Metrics.Mean,
+		Gini:     r.Metrics.Gini,
+		StdDev:   r.Metrics.StdDev,
+		Members:  members,
+	}
+	if !math.IsInf(r.Metrics.MaxMinRatio, 0) {
+		ratio := r.Metrics.MaxMinRatio
+		res.MaxMinRatio = &ratio
+	}
+
+	return res
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
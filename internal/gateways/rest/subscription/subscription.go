@@ -0,0 +1,206 @@
+package subscription
+
+import (
+	"net/http"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
+	g := v1.Group("/subscriptions")
+
+	g.POST("/add", addSubscription(cases.Subscription))
+	g.GET("/list", listSubscriptions(cases.Subscription))
+	g.POST("/delete", deleteSubscription(cases.Subscription))
+	g.GET("/deliveries", listDeliveries(cases.Subscription))
+	g.POST("/redeliver", redeliver(cases.Subscription))
+}
+
+// @Summary Подписать внешнюю систему на события PR
+// @Description Пустой events — все события. Тело доставки подписывается секретом:
+// @Description заголовок X-Gopr-Signature-256 содержит sha256=<HMAC-SHA256 тела в hex>.
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param body body domain.SubscriptionAddInput true "URL, фильтр событий и секрет"
+// @Success 201 {object} map[string]dto.Subscription
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/add [post]
+func addSubscription(subCase *usecase.Subscription) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.SubscriptionAddInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		sub, err := subCase.Add(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"subscription": convertSubscription(sub)})
+	}
+}
+
+// @Summary Список подписок
+// @Tags Subscriptions
+// @Produce json
+// @Success 200 {object} dto.SubscriptionList
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/list [get]
+func listSubscriptions(subCase *usecase.Subscription) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subs, err := subCase.List(c)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		res := make([]dto.Subscription, 0, len(subs))
+		for _, sub := range subs {
+			res = append(res, convertSubscription(sub))
+		}
+
+		c.JSON(http.StatusOK, dto.SubscriptionList{Subscriptions: res})
+	}
+}
+
+// @Summary Удалить подписку
+// @Description Журнал доставок подписки удаляется вместе с ней.
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param body body domain.SubscriptionDeleteInput true "Subscription id"
+// @Success 200 {object} map[string]dto.Subscription
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/delete [post]
+func deleteSubscription(subCase *usecase.Subscription) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.SubscriptionDeleteInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		sub, err := subCase.Delete(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"subscription": convertSubscription(sub)})
+	}
+}
+
+// @Summary Журнал доставок подписки
+// @Description По умолчанию новые доставки первыми. Доставка, исчерпавшая попытки, — DEAD.
+// @Tags Subscriptions
+// @Produce json
+// @Param query query domain.DeliveryListQuery true "Subscription id, статус и пагинация"
+// @Success 200 {object} dto.DeliveryList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/deliveries [get]
+func listDeliveries(subCase *usecase.Subscription) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.DeliveryListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		page, err := subCase.Deliveries(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		res := make([]dto.Delivery, 0, len(page.Deliveries))
+		for _, d := range page.Deliveries {
+			res = append(res, convertDelivery(d))
+		}
+
+		c.JSON(http.StatusOK, dto.DeliveryList{
+			SubscriptionID: query.SubscriptionId,
+			Deliveries:     res,
+			NextCursor:     page.NextCursor,
+		})
+	}
+}
+
+// @Summary Повторить доставку
+// @Description Событие отправляется заново новой доставкой с тем же event_id и телом.
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param body body domain.RedeliverInput true "Delivery id"
+// @Success 202 {object} map[string]dto.Delivery
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/redeliver [post]
+func redeliver(subCase *usecase.Subscription) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.RedeliverInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		d, err := subCase.Redeliver(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{"delivery": convertDelivery(d)})
+	}
+}
+
+func convertSubscription(sub *domain.Subscription) dto.Subscription {
+	events := make([]string, 0, len(sub.Events))
+	for _, e := range sub.Events {
+		events = append(events, string(e))
+	}
+
+	return dto.Subscription{
+		SubscriptionID: sub.Id,
+		URL:            sub.URL,
+		Events:         events,
+		CreatedAt:      sub.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func convertDelivery(d *domain.Delivery) dto.Delivery {
+	var nextAttemptAt string
+	if d.NextAttemptAt != nil {
+		nextAttemptAt = d.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	return dto.Delivery{
+		DeliveryID:     d.Id,
+		SubscriptionID: d.SubscriptionId,
+		EventID:        d.EventId,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		ResponseCode:   d.ResponseCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  nextAttemptAt,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      d.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,113 @@
+package subscription_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/subscription"
+	"gopr/internal/gateways/rest/testhelpers"
+	"gopr/internal/gateways/rest/validation"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+// okSender принимает любую доставку.
+type okSender struct{}
+
+func (okSender) Send(context.Context, *domain.Subscription, *domain.Delivery) (int, error) {
+	return http.StatusOK, nil
+}
+
+func setup(t *testing.T) (testhelpers.Client, *usecase.Subscription) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	subCase := usecase.NewSubscription(memory.NewSubscriptionRepo(memory.NewDB()), okSender{}, 1,
+		func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+	)
+
+	r := gin.New()
+	subscription.Setup(r.Group("/api/v1"), usecase.Cases{Subscription: subCase})
+	return testhelpers.Client{Handler: r, Prefix: "/api/v1/subscriptions"}, subCase
+}
+
+func TestSubscriptions_CRUD(t *testing.T) {
+	r, _ := setup(t)
+
+	code, res := r.Do(t, http.MethodPost, "/add",
+		`{"url":"https://example.com/hook","events":["pull_request.merged"],"secret":"0123456789abcdef"}`)
+	require.Equal(t, http.StatusCreated, code)
+	added := testhelpers.Decode[dto.Subscription](t, res["subscription"])
+	require.NotEmpty(t, added.SubscriptionID)
+	require.Equal(t, []string{"pull_request.merged"}, added.Events)
+	_, hasSecret := testhelpers.Decode[map[string]any](t, res["subscription"])["secret"]
+	require.False(t, hasSecret)
+
+	for _, body := range []string{
+		`{"url":"not a url","secret":"0123456789abcdef"}`,
+		`{"url":"https://example.com/hook","secret":"short"}`,
+		`{"url":"https://example.com/hook","events":["pr.closed"],"secret":"0123456789abcdef"}`,
+	} {
+		code, res = r.Do(t, http.MethodPost, "/add", body)
+		require.Equal(t, http.StatusBadRequest, code, body)
+		require.Equal(t, "VALIDATION_ERROR", testhelpers.ErrorCode(t, res))
+	}
+
+	code, res = r.Do(t, http.MethodGet, "/list", "")
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, testhelpers.Decode[[]dto.Subscription](t, res["subscriptions"]), 1)
+
+	code, _ = r.Do(t, http.MethodPost, "/delete", `{"subscription_id":"`+added.SubscriptionID+`"}`)
+	require.Equal(t, http.StatusOK, code)
+
+	code, res = r.Do(t, http.MethodPost, "/delete", `{"subscription_id":"`+added.SubscriptionID+`"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+}
+
+func TestSubscriptions_Deliveries(t *testing.T) {
+	r, subCase := setup(t)
+
+	code, res := r.Do(t, http.MethodPost, "/add", `{"url":"https://example.com/hook","secret":"0123456789abcdef"}`)
+	require.Equal(t, http.StatusCreated, code)
+	subID := testhelpers.Decode[dto.Subscription](t, res["subscription"]).SubscriptionID
+
+	for i := range 3 {
+		require.NoError(t, subCase.Publish(context.Background(), &domain.Event{
+			Id:          fmt.Sprintf("e%d", i),
+			Type:        domain.EventReviewersAssigned,
+			PullRequest: &domain.PullRequest{Id: "pr-1"},
+		}))
+	}
+	subCase.Wait()
+
+	code, res = r.Do(t, http.MethodGet, "/deliveries?subscription_id="+subID+"&limit=2", "")
+	require.Equal(t, http.StatusOK, code)
+	deliveries := testhelpers.Decode[[]dto.Delivery](t, res["deliveries"])
+	require.Len(t, deliveries, 2)
+	require.NotEmpty(t, testhelpers.Decode[string](t, res["next_cursor"]))
+	require.Equal(t, "DELIVERED", deliveries[0].Status)
+	require.Equal(t, "pr-1", testhelpers.Decode[domain.Event](t, deliveries[0].Payload).PullRequest.Id)
+
+	code, res = r.Do(t, http.MethodPost, "/redeliver", `{"delivery_id":"`+deliveries[1].DeliveryID+`"}`)
+	require.Equal(t, http.StatusAccepted, code)
+	require.Equal(t, deliveries[1].EventID, testhelpers.Decode[dto.Delivery](t, res["delivery"]).EventID)
+	subCase.Wait()
+
+	code, res = r.Do(t, http.MethodGet, "/deliveries?subscription_id=missing", "")
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+
+	code, res = r.Do(t, http.MethodPost, "/redeliver", `{"delivery_id":"missing"}`)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, "NOT_FOUND", testhelpers.ErrorCode(t, res))
+}
@@ -5,6 +5,7 @@ import (
 
 	"gopr/internal/domain"
 	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
 	"gopr/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -15,6 +16,13 @@ func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 
 	g.POST("/add", addTeam(cases.Team))
 	g.GET("/get", getTeam(cases.Team))
+	g.GET("/list", listTeams(cases.Team))
+	g.POST("/rename", renameTeam(cases.Team))
+	g.POST("/delete", deleteTeam(cases.Team))
+	g.POST("/addMember", addMember(cases.Team))
+	g.POST("/removeMember", removeMember(cases.Team))
+	g.POST("/setMemberRole", setMemberRole(cases.Team))
+	g.POST("/setParent", setParent(cases.Team))
 }
 
 // @Summary Создать команду с участниками (создаёт/обновляет пользователей)
@@ -24,28 +32,19 @@ func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 // @Param team body domain.TeamAddInput true "Team object"
 // @Success 201 {object} map[string]dto.Team
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /team/add [post]
 func addTeam(teamCase *usecase.Team) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		input := &domain.TeamAddInput{}
 		if err := c.ShouldBindJSON(input); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "invalid json",
-				},
-			})
+			apierr.RenderBind(c, err, "invalid json")
 			return
 		}
 
 		domainTeam, err := teamCase.AddTeam(c, input)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "TEAM_EXISTS",
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
 			return
 		}
 
@@ -56,33 +55,31 @@ func addTeam(teamCase *usecase.Team) gin.HandlerFunc {
 }
 
 // @Summary Получить команду с участниками
+// @Description С subtree=true в ответ добавляются подкоманды и участники всего поддерева.
 // @Tags Teams
 // @Produce json
-// @Param team_name query string true "Team name"
+// @Param query query domain.GetTeamQuery true "Team name"
 // @Success 200 {object} dto.Team
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /team/get [get]
 func getTeam(teamCase *usecase.Team) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		teamName := c.Query("team_name")
-		if teamName == "" {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "team_name required",
-				},
-			})
+		var query domain.GetTeamQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
 			return
 		}
 
-		domainTeam, err := teamCase.GetTeam(c, teamName)
+		get := teamCase.GetTeam
+		if query.Subtree {
+			get = teamCase.GetSubtree
+		}
+
+		domainTeam, err := get(c, query.TeamName)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "NOT_FOUND",
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
 			return
 		}
 
@@ -90,18 +87,248 @@ func getTeam(teamCase *usecase.Team) gin.HandlerFunc {
 	}
 }
 
-func convertTeam(t *domain.TeamWithMembers) dto.Team {
-	members := make([]dto.TeamMember, 0, len(t.Members))
-	for _, m := range t.Members {
-		members = append(members, dto.TeamMember{
-			UserID:   m.Id,
-			Username: m.Username,
-			IsActive: m.IsActive,
+// @Summary Список команд с количеством участников
+// @Tags Teams
+// @Produce json
+// @Success 200 {object} dto.TeamList
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/list [get]
+func listTeams(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		teams, err := teamCase.ListTeams(c)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		res := make([]dto.TeamSummary, 0, len(teams))
+		for _, t := range teams {
+			res = append(res, dto.TeamSummary{
+				TeamName:     t.Team.Name,
+				ParentName:   t.ParentName,
+				MembersCount: t.MembersCount,
+				ActiveCount:  t.ActiveCount,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.TeamList{Teams: res})
+	}
+}
+
+// @Summary Переименовать команду
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamRenameInput true "Текущее и новое имя"
+// @Success 200 {object} map[string]dto.Team
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/rename [post]
+func renameTeam(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamRenameInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		domainTeam, err := teamCase.Rename(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"team": convertTeam(domainTeam)})
+	}
+}
+
+// @Summary Удалить команду (участники остаются без команды)
+// @Description Если участники назначены на открытые PR, без force возвращается TEAM_HAS_OPEN_REVIEWS,
+// @Description с force они снимаются с этих PR.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamDeleteInput true "Команда и флаг force"
+// @Success 200 {object} dto.TeamDeleteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/delete [post]
+func deleteTeam(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamDeleteInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		res, err := teamCase.Delete(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		removed := make([]dto.ReviewAssignment, 0, len(res.RemovedReviews))
+		for _, a := range res.RemovedReviews {
+			removed = append(removed, dto.ReviewAssignment{
+				PullRequestID: a.PullRequestId,
+				ReviewerID:    a.ReviewerId,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.TeamDeleteResponse{
+			TeamName:       res.Team.Name,
+			RemovedReviews: removed,
 		})
 	}
+}
 
-	return dto.Team{
+// @Summary Добавить участника в команду (создаёт пользователя, если его нет)
+// @Description Пользователь может состоять в нескольких командах.
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamMemberInput true "Участник"
+// @Success 200 {object} map[string]dto.Team
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/addMember [post]
+func addMember(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamMemberInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		domainTeam, err := teamCase.AddMember(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"team": convertTeam(domainTeam)})
+	}
+}
+
+// @Summary Вывести участника из команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamRemoveMemberInput true "Команда и пользователь"
+// @Success 200 {object} map[string]dto.Team
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/removeMember [post]
+func removeMember(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamRemoveMemberInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		domainTeam, err := teamCase.RemoveMember(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"team": convertTeam(domainTeam)})
+	}
+}
+
+// @Summary Изменить роль участника команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamMemberRoleInput true "Команда, пользователь и роль"
+// @Success 200 {object} map[string]dto.Team
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/setMemberRole [post]
+func setMemberRole(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamMemberRoleInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		domainTeam, err := teamCase.SetMemberRole(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"team": convertTeam(domainTeam)})
+	}
+}
+
+// @Summary Перенести команду в другую или сделать корневой
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param body body domain.TeamSetParentInput true "Команда и новый родитель"
+// @Success 200 {object} map[string]dto.Team
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /team/setParent [post]
+func setParent(teamCase *usecase.Team) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.TeamSetParentInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		domainTeam, err := teamCase.SetParent(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"team": convertTeam(domainTeam)})
+	}
+}
+
+func convertTeam(t *domain.TeamWithMembers) dto.Team {
+	res := dto.Team{
 		TeamName: t.Team.Name,
-		Members:  members,
+		Members:  convertMembers(t.Members),
+	}
+
+	if t.Parent != nil {
+		res.ParentName = t.Parent.Name
+	}
+
+	for _, sub := range t.Subteams {
+		res.Subteams = append(res.Subteams, convertTeam(sub))
+	}
+	if t.AggregateMembers != nil {
+		res.AggregateMembers = convertMembers(t.AggregateMembers)
+	}
+
+	return res
+}
+
+func convertMembers(members []*domain.TeamMember) []dto.TeamMember {
+	res := make([]dto.TeamMember, 0, len(members))
+	for _, m := range members {
+		res = append(res, dto.TeamMember{
+			UserID:   m.User.Id,
+			Username: m.User.Username,
+			IsActive: m.User.IsActive,
+			Role:     string(m.Role),
+		})
 	}
+	return res
 }
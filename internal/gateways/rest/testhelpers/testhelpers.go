@@ -0,0 +1,53 @@
+package testhelpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/dto"
+)
+
+// Client шлёт запросы к хендлерам REST под общим префиксом пути,
+// например /api/v1/identities.
+type Client struct {
+	Handler http.Handler
+	Prefix  string
+}
+
+// Do выполняет запрос и возвращает код ответа и поля JSON-объекта из тела.
+func (c Client) Do(t *testing.T, method, target, body string) (int, map[string]json.RawMessage) {
+	t.Helper()
+
+	code, raw := c.DoRaw(t, method, target, body)
+	return code, Decode[map[string]json.RawMessage](t, raw)
+}
+
+// DoRaw выполняет запрос и возвращает код и тело ответа как есть.
+func (c Client) DoRaw(t *testing.T, method, target, body string) (int, []byte) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, c.Prefix+target, strings.NewReader(body))
+	w := httptest.NewRecorder()
+	c.Handler.ServeHTTP(w, req)
+	return w.Code, w.Body.Bytes()
+}
+
+// Decode разбирает поле ответа в T.
+func Decode[T any](t *testing.T, raw json.RawMessage) T {
+	t.Helper()
+
+	var v T
+	require.NoError(t, json.Unmarshal(raw, &v), string(raw))
+	return v
+}
+
+// ErrorCode возвращает код ошибки из ответа.
+func ErrorCode(t *testing.T, res map[string]json.RawMessage) string {
+	t.Helper()
+	return Decode[dto.ErrorObject](t, res["error"]).Code
+}
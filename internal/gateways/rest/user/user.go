@@ -2,22 +2,30 @@ package user
 
 import (
 	"net/http"
+	"time"
 
+	"gopr/internal/domain"
 	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
 	"gopr/internal/usecase"
 
 	"github.com/gin-gonic/gin"
 )
 
 type setActiveInput struct {
-	UserID   string `json:"user_id"`
-	IsActive bool   `json:"is_active"`
+	UserID   string `json:"user_id" binding:"required,notblank,max=128"`
+	IsActive *bool  `json:"is_active" binding:"required"`
 }
 
 func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 	g := v1.Group("/users")
 
 	g.POST("/setIsActive", setActive(cases.User))
+	g.GET("/getReview", getReview(cases.User))
+	g.GET("/get", getUser(cases.User))
+	g.GET("/list", listUsers(cases.User))
+	g.POST("/update", updateUser(cases.User))
+	g.POST("/moveToTeam", moveToTeam(cases.User))
 }
 
 // @Summary Установить флаг активности пользователя
@@ -26,39 +34,233 @@ func Setup(v1 *gin.RouterGroup, cases usecase.Cases) {
 // @Produce json
 // @Param body body setActiveInput true "User ID and active flag"
 // @Success 200 {object} map[string]dto.User
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
 // @Router /users/setIsActive [post]
 func setActive(userCase *usecase.User) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var input setActiveInput
 		if err := c.ShouldBindJSON(&input); err != nil {
-			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "BAD_REQUEST",
-					Message: "invalid json",
-				},
-			})
+			apierr.RenderBind(c, err, "invalid json")
 			return
 		}
 
-		user, teamName, err := userCase.SetActive(c, input.UserID, input.IsActive)
+		user, err := userCase.SetActive(c, input.UserID, *input.IsActive)
 		if err != nil {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				Error: dto.ErrorObject{
-					Code:    "NOT_FOUND",
-					Message: err.Error(),
-				},
-			})
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user": convertUser(user)})
+	}
+}
+
+// @Summary Получить PR'ы, где пользователь назначен ревьювером
+// @Tags Users
+// @Produce json
+// @Param query query domain.UserReviewsQuery true "Фильтры и пагинация"
+// @Success 200 {object} dto.UserReviews
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/getReview [get]
+func getReview(userCase *usecase.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.UserReviewsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		reviews, err := userCase.GetReviews(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		prs := make([]dto.PullRequestShort, 0, len(reviews.PRs))
+		for _, p := range reviews.PRs {
+			prs = append(prs, convertPRShort(p))
+		}
+
+		c.JSON(http.StatusOK, dto.UserReviews{
+			UserID:       reviews.UserID,
+			PullRequests: prs,
+			NextCursor:   reviews.NextCursor,
+		})
+	}
+}
+
+// @Summary Получить пользователя с командой и текущей нагрузкой
+// @Tags Users
+// @Produce json
+// @Param query query domain.GetUserQuery true "User id"
+// @Success 200 {object} map[string]dto.User
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/get [get]
+func getUser(userCase *usecase.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.GetUserQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		user, err := userCase.Get(c, query.UserId)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user": convertUser(user)})
+	}
+}
+
+// @Summary Список пользователей с поиском по префиксу имени
+// @Tags Users
+// @Produce json
+// @Param query query domain.UserListQuery false "Фильтры и пагинация"
+// @Success 200 {object} dto.UserList
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/list [get]
+func listUsers(userCase *usecase.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query domain.UserListQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			apierr.RenderBind(c, err, "invalid query")
+			return
+		}
+
+		page, err := userCase.List(c, &query)
+		if err != nil {
+			apierr.Render(c, err)
 			return
 		}
 
-		resp := dto.User{
-			UserID:   user.Id,
-			Username: user.Username,
-			TeamName: teamName,
-			IsActive: user.IsActive,
+		users := make([]dto.User, 0, len(page.Users))
+		for _, u := range page.Users {
+			users = append(users, convertUser(u))
 		}
 
-		c.JSON(http.StatusOK, gin.H{"user": resp})
+		c.JSON(http.StatusOK, dto.UserList{
+			Users:      users,
+			NextCursor: page.NextCursor,
+		})
+	}
+}
+
+// @Summary Изменить имя пользователя
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body domain.UserUpdateInput true "User id и новое имя"
+// @Success 200 {object} map[string]dto.User
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/update [post]
+func updateUser(userCase *usecase.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.UserUpdateInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		user, err := userCase.UpdateUsername(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"user": convertUser(user)})
+	}
+}
+
+// @Summary Перевести пользователя в другую команду
+// @Description Открытые ревью пользователя передаются другим участникам старой команды.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body domain.UserMoveInput true "User id и новая команда"
+// @Success 200 {object} dto.UserMoveResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/moveToTeam [post]
+func moveToTeam(userCase *usecase.User) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := &domain.UserMoveInput{}
+		if err := c.ShouldBindJSON(input); err != nil {
+			apierr.RenderBind(c, err, "invalid json")
+			return
+		}
+
+		res, err := userCase.MoveToTeam(c, input)
+		if err != nil {
+			apierr.Render(c, err)
+			return
+		}
+
+		reassigned := make([]dto.Reassignment, 0, len(res.Reassigned))
+		for _, r := range res.Reassigned {
+			reassigned = append(reassigned, dto.Reassignment{
+				PullRequestID: r.PullRequestId,
+				OldReviewerID: r.OldReviewerId,
+				NewReviewerID: r.NewReviewerId,
+			})
+		}
+
+		c.JSON(http.StatusOK, dto.UserMoveResponse{
+			User:       convertUser(res.User),
+			Reassigned: reassigned,
+		})
+	}
+}
+
+func convertUser(u *domain.UserDetails) dto.User {
+	teams := make([]dto.UserTeam, 0, len(u.Teams))
+	for _, t := range u.Teams {
+		teams = append(teams, dto.UserTeam{
+			TeamName: t.Team.Name,
+			Role:     string(t.Role),
+		})
+	}
+
+	return dto.User{
+		UserID:      u.User.Id,
+		Username:    u.User.Username,
+		TeamName:    u.TeamName,
+		IsActive:    u.User.IsActive,
+		ReviewLoad:  u.ReviewLoad,
+		IsAvailable: u.IsAvailable,
+		Teams:       teams,
+	}
+}
+
+func convertPRShort(p *domain.PullRequestWithReviewers) dto.PullRequestShort {
+	var createdAt *string
+	if !p.PR.CreatedAt.IsZero() {
+		s := p.PR.CreatedAt.Format(time.RFC3339)
+		createdAt = &s
+	}
+
+	reviewers := p.Reviewers
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	return dto.PullRequestShort{
+		PullRequestID:     p.PR.Id,
+		PullRequestName:   p.PR.Name,
+		AuthorID:          p.PR.AuthorId,
+		Status:            p.PR.Status,
+		AssignedReviewers: reviewers,
+		CreatedAt:         createdAt,
 	}
 }
@@ -0,0 +1,70 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/go-playground/validator/v10/non-standard/validators"
+)
+
+// Setup настраивает валидатор gin: имена полей в ошибках берутся из
+// json/form тегов, регистрируются нестандартные правила.
+func Setup() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected validator engine %T", binding.Validator.Engine())
+	}
+
+	v.RegisterTagNameFunc(fieldName)
+
+	if err := v.RegisterValidation("notblank", validators.NotBlank); err != nil {
+		return fmt.Errorf("register notblank: %w", err)
+	}
+
+	return nil
+}
+
+// Violations превращает ошибку валидатора в список нарушений по полям.
+func Violations(err error) ([]domain.FieldViolation, bool) {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, false
+	}
+
+	res := make([]domain.FieldViolation, 0, len(verrs))
+	for _, fe := range verrs {
+		res = append(res, domain.FieldViolation{
+			Field:  fieldPath(fe.Namespace()),
+			Reason: fe.Tag(),
+		})
+	}
+
+	return res, true
+}
+
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldPath отрезает имя корневой структуры: "TeamAddInput.members[0].user_id" -> "members[0].user_id".
+func fieldPath(namespace string) string {
+	_, path, found := strings.Cut(namespace, ".")
+	if !found {
+		return namespace
+	}
+	return path
+}
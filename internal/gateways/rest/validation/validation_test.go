@@ -0,0 +1,88 @@
+package validation_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/gateways/rest/validation"
+)
+
+func bind(t *testing.T, body string, obj any) error {
+	t.Helper()
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	return c.ShouldBindJSON(obj)
+}
+
+func TestViolations_TeamAddInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	err := bind(t, `{
+		"team_name": "   ",
+		"members": [
+			{"user_id": "u1", "username": "alice", "is_active": true},
+			{"user_id": "u1", "username": "bob", "is_active": true}
+		]
+	}`, &domain.TeamAddInput{})
+	require.Error(t, err)
+
+	violations, ok := validation.Violations(err)
+	require.True(t, ok)
+	require.ElementsMatch(t, []domain.FieldViolation{
+		{Field: "team_name", Reason: "notblank"},
+		{Field: "members", Reason: "unique"},
+	}, violations)
+
+	err = bind(t, `{
+		"team_name": "backend",
+		"members": [
+			{"user_id": "u1", "username": "alice", "is_active": true},
+			{"user_id": "u2", "username": "", "is_active": true}
+		]
+	}`, &domain.TeamAddInput{})
+	require.Error(t, err)
+
+	violations, ok = validation.Violations(err)
+	require.True(t, ok)
+	require.Equal(t, []domain.FieldViolation{
+		{Field: "members[1].username", Reason: "required"},
+	}, violations)
+}
+
+func TestViolations_CreatePullRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	err := bind(t, `{"pull_request_name": "`+strings.Repeat("x", 10*1024)+`"}`, &domain.CreatePullRequest{})
+	require.Error(t, err)
+
+	violations, ok := validation.Violations(err)
+	require.True(t, ok)
+	require.ElementsMatch(t, []domain.FieldViolation{
+		{Field: "author_id", Reason: "required"},
+		{Field: "pull_request_name", Reason: "max"},
+	}, violations)
+
+	require.NoError(t, bind(t, `{"author_id": "u1", "pull_request_name": "Add search"}`, &domain.CreatePullRequest{}))
+}
+
+func TestViolations_NotValidationError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	require.NoError(t, validation.Setup())
+
+	err := bind(t, `{"author_id": `, &domain.CreatePullRequest{})
+	require.Error(t, err)
+
+	_, ok := validation.Violations(err)
+	require.False(t, ok)
+}
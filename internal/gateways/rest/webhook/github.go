@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"gopr/internal/domain"
+)
+
+// githubPullRequestEvent — поля события pull_request, которые использует gopr.
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Title  string `json:"title"`
+		Draft  bool   `json:"draft"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// parseGitHub приводит событие GitHub к PullRequestHook. Для событий, на которые
+// gopr не реагирует, возвращает nil и причину.
+func parseGitHub(event string, body []byte) (*domain.PullRequestHook, string, error) {
+	if event != "pull_request" {
+		return nil, "unsupported event " + event, nil
+	}
+
+	var p githubPullRequestEvent
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, "", err
+	}
+
+	var action domain.HookAction
+	switch p.Action {
+	case "opened":
+		action = domain.HookActionOpened
+	case "reopened":
+		action = domain.HookActionReopened
+	case "ready_for_review":
+		action = domain.HookActionReady
+	case "closed":
+		action = domain.HookActionMerged
+		if !p.PullRequest.Merged {
+			action = domain.HookActionClosed
+		}
+	default:
+		return nil, "unsupported action " + p.Action, nil
+	}
+
+	return &domain.PullRequestHook{
+		Provider:    domain.HookProviderGitHub,
+		Action:      action,
+		Repository:  p.Repository.FullName,
+		Number:      p.Number,
+		Title:       p.PullRequest.Title,
+		AuthorLogin: p.PullRequest.User.Login,
+		SenderLogin: p.Sender.Login,
+		Draft:       p.PullRequest.Draft,
+	}, "", nil
+}
@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"gopr/internal/domain"
+)
+
+// gitlabMergeRequestEvent — поля Merge Request Hook, которые использует gopr.
+// Логина автора MR в событии нет, только его числовой id, поэтому автором
+// считается пользователь, открывший или переоткрывший MR.
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		Iid    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+		Draft  bool   `json:"draft"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// parseGitLab приводит событие GitLab к PullRequestHook. Для событий, на которые
+// gopr не реагирует, возвращает nil и причину.
+func parseGitLab(event string, body []byte) (*domain.PullRequestHook, string, error) {
+	if event != "Merge Request Hook" {
+		return nil, "unsupported event " + event, nil
+	}
+
+	var p gitlabMergeRequestEvent
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, "", err
+	}
+
+	attrs := p.ObjectAttributes
+
+	var action domain.HookAction
+	switch attrs.Action {
+	case "open":
+		action = domain.HookActionOpened
+	case "reopen":
+		action = domain.HookActionReopened
+	case "merge":
+		action = domain.HookActionMerged
+	case "close":
+		action = domain.HookActionClosed
+	case "update":
+		if d := p.Changes.Draft; d == nil || !d.Previous || d.Current {
+			return nil, "update without leaving draft", nil
+		}
+		action = domain.HookActionReady
+	default:
+		return nil, "unsupported action " + attrs.Action, nil
+	}
+
+	return &domain.PullRequestHook{
+		Provider:    domain.HookProviderGitLab,
+		Action:      action,
+		Repository:  p.Project.PathWithNamespace,
+		Number:      attrs.Iid,
+		Title:       attrs.Title,
+		AuthorLogin: p.User.Username,
+		SenderLogin: p.User.Username,
+		Draft:       attrs.Draft,
+	}, "", nil
+}
@@ -0,0 +1,160 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"gopr/cmd/config"
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/apierr"
+	"gopr/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPayload — предел тела вебхука; GitHub присылает до 25 МБ, но события PR намного меньше.
+const maxPayload = 5 << 20
+
+var errBadSignature = domain.NewError(domain.ErrCodeUnauthorized, "invalid webhook signature")
+
+// Setup регистрирует эндпоинты только для провайдеров с заданным секретом.
+func Setup(v1 *gin.RouterGroup, cases usecase.Cases, cfg *config.Config) {
+	g := v1.Group("/webhooks")
+
+	if secret := cfg.Webhooks.GitHub.Secret; secret != "" {
+		g.POST("/github", github(cases.Webhook, secret))
+	}
+	if secret := cfg.Webhooks.GitLab.Secret; secret != "" {
+		g.POST("/gitlab", gitlab(cases.Webhook, secret))
+	}
+}
+
+// @Summary Принять вебхук GitHub о pull request
+// @Description Подпись X-Hub-Signature-256 проверяется секретом WEBHOOK_GITHUB_SECRET.
+// @Description opened, reopened и ready_for_review создают PR, closed с merged=true мержит его, closed без merge снимает с него ревьюверов; остальные события игнорируются.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-GitHub-Event header string true "Тип события"
+// @Param X-Hub-Signature-256 header string true "sha256=<hex HMAC-SHA256 тела>"
+// @Success 200 {object} dto.WebhookResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/github [post]
+func github(hookCase *usecase.Webhook, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+
+		if !validGitHubSignature(secret, body, c.GetHeader("X-Hub-Signature-256")) {
+			apierr.Render(c, errBadSignature)
+			return
+		}
+
+		hook, reason, err := parseGitHub(c.GetHeader("X-GitHub-Event"), body)
+		if err != nil {
+			apierr.BadRequest(c, "invalid payload")
+			return
+		}
+
+		handle(c, hookCase, hook, reason)
+	}
+}
+
+// @Summary Принять вебхук GitLab о merge request
+// @Description Заголовок X-Gitlab-Token сравнивается с секретом WEBHOOK_GITLAB_SECRET.
+// @Description open, reopen и снятие draft создают PR, merge мержит его, close снимает с него ревьюверов; остальные события игнорируются.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param X-Gitlab-Event header string true "Тип события"
+// @Param X-Gitlab-Token header string true "Секрет вебхука"
+// @Success 200 {object} dto.WebhookResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/gitlab [post]
+func gitlab(hookCase *usecase.Webhook, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, ok := readBody(c)
+		if !ok {
+			return
+		}
+
+		// GitLab не подписывает тело, а присылает сам секрет в заголовке, поэтому
+		// вместо HMAC, как у GitHub, заголовок сравнивается с секретом за постоянное время.
+		// Секрет виден каждому, кто видит запрос, — вебхуки GitLab стоит слать только по HTTPS.
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(c.GetHeader("X-Gitlab-Token"))) != 1 {
+			apierr.Render(c, errBadSignature)
+			return
+		}
+
+		hook, reason, err := parseGitLab(c.GetHeader("X-Gitlab-Event"), body)
+		if err != nil {
+			apierr.BadRequest(c, "invalid payload")
+			return
+		}
+
+		handle(c, hookCase, hook, reason)
+	}
+}
+
+// handle передаёт событие в usecase; hook == nil — событие отброшено при
+// разборе по причине reason.
+func handle(c *gin.Context, hookCase *usecase.Webhook, hook *domain.PullRequestHook, reason string) {
+	if hook == nil {
+		c.JSON(http.StatusOK, dto.WebhookResult{
+			Outcome: string(domain.HookOutcomeIgnored),
+			Reason:  reason,
+		})
+		return
+	}
+
+	res, err := hookCase.Handle(c, hook)
+	if err != nil {
+		apierr.Render(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.WebhookResult{
+		Outcome:       string(res.Outcome),
+		PullRequestID: res.PullRequestId,
+		Reason:        res.Reason,
+	})
+}
+
+func readBody(c *gin.Context) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPayload))
+	if err != nil {
+		apierr.BadRequest(c, "can't read payload")
+		return nil, false
+	}
+	return body, true
+}
+
+func validGitHubSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,279 @@
+package webhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"gopr/cmd/config"
+	"gopr/internal/domain"
+	"gopr/internal/dto"
+	"gopr/internal/gateways/rest/webhook"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+const (
+	githubSecret = "gh-secret"
+	gitlabSecret = "gl-secret"
+)
+
+type env struct {
+	router *gin.Engine
+	prRepo *memory.PullRequestRepo
+}
+
+func setup(t *testing.T) *env {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+	_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "octocat", IsActive: true},
+			{UserID: "u2", Username: "hubot", IsActive: true},
+			{UserID: "alice", Username: "alice", IsActive: true},
+			{UserID: "bob", Username: "bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	cases := usecase.Cases{
+		PullRequest: prCase,
+		Webhook: usecase.NewWebhook(prCase, usecase.NewIdentity(memory.NewIdentityRepo(db), userRepo, map[domain.IdentityProvider]map[string]string{
+			domain.IdentityGitHub: {"octocat": "u1", "hubot": "u2"},
+			domain.IdentityGitLab: {"alice": "alice", "bob": "bob"},
+		})),
+	}
+
+	cfg := &config.Config{}
+	cfg.Webhooks.GitHub.Secret = githubSecret
+	cfg.Webhooks.GitLab.Secret = gitlabSecret
+
+	r := gin.New()
+	webhook.Setup(r.Group("/api/v1"), cases, cfg)
+
+	return &env{router: r, prRepo: prRepo}
+}
+
+func payload(t *testing.T, name string) []byte {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return body
+}
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(githubSecret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (e *env) github(t *testing.T, event, name string) (int, dto.WebhookResult) {
+	t.Helper()
+
+	body := payload(t, name)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github", strings.NewReader(string(body)))
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", sign(body))
+
+	return e.do(t, req)
+}
+
+func (e *env) gitlab(t *testing.T, name string) (int, dto.WebhookResult) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/gitlab", strings.NewReader(string(payload(t, name))))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", gitlabSecret)
+
+	return e.do(t, req)
+}
+
+func (e *env) do(t *testing.T, req *http.Request) (int, dto.WebhookResult) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+
+	var res dto.WebhookResult
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	}
+	return w.Code, res
+}
+
+func TestGitHub_PullRequestLifecycle(t *testing.T) {
+	e := setup(t)
+	ctx := context.Background()
+
+	code, res := e.github(t, "ping", "github_ping.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ignored", res.Outcome)
+
+	code, res = e.github(t, "pull_request", "github_pull_request_opened.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "created", PullRequestID: "acme/api#42"}, res)
+
+	pr, err := e.prRepo.GetByID(ctx, "acme/api#42")
+	require.NoError(t, err)
+	require.Equal(t, "u1", pr.AuthorId)
+	require.Equal(t, "Add PR search", pr.Name)
+
+	reviewers, err := e.prRepo.ListReviewers(ctx, pr.Id)
+	require.NoError(t, err)
+	require.Len(t, reviewers, 2)
+	require.NotContains(t, reviewers, "u1")
+
+	code, res = e.github(t, "pull_request", "github_pull_request_opened.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ignored", res.Outcome)
+
+	code, res = e.github(t, "pull_request", "github_pull_request_synchronize.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ignored", res.Outcome)
+
+	code, res = e.github(t, "pull_request", "github_pull_request_closed_merged.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "merged", PullRequestID: "acme/api#42"}, res)
+
+	events, err := e.prRepo.ListEvents(ctx, pr.Id)
+	require.NoError(t, err)
+	merged := events[len(events)-1]
+	require.Equal(t, domain.PullRequestEventMerged, merged.Type)
+	require.Equal(t, "u2", merged.Actor)
+}
+
+func TestGitHub_Draft(t *testing.T) {
+	e := setup(t)
+
+	code, res := e.github(t, "pull_request", "github_pull_request_opened_draft.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ignored", res.Outcome)
+
+	_, err := e.prRepo.GetByID(context.Background(), "acme/api#43")
+	require.Error(t, err)
+
+	code, res = e.github(t, "pull_request", "github_pull_request_ready_for_review.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "created", PullRequestID: "acme/api#43"}, res)
+
+	code, res = e.github(t, "pull_request", "github_pull_request_closed_unmerged.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "closed", PullRequestID: "acme/api#43"}, res)
+
+	pr, err := e.prRepo.GetByID(context.Background(), "acme/api#43")
+	require.NoError(t, err)
+	require.Equal(t, string(domain.PullRequestStatusOpen), pr.Status)
+
+	reviewers, err := e.prRepo.ListReviewers(context.Background(), pr.Id)
+	require.NoError(t, err)
+	require.Empty(t, reviewers)
+
+	events, err := e.prRepo.ListEvents(context.Background(), pr.Id)
+	require.NoError(t, err)
+	removed := events[len(events)-1]
+	require.Equal(t, domain.PullRequestEventReviewerRemoved, removed.Type)
+	require.Equal(t, domain.ReasonClosed, removed.Reason)
+	require.Equal(t, "u1", removed.Actor)
+}
+
+func TestGitHub_BadSignature(t *testing.T) {
+	e := setup(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/github",
+		strings.NewReader(string(payload(t, "github_pull_request_opened.json"))))
+	req.Header.Set("X-GitHub-Event", "pull_request")
+	req.Header.Set("X-Hub-Signature-256", sign([]byte("tampered")))
+
+	code, _ := e.do(t, req)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	req.Header.Del("X-Hub-Signature-256")
+	code, _ = e.do(t, req)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestGitLab_MergeRequestLifecycle(t *testing.T) {
+	e := setup(t)
+	ctx := context.Background()
+
+	code, res := e.gitlab(t, "gitlab_merge_request_open.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "created", PullRequestID: "acme/web!7"}, res)
+
+	code, res = e.gitlab(t, "gitlab_merge_request_open_draft.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "ignored", res.Outcome)
+
+	code, res = e.gitlab(t, "gitlab_merge_request_update_ready.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "created", PullRequestID: "acme/web!8"}, res)
+
+	code, res = e.gitlab(t, "gitlab_merge_request_merge.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "merged", PullRequestID: "acme/web!7"}, res)
+
+	pr, err := e.prRepo.GetByID(ctx, "acme/web!7")
+	require.NoError(t, err)
+	require.Equal(t, "alice", pr.AuthorId)
+	require.NotNil(t, pr.MergedAt)
+
+	code, res = e.gitlab(t, "gitlab_merge_request_close.json")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, dto.WebhookResult{Outcome: "closed", PullRequestID: "acme/web!8"}, res)
+
+	reviewers, err := e.prRepo.ListReviewers(ctx, "acme/web!8")
+	require.NoError(t, err)
+	require.Empty(t, reviewers)
+}
+
+func TestGitLab_BadToken(t *testing.T) {
+	e := setup(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/gitlab",
+		strings.NewReader(string(payload(t, "gitlab_merge_request_open.json"))))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", "wrong")
+
+	code, _ := e.do(t, req)
+	require.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestUnknownAuthor(t *testing.T) {
+	e := setup(t)
+
+	body := strings.ReplaceAll(string(payload(t, "gitlab_merge_request_open.json")), `"username": "alice"`, `"username": "mallory"`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/gitlab", strings.NewReader(body))
+	req.Header.Set("X-Gitlab-Event", "Merge Request Hook")
+	req.Header.Set("X-Gitlab-Token", gitlabSecret)
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	require.Contains(t, w.Body.String(), string(domain.ErrCodeUnknownIdentity))
+
+	_, err := e.prRepo.GetByID(context.Background(), "acme/web!7")
+	require.Error(t, err)
+}
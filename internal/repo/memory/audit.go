@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"slices"
+	"time"
+)
+
+type AuditRepo struct {
+	db *DB
+}
+
+func NewAuditRepo(db *DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func (r *AuditRepo) Append(_ context.Context, record *domain.AuditRecord) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.audit[record.Id]; ok {
+		return fmt.Errorf("insert audit_log: %w", repo.ErrAlreadyExists)
+	}
+
+	record.CreatedAt = r.db.now()
+
+	r.db.audit[record.Id] = copyAuditRecord(record)
+	return nil
+}
+
+func (r *AuditRepo) List(_ context.Context, filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.AuditRecord
+	for _, record := range r.db.audit {
+		if filter.Actor != "" && record.Actor != filter.Actor {
+			continue
+		}
+		if filter.ClaimedActor != "" && record.ClaimedActor != filter.ClaimedActor {
+			continue
+		}
+		if filter.Action != "" && record.Action != filter.Action {
+			continue
+		}
+		if filter.TargetType != "" && record.TargetType != filter.TargetType {
+			continue
+		}
+		if filter.TargetId != "" && record.TargetId != filter.TargetId {
+			continue
+		}
+		if filter.RequestId != "" && record.RequestId != filter.RequestId {
+			continue
+		}
+		if !inRange(record.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+		res = append(res, copyAuditRecord(record))
+	}
+
+	res = keyset(res, filter.Order, filter.After, func(record *domain.AuditRecord) (time.Time, string) {
+		return record.CreatedAt, record.Id
+	})
+
+	return limit(res, filter.Limit), nil
+}
+
+func (r *AuditRepo) DeleteBefore(_ context.Context, before time.Time) (int64, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var n int64
+	for id, record := range r.db.audit {
+		if record.CreatedAt.Before(before) {
+			delete(r.db.audit, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func copyAuditRecord(record *domain.AuditRecord) *domain.AuditRecord {
+	c := *record
+	c.Before = slices.Clone(record.Before)
+	c.After = slices.Clone(record.After)
+	return &c
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"sort"
+)
+
+type identityKey struct {
+	provider domain.IdentityProvider
+	login    string
+}
+
+type IdentityRepo struct {
+	db *DB
+}
+
+func NewIdentityRepo(db *DB) *IdentityRepo {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) Create(_ context.Context, identity *domain.Identity) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := identityKey{provider: identity.Provider, login: identity.Login}
+	if _, ok := r.db.identities[key]; ok || r.db.identityByExternalID(identity.Provider, identity.ExternalId) != nil {
+		return fmt.Errorf("insert user_identity: %w", repo.ErrAlreadyExists)
+	}
+	if _, ok := r.db.users[identity.UserId]; !ok {
+		return fmt.Errorf("insert user_identity: %w", errForeignKey)
+	}
+
+	identity.CreatedAt = r.db.now()
+
+	c := *identity
+	r.db.identities[key] = &c
+
+	return nil
+}
+
+func (r *IdentityRepo) GetByLogin(_ context.Context, provider domain.IdentityProvider, login string) (*domain.Identity, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i, ok := r.db.identities[identityKey{provider: provider, login: login}]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	c := *i
+	return &c, nil
+}
+
+func (r *IdentityRepo) GetByExternalID(_ context.Context, provider domain.IdentityProvider, externalID int64) (*domain.Identity, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	i := r.db.identityByExternalID(provider, externalID)
+	if i == nil {
+		return nil, repo.ErrNotFound
+	}
+
+	c := *i
+	return &c, nil
+}
+
+func (r *IdentityRepo) ListByUser(_ context.Context, userID string) ([]*domain.Identity, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.Identity
+	for _, i := range r.db.identities {
+		if i.UserId == userID {
+			c := *i
+			res = append(res, &c)
+		}
+	}
+
+	sort.Slice(res, func(a, b int) bool {
+		if !res[a].CreatedAt.Equal(res[b].CreatedAt) {
+			return res[a].CreatedAt.Before(res[b].CreatedAt)
+		}
+		if res[a].Provider != res[b].Provider {
+			return res[a].Provider < res[b].Provider
+		}
+		return res[a].Login < res[b].Login
+	})
+
+	return res, nil
+}
+
+func (r *IdentityRepo) UpdateExternalID(_ context.Context, provider domain.IdentityProvider, login string, externalID int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	i, ok := r.db.identities[identityKey{provider: provider, login: login}]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if other := r.db.identityByExternalID(provider, externalID); other != nil && other != i {
+		return fmt.Errorf("update user_identity: %w", repo.ErrAlreadyExists)
+	}
+
+	i.ExternalId = externalID
+	return nil
+}
+
+func (r *IdentityRepo) Delete(_ context.Context, provider domain.IdentityProvider, login string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := identityKey{provider: provider, login: login}
+	if _, ok := r.db.identities[key]; !ok {
+		return repo.ErrNotFound
+	}
+
+	delete(r.db.identities, key)
+	return nil
+}
+
+// identityByExternalID ищет учётную запись по числовому id; 0 — как NULL в pg, ни с чем не совпадает.
+func (db *DB) identityByExternalID(provider domain.IdentityProvider, externalID int64) *domain.Identity {
+	if externalID == 0 {
+		return nil
+	}
+	for _, i := range db.identities {
+		if i.Provider == provider && i.ExternalId == externalID {
+			return i
+		}
+	}
+	return nil
+}
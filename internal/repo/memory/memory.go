@@ -0,0 +1,163 @@
+// Package memory — хранилище в памяти с семантикой схемы Postgres: уникальные
+// ключи, внешние ключи и их ON DELETE, порядок выдачи как у запросов pg.
+package memory
+
+import (
+	"errors"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ repo.User         = &UserRepo{}
+	_ repo.Team         = &TeamRepo{}
+	_ repo.PullRequest  = &PullRequestRepo{}
+	_ repo.Identity     = &IdentityRepo{}
+	_ repo.Subscription = &SubscriptionRepo{}
+	_ repo.Notification = &NotificationRepo{}
+	_ repo.Outbox       = &OutboxRepo{}
+	_ repo.Stats        = &StatsRepo{}
+	_ repo.Audit        = &AuditRepo{}
+	_ repo.Factory      = &Factory{}
+)
+
+var (
+	errForeignKey = errors.New("foreign key violation")
+	errCheck      = errors.New("check constraint violation")
+)
+
+type membershipKey struct {
+	teamID string
+	userID string
+}
+
+// DB — общие для всех репозиториев таблицы под одним мьютексом.
+type DB struct {
+	mu sync.RWMutex
+	// txMu выстраивает транзакции Transactor в очередь
+	txMu sync.Mutex
+	now  func() time.Time
+
+	users       map[string]*domain.User
+	away        map[string]*domain.Away
+	teams       map[string]*domain.Team
+	memberships map[membershipKey]*domain.Membership
+	prs         map[string]*domain.PullRequest
+	// reviewers хранит ревьюверов PR в порядке назначения
+	reviewers map[string][]string
+	events    []*domain.PullRequestEvent
+	eventSeq  int64
+	syncs     map[string]*domain.ReviewSync
+
+	identities map[identityKey]*domain.Identity
+
+	subscriptions map[string]*domain.Subscription
+	deliveries    map[string]*domain.Delivery
+
+	preferences map[notificationKey]*domain.NotificationPreference
+	slaWarnings map[slaWarningKey]struct{}
+	digests     map[string]*domain.DigestSettings
+
+	audit map[string]*domain.AuditRecord
+
+	// outbox упорядочен по Id
+	outbox       []*domain.OutboxMessage
+	outboxSeq    int64
+	publishedSeq int64
+}
+
+func NewDB() *DB {
+	return &DB{
+		now:         time.Now,
+		users:       make(map[string]*domain.User),
+		away:        make(map[string]*domain.Away),
+		teams:       make(map[string]*domain.Team),
+		memberships: make(map[membershipKey]*domain.Membership),
+		prs:         make(map[string]*domain.PullRequest),
+		reviewers:   make(map[string][]string),
+		syncs:       make(map[string]*domain.ReviewSync),
+		identities:  make(map[identityKey]*domain.Identity),
+
+		subscriptions: make(map[string]*domain.Subscription),
+		deliveries:    make(map[string]*domain.Delivery),
+
+		preferences: make(map[notificationKey]*domain.NotificationPreference),
+		slaWarnings: make(map[slaWarningKey]struct{}),
+		digests:     make(map[string]*domain.DigestSettings),
+
+		audit: make(map[string]*domain.AuditRecord),
+	}
+}
+
+// SetClock подменяет источник NOW() для created_at, merged_at и т.п.
+func (db *DB) SetClock(now func() time.Time) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.now = now
+}
+
+// Factory создаёт репозитории поверх одного DB.
+type Factory struct {
+	db *DB
+}
+
+func NewFactory(db *DB) *Factory {
+	return &Factory{db: db}
+}
+
+func (f *Factory) User() repo.User                 { return NewUserRepo(f.db) }
+func (f *Factory) Team() repo.Team                 { return NewTeamRepo(f.db) }
+func (f *Factory) PullRequest() repo.PullRequest   { return NewPullRequestRepo(f.db) }
+func (f *Factory) Identity() repo.Identity         { return NewIdentityRepo(f.db) }
+func (f *Factory) Subscription() repo.Subscription { return NewSubscriptionRepo(f.db) }
+func (f *Factory) Notification() repo.Notification { return NewNotificationRepo(f.db) }
+func (f *Factory) Audit() repo.Audit               { return NewAuditRepo(f.db) }
+func (f *Factory) Outbox() repo.Outbox             { return NewOutboxRepo(f.db) }
+func (f *Factory) Stats() repo.Stats               { return NewStatsRepo(f.db) }
+func (f *Factory) Transactor() repo.Transactor     { return NewTransactor(f.db) }
+
+// keyset оставляет записи строго после курсора и сортирует их по (created_at, id).
+func keyset[T any](items []T, order domain.SortOrder, after *domain.Cursor, key func(T) (time.Time, string)) []T {
+	less := func(at time.Time, id string, bt time.Time, bid string) bool {
+		if !at.Equal(bt) {
+			return at.Before(bt)
+		}
+		return id < bid
+	}
+
+	var res []T
+	for _, it := range items {
+		if after != nil {
+			at, id := key(it)
+			if order == domain.SortNewest && !less(at, id, after.CreatedAt, after.Id) {
+				continue
+			}
+			if order != domain.SortNewest && !less(after.CreatedAt, after.Id, at, id) {
+				continue
+			}
+		}
+		res = append(res, it)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		at, aid := key(res[i])
+		bt, bid := key(res[j])
+		if order == domain.SortNewest {
+			return less(bt, bid, at, aid)
+		}
+		return less(at, aid, bt, bid)
+	})
+
+	return res
+}
+
+func limit[T any](items []T, n int) []T {
+	if n > 0 && len(items) > n {
+		return items[:n]
+	}
+	return items
+}
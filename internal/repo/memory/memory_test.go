@@ -0,0 +1,25 @@
+package memory_test
+
+import (
+	"testing"
+
+	"gopr/internal/repo/memory"
+	"gopr/internal/repo/testhelpers"
+)
+
+func TestConformance(t *testing.T) {
+	testhelpers.RunConformance(t, func(t *testing.T) testhelpers.Repos {
+		db := memory.NewDB()
+		return testhelpers.Repos{
+			User:         memory.NewUserRepo(db),
+			Team:         memory.NewTeamRepo(db),
+			PullRequest:  memory.NewPullRequestRepo(db),
+			Identity:     memory.NewIdentityRepo(db),
+			Subscription: memory.NewSubscriptionRepo(db),
+			Notification: memory.NewNotificationRepo(db),
+			Outbox:       memory.NewOutboxRepo(db),
+			Audit:        memory.NewAuditRepo(db),
+			Transactor:   memory.NewTransactor(db),
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"slices"
+	"time"
+)
+
+type notificationKey struct {
+	userID  string
+	channel domain.NotificationChannel
+}
+
+type slaWarningKey struct {
+	prID       string
+	reviewerID string
+}
+
+type NotificationRepo struct {
+	db *DB
+}
+
+func NewNotificationRepo(db *DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) SetPreference(_ context.Context, pref *domain.NotificationPreference) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	switch pref.Channel {
+	case domain.NotificationSlack, domain.NotificationEmail, domain.NotificationHTTP:
+	default:
+		return fmt.Errorf("upsert notification_preference: %w", errCheck)
+	}
+	if _, ok := r.db.users[pref.UserId]; !ok {
+		return fmt.Errorf("upsert notification_preference: %w", errForeignKey)
+	}
+
+	key := notificationKey{userID: pref.UserId, channel: pref.Channel}
+	ts := r.db.now()
+	if old, ok := r.db.preferences[key]; ok {
+		pref.CreatedAt = old.CreatedAt
+	} else {
+		pref.CreatedAt = ts
+	}
+	pref.UpdatedAt = ts
+
+	c := *pref
+	r.db.preferences[key] = &c
+	return nil
+}
+
+func (r *NotificationRepo) DeletePreference(_ context.Context, userID string, channel domain.NotificationChannel) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := notificationKey{userID: userID, channel: channel}
+	if _, ok := r.db.preferences[key]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.db.preferences, key)
+	return nil
+}
+
+func (r *NotificationRepo) ListPreferences(_ context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.NotificationPreference
+	for key, p := range r.db.preferences {
+		if key.userID == userID {
+			c := *p
+			res = append(res, &c)
+		}
+	}
+	slices.SortFunc(res, func(a, b *domain.NotificationPreference) int {
+		return cmp.Compare(a.Channel, b.Channel)
+	})
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkSLAWarned(_ context.Context, prID, reviewerID string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.prs[prID]; !ok {
+		return false, fmt.Errorf("insert notification_sla_warning: %w", errForeignKey)
+	}
+
+	key := slaWarningKey{prID: prID, reviewerID: reviewerID}
+	if _, ok := r.db.slaWarnings[key]; ok {
+		return false, nil
+	}
+	r.db.slaWarnings[key] = struct{}{}
+	return true, nil
+}
+
+func (r *NotificationRepo) UnmarkSLAWarned(_ context.Context, prID, reviewerID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.slaWarnings, slaWarningKey{prID: prID, reviewerID: reviewerID})
+	return nil
+}
+
+func (r *NotificationRepo) SetDigest(_ context.Context, settings *domain.DigestSettings) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if !validHour(settings.Hour) || (settings.QuietStart != nil && !validHour(*settings.QuietStart)) ||
+		(settings.QuietEnd != nil && !validHour(*settings.QuietEnd)) {
+		return fmt.Errorf("upsert notification_digest: %w", errCheck)
+	}
+	if _, ok := r.db.users[settings.UserId]; !ok {
+		return fmt.Errorf("upsert notification_digest: %w", errForeignKey)
+	}
+
+	settings.LastSentAt = nil
+	if old, ok := r.db.digests[settings.UserId]; ok {
+		settings.LastSentAt = old.LastSentAt
+	}
+	settings.UpdatedAt = r.db.now()
+
+	r.db.digests[settings.UserId] = copyDigest(settings)
+	return nil
+}
+
+func (r *NotificationRepo) GetDigest(_ context.Context, userID string) (*domain.DigestSettings, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	s, ok := r.db.digests[userID]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return copyDigest(s), nil
+}
+
+func (r *NotificationRepo) ListEnabledDigests(_ context.Context) ([]*domain.DigestSettings, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.DigestSettings
+	for _, s := range r.db.digests {
+		if s.Enabled {
+			res = append(res, copyDigest(s))
+		}
+	}
+	slices.SortFunc(res, func(a, b *domain.DigestSettings) int {
+		return cmp.Compare(a.UserId, b.UserId)
+	})
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkDigestSent(_ context.Context, userID string, prev *time.Time, sentAt time.Time) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	s, ok := r.db.digests[userID]
+	if !ok {
+		return false, nil
+	}
+	if (s.LastSentAt == nil) != (prev == nil) || (prev != nil && !s.LastSentAt.Equal(*prev)) {
+		return false, nil
+	}
+
+	s.LastSentAt = &sentAt
+	return true, nil
+}
+
+func validHour(h int) bool {
+	return h >= 0 && h <= 23
+}
+
+func copyDigest(s *domain.DigestSettings) *domain.DigestSettings {
+	c := *s
+	if s.QuietStart != nil {
+		v := *s.QuietStart
+		c.QuietStart = &v
+	}
+	if s.QuietEnd != nil {
+		v := *s.QuietEnd
+		c.QuietEnd = &v
+	}
+	if s.LastSentAt != nil {
+		v := *s.LastSentAt
+		c.LastSentAt = &v
+	}
+	return &c
+}
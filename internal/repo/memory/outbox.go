@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"slices"
+)
+
+type OutboxRepo struct {
+	db *DB
+}
+
+func NewOutboxRepo(db *DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (r *OutboxRepo) Add(_ context.Context, msg *domain.OutboxMessage) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.outboxSeq++
+	msg.Id = r.db.outboxSeq
+	msg.CreatedAt = r.db.now()
+
+	r.db.outbox = append(r.db.outbox, copyOutboxMessage(msg))
+	return nil
+}
+
+func (r *OutboxRepo) ListPending(_ context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.OutboxMessage
+	for _, msg := range r.db.outbox {
+		if msg.PublishedAt != nil || msg.DeadAt != nil {
+			continue
+		}
+		if len(res) == limit {
+			break
+		}
+		res = append(res, copyOutboxMessage(msg))
+	}
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkPublished(_ context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	msg := r.db.findOutboxMessage(id)
+	if msg == nil {
+		return repo.ErrNotFound
+	}
+
+	if msg.PublishedAt == nil {
+		ts := r.db.now()
+		r.db.publishedSeq++
+		msg.PublishedAt, msg.PublishedSeq = &ts, r.db.publishedSeq
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(_ context.Context, id int64, acceptedBy []string, lastError string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	msg := r.db.findOutboxMessage(id)
+	if msg == nil {
+		return repo.ErrNotFound
+	}
+
+	msg.Attempts++
+	msg.LastError = lastError
+	msg.AcceptedBy = slices.Clone(acceptedBy)
+	return nil
+}
+
+func (r *OutboxRepo) MarkDead(_ context.Context, id int64) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	msg := r.db.findOutboxMessage(id)
+	if msg == nil {
+		return repo.ErrNotFound
+	}
+
+	if msg.DeadAt == nil {
+		ts := r.db.now()
+		msg.DeadAt = &ts
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListPublished(_ context.Context, afterSeq int64, n int) ([]*domain.OutboxMessage, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.OutboxMessage
+	for _, msg := range r.db.outbox {
+		if msg.PublishedSeq > afterSeq {
+			res = append(res, copyOutboxMessage(msg))
+		}
+	}
+
+	slices.SortFunc(res, func(a, b *domain.OutboxMessage) int {
+		return cmp.Compare(a.PublishedSeq, b.PublishedSeq)
+	})
+	return limit(res, n), nil
+}
+
+func (r *OutboxRepo) LastPublishedSeq(_ context.Context) (int64, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return r.db.publishedSeq, nil
+}
+
+// findOutboxMessage ищет событие по id; outbox упорядочен по id. Вызывается под мьютексом.
+func (db *DB) findOutboxMessage(id int64) *domain.OutboxMessage {
+	i, ok := slices.BinarySearchFunc(db.outbox, id, func(msg *domain.OutboxMessage, id int64) int {
+		return cmp.Compare(msg.Id, id)
+	})
+	if !ok {
+		return nil
+	}
+	return db.outbox[i]
+}
+
+func copyOutboxMessage(msg *domain.OutboxMessage) *domain.OutboxMessage {
+	c := *msg
+	c.Payload = slices.Clone(msg.Payload)
+	c.AcceptedBy = slices.Clone(msg.AcceptedBy)
+	c.PublishedAt = copyTime(msg.PublishedAt)
+	c.DeadAt = copyTime(msg.DeadAt)
+	return &c
+}
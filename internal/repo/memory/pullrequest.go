@@ -0,0 +1,348 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type PullRequestRepo struct {
+	db *DB
+}
+
+func NewPullRequestRepo(db *DB) *PullRequestRepo {
+	return &PullRequestRepo{db: db}
+}
+
+func (r *PullRequestRepo) Create(_ context.Context, pr *domain.PullRequest) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.prs[pr.Id]; ok {
+		return fmt.Errorf("insert pull_request: %w", repo.ErrAlreadyExists)
+	}
+	if _, ok := r.db.users[pr.AuthorId]; !ok {
+		return fmt.Errorf("insert pull_request: %w", errForeignKey)
+	}
+	if pr.TeamId != "" {
+		if _, ok := r.db.teams[pr.TeamId]; !ok {
+			return fmt.Errorf("insert pull_request: %w", errForeignKey)
+		}
+	}
+
+	c := *pr
+	c.TeamName = ""
+	c.CreatedAt = r.db.now()
+	c.UpdatedAt = time.Time{}
+	c.MergedAt = nil
+	r.db.prs[c.Id] = &c
+
+	return nil
+}
+
+func (r *PullRequestRepo) GetByID(_ context.Context, id string) (*domain.PullRequest, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	pr, ok := r.db.prs[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	return r.db.readPR(pr), nil
+}
+
+func (r *PullRequestRepo) UpdateStatusMerged(_ context.Context, id string) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	pr, ok := r.db.prs[id]
+	if !ok {
+		return false, repo.ErrNotFound
+	}
+	if pr.Status != string(domain.PullRequestStatusOpen) {
+		return false, nil
+	}
+
+	next := *pr
+	next.Status = string(domain.PullRequestStatusClosed)
+	if next.MergedAt == nil {
+		now := r.db.now()
+		next.MergedAt = &now
+	}
+	r.db.prs[id] = &next
+
+	return true, nil
+}
+
+func (r *PullRequestRepo) AddReviewer(_ context.Context, prID, reviewerID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if slices.Contains(r.db.reviewers[prID], reviewerID) {
+		return fmt.Errorf("insert reviewer: %w", repo.ErrAlreadyExists)
+	}
+	if _, ok := r.db.prs[prID]; !ok {
+		return fmt.Errorf("insert reviewer: %w", errForeignKey)
+	}
+	if _, ok := r.db.users[reviewerID]; !ok {
+		return fmt.Errorf("insert reviewer: %w", errForeignKey)
+	}
+
+	r.db.reviewers[prID] = append(r.db.reviewers[prID], reviewerID)
+
+	return nil
+}
+
+func (r *PullRequestRepo) RemoveReviewer(_ context.Context, prID, reviewerID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	revs := r.db.reviewers[prID]
+	i := slices.Index(revs, reviewerID)
+	if i < 0 {
+		return repo.ErrNotFound
+	}
+
+	r.db.reviewers[prID] = slices.Delete(slices.Clone(revs), i, i+1)
+
+	return nil
+}
+
+func (r *PullRequestRepo) ListReviewers(_ context.Context, prID string) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if len(r.db.reviewers[prID]) == 0 {
+		return nil, nil
+	}
+
+	return slices.Clone(r.db.reviewers[prID]), nil
+}
+
+func (r *PullRequestRepo) ListReviewersByPRs(_ context.Context, prIDs []string) (map[string][]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	res := make(map[string][]string, len(prIDs))
+	for _, id := range prIDs {
+		if revs := r.db.reviewers[id]; len(revs) > 0 {
+			sorted := slices.Clone(revs)
+			slices.Sort(sorted)
+			res[id] = sorted
+		}
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) ListOpenReviewsByTeam(_ context.Context, teamID string) ([]*domain.ReviewAssignment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.ReviewAssignment
+	for prID, revs := range r.db.reviewers {
+		pr := r.db.prs[prID]
+		if pr.TeamId != teamID || pr.Status != string(domain.PullRequestStatusOpen) {
+			continue
+		}
+		for _, id := range revs {
+			res = append(res, &domain.ReviewAssignment{PullRequestId: prID, ReviewerId: id})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].PullRequestId != res[j].PullRequestId {
+			return res[i].PullRequestId < res[j].PullRequestId
+		}
+		return res[i].ReviewerId < res[j].ReviewerId
+	})
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(_ context.Context, reviewerIDs []string) (map[string]int, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	res := make(map[string]int, len(reviewerIDs))
+	for prID, revs := range r.db.reviewers {
+		if r.db.prs[prID].Status != string(domain.PullRequestStatusOpen) {
+			continue
+		}
+		for _, id := range revs {
+			if slices.Contains(reviewerIDs, id) {
+				res[id]++
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) AddEvent(_ context.Context, event *domain.PullRequestEvent) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.prs[event.PullRequestId]; !ok {
+		return fmt.Errorf("insert pull_request_history: %w", errForeignKey)
+	}
+
+	r.db.eventSeq++
+	event.Id = r.db.eventSeq
+	event.CreatedAt = r.db.now()
+
+	c := *event
+	r.db.events = append(r.db.events, &c)
+
+	return nil
+}
+
+func (r *PullRequestRepo) ListEvents(_ context.Context, prID string) ([]*domain.PullRequestEvent, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.PullRequestEvent
+	for _, ev := range r.db.events {
+		if ev.PullRequestId == prID {
+			c := *ev
+			res = append(res, &c)
+		}
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) SaveReviewSync(_ context.Context, sync *domain.ReviewSync) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.prs[sync.PullRequestId]; !ok {
+		return fmt.Errorf("upsert pull_request_sync: %w", errForeignKey)
+	}
+
+	sync.UpdatedAt = r.db.now()
+
+	c := *sync
+	r.db.syncs[sync.PullRequestId] = &c
+
+	return nil
+}
+
+func (r *PullRequestRepo) GetReviewSync(_ context.Context, prID string) (*domain.ReviewSync, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	s, ok := r.db.syncs[prID]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	c := *s
+	return &c, nil
+}
+
+func (r *PullRequestRepo) ListByReviewer(_ context.Context, filter *domain.ReviewerPRFilter) ([]*domain.PullRequest, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.PullRequest
+	for _, pr := range r.db.prs {
+		if !slices.Contains(r.db.reviewers[pr.Id], filter.ReviewerId) {
+			continue
+		}
+		if filter.Status != "" && pr.Status != string(filter.Status) {
+			continue
+		}
+		if filter.TeamId != "" && pr.TeamId != filter.TeamId {
+			continue
+		}
+		if !inRange(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+			continue
+		}
+		res = append(res, r.db.readPR(pr))
+	}
+
+	res = keyset(res, filter.Order, filter.After, prKey)
+
+	return limit(res, filter.Limit), nil
+}
+
+func (r *PullRequestRepo) List(_ context.Context, filter *domain.PullRequestFilter) ([]*domain.PullRequest, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	name := strings.ToLower(filter.NameContains)
+
+	var res []*domain.PullRequest
+	for _, pr := range r.db.prs {
+		read := r.db.readPR(pr)
+
+		if filter.Status != "" && pr.Status != string(filter.Status) {
+			continue
+		}
+		if filter.AuthorId != "" && pr.AuthorId != filter.AuthorId {
+			continue
+		}
+		if filter.TeamName != "" && read.TeamName != filter.TeamName {
+			continue
+		}
+		if filter.ReviewerId != "" && !slices.Contains(r.db.reviewers[pr.Id], filter.ReviewerId) {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(pr.Name), name) {
+			continue
+		}
+		if !inRange(pr.CreatedAt, filter.CreatedFrom, filter.CreatedTo) {
+			continue
+		}
+		if !filter.MergedFrom.IsZero() || !filter.MergedTo.IsZero() {
+			// NULL merged_at не проходит ни одно сравнение
+			if pr.MergedAt == nil || !inRange(*pr.MergedAt, filter.MergedFrom, filter.MergedTo) {
+				continue
+			}
+		}
+		if filter.FewerReviewersThan > 0 && len(r.db.reviewers[pr.Id]) >= filter.FewerReviewersThan {
+			continue
+		}
+
+		res = append(res, read)
+	}
+
+	res = keyset(res, filter.Order, filter.After, prKey)
+
+	return limit(res, filter.Limit), nil
+}
+
+// readPR копирует PR и подставляет название команды ревью; вызывается под мьютексом.
+func (db *DB) readPR(pr *domain.PullRequest) *domain.PullRequest {
+	c := *pr
+	if t, ok := db.teams[pr.TeamId]; ok {
+		c.TeamName = t.Name
+	}
+	if pr.MergedAt != nil {
+		merged := *pr.MergedAt
+		c.MergedAt = &merged
+	}
+	return &c
+}
+
+func prKey(pr *domain.PullRequest) (time.Time, string) {
+	return pr.CreatedAt, pr.Id
+}
+
+// inRange проверяет from <= t < to; нулевая граница не ограничивает.
+func inRange(t, from, to time.Time) bool {
+	if !from.IsZero() && t.Before(from) {
+		return false
+	}
+	if !to.IsZero() && !t.Before(to) {
+		return false
+	}
+	return true
+}
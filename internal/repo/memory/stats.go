@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"gopr/internal/domain"
+	"sort"
+	"time"
+)
+
+// StatsRepo считает те же агрегаты, что и pg.StatsRepo, перебором PR фильтра.
+type StatsRepo struct {
+	db *DB
+}
+
+func NewStatsRepo(db *DB) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+func (r *StatsRepo) UserStats(_ context.Context, filter *domain.StatsFilter) ([]*domain.UserStats, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	byUser := make(map[string]*domain.UserStats)
+	get := func(id string) *domain.UserStats {
+		if s, ok := byUser[id]; ok {
+			return s
+		}
+		u, ok := r.db.users[id]
+		if !ok {
+			// как JOIN с users в pg: назначения удалённых пользователей не считаются
+			return &domain.UserStats{}
+		}
+		s := &domain.UserStats{UserId: id, Username: u.Username}
+		byUser[id] = s
+		return s
+	}
+
+	prs := r.db.statsPRs(filter)
+	for _, pr := range prs {
+		for _, id := range r.db.reviewers[pr.Id] {
+			if pr.Status == string(domain.PullRequestStatusOpen) {
+				get(id).Open++
+			} else {
+				get(id).Merged++
+			}
+		}
+	}
+
+	for _, ev := range r.db.events {
+		if _, ok := prs[ev.PullRequestId]; !ok || ev.ReviewerId == "" {
+			continue
+		}
+		switch ev.Type {
+		case domain.PullRequestEventReviewerAssigned:
+			get(ev.ReviewerId).Assigned++
+		case domain.PullRequestEventReviewerRemoved:
+			get(ev.ReviewerId).ReassignedAway++
+		default:
+			get(ev.ReviewerId)
+		}
+	}
+
+	res := make([]*domain.UserStats, 0, len(byUser))
+	for _, s := range byUser {
+		res = append(res, s)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Assigned != res[j].Assigned {
+			return res[i].Assigned > res[j].Assigned
+		}
+		return res[i].UserId < res[j].UserId
+	})
+
+	return res, nil
+}
+
+func (r *StatsRepo) TeamStats(_ context.Context, filter *domain.StatsFilter) ([]*domain.TeamStats, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	byTeam := make(map[string]*domain.TeamStats, len(r.db.teams))
+	for id, t := range r.db.teams {
+		if filter.TeamId != "" && id != filter.TeamId {
+			continue
+		}
+		c := *t
+		byTeam[id] = &domain.TeamStats{Team: &c}
+	}
+
+	prs := r.db.statsPRs(filter)
+	for _, pr := range prs {
+		s, ok := byTeam[pr.TeamId]
+		if !ok {
+			continue
+		}
+		s.PullRequests++
+		if pr.Status == string(domain.PullRequestStatusOpen) {
+			s.Open++
+		} else {
+			s.Merged++
+		}
+	}
+
+	for _, ev := range r.db.events {
+		pr, ok := prs[ev.PullRequestId]
+		if !ok {
+			continue
+		}
+		s, ok := byTeam[pr.TeamId]
+		if !ok {
+			continue
+		}
+		switch ev.Type {
+		case domain.PullRequestEventReviewerAssigned:
+			s.Assignments++
+		case domain.PullRequestEventReviewerRemoved:
+			s.Reassignments++
+		}
+	}
+
+	res := make([]*domain.TeamStats, 0, len(byTeam))
+	for _, s := range byTeam {
+		res = append(res, s)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Team.Name < res[j].Team.Name
+	})
+
+	return res, nil
+}
+
+func (r *StatsRepo) MergeTime(_ context.Context, filter *domain.StatsFilter) (*domain.MergeTimeStats, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var secs []float64
+	for _, pr := range r.db.statsPRs(filter) {
+		if pr.MergedAt != nil {
+			secs = append(secs, pr.MergedAt.Sub(pr.CreatedAt).Seconds())
+		}
+	}
+
+	res := &domain.MergeTimeStats{Merged: len(secs)}
+	if len(secs) == 0 {
+		return res, nil
+	}
+
+	sort.Float64s(secs)
+
+	sum := 0.0
+	for _, s := range secs {
+		sum += s
+	}
+
+	res.Avg = seconds(sum / float64(len(secs)))
+	res.P50 = seconds(percentile(secs, 0.5))
+	res.P90 = seconds(percentile(secs, 0.9))
+	res.P95 = seconds(percentile(secs, 0.95))
+	res.P99 = seconds(percentile(secs, 0.99))
+
+	return res, nil
+}
+
+// statsPRs — PR, созданные в [From, To) и ревьюемые командой фильтра; вызывается под мьютексом.
+func (db *DB) statsPRs(filter *domain.StatsFilter) map[string]*domain.PullRequest {
+	res := make(map[string]*domain.PullRequest)
+	for id, pr := range db.prs {
+		if filter.TeamId != "" && pr.TeamId != filter.TeamId {
+			continue
+		}
+		if !inRange(pr.CreatedAt, filter.From, filter.To) {
+			continue
+		}
+		res[id] = pr
+	}
+	return res
+}
+
+// percentile повторяет percentile_cont: линейная интерполяция по отсортированной выборке.
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
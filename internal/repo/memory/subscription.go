@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"slices"
+	"time"
+)
+
+type SubscriptionRepo struct {
+	db *DB
+}
+
+func NewSubscriptionRepo(db *DB) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+func (r *SubscriptionRepo) Create(_ context.Context, sub *domain.Subscription) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.subscriptions[sub.Id]; ok {
+		return fmt.Errorf("insert webhook_subscription: %w", repo.ErrAlreadyExists)
+	}
+
+	sub.CreatedAt = r.db.now()
+
+	r.db.subscriptions[sub.Id] = copySubscription(sub)
+	return nil
+}
+
+func (r *SubscriptionRepo) GetByID(_ context.Context, id string) (*domain.Subscription, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	sub, ok := r.db.subscriptions[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return copySubscription(sub), nil
+}
+
+func (r *SubscriptionRepo) List(_ context.Context) ([]*domain.Subscription, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.Subscription
+	for _, sub := range r.db.subscriptions {
+		res = append(res, copySubscription(sub))
+	}
+
+	return keyset(res, domain.SortOldest, nil, func(s *domain.Subscription) (time.Time, string) {
+		return s.CreatedAt, s.Id
+	}), nil
+}
+
+func (r *SubscriptionRepo) Delete(_ context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.subscriptions[id]; !ok {
+		return repo.ErrNotFound
+	}
+
+	delete(r.db.subscriptions, id)
+	for deliveryID, d := range r.db.deliveries {
+		if d.SubscriptionId == id {
+			delete(r.db.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(_ context.Context, d *domain.Delivery) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.deliveries[d.Id]; ok {
+		return fmt.Errorf("insert webhook_delivery: %w", repo.ErrAlreadyExists)
+	}
+	if _, ok := r.db.subscriptions[d.SubscriptionId]; !ok {
+		return fmt.Errorf("insert webhook_delivery: %w", errForeignKey)
+	}
+
+	now := r.db.now()
+	d.CreatedAt, d.UpdatedAt = now, now
+
+	r.db.deliveries[d.Id] = copyDelivery(d)
+	return nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(_ context.Context, d *domain.Delivery) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.deliveries[d.Id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+
+	d.UpdatedAt = r.db.now()
+
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.ResponseCode = d.ResponseCode
+	stored.LastError = d.LastError
+	stored.NextAttemptAt = copyTime(d.NextAttemptAt)
+	stored.UpdatedAt = d.UpdatedAt
+	return nil
+}
+
+func (r *SubscriptionRepo) GetDelivery(_ context.Context, id string) (*domain.Delivery, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	d, ok := r.db.deliveries[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+	return copyDelivery(d), nil
+}
+
+func (r *SubscriptionRepo) ListDeliveries(_ context.Context, filter *domain.DeliveryFilter) ([]*domain.Delivery, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.Delivery
+	for _, d := range r.db.deliveries {
+		if d.SubscriptionId != filter.SubscriptionId {
+			continue
+		}
+		if filter.Status != "" && d.Status != filter.Status {
+			continue
+		}
+		res = append(res, copyDelivery(d))
+	}
+
+	res = keyset(res, filter.Order, filter.After, func(d *domain.Delivery) (time.Time, string) {
+		return d.CreatedAt, d.Id
+	})
+
+	return limit(res, filter.Limit), nil
+}
+
+func (r *SubscriptionRepo) ClaimDeliveries(_ context.Context, now, until time.Time, limit int) ([]*domain.Delivery, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	var due []*domain.Delivery
+	for _, d := range r.db.deliveries {
+		if d.Status == domain.DeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+	slices.SortFunc(due, func(a, b *domain.Delivery) int {
+		if c := a.NextAttemptAt.Compare(*b.NextAttemptAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Id, b.Id)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	res := make([]*domain.Delivery, 0, len(due))
+	for _, d := range due {
+		d.NextAttemptAt = &until
+		res = append(res, copyDelivery(d))
+	}
+	return res, nil
+}
+
+func copySubscription(sub *domain.Subscription) *domain.Subscription {
+	c := *sub
+	c.Events = slices.Clone(sub.Events)
+	return &c
+}
+
+func copyDelivery(d *domain.Delivery) *domain.Delivery {
+	c := *d
+	c.Payload = slices.Clone(d.Payload)
+	c.NextAttemptAt = copyTime(d.NextAttemptAt)
+	return &c
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	v := *t
+	return &v
+}
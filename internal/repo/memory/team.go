@@ -0,0 +1,364 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"sort"
+)
+
+type TeamRepo struct {
+	db *DB
+}
+
+func NewTeamRepo(db *DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+func (r *TeamRepo) Create(_ context.Context, team *domain.Team) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.teams[team.Id]; ok || r.db.teamByName(team.Name) != nil {
+		return fmt.Errorf("insert team: %w", repo.ErrAlreadyExists)
+	}
+	if err := r.db.checkParent(team.Id, team.ParentId); err != nil {
+		return fmt.Errorf("insert team: %w", err)
+	}
+
+	t := *team
+	r.db.teams[t.Id] = &t
+
+	return nil
+}
+
+func (r *TeamRepo) GetByID(_ context.Context, id string) (*domain.Team, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	t, ok := r.db.teams[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	res := *t
+	return &res, nil
+}
+
+func (r *TeamRepo) GetByName(_ context.Context, name string) (*domain.Team, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	t := r.db.teamByName(name)
+	if t == nil {
+		return nil, repo.ErrNotFound
+	}
+
+	res := *t
+	return &res, nil
+}
+
+func (r *TeamRepo) List(_ context.Context) ([]*domain.TeamSummary, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.TeamSummary
+	for _, t := range r.db.teams {
+		c := *t
+		summary := &domain.TeamSummary{Team: &c}
+
+		if p, ok := r.db.teams[t.ParentId]; ok {
+			summary.ParentName = p.Name
+		}
+		for _, m := range r.db.teamMemberships(t.Id) {
+			summary.MembersCount++
+			if r.db.users[m.UserId].IsActive {
+				summary.ActiveCount++
+			}
+		}
+
+		res = append(res, summary)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Team.Name < res[j].Team.Name
+	})
+
+	return res, nil
+}
+
+func (r *TeamRepo) Rename(_ context.Context, id, name string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t, ok := r.db.teams[id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if other := r.db.teamByName(name); other != nil && other.Id != id {
+		return fmt.Errorf("rename team: %w", repo.ErrAlreadyExists)
+	}
+
+	next := *t
+	next.Name = name
+	r.db.teams[id] = &next
+
+	return nil
+}
+
+func (r *TeamRepo) SetParent(_ context.Context, id, parentID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	t, ok := r.db.teams[id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+	if err := r.db.checkParent(id, parentID); err != nil {
+		return fmt.Errorf("update team parent: %w", err)
+	}
+
+	next := *t
+	next.ParentId = parentID
+	r.db.teams[id] = &next
+
+	return nil
+}
+
+// ListAncestors возвращает предков команды, начиная с непосредственного родителя.
+func (r *TeamRepo) ListAncestors(_ context.Context, id string) ([]*domain.Team, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.Team
+
+	t, ok := r.db.teams[id]
+	// защита от цикла: в pg её даёт проверка в usecase, здесь — ограничение глубины
+	for ok && len(res) < len(r.db.teams) {
+		p, found := r.db.teams[t.ParentId]
+		if !found {
+			break
+		}
+		c := *p
+		res = append(res, &c)
+		t = p
+	}
+
+	return res, nil
+}
+
+// ListSubtree возвращает команду и всех её потомков по уровням, корень первым.
+func (r *TeamRepo) ListSubtree(_ context.Context, id string) ([]*domain.Team, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	root, ok := r.db.teams[id]
+	if !ok {
+		return nil, nil
+	}
+
+	c := *root
+	res := []*domain.Team{&c}
+	level := []string{root.Id}
+
+	for len(level) > 0 && len(res) <= len(r.db.teams) {
+		var next []*domain.Team
+		for _, t := range r.db.teams {
+			for _, parentID := range level {
+				if t.ParentId == parentID {
+					c := *t
+					next = append(next, &c)
+				}
+			}
+		}
+
+		sort.Slice(next, func(i, j int) bool {
+			return next[i].Name < next[j].Name
+		})
+
+		level = level[:0]
+		for _, t := range next {
+			level = append(level, t.Id)
+		}
+		res = append(res, next...)
+	}
+
+	return res, nil
+}
+
+// Delete удаляет команду: членства удаляются, а основная команда
+// пользователей, команда ревью PR и родитель подкоманд сбрасываются.
+func (r *TeamRepo) Delete(_ context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.teams[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.db.teams, id)
+
+	for k := range r.db.memberships {
+		if k.teamID == id {
+			delete(r.db.memberships, k)
+		}
+	}
+	for uid, u := range r.db.users {
+		if u.TeamId == id {
+			next := *u
+			next.TeamId = ""
+			r.db.users[uid] = &next
+		}
+	}
+	for prID, pr := range r.db.prs {
+		if pr.TeamId == id {
+			next := *pr
+			next.TeamId = ""
+			r.db.prs[prID] = &next
+		}
+	}
+	for tid, t := range r.db.teams {
+		if t.ParentId == id {
+			next := *t
+			next.ParentId = ""
+			r.db.teams[tid] = &next
+		}
+	}
+
+	return nil
+}
+
+func (r *TeamRepo) AddMember(_ context.Context, m *domain.Membership) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := membershipKey{m.TeamId, m.UserId}
+	if _, ok := r.db.memberships[key]; ok {
+		return fmt.Errorf("insert team_membership: %w", repo.ErrAlreadyExists)
+	}
+	if _, ok := r.db.teams[m.TeamId]; !ok {
+		return fmt.Errorf("insert team_membership: %w", errForeignKey)
+	}
+	if _, ok := r.db.users[m.UserId]; !ok {
+		return fmt.Errorf("insert team_membership: %w", errForeignKey)
+	}
+
+	m.CreatedAt = r.db.now()
+	c := *m
+	r.db.memberships[key] = &c
+
+	return nil
+}
+
+func (r *TeamRepo) UpdateMemberRole(_ context.Context, teamID, userID string, role domain.MembershipRole) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := membershipKey{teamID, userID}
+	m, ok := r.db.memberships[key]
+	if !ok {
+		return repo.ErrNotFound
+	}
+
+	next := *m
+	next.Role = role
+	r.db.memberships[key] = &next
+
+	return nil
+}
+
+func (r *TeamRepo) RemoveMember(_ context.Context, teamID, userID string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := membershipKey{teamID, userID}
+	if _, ok := r.db.memberships[key]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.db.memberships, key)
+
+	return nil
+}
+
+func (r *TeamRepo) ListMembers(_ context.Context, teamID string) ([]*domain.TeamMember, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.TeamMember
+	for _, m := range r.db.teamMemberships(teamID) {
+		u := *r.db.users[m.UserId]
+		res = append(res, &domain.TeamMember{
+			User:     &u,
+			Role:     m.Role,
+			JoinedAt: m.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUser(_ context.Context, userID string) ([]*domain.UserTeam, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var memberships []*domain.Membership
+	for k, m := range r.db.memberships {
+		if k.userID == userID {
+			memberships = append(memberships, m)
+		}
+	}
+
+	sort.Slice(memberships, func(i, j int) bool {
+		if !memberships[i].CreatedAt.Equal(memberships[j].CreatedAt) {
+			return memberships[i].CreatedAt.Before(memberships[j].CreatedAt)
+		}
+		return r.db.teams[memberships[i].TeamId].Name < r.db.teams[memberships[j].TeamId].Name
+	})
+
+	var res []*domain.UserTeam
+	for _, m := range memberships {
+		t := *r.db.teams[m.TeamId]
+		res = append(res, &domain.UserTeam{Team: &t, Role: m.Role})
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUsers(ctx context.Context, userIDs []string) (map[string][]*domain.UserTeam, error) {
+	res := make(map[string][]*domain.UserTeam, len(userIDs))
+	for _, id := range userIDs {
+		teams, err := r.ListByUser(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if len(teams) > 0 {
+			res[id] = teams
+		}
+	}
+
+	return res, nil
+}
+
+// teamByName вызывается под мьютексом.
+func (db *DB) teamByName(name string) *domain.Team {
+	for _, t := range db.teams {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// checkParent повторяет fk_team_parent и chk_team_parent_self; вызывается под мьютексом.
+func (db *DB) checkParent(id, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	if parentID == id {
+		return errCheck
+	}
+	if _, ok := db.teams[parentID]; !ok {
+		return errForeignKey
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"maps"
+	"slices"
+)
+
+var _ repo.Transactor = &Transactor{}
+
+type txKey struct{}
+
+// Transactor хранилища в памяти выполняет транзакции по одной: перед fn
+// снимает копию таблиц и, если fn вернула ошибку, возвращает её на место.
+// Изменения, сделанные за это время мимо транзакции, при откате теряются.
+type Transactor struct {
+	db *DB
+}
+
+func NewTransactor(db *DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// InTx выполняет fn в транзакции; вложенный вызов работает в уже открытой.
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if db, ok := ctx.Value(txKey{}).(*DB); ok && db == t.db {
+		return fn(ctx)
+	}
+
+	t.db.txMu.Lock()
+	defer t.db.txMu.Unlock()
+
+	t.db.mu.Lock()
+	saved := t.db.snapshot()
+	t.db.mu.Unlock()
+
+	committed := false
+	defer func() {
+		if !committed {
+			t.db.mu.Lock()
+			t.db.restore(saved)
+			t.db.mu.Unlock()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, t.db)); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// tables — копия таблиц DB для отката транзакции.
+type tables struct {
+	users       map[string]*domain.User
+	away        map[string]*domain.Away
+	teams       map[string]*domain.Team
+	memberships map[membershipKey]*domain.Membership
+	prs         map[string]*domain.PullRequest
+	reviewers   map[string][]string
+	events      []*domain.PullRequestEvent
+	eventSeq    int64
+	syncs       map[string]*domain.ReviewSync
+
+	identities map[identityKey]*domain.Identity
+
+	subscriptions map[string]*domain.Subscription
+	deliveries    map[string]*domain.Delivery
+
+	preferences map[notificationKey]*domain.NotificationPreference
+	slaWarnings map[slaWarningKey]struct{}
+	digests     map[string]*domain.DigestSettings
+
+	audit map[string]*domain.AuditRecord
+
+	outbox       []*domain.OutboxMessage
+	outboxSeq    int64
+	publishedSeq int64
+}
+
+// snapshot копирует таблицы под db.mu. Строки копируются по значению: репозитории
+// меняют сохранённые строки только присваиванием полей.
+func (db *DB) snapshot() *tables {
+	reviewers := make(map[string][]string, len(db.reviewers))
+	for prID, ids := range db.reviewers {
+		reviewers[prID] = slices.Clone(ids)
+	}
+
+	return &tables{
+		users:       cloneRows(db.users),
+		away:        cloneRows(db.away),
+		teams:       cloneRows(db.teams),
+		memberships: cloneRows(db.memberships),
+		prs:         cloneRows(db.prs),
+		reviewers:   reviewers,
+		events:      cloneList(db.events),
+		eventSeq:    db.eventSeq,
+		syncs:       cloneRows(db.syncs),
+
+		identities: cloneRows(db.identities),
+
+		subscriptions: cloneRows(db.subscriptions),
+		deliveries:    cloneRows(db.deliveries),
+
+		preferences: cloneRows(db.preferences),
+		slaWarnings: maps.Clone(db.slaWarnings),
+		digests:     cloneRows(db.digests),
+
+		audit: cloneRows(db.audit),
+
+		outbox:       cloneList(db.outbox),
+		outboxSeq:    db.outboxSeq,
+		publishedSeq: db.publishedSeq,
+	}
+}
+
+// restore возвращает таблицы из копии; вызывается под db.mu.
+func (db *DB) restore(s *tables) {
+	db.users, db.away, db.teams, db.memberships = s.users, s.away, s.teams, s.memberships
+	db.prs, db.reviewers, db.events, db.eventSeq, db.syncs = s.prs, s.reviewers, s.events, s.eventSeq, s.syncs
+	db.identities = s.identities
+	db.subscriptions, db.deliveries = s.subscriptions, s.deliveries
+	db.preferences, db.slaWarnings, db.digests = s.preferences, s.slaWarnings, s.digests
+	db.audit = s.audit
+	db.outbox, db.outboxSeq, db.publishedSeq = s.outbox, s.outboxSeq, s.publishedSeq
+}
+
+func cloneRows[K comparable, V any](rows map[K]*V) map[K]*V {
+	res := make(map[K]*V, len(rows))
+	for k, v := range rows {
+		c := *v
+		res[k] = &c
+	}
+	return res
+}
+
+func cloneList[V any](rows []*V) []*V {
+	res := make([]*V, 0, len(rows))
+	for _, v := range rows {
+		c := *v
+		res = append(res, &c)
+	}
+	return res
+}
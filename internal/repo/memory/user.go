@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"sort"
+	"strings"
+	"time"
+)
+
+type UserRepo struct {
+	db *DB
+}
+
+func NewUserRepo(db *DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+// Create добавляет пользователя; если задана основная команда, он сразу
+// становится её участником с ролью member.
+func (r *UserRepo) Create(_ context.Context, user *domain.User) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[user.Id]; ok {
+		return fmt.Errorf("insert user: %w", repo.ErrAlreadyExists)
+	}
+	if user.TeamId != "" {
+		if _, ok := r.db.teams[user.TeamId]; !ok {
+			return fmt.Errorf("insert user: %w", errForeignKey)
+		}
+	}
+
+	now := r.db.now()
+	u := *user
+	u.CreatedAt = now
+	u.UpdatedAt = now
+	r.db.users[u.Id] = &u
+
+	if u.TeamId != "" {
+		r.db.memberships[membershipKey{u.TeamId, u.Id}] = &domain.Membership{
+			TeamId:    u.TeamId,
+			UserId:    u.Id,
+			Role:      domain.RoleMember,
+			CreatedAt: now,
+		}
+	}
+
+	return nil
+}
+
+func (r *UserRepo) GetByID(_ context.Context, id string) (*domain.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	u, ok := r.db.users[id]
+	if !ok {
+		return nil, repo.ErrNotFound
+	}
+
+	res := *u
+	return &res, nil
+}
+
+func (r *UserRepo) UpdateIsActive(_ context.Context, id string, isActive bool) error {
+	return r.update(id, func(u *domain.User) error {
+		u.IsActive = isActive
+		return nil
+	})
+}
+
+func (r *UserRepo) UpdateTeam(_ context.Context, id, teamID string) error {
+	return r.update(id, func(u *domain.User) error {
+		if teamID != "" {
+			if _, ok := r.db.teams[teamID]; !ok {
+				return fmt.Errorf("update user team: %w", errForeignKey)
+			}
+		}
+		u.TeamId = teamID
+		return nil
+	})
+}
+
+func (r *UserRepo) UpdateUsername(_ context.Context, id, username string) error {
+	return r.update(id, func(u *domain.User) error {
+		u.Username = username
+		return nil
+	})
+}
+
+func (r *UserRepo) update(id string, fn func(u *domain.User) error) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u, ok := r.db.users[id]
+	if !ok {
+		return repo.ErrNotFound
+	}
+
+	next := *u
+	if err := fn(&next); err != nil {
+		return err
+	}
+	next.UpdatedAt = r.db.now()
+	r.db.users[id] = &next
+
+	return nil
+}
+
+func (r *UserRepo) ListByTeam(_ context.Context, teamID string, onlyActive bool) ([]*domain.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	members := r.db.teamMemberships(teamID)
+
+	var res []*domain.User
+	for _, m := range members {
+		u := *r.db.users[m.UserId]
+		if onlyActive && !u.IsActive {
+			continue
+		}
+		res = append(res, &u)
+	}
+
+	return res, nil
+}
+
+func (r *UserRepo) List(_ context.Context, filter *domain.UserFilter) ([]*domain.User, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	prefix := strings.ToLower(filter.UsernamePrefix)
+
+	var users []*domain.User
+	for _, u := range r.db.users {
+		if !strings.HasPrefix(strings.ToLower(u.Username), prefix) {
+			continue
+		}
+		if filter.TeamId != "" {
+			if _, ok := r.db.memberships[membershipKey{filter.TeamId, u.Id}]; !ok {
+				continue
+			}
+		}
+		c := *u
+		users = append(users, &c)
+	}
+
+	users = keyset(users, domain.SortOldest, filter.After, func(u *domain.User) (time.Time, string) {
+		return u.CreatedAt, u.Id
+	})
+
+	return limit(users, filter.Limit), nil
+}
+
+// teamMemberships возвращает членства команды по (created_at, user_id); вызывать под мьютексом.
+func (db *DB) teamMemberships(teamID string) []*domain.Membership {
+	var res []*domain.Membership
+	for k, m := range db.memberships {
+		if k.teamID == teamID {
+			res = append(res, m)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].CreatedAt.Equal(res[j].CreatedAt) {
+			return res[i].CreatedAt.Before(res[j].CreatedAt)
+		}
+		return res[i].UserId < res[j].UserId
+	})
+
+	return res
+}
+
+func (r *UserRepo) SetAway(_ context.Context, away *domain.Away) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[away.UserId]; !ok {
+		return fmt.Errorf("upsert user_away: %w", errForeignKey)
+	}
+
+	if old, ok := r.db.away[away.UserId]; ok {
+		away.CreatedAt = old.CreatedAt
+	} else {
+		away.CreatedAt = r.db.now()
+	}
+
+	a := *away
+	r.db.away[a.UserId] = &a
+	return nil
+}
+
+func (r *UserRepo) DeleteAway(_ context.Context, id string) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.away[id]; !ok {
+		return repo.ErrNotFound
+	}
+	delete(r.db.away, id)
+	return nil
+}
+
+func (r *UserRepo) ListReturning(_ context.Context, now time.Time) ([]*domain.Away, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var res []*domain.Away
+	for _, a := range r.db.away {
+		if !a.Until.After(now) {
+			c := *a
+			res = append(res, &c)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Until.Equal(res[j].Until) {
+			return res[i].Until.Before(res[j].Until)
+		}
+		return res[i].UserId < res[j].UserId
+	})
+
+	return res, nil
+}
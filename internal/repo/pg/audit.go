@@ -0,0 +1,148 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewAuditRepo(db *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *AuditRepo) Append(ctx context.Context, record *domain.AuditRecord) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO audit_log(id, actor, claimed_actor, action, target_type, target_id, snapshot_before, snapshot_after, request_id, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::json, NULLIF($8, '')::json, $9, NOW())
+         RETURNING created_at`,
+		record.Id,
+		record.Actor,
+		record.ClaimedActor,
+		record.Action,
+		record.TargetType,
+		record.TargetId,
+		string(record.Before),
+		string(record.After),
+		record.RequestId,
+	).Scan(&record.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert audit_log: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert audit_log: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepo) List(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	builder := r.psql.
+		Select(
+			"id", "actor", "claimed_actor", "action", "target_type", "target_id",
+			"COALESCE(snapshot_before::text, '')", "COALESCE(snapshot_after::text, '')",
+			"request_id", "created_at",
+		).
+		From("audit_log")
+
+	if filter.Actor != "" {
+		builder = builder.Where(sq.Eq{"actor": filter.Actor})
+	}
+	if filter.ClaimedActor != "" {
+		builder = builder.Where(sq.Eq{"claimed_actor": filter.ClaimedActor})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(sq.Eq{"action": filter.Action})
+	}
+	if filter.TargetType != "" {
+		builder = builder.Where(sq.Eq{"target_type": filter.TargetType})
+	}
+	if filter.TargetId != "" {
+		builder = builder.Where(sq.Eq{"target_id": filter.TargetId})
+	}
+	if filter.RequestId != "" {
+		builder = builder.Where(sq.Eq{"request_id": filter.RequestId})
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.From})
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.To})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listAudit: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listAudit: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.AuditRecord
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit_log: %w", err)
+		}
+		res = append(res, record)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *AuditRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM audit_log WHERE created_at < $1`,
+		before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete audit_log: %w", err)
+	}
+	return res.RowsAffected(), nil
+}
+
+func scanAuditRecord(row pgx.Row) (*domain.AuditRecord, error) {
+	var (
+		record        domain.AuditRecord
+		before, after string
+	)
+	err := row.Scan(
+		&record.Id, &record.Actor, &record.ClaimedActor, &record.Action, &record.TargetType, &record.TargetId,
+		&before, &after, &record.RequestId, &record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if before != "" {
+		record.Before = []byte(before)
+	}
+	if after != "" {
+		record.After = []byte(after)
+	}
+
+	return &record, nil
+}
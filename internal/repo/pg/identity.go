@@ -0,0 +1,142 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdentityRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewIdentityRepo(db *pgxpool.Pool) *IdentityRepo {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) Create(ctx context.Context, identity *domain.Identity) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO user_identity(provider, login, user_id, external_id, created_at)
+         VALUES ($1, $2, $3, NULLIF($4::BIGINT, 0), NOW())
+         RETURNING created_at`,
+		identity.Provider,
+		identity.Login,
+		identity.UserId,
+		identity.ExternalId,
+	).Scan(&identity.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert user_identity: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert user_identity: %w", err)
+	}
+	return nil
+}
+
+func (r *IdentityRepo) GetByLogin(ctx context.Context, provider domain.IdentityProvider, login string) (*domain.Identity, error) {
+	row := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE provider = $1 AND login = $2`,
+		provider,
+		login,
+	)
+
+	return scanIdentity(row)
+}
+
+func (r *IdentityRepo) GetByExternalID(ctx context.Context, provider domain.IdentityProvider, externalID int64) (*domain.Identity, error) {
+	row := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE provider = $1 AND external_id = $2`,
+		provider,
+		externalID,
+	)
+
+	return scanIdentity(row)
+}
+
+func (r *IdentityRepo) ListByUser(ctx context.Context, userID string) ([]*domain.Identity, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE user_id = $1
+         ORDER BY created_at, provider, login`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user_identity: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Identity
+	for rows.Next() {
+		var i domain.Identity
+		if err := rows.Scan(&i.Provider, &i.Login, &i.UserId, &i.ExternalId, &i.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan user_identity: %w", err)
+		}
+		res = append(res, &i)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *IdentityRepo) UpdateExternalID(ctx context.Context, provider domain.IdentityProvider, login string, externalID int64) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE user_identity
+         SET external_id = NULLIF($3::BIGINT, 0)
+         WHERE provider = $1 AND login = $2`,
+		provider,
+		login,
+		externalID,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("update user_identity: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("update user_identity: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *IdentityRepo) Delete(ctx context.Context, provider domain.IdentityProvider, login string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM user_identity
+         WHERE provider = $1 AND login = $2`,
+		provider,
+		login,
+	)
+	if err != nil {
+		return fmt.Errorf("delete user_identity: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func scanIdentity(row pgx.Row) (*domain.Identity, error) {
+	var i domain.Identity
+
+	err := row.Scan(&i.Provider, &i.Login, &i.UserId, &i.ExternalId, &i.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select user_identity: %w", err)
+	}
+
+	return &i, nil
+}
@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ repo.OutboxListener = &Factory{}
+
+// outboxChannel — канал NOTIFY, в который триггер outbox пишет о публикации.
+const outboxChannel = "gopr_outbox"
+
+// relistenDelay — пауза перед повторной подпиской после обрыва соединения.
+const relistenDelay = time.Second
+
+// ListenOutbox слушает публикации outbox на отдельном соединении пула. После
+// обрыва соединение переподключается и канал получает сигнал: за время обрыва
+// события могли быть пропущены.
+func (f *Factory) ListenOutbox(ctx context.Context) (<-chan struct{}, error) {
+	c, err := listen(ctx, f.db)
+	if err != nil {
+		return nil, err
+	}
+
+	wake := make(chan struct{}, 1)
+	signal := func() {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(wake)
+
+		for {
+			for c != nil {
+				if _, err := c.Conn().WaitForNotification(ctx); err != nil {
+					// соединение с LISTEN в пул не возвращаем
+					_ = c.Hijack().Close(context.Background())
+					c = nil
+					if ctx.Err() != nil {
+						return
+					}
+					slogx.WithErr(slogx.FromCtx(ctx), err).Warn("outbox listener disconnected")
+				}
+				signal()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(relistenDelay):
+			}
+
+			if c, err = listen(ctx, f.db); err != nil {
+				slogx.WithErr(slogx.FromCtx(ctx), err).Warn("failed to relisten outbox")
+				continue
+			}
+			signal()
+		}
+	}()
+
+	return wake, nil
+}
+
+func listen(ctx context.Context, db *pgxpool.Pool) (*pgxpool.Conn, error) {
+	c, err := db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire listen conn: %w", err)
+	}
+
+	if _, err := c.Exec(ctx, "LISTEN "+outboxChannel); err != nil {
+		_ = c.Hijack().Close(context.Background())
+		return nil, fmt.Errorf("listen %s: %w", outboxChannel, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,41 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/repo"
+)
+
+var _ repo.OutboxLocker = &Factory{}
+
+// outboxLockKey — ключ advisory-блокировки relay outbox.
+const outboxLockKey = 7_240_301
+
+// LockOutbox берёт сессионную advisory-блокировку на отдельном соединении пула
+// и держит его до unlock. Если соединение оборвётся, блокировка снимется сама.
+func (f *Factory) LockOutbox(ctx context.Context) (func(), bool, error) {
+	c, err := f.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire lock conn: %w", err)
+	}
+
+	var ok bool
+	if err := c.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, outboxLockKey).Scan(&ok); err != nil {
+		_ = c.Hijack().Close(context.Background())
+		return nil, false, fmt.Errorf("lock outbox: %w", err)
+	}
+	if !ok {
+		c.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// соединение, не отпустившее блокировку, в пул не возвращаем
+		if _, err := c.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, outboxLockKey); err != nil {
+			_ = c.Hijack().Close(context.Background())
+			return
+		}
+		c.Release()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,142 @@
+package pg
+
+import (
+	"errors"
+	"fmt"
+	"gopr/migrations"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+var (
+	ErrSchemaOutdated = errors.New("database schema is behind the binary")
+	ErrSchemaDirty    = errors.New("database schema is dirty")
+)
+
+// Migrator применяет встроенные миграции к базе по DSN.
+type Migrator struct {
+	m      *migrate.Migrate
+	latest uint
+}
+
+func NewMigrator(dsn string) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("open migrations: %w", err)
+	}
+
+	latest, err := lastVersion(src)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("migrate init: %w", err)
+	}
+
+	return &Migrator{m: m, latest: latest}, nil
+}
+
+// LatestVersion — версия последней миграции, встроенной в бинарник.
+func LatestVersion() (uint, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("open migrations: %w", err)
+	}
+	defer src.Close()
+
+	return lastVersion(src)
+}
+
+func lastVersion(src source.Driver) (uint, error) {
+	v, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("first migration: %w", err)
+	}
+
+	for {
+		next, err := src.Next(v)
+		if errors.Is(err, fs.ErrNotExist) {
+			return v, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("next migration: %w", err)
+		}
+		v = next
+	}
+}
+
+func (m *Migrator) Latest() uint {
+	return m.latest
+}
+
+// Up применяет steps миграций вперёд, steps <= 0 — все недостающие.
+func (m *Migrator) Up(steps int) error {
+	var err error
+	if steps > 0 {
+		err = m.m.Steps(steps)
+	} else {
+		err = m.m.Up()
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+	return nil
+}
+
+// Down откатывает steps последних миграций.
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("migrate down: steps must be positive, got %d", steps)
+	}
+	if err := m.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate down: %w", err)
+	}
+	return nil
+}
+
+// Version возвращает текущую версию схемы; 0 — миграции не применялись.
+func (m *Migrator) Version() (uint, bool, error) {
+	v, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("migrate version: %w", err)
+	}
+	return v, dirty, nil
+}
+
+// Force записывает версию без выполнения миграций и снимает флаг dirty.
+func (m *Migrator) Force(version int) error {
+	if err := m.m.Force(version); err != nil {
+		return fmt.Errorf("migrate force: %w", err)
+	}
+	return nil
+}
+
+// Check проверяет, что схема не отстаёт от бинарника и не осталась
+// в промежуточном состоянии после упавшей миграции.
+func (m *Migrator) Check() error {
+	v, dirty, err := m.Version()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("%w: version %d", ErrSchemaDirty, v)
+	}
+	if v < m.latest {
+		return fmt.Errorf("%w: version %d, want %d", ErrSchemaOutdated, v, m.latest)
+	}
+	return nil
+}
+
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
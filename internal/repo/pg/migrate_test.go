@@ -0,0 +1,51 @@
+package pg_test
+
+import (
+	"io/fs"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/repo/pg"
+	"gopr/internal/repo/testhelpers"
+	"gopr/migrations"
+)
+
+func TestLatestVersion(t *testing.T) {
+	ups, err := fs.Glob(migrations.FS, "*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, ups)
+
+	latest, err := pg.LatestVersion()
+	require.NoError(t, err)
+
+	version, err := strconv.ParseUint(strings.SplitN(ups[len(ups)-1], "_", 2)[0], 10, 64)
+	require.NoError(t, err)
+	require.EqualValues(t, version, latest)
+}
+
+func TestMigrator_E2E(t *testing.T) {
+	dsn, cleanup := testhelpers.StartPostgresContainer(t)
+	defer cleanup()
+
+	m, err := pg.NewMigrator(dsn)
+	require.NoError(t, err)
+	defer m.Close()
+
+	require.ErrorIs(t, m.Check(), pg.ErrSchemaOutdated)
+
+	require.NoError(t, m.Up(0))
+	require.NoError(t, m.Check())
+
+	require.NoError(t, m.Down(1))
+	version, dirty, err := m.Version()
+	require.NoError(t, err)
+	require.False(t, dirty)
+	require.Equal(t, m.Latest()-1, version)
+	require.ErrorIs(t, m.Check(), pg.ErrSchemaOutdated)
+
+	require.NoError(t, m.Force(int(m.Latest())))
+	require.NoError(t, m.Check())
+}
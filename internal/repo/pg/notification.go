@@ -0,0 +1,195 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationRepo(db *pgxpool.Pool) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) SetPreference(ctx context.Context, pref *domain.NotificationPreference) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO notification_preference(user_id, channel, address, created_at, updated_at)
+         VALUES ($1, $2, $3, NOW(), NOW())
+         ON CONFLICT (user_id, channel) DO UPDATE SET address = EXCLUDED.address, updated_at = NOW()
+         RETURNING created_at, updated_at`,
+		pref.UserId,
+		pref.Channel,
+		pref.Address,
+	).Scan(&pref.CreatedAt, &pref.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert notification_preference: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM notification_preference WHERE user_id = $1 AND channel = $2`,
+		userID,
+		channel,
+	)
+	if err != nil {
+		return fmt.Errorf("delete notification_preference: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepo) ListPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT user_id, channel, address, created_at, updated_at
+         FROM notification_preference
+         WHERE user_id = $1
+         ORDER BY channel`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_preference: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.NotificationPreference
+	for rows.Next() {
+		var p domain.NotificationPreference
+		if err := rows.Scan(&p.UserId, &p.Channel, &p.Address, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan notification_preference: %w", err)
+		}
+		res = append(res, &p)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkSLAWarned(ctx context.Context, prID, reviewerID string) (bool, error) {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO notification_sla_warning(pull_request_id, reviewer_id, warned_at)
+         VALUES ($1, $2, NOW())
+         ON CONFLICT DO NOTHING`,
+		prID,
+		reviewerID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert notification_sla_warning: %w", err)
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func (r *NotificationRepo) UnmarkSLAWarned(ctx context.Context, prID, reviewerID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM notification_sla_warning
+         WHERE pull_request_id = $1 AND reviewer_id = $2`,
+		prID,
+		reviewerID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete notification_sla_warning: %w", err)
+	}
+	return nil
+}
+
+const digestColumns = `user_id, enabled, timezone, hour, quiet_start, quiet_end, last_sent_at, updated_at`
+
+func (r *NotificationRepo) SetDigest(ctx context.Context, settings *domain.DigestSettings) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO notification_digest(user_id, enabled, timezone, hour, quiet_start, quiet_end, updated_at)
+         VALUES ($1, $2, $3, $4, $5, $6, NOW())
+         ON CONFLICT (user_id) DO UPDATE SET enabled     = EXCLUDED.enabled,
+                                             timezone    = EXCLUDED.timezone,
+                                             hour        = EXCLUDED.hour,
+                                             quiet_start = EXCLUDED.quiet_start,
+                                             quiet_end   = EXCLUDED.quiet_end,
+                                             updated_at  = NOW()
+         RETURNING last_sent_at, updated_at`,
+		settings.UserId,
+		settings.Enabled,
+		settings.Timezone,
+		settings.Hour,
+		settings.QuietStart,
+		settings.QuietEnd,
+	).Scan(&settings.LastSentAt, &settings.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert notification_digest: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepo) GetDigest(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+digestColumns+` FROM notification_digest WHERE user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_digest: %w", err)
+	}
+
+	settings, err := collectDigests(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, repo.ErrNotFound
+	}
+	return settings[0], nil
+}
+
+func (r *NotificationRepo) ListEnabledDigests(ctx context.Context) ([]*domain.DigestSettings, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT `+digestColumns+` FROM notification_digest WHERE enabled ORDER BY user_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_digest: %w", err)
+	}
+
+	return collectDigests(rows)
+}
+
+func (r *NotificationRepo) MarkDigestSent(ctx context.Context, userID string, prev *time.Time, sentAt time.Time) (bool, error) {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE notification_digest
+         SET last_sent_at = $3
+         WHERE user_id = $1 AND last_sent_at IS NOT DISTINCT FROM $2`,
+		userID,
+		prev,
+		sentAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update notification_digest: %w", err)
+	}
+	return res.RowsAffected() == 1, nil
+}
+
+func collectDigests(rows pgx.Rows) ([]*domain.DigestSettings, error) {
+	defer rows.Close()
+
+	var res []*domain.DigestSettings
+	for rows.Next() {
+		var s domain.DigestSettings
+		if err := rows.Scan(&s.UserId, &s.Enabled, &s.Timezone, &s.Hour, &s.QuietStart, &s.QuietEnd, &s.LastSentAt, &s.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan notification_digest: %w", err)
+		}
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
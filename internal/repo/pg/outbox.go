@@ -0,0 +1,179 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepo(db *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (r *OutboxRepo) Add(ctx context.Context, msg *domain.OutboxMessage) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO outbox(pull_request_id, event_type, payload, created_at)
+         VALUES ($1, $2, $3, NOW())
+         RETURNING id, created_at`,
+		msg.PullRequestId,
+		msg.EventType,
+		string(msg.Payload),
+	).Scan(&msg.Id, &msg.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT id, pull_request_id, event_type, payload, attempts, last_error, accepted_by, created_at, published_at, published_seq, dead_at
+         FROM outbox
+         WHERE published_at IS NULL AND dead_at IS NULL
+         ORDER BY id
+         LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.OutboxMessage
+	for rows.Next() {
+		msg, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox: %w", err)
+		}
+		res = append(res, msg)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox
+         SET published_at  = COALESCE(published_at, NOW()),
+             published_seq = COALESCE(published_seq, nextval('outbox_published_seq'))
+         WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, acceptedBy []string, lastError string) error {
+	if acceptedBy == nil {
+		acceptedBy = []string{}
+	}
+
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, last_error = $2, accepted_by = $3 WHERE id = $1`,
+		id,
+		lastError,
+		acceptedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkDead(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE outbox SET dead_at = COALESCE(dead_at, NOW()) WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListPublished(ctx context.Context, afterSeq int64, limit int) ([]*domain.OutboxMessage, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT id, pull_request_id, event_type, payload, attempts, last_error, accepted_by, created_at, published_at, published_seq, dead_at
+         FROM outbox
+         WHERE published_seq > $1
+         ORDER BY published_seq
+         LIMIT $2`,
+		afterSeq,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.OutboxMessage
+	for rows.Next() {
+		msg, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox: %w", err)
+		}
+		res = append(res, msg)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *OutboxRepo) LastPublishedSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT COALESCE(MAX(published_seq), 0) FROM outbox`,
+	).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("select outbox: %w", err)
+	}
+	return seq, nil
+}
+
+func scanOutboxMessage(row pgx.Row) (*domain.OutboxMessage, error) {
+	var (
+		msg     domain.OutboxMessage
+		payload string
+		seq     *int64
+	)
+	err := row.Scan(
+		&msg.Id, &msg.PullRequestId, &msg.EventType, &payload,
+		&msg.Attempts, &msg.LastError, &msg.AcceptedBy, &msg.CreatedAt, &msg.PublishedAt, &seq, &msg.DeadAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	msg.Payload = []byte(payload)
+	if len(msg.AcceptedBy) == 0 {
+		msg.AcceptedBy = nil
+	}
+	if seq != nil {
+		msg.PublishedSeq = *seq
+	}
+
+	return &msg, nil
+}
@@ -1,9 +1,76 @@
 package pg
 
-import "gopr/internal/repo"
+import (
+	"errors"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 var (
-	_ repo.User        = &UserRepo{}
-	_ repo.Team        = &TeamRepo{}
-	_ repo.PullRequest = &PullRequestRepo{}
+	_ repo.User         = &UserRepo{}
+	_ repo.Team         = &TeamRepo{}
+	_ repo.PullRequest  = &PullRequestRepo{}
+	_ repo.Identity     = &IdentityRepo{}
+	_ repo.Subscription = &SubscriptionRepo{}
+	_ repo.Notification = &NotificationRepo{}
+	_ repo.Outbox       = &OutboxRepo{}
+	_ repo.Stats        = &StatsRepo{}
+	_ repo.Audit        = &AuditRepo{}
+	_ repo.Factory      = &Factory{}
 )
+
+// Factory создаёт репозитории поверх одного пула.
+type Factory struct {
+	db *pgxpool.Pool
+}
+
+func NewFactory(db *pgxpool.Pool) *Factory {
+	return &Factory{db: db}
+}
+
+func (f *Factory) User() repo.User                 { return NewUserRepo(f.db) }
+func (f *Factory) Team() repo.Team                 { return NewTeamRepo(f.db) }
+func (f *Factory) PullRequest() repo.PullRequest   { return NewPullRequestRepo(f.db) }
+func (f *Factory) Identity() repo.Identity         { return NewIdentityRepo(f.db) }
+func (f *Factory) Subscription() repo.Subscription { return NewSubscriptionRepo(f.db) }
+func (f *Factory) Notification() repo.Notification { return NewNotificationRepo(f.db) }
+func (f *Factory) Audit() repo.Audit               { return NewAuditRepo(f.db) }
+func (f *Factory) Outbox() repo.Outbox             { return NewOutboxRepo(f.db) }
+func (f *Factory) Stats() repo.Stats               { return NewStatsRepo(f.db) }
+func (f *Factory) Transactor() repo.Transactor     { return NewTransactor(f.db) }
+
+const uniqueViolation = "23505"
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// keyset добавляет к запросу сортировку по (createdCol, idCol) и условие
+// «строго после курсора» в выбранном направлении.
+func keyset(b sq.SelectBuilder, order domain.SortOrder, after *domain.Cursor, createdCol, idCol string) sq.SelectBuilder {
+	dir, cmp := "ASC", ">"
+	if order == domain.SortNewest {
+		dir, cmp = "DESC", "<"
+	}
+
+	if after != nil {
+		b = b.Where(
+			"("+createdCol+", "+idCol+") "+cmp+" (?, ?)",
+			after.CreatedAt, after.Id,
+		)
+	}
+
+	return b.OrderBy(createdCol+" "+dir, idCol+" "+dir)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
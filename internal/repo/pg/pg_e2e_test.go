@@ -0,0 +1,61 @@
+package pg_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/repo/pg"
+	"gopr/internal/repo/testhelpers"
+)
+
+func TestConformance_E2E(t *testing.T) {
+	db, cleanup := testhelpers.StartPostgres(t)
+	defer cleanup()
+
+	testhelpers.RunConformance(t, func(t *testing.T) testhelpers.Repos {
+		_, err := db.Exec(context.Background(),
+			`TRUNCATE pull_request_history, pull_request_reviewer, pull_requests, team_membership, "users", team,
+                      user_identity, webhook_subscription, webhook_delivery, outbox,
+                      notification_preference, notification_sla_warning, notification_digest, user_away,
+                      audit_log
+             RESTART IDENTITY CASCADE`,
+		)
+		require.NoError(t, err)
+
+		return testhelpers.Repos{
+			User:         pg.NewUserRepo(db),
+			Team:         pg.NewTeamRepo(db),
+			PullRequest:  pg.NewPullRequestRepo(db),
+			Identity:     pg.NewIdentityRepo(db),
+			Subscription: pg.NewSubscriptionRepo(db),
+			Notification: pg.NewNotificationRepo(db),
+			Outbox:       pg.NewOutboxRepo(db),
+			Audit:        pg.NewAuditRepo(db),
+			Transactor:   pg.NewTransactor(db),
+		}
+	})
+}
+
+func TestLockOutbox_E2E(t *testing.T) {
+	ctx := context.Background()
+	db, cleanup := testhelpers.StartPostgres(t)
+	defer cleanup()
+	f := pg.NewFactory(db)
+
+	unlock, ok, err := f.LockOutbox(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// пока блокировку держит один relay, другой пропускает проход
+	_, ok, err = f.LockOutbox(ctx)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	unlock()
+	unlock, ok, err = f.LockOutbox(ctx)
+	require.NoError(t, err)
+	require.True(t, ok)
+	unlock()
+}
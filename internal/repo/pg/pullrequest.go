@@ -25,14 +25,18 @@ func NewPullRequestRepo(db *pgxpool.Pool) *PullRequestRepo {
 }
 
 func (r *PullRequestRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO pull_requests(id, author_id, name, status, created_at)
-         VALUES ($1, $2, $3, $4, NOW())`,
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO pull_requests(id, author_id, name, team_id, status, created_at)
+         VALUES ($1, $2, $3, NULLIF($4, ''), $5, NOW())`,
 		pr.Id,
 		pr.AuthorId,
 		pr.Name,
+		pr.TeamId,
 		pr.Status,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert pull_request: %w", repo.ErrAlreadyExists)
+	}
 	if err != nil {
 		return fmt.Errorf("insert pull_request: %w", err)
 	}
@@ -42,15 +46,19 @@ func (r *PullRequestRepo) Create(ctx context.Context, pr *domain.PullRequest) er
 func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullRequest, error) {
 	var pr domain.PullRequest
 
-	err := r.db.QueryRow(ctx,
-		`SELECT id, author_id, name, status, created_at, merged_at
-         FROM pull_requests
-         WHERE id = $1`,
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT pr.id, pr.author_id, pr.name, COALESCE(pr.team_id, ''), COALESCE(rt.name, ''),
+                pr.status, pr.created_at, pr.merged_at
+         FROM pull_requests AS pr
+         LEFT JOIN team AS rt ON rt.id = pr.team_id
+         WHERE pr.id = $1`,
 		id,
 	).Scan(
 		&pr.Id,
 		&pr.AuthorId,
 		&pr.Name,
+		&pr.TeamId,
+		&pr.TeamName,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.MergedAt,
@@ -66,31 +74,44 @@ func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullR
 	return &pr, nil
 }
 
-func (r *PullRequestRepo) UpdateStatusMerged(ctx context.Context, id string) error {
-	res, err := r.db.Exec(ctx,
+func (r *PullRequestRepo) UpdateStatusMerged(ctx context.Context, id string) (bool, error) {
+	res, err := conn(ctx, r.db).Exec(ctx,
 		`UPDATE pull_requests
          SET status = 'MERGED',
              merged_at = COALESCE(merged_at, NOW())
-         WHERE id = $1`,
+         WHERE id = $1 AND status = 'OPEN'`,
 		id,
 	)
 	if err != nil {
-		return fmt.Errorf("update merged: %w", err)
+		return false, fmt.Errorf("update merged: %w", err)
+	}
+	if res.RowsAffected() > 0 {
+		return true, nil
 	}
 
-	if res.RowsAffected() == 0 {
-		return repo.ErrNotFound
+	var exists bool
+	if err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = $1)`,
+		id,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("select pull_request: %w", err)
+	}
+	if !exists {
+		return false, repo.ErrNotFound
 	}
 
-	return nil
+	return false, nil
 }
 
 func (r *PullRequestRepo) AddReviewer(ctx context.Context, prID, reviewerID string) error {
-	_, err := r.db.Exec(ctx,
+	_, err := conn(ctx, r.db).Exec(ctx,
 		`INSERT INTO pull_request_reviewer(pull_request_id, reviewer_id)
          VALUES ($1, $2)`,
 		prID, reviewerID,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert reviewer: %w", repo.ErrAlreadyExists)
+	}
 	if err != nil {
 		return fmt.Errorf("insert reviewer: %w", err)
 	}
@@ -98,7 +119,7 @@ func (r *PullRequestRepo) AddReviewer(ctx context.Context, prID, reviewerID stri
 }
 
 func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
-	res, err := r.db.Exec(ctx,
+	res, err := conn(ctx, r.db).Exec(ctx,
 		`DELETE FROM pull_request_reviewer
          WHERE pull_request_id = $1 AND reviewer_id = $2`,
 		prID, reviewerID,
@@ -115,7 +136,7 @@ func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, prID, reviewerID s
 }
 
 func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]string, error) {
-	rows, err := r.db.Query(ctx,
+	rows, err := conn(ctx, r.db).Query(ctx,
 		`SELECT reviewer_id
          FROM pull_request_reviewer
          WHERE pull_request_id = $1`,
@@ -138,29 +159,320 @@ func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]str
 	return ids, nil
 }
 
-func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]*domain.PullRequest, error) {
+func (r *PullRequestRepo) ListReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	res := make(map[string][]string, len(prIDs))
+	if len(prIDs) == 0 {
+		return res, nil
+	}
+
+	sql, args, err := r.psql.
+		Select("pull_request_id", "reviewer_id").
+		From("pull_request_reviewer").
+		Where(sq.Eq{"pull_request_id": prIDs}).
+		OrderBy("pull_request_id", "reviewer_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listReviewersByPRs: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listReviewersByPRs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, reviewerID string
+		if err := rows.Scan(&prID, &reviewerID); err != nil {
+			return nil, fmt.Errorf("scan listReviewersByPRs: %w", err)
+		}
+		res[prID] = append(res[prID], reviewerID)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) ListOpenReviewsByTeam(ctx context.Context, teamID string) ([]*domain.ReviewAssignment, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT rr.pull_request_id, rr.reviewer_id
+         FROM pull_request_reviewer AS rr
+         JOIN pull_requests AS pr ON pr.id = rr.pull_request_id
+         WHERE pr.team_id = $1 AND pr.status = 'OPEN'
+         ORDER BY rr.pull_request_id, rr.reviewer_id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query open reviews by team: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.ReviewAssignment
+	for rows.Next() {
+		var a domain.ReviewAssignment
+		if err := rows.Scan(&a.PullRequestId, &a.ReviewerId); err != nil {
+			return nil, fmt.Errorf("scan open review: %w", err)
+		}
+		res = append(res, &a)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	res := make(map[string]int, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return res, nil
+	}
+
 	sql, args, err := r.psql.
-		Select(
-			"pr.id",
-			"pr.author_id",
-			"pr.name",
-			"pr.status",
-			"pr.created_at",
-			"pr.merged_at",
-		).
+		Select("rr.reviewer_id", "COUNT(*)").
+		From("pull_request_reviewer AS rr").
+		Join("pull_requests AS pr ON pr.id = rr.pull_request_id").
+		Where(sq.Eq{"rr.reviewer_id": reviewerIDs, "pr.status": domain.PullRequestStatusOpen}).
+		GroupBy("rr.reviewer_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql countOpenReviews: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query countOpenReviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id string
+			n  int
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan countOpenReviews: %w", err)
+		}
+		res[id] = n
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) AddEvent(ctx context.Context, event *domain.PullRequestEvent) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO pull_request_history(pull_request_id, type, reviewer_id, actor, reason, created_at)
+         VALUES ($1, $2, NULLIF($3, ''), $4, $5, NOW())
+         RETURNING id, created_at`,
+		event.PullRequestId,
+		event.Type,
+		event.ReviewerId,
+		event.Actor,
+		event.Reason,
+	).Scan(&event.Id, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert pull_request_history: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepo) ListEvents(ctx context.Context, prID string) ([]*domain.PullRequestEvent, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT id, pull_request_id, type, COALESCE(reviewer_id, ''), actor, reason, created_at
+         FROM pull_request_history
+         WHERE pull_request_id = $1
+         ORDER BY id`,
+		prID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pull_request_history: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.PullRequestEvent
+	for rows.Next() {
+		var ev domain.PullRequestEvent
+		if err := rows.Scan(
+			&ev.Id,
+			&ev.PullRequestId,
+			&ev.Type,
+			&ev.ReviewerId,
+			&ev.Actor,
+			&ev.Reason,
+			&ev.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan pull_request_history: %w", err)
+		}
+		res = append(res, &ev)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) SaveReviewSync(ctx context.Context, sync *domain.ReviewSync) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO pull_request_sync(pull_request_id, status, attempts, last_error, updated_at)
+         VALUES ($1, $2, $3, $4, NOW())
+         ON CONFLICT (pull_request_id) DO UPDATE
+             SET status = EXCLUDED.status,
+                 attempts = EXCLUDED.attempts,
+                 last_error = EXCLUDED.last_error,
+                 updated_at = EXCLUDED.updated_at
+         RETURNING updated_at`,
+		sync.PullRequestId,
+		sync.Status,
+		sync.Attempts,
+		sync.LastError,
+	).Scan(&sync.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert pull_request_sync: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepo) GetReviewSync(ctx context.Context, prID string) (*domain.ReviewSync, error) {
+	var s domain.ReviewSync
+
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT pull_request_id, status, attempts, last_error, updated_at
+         FROM pull_request_sync
+         WHERE pull_request_id = $1`,
+		prID,
+	).Scan(&s.PullRequestId, &s.Status, &s.Attempts, &s.LastError, &s.UpdatedAt)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request_sync: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (r *PullRequestRepo) ListByReviewer(ctx context.Context, filter *domain.ReviewerPRFilter) ([]*domain.PullRequest, error) {
+	builder := r.psql.
+		Select(prColumns...).
 		From("pull_requests AS pr").
+		LeftJoin("team AS rt ON rt.id = pr.team_id").
 		Join("pull_request_reviewer AS rr ON rr.pull_request_id = pr.id").
-		Where(sq.Eq{"rr.reviewer_id": reviewerID}).
-		ToSql()
+		Where(sq.Eq{"rr.reviewer_id": filter.ReviewerId})
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"pr.status": filter.Status})
+	}
+	if filter.TeamId != "" {
+		builder = builder.Where(sq.Eq{"pr.team_id": filter.TeamId})
+	}
+	if !filter.CreatedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.created_at": filter.CreatedFrom})
+	}
+	if !filter.CreatedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.created_at": filter.CreatedTo})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "pr.created_at", "pr.id")
 
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := builder.ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("build sql listByReviewer: %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, sql, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query listByReviewer: %w", err)
 	}
+
+	return collectPullRequests(rows)
+}
+
+func (r *PullRequestRepo) List(ctx context.Context, filter *domain.PullRequestFilter) ([]*domain.PullRequest, error) {
+	builder := r.psql.
+		Select(prColumns...).
+		From("pull_requests AS pr").
+		LeftJoin("team AS rt ON rt.id = pr.team_id")
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"pr.status": filter.Status})
+	}
+	if filter.AuthorId != "" {
+		builder = builder.Where(sq.Eq{"pr.author_id": filter.AuthorId})
+	}
+	if filter.TeamName != "" {
+		builder = builder.Where(sq.Eq{"rt.name": filter.TeamName})
+	}
+	if filter.ReviewerId != "" {
+		builder = builder.Where(
+			"EXISTS (SELECT 1 FROM pull_request_reviewer rr WHERE rr.pull_request_id = pr.id AND rr.reviewer_id = ?)",
+			filter.ReviewerId,
+		)
+	}
+	if filter.NameContains != "" {
+		builder = builder.Where(sq.ILike{"pr.name": "%" + escapeLike(filter.NameContains) + "%"})
+	}
+	if !filter.CreatedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.created_at": filter.CreatedFrom})
+	}
+	if !filter.CreatedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.created_at": filter.CreatedTo})
+	}
+	if !filter.MergedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.merged_at": filter.MergedFrom})
+	}
+	if !filter.MergedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.merged_at": filter.MergedTo})
+	}
+	if filter.FewerReviewersThan > 0 {
+		builder = builder.Where(
+			"(SELECT COUNT(*) FROM pull_request_reviewer rc WHERE rc.pull_request_id = pr.id) < ?",
+			filter.FewerReviewersThan,
+		)
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "pr.created_at", "pr.id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listPullRequests: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listPullRequests: %w", err)
+	}
+
+	return collectPullRequests(rows)
+}
+
+// prColumns — колонки для collectPullRequests; rt — LEFT JOIN команды ревью.
+var prColumns = []string{
+	"pr.id",
+	"pr.author_id",
+	"pr.name",
+	"COALESCE(pr.team_id, '')",
+	"COALESCE(rt.name, '')",
+	"pr.status",
+	"pr.created_at",
+	"pr.merged_at",
+}
+
+func collectPullRequests(rows pgx.Rows) ([]*domain.PullRequest, error) {
 	defer rows.Close()
 
 	var res []*domain.PullRequest
@@ -171,15 +483,20 @@ func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string)
 			&pr.Id,
 			&pr.AuthorId,
 			&pr.Name,
+			&pr.TeamId,
+			&pr.TeamName,
 			&pr.Status,
 			&pr.CreatedAt,
 			&pr.MergedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("scan listByReviewer: %w", err)
+			return nil, fmt.Errorf("scan pull_request: %w", err)
 		}
 		res = append(res, &pr)
 	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
 
 	return res, nil
 }
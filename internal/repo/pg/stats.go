@@ -0,0 +1,201 @@
+package pg
+
+import (
+	"context"
+	"fmt"
+	"gopr/internal/domain"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StatsRepo считает агрегаты по PR, созданным в диапазоне фильтра. Выборка PR
+// идёт по индексам (created_at) и (team_id, created_at), назначения и события
+// подтягиваются по первичным ключам pull_request_reviewer и pull_request_history.
+type StatsRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStatsRepo(db *pgxpool.Pool) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+func (r *StatsRepo) UserStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.UserStats, error) {
+	prs, args, err := statsPRs(filter, "id", "status").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql userStats: %w", err)
+	}
+
+	sql, err := sq.Dollar.ReplacePlaceholders(
+		`WITH prs AS (` + prs + `),
+         cur AS (
+             SELECT rr.reviewer_id AS user_id,
+                    COUNT(*) FILTER (WHERE prs.status = 'OPEN')   AS open,
+                    COUNT(*) FILTER (WHERE prs.status = 'MERGED') AS merged
+             FROM prs
+             JOIN pull_request_reviewer AS rr ON rr.pull_request_id = prs.id
+             GROUP BY rr.reviewer_id
+         ),
+         hist AS (
+             SELECT h.reviewer_id AS user_id,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_ASSIGNED') AS assigned,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_REMOVED')  AS removed
+             FROM prs
+             JOIN pull_request_history AS h ON h.pull_request_id = prs.id
+             WHERE h.reviewer_id IS NOT NULL
+             GROUP BY h.reviewer_id
+         )
+         SELECT u.id, u.username,
+                COALESCE(hist.assigned, 0), COALESCE(cur.open, 0),
+                COALESCE(cur.merged, 0), COALESCE(hist.removed, 0)
+         FROM "users" AS u
+         LEFT JOIN cur ON cur.user_id = u.id
+         LEFT JOIN hist ON hist.user_id = u.id
+         WHERE cur.user_id IS NOT NULL OR hist.user_id IS NOT NULL
+         ORDER BY 3 DESC, u.id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build sql userStats: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query userStats: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.UserStats, 0)
+	for rows.Next() {
+		var s domain.UserStats
+		if err := rows.Scan(&s.UserId, &s.Username, &s.Assigned, &s.Open, &s.Merged, &s.ReassignedAway); err != nil {
+			return nil, fmt.Errorf("scan userStats: %w", err)
+		}
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *StatsRepo) TeamStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.TeamStats, error) {
+	prs, args, err := statsPRs(filter, "id", "team_id", "status").
+		Where("team_id IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql teamStats: %w", err)
+	}
+
+	query := `WITH prs AS (` + prs + `),
+         agg AS (
+             SELECT team_id,
+                    COUNT(*)                                 AS total,
+                    COUNT(*) FILTER (WHERE status = 'OPEN')   AS open,
+                    COUNT(*) FILTER (WHERE status = 'MERGED') AS merged
+             FROM prs
+             GROUP BY team_id
+         ),
+         hist AS (
+             SELECT prs.team_id,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_ASSIGNED') AS assigned,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_REMOVED')  AS removed
+             FROM prs
+             JOIN pull_request_history AS h ON h.pull_request_id = prs.id
+             GROUP BY prs.team_id
+         )
+         SELECT t.id, t.name, COALESCE(t.parent_id, ''),
+                COALESCE(agg.total, 0), COALESCE(agg.open, 0), COALESCE(agg.merged, 0),
+                COALESCE(hist.assigned, 0), COALESCE(hist.removed, 0)
+         FROM team AS t
+         LEFT JOIN agg ON agg.team_id = t.id
+         LEFT JOIN hist ON hist.team_id = t.id`
+
+	if filter.TeamId != "" {
+		query += ` WHERE t.id = ?`
+		args = append(args, filter.TeamId)
+	}
+
+	sql, err := sq.Dollar.ReplacePlaceholders(query + ` ORDER BY t.name`)
+	if err != nil {
+		return nil, fmt.Errorf("build sql teamStats: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query teamStats: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.TeamStats, 0)
+	for rows.Next() {
+		var (
+			t domain.Team
+			s domain.TeamStats
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &s.PullRequests, &s.Open, &s.Merged, &s.Assignments, &s.Reassignments); err != nil {
+			return nil, fmt.Errorf("scan teamStats: %w", err)
+		}
+		s.Team = &t
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *StatsRepo) MergeTime(ctx context.Context, filter *domain.StatsFilter) (*domain.MergeTimeStats, error) {
+	sql, args, err := statsPRs(filter,
+		"COUNT(*)",
+		"COALESCE(AVG(EXTRACT(EPOCH FROM merged_at - created_at)::float8), 0)",
+		"COALESCE(percentile_cont(ARRAY[0.5, 0.9, 0.95, 0.99]) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM merged_at - created_at)::float8), ARRAY[0, 0, 0, 0]::float8[])",
+	).
+		Where("merged_at IS NOT NULL").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql mergeTime: %w", err)
+	}
+
+	var (
+		res  domain.MergeTimeStats
+		avg  float64
+		pcts []float64
+	)
+	if err := conn(ctx, r.db).QueryRow(ctx, sql, args...).Scan(&res.Merged, &avg, &pcts); err != nil {
+		return nil, fmt.Errorf("query mergeTime: %w", err)
+	}
+	if len(pcts) != 4 {
+		return nil, fmt.Errorf("query mergeTime: unexpected percentiles %v", pcts)
+	}
+
+	res.Avg = seconds(avg)
+	res.P50 = seconds(pcts[0])
+	res.P90 = seconds(pcts[1])
+	res.P95 = seconds(pcts[2])
+	res.P99 = seconds(pcts[3])
+
+	return &res, nil
+}
+
+// statsPRs — выборка PR фильтра с плейсхолдерами '?', чтобы её можно было
+// встроить в CTE и пронумеровать вместе с остальным запросом.
+func statsPRs(filter *domain.StatsFilter, columns ...string) sq.SelectBuilder {
+	builder := sq.Select(columns...).
+		From("pull_requests").
+		Where(sq.GtOrEq{"created_at": filter.From}).
+		Where(sq.Lt{"created_at": filter.To})
+
+	if filter.TeamId != "" {
+		builder = builder.Where(sq.Eq{"team_id": filter.TeamId})
+	}
+
+	return builder
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
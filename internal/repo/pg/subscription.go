@@ -0,0 +1,294 @@
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubscriptionRepo struct {
+	db   *pgxpool.Pool
+	psql sq.StatementBuilderType
+}
+
+func NewSubscriptionRepo(db *pgxpool.Pool) *SubscriptionRepo {
+	return &SubscriptionRepo{
+		db:   db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *SubscriptionRepo) Create(ctx context.Context, sub *domain.Subscription) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO webhook_subscription(id, url, events, secret, created_at)
+         VALUES ($1, $2, $3, $4, NOW())
+         RETURNING created_at`,
+		sub.Id,
+		sub.URL,
+		eventsToStrings(sub.Events),
+		sub.Secret,
+	).Scan(&sub.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert webhook_subscription: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert webhook_subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	row := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT id, url, events, secret, created_at
+         FROM webhook_subscription
+         WHERE id = $1`,
+		id,
+	)
+
+	sub, err := scanSubscription(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepo) List(ctx context.Context) ([]*domain.Subscription, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT id, url, events, secret, created_at
+         FROM webhook_subscription
+         ORDER BY created_at, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook_subscription: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_subscription: %w", err)
+		}
+		res = append(res, sub)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *SubscriptionRepo) Delete(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM webhook_subscription WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete webhook_subscription: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, d *domain.Delivery) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO webhook_delivery(id, subscription_id, event_id, event_type, payload, status,
+                                      attempts, response_code, last_error, next_attempt_at, created_at, updated_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+         RETURNING created_at, updated_at`,
+		d.Id,
+		d.SubscriptionId,
+		d.EventId,
+		d.EventType,
+		string(d.Payload),
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		d.NextAttemptAt,
+	).Scan(&d.CreatedAt, &d.UpdatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert webhook_delivery: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert webhook_delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`UPDATE webhook_delivery
+         SET status = $2, attempts = $3, response_code = $4, last_error = $5, next_attempt_at = $6, updated_at = NOW()
+         WHERE id = $1
+         RETURNING updated_at`,
+		d.Id,
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		d.NextAttemptAt,
+	).Scan(&d.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repo.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update webhook_delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) GetDelivery(ctx context.Context, id string) (*domain.Delivery, error) {
+	row := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT id, subscription_id, event_id, event_type, payload, status,
+                attempts, response_code, last_error, next_attempt_at, created_at, updated_at
+         FROM webhook_delivery
+         WHERE id = $1`,
+		id,
+	)
+
+	d, err := scanDelivery(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *SubscriptionRepo) ListDeliveries(ctx context.Context, filter *domain.DeliveryFilter) ([]*domain.Delivery, error) {
+	builder := r.psql.
+		Select(
+			"id", "subscription_id", "event_id", "event_type", "payload", "status",
+			"attempts", "response_code", "last_error", "next_attempt_at", "created_at", "updated_at",
+		).
+		From("webhook_delivery").
+		Where(sq.Eq{"subscription_id": filter.SubscriptionId})
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listDeliveries: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_delivery: %w", err)
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+// ClaimDeliveries блокирует строки через SKIP LOCKED, поэтому инстансы, опрашивающие
+// доставки одновременно, забирают разные строки.
+func (r *SubscriptionRepo) ClaimDeliveries(ctx context.Context, now, until time.Time, limit int) ([]*domain.Delivery, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`UPDATE webhook_delivery AS d
+         SET next_attempt_at = $2
+         FROM (SELECT id
+               FROM webhook_delivery
+               WHERE status = 'PENDING' AND next_attempt_at <= $1
+               ORDER BY next_attempt_at, id
+               LIMIT $3
+               FOR UPDATE SKIP LOCKED) AS due
+         WHERE d.id = due.id
+         RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status,
+                   d.attempts, d.response_code, d.last_error, d.next_attempt_at, d.created_at, d.updated_at`,
+		now,
+		until,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook_delivery: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_delivery: %w", err)
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.Subscription, error) {
+	var (
+		sub    domain.Subscription
+		events []string
+	)
+	if err := row.Scan(&sub.Id, &sub.URL, &events, &sub.Secret, &sub.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		sub.Events = append(sub.Events, domain.EventType(e))
+	}
+
+	return &sub, nil
+}
+
+func scanDelivery(row pgx.Row) (*domain.Delivery, error) {
+	var (
+		d       domain.Delivery
+		payload string
+	)
+	err := row.Scan(
+		&d.Id, &d.SubscriptionId, &d.EventId, &d.EventType, &payload, &d.Status,
+		&d.Attempts, &d.ResponseCode, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+
+	return &d, nil
+}
+
+func eventsToStrings(events []domain.EventType) []string {
+	res := make([]string, 0, len(events))
+	for _, e := range events {
+		res = append(res, string(e))
+	}
+	return res
+}
@@ -25,12 +25,16 @@ func NewTeamRepo(db *pgxpool.Pool) *TeamRepo {
 }
 
 func (r *TeamRepo) Create(ctx context.Context, team *domain.Team) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO team(id, name)
-         VALUES ($1, $2)`,
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`INSERT INTO team(id, name, parent_id)
+         VALUES ($1, $2, NULLIF($3, ''))`,
 		team.Id,
 		team.Name,
+		team.ParentId,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert team: %w", repo.ErrAlreadyExists)
+	}
 	if err != nil {
 		return fmt.Errorf("insert team: %w", err)
 	}
@@ -40,12 +44,12 @@ func (r *TeamRepo) Create(ctx context.Context, team *domain.Team) error {
 func (r *TeamRepo) GetByID(ctx context.Context, id string) (*domain.Team, error) {
 	var t domain.Team
 
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT id, name, COALESCE(parent_id, '')
          FROM team
          WHERE id = $1`,
 		id,
-	).Scan(&t.Id, &t.Name)
+	).Scan(&t.Id, &t.Name, &t.ParentId)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repo.ErrNotFound
@@ -60,12 +64,12 @@ func (r *TeamRepo) GetByID(ctx context.Context, id string) (*domain.Team, error)
 func (r *TeamRepo) GetByName(ctx context.Context, name string) (*domain.Team, error) {
 	var t domain.Team
 
-	err := r.db.QueryRow(ctx,
-		`SELECT id, name
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT id, name, COALESCE(parent_id, '')
          FROM team
          WHERE name = $1`,
 		name,
-	).Scan(&t.Id, &t.Name)
+	).Scan(&t.Id, &t.Name, &t.ParentId)
 
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, repo.ErrNotFound
@@ -76,3 +80,318 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (*domain.Team, er
 
 	return &t, nil
 }
+
+func (r *TeamRepo) List(ctx context.Context) ([]*domain.TeamSummary, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT t.id, t.name, COALESCE(t.parent_id, ''), COALESCE(p.name, ''),
+                COUNT(u.id),
+                COUNT(u.id) FILTER (WHERE u.is_active)
+         FROM team AS t
+         LEFT JOIN team AS p ON p.id = t.parent_id
+         LEFT JOIN team_membership AS m ON m.team_id = t.id
+         LEFT JOIN "users" AS u ON u.id = m.user_id
+         GROUP BY t.id, t.name, p.name
+         ORDER BY t.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.TeamSummary
+	for rows.Next() {
+		var (
+			t       domain.Team
+			summary domain.TeamSummary
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &summary.ParentName, &summary.MembersCount, &summary.ActiveCount); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		summary.Team = &t
+		res = append(res, &summary)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) Rename(ctx context.Context, id, name string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE team
+         SET name = $1
+         WHERE id = $2`,
+		name,
+		id,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("rename team: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("rename team: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) SetParent(ctx context.Context, id, parentID string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE team
+         SET parent_id = NULLIF($1, '')
+         WHERE id = $2`,
+		parentID,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update team parent: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+// ListAncestors возвращает предков команды, начиная с непосредственного родителя.
+func (r *TeamRepo) ListAncestors(ctx context.Context, id string) ([]*domain.Team, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`WITH RECURSIVE anc AS (
+             SELECT p.id, p.name, COALESCE(p.parent_id, '') AS parent_id, 1 AS depth
+             FROM team AS c
+             JOIN team AS p ON p.id = c.parent_id
+             WHERE c.id = $1
+             UNION ALL
+             SELECT p.id, p.name, COALESCE(p.parent_id, ''), anc.depth + 1
+             FROM team AS p
+             JOIN anc ON p.id = anc.parent_id
+         )
+         SELECT id, name, parent_id
+         FROM anc
+         ORDER BY depth`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team ancestors: %w", err)
+	}
+
+	return collectTeams(rows)
+}
+
+// ListSubtree возвращает команду и всех её потомков по уровням, корень первым.
+func (r *TeamRepo) ListSubtree(ctx context.Context, id string) ([]*domain.Team, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`WITH RECURSIVE sub AS (
+             SELECT id, name, COALESCE(parent_id, '') AS parent_id, 0 AS depth
+             FROM team
+             WHERE id = $1
+             UNION ALL
+             SELECT t.id, t.name, t.parent_id, sub.depth + 1
+             FROM team AS t
+             JOIN sub ON t.parent_id = sub.id
+         )
+         SELECT id, name, parent_id
+         FROM sub
+         ORDER BY depth, name`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team subtree: %w", err)
+	}
+
+	return collectTeams(rows)
+}
+
+func (r *TeamRepo) Delete(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM team
+         WHERE id = $1`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete team: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) AddMember(ctx context.Context, m *domain.Membership) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO team_membership(team_id, user_id, role, created_at)
+         VALUES ($1, $2, $3, NOW())
+         RETURNING created_at`,
+		m.TeamId,
+		m.UserId,
+		m.Role,
+	).Scan(&m.CreatedAt)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert team_membership: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert team_membership: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) UpdateMemberRole(ctx context.Context, teamID, userID string, role domain.MembershipRole) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE team_membership
+         SET role = $1
+         WHERE team_id = $2 AND user_id = $3`,
+		role,
+		teamID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("update team_membership: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) RemoveMember(ctx context.Context, teamID, userID string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`DELETE FROM team_membership
+         WHERE team_id = $1 AND user_id = $2`,
+		teamID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete team_membership: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) ListMembers(ctx context.Context, teamID string) ([]*domain.TeamMember, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT u.id, u.username, COALESCE(u.team_id, ''), u.is_active, u.created_at, u.updated_at, m.role, m.created_at
+         FROM team_membership AS m
+         JOIN "users" AS u ON u.id = m.user_id
+         WHERE m.team_id = $1
+         ORDER BY m.created_at, u.id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team members: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.TeamMember
+	for rows.Next() {
+		var (
+			u domain.User
+			m domain.TeamMember
+		)
+		if err := rows.Scan(&u.Id, &u.Username, &u.TeamId, &u.IsActive, &u.CreatedAt, &u.UpdatedAt, &m.Role, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("scan team member: %w", err)
+		}
+		m.User = &u
+		res = append(res, &m)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUser(ctx context.Context, userID string) ([]*domain.UserTeam, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT t.id, t.name, COALESCE(t.parent_id, ''), m.role
+         FROM team_membership AS m
+         JOIN team AS t ON t.id = m.team_id
+         WHERE m.user_id = $1
+         ORDER BY m.created_at, t.name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user teams: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.UserTeam
+	for rows.Next() {
+		var (
+			t  domain.Team
+			ut domain.UserTeam
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &ut.Role); err != nil {
+			return nil, fmt.Errorf("scan user team: %w", err)
+		}
+		ut.Team = &t
+		res = append(res, &ut)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUsers(ctx context.Context, userIDs []string) (map[string][]*domain.UserTeam, error) {
+	res := make(map[string][]*domain.UserTeam, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+
+	sql, args, err := r.psql.
+		Select("m.user_id", "t.id", "t.name", "COALESCE(t.parent_id, '')", "m.role").
+		From("team_membership AS m").
+		Join("team AS t ON t.id = m.team_id").
+		Where(sq.Eq{"m.user_id": userIDs}).
+		OrderBy("m.user_id", "m.created_at", "t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listByUsers: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users teams: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			t      domain.Team
+			ut     domain.UserTeam
+		)
+		if err := rows.Scan(&userID, &t.Id, &t.Name, &t.ParentId, &ut.Role); err != nil {
+			return nil, fmt.Errorf("scan user team: %w", err)
+		}
+		ut.Team = &t
+		res[userID] = append(res[userID], &ut)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func collectTeams(rows pgx.Rows) ([]*domain.Team, error) {
+	defer rows.Close()
+
+	var res []*domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		res = append(res, &t)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
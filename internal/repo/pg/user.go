@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"gopr/internal/domain"
 	"gopr/internal/repo"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jackc/pgx/v5"
@@ -24,15 +26,27 @@ func NewUserRepo(db *pgxpool.Pool) *UserRepo {
 	}
 }
 
+// Create добавляет пользователя; если задана основная команда, он сразу
+// становится её участником с ролью member.
 func (r *UserRepo) Create(ctx context.Context, user *domain.User) error {
-	_, err := r.db.Exec(ctx,
-		`INSERT INTO "users"(id, username, team_id, is_active, created_at, updated_at)
-         VALUES ($1, $2, $3, $4, NOW(), NOW())`,
+	_, err := conn(ctx, r.db).Exec(ctx,
+		`WITH u AS (
+             INSERT INTO "users"(id, username, team_id, is_active, created_at, updated_at)
+             VALUES ($1, $2, NULLIF($3, ''), $4, NOW(), NOW())
+             RETURNING id, team_id
+         )
+         INSERT INTO team_membership(team_id, user_id, role, created_at)
+         SELECT team_id, id, 'member', NOW()
+         FROM u
+         WHERE team_id IS NOT NULL`,
 		user.Id,
 		user.Username,
 		user.TeamId,
 		user.IsActive,
 	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert user: %w", repo.ErrAlreadyExists)
+	}
 	if err != nil {
 		return fmt.Errorf("insert user: %w", err)
 	}
@@ -42,8 +56,8 @@ func (r *UserRepo) Create(ctx context.Context, user *domain.User) error {
 func (r *UserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
 	var u domain.User
 
-	err := r.db.QueryRow(ctx,
-		`SELECT id, username, team_id, is_active, created_at, updated_at
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`SELECT id, username, COALESCE(team_id, ''), is_active, created_at, updated_at
          FROM "users"
          WHERE id = $1`,
 		id,
@@ -66,7 +80,7 @@ func (r *UserRepo) GetByID(ctx context.Context, id string) (*domain.User, error)
 }
 
 func (r *UserRepo) UpdateIsActive(ctx context.Context, id string, isActive bool) error {
-	res, err := r.db.Exec(ctx,
+	res, err := conn(ctx, r.db).Exec(ctx,
 		`UPDATE "users"
          SET is_active = $1, updated_at = NOW()
          WHERE id = $2`,
@@ -82,14 +96,50 @@ func (r *UserRepo) UpdateIsActive(ctx context.Context, id string, isActive bool)
 	return nil
 }
 
+func (r *UserRepo) UpdateTeam(ctx context.Context, id, teamID string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE "users"
+         SET team_id = NULLIF($1, ''), updated_at = NOW()
+         WHERE id = $2`,
+		teamID,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update user team: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdateUsername(ctx context.Context, id, username string) error {
+	res, err := conn(ctx, r.db).Exec(ctx,
+		`UPDATE "users"
+         SET username = $1, updated_at = NOW()
+         WHERE id = $2`,
+		username,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update username: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
 func (r *UserRepo) ListByTeam(ctx context.Context, teamID string, onlyActive bool) ([]*domain.User, error) {
 	builder := r.psql.
-		Select("id", "username", "team_id", "is_active", "created_at", "updated_at").
-		From(`"users"`).
-		Where(sq.Eq{"team_id": teamID})
+		Select("u.id", "u.username", "COALESCE(u.team_id, '')", "u.is_active", "u.created_at", "u.updated_at").
+		From(`"users" AS u`).
+		Join("team_membership AS m ON m.user_id = u.id").
+		Where(sq.Eq{"m.team_id": teamID}).
+		OrderBy("m.created_at", "u.id")
 
 	if onlyActive {
-		builder = builder.Where(sq.Eq{"is_active": true})
+		builder = builder.Where(sq.Eq{"u.is_active": true})
 	}
 
 	sql, args, err := builder.ToSql()
@@ -97,10 +147,49 @@ func (r *UserRepo) ListByTeam(ctx context.Context, teamID string, onlyActive boo
 		return nil, fmt.Errorf("build sql (list users by team): %w", err)
 	}
 
-	rows, err := r.db.Query(ctx, sql, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query users by team: %w", err)
 	}
+
+	return collectUsers(rows)
+}
+
+func (r *UserRepo) List(ctx context.Context, filter *domain.UserFilter) ([]*domain.User, error) {
+	builder := r.psql.
+		Select("id", "username", "COALESCE(team_id, '')", "is_active", "created_at", "updated_at").
+		From(`"users"`)
+
+	if filter.UsernamePrefix != "" {
+		builder = builder.Where(sq.Like{"lower(username)": strings.ToLower(escapeLike(filter.UsernamePrefix)) + "%"})
+	}
+	if filter.TeamId != "" {
+		builder = builder.Where(
+			"EXISTS (SELECT 1 FROM team_membership m WHERE m.user_id = users.id AND m.team_id = ?)",
+			filter.TeamId,
+		)
+	}
+
+	builder = keyset(builder, domain.SortOldest, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	sql, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql (list users): %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users: %w", err)
+	}
+
+	return collectUsers(rows)
+}
+
+func collectUsers(rows pgx.Rows) ([]*domain.User, error) {
 	defer rows.Close()
 
 	var result []*domain.User
@@ -125,3 +214,57 @@ func (r *UserRepo) ListByTeam(ctx context.Context, teamID string, onlyActive boo
 
 	return result, nil
 }
+
+func (r *UserRepo) SetAway(ctx context.Context, away *domain.Away) error {
+	err := conn(ctx, r.db).QueryRow(ctx,
+		`INSERT INTO user_away(user_id, until, created_at)
+         VALUES ($1, $2, NOW())
+         ON CONFLICT (user_id) DO UPDATE SET until = EXCLUDED.until
+         RETURNING created_at`,
+		away.UserId,
+		away.Until,
+	).Scan(&away.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert user_away: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepo) DeleteAway(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM user_away WHERE user_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete user_away: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) ListReturning(ctx context.Context, now time.Time) ([]*domain.Away, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		`SELECT user_id, until, created_at
+         FROM user_away
+         WHERE until <= $1
+         ORDER BY until, user_id`,
+		now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user_away: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Away
+	for rows.Next() {
+		var a domain.Away
+		if err := rows.Scan(&a.UserId, &a.Until, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan user_away: %w", err)
+		}
+		res = append(res, &a)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"gopr/internal/domain"
+	"time"
 )
 
 type User interface {
@@ -11,8 +12,17 @@ type User interface {
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 
 	UpdateIsActive(ctx context.Context, id string, isActive bool) error
+	UpdateTeam(ctx context.Context, id, teamID string) error
+	UpdateUsername(ctx context.Context, id, username string) error
 
 	ListByTeam(ctx context.Context, teamID string, onlyActive bool) ([]*domain.User, error)
+	List(ctx context.Context, filter *domain.UserFilter) ([]*domain.User, error)
+
+	// SetAway запоминает или переносит дату возвращения пользователя.
+	SetAway(ctx context.Context, away *domain.Away) error
+	DeleteAway(ctx context.Context, id string) error
+	// ListReturning возвращает отсутствия с Until не позже now по возрастанию Until.
+	ListReturning(ctx context.Context, now time.Time) ([]*domain.Away, error)
 }
 
 type Team interface {
@@ -21,6 +31,23 @@ type Team interface {
 	GetByID(ctx context.Context, id string) (*domain.Team, error)
 
 	GetByName(ctx context.Context, name string) (*domain.Team, error)
+
+	List(ctx context.Context) ([]*domain.TeamSummary, error)
+
+	Rename(ctx context.Context, id, name string) error
+	SetParent(ctx context.Context, id, parentID string) error
+
+	ListAncestors(ctx context.Context, id string) ([]*domain.Team, error)
+	ListSubtree(ctx context.Context, id string) ([]*domain.Team, error)
+
+	Delete(ctx context.Context, id string) error
+
+	AddMember(ctx context.Context, m *domain.Membership) error
+	UpdateMemberRole(ctx context.Context, teamID, userID string, role domain.MembershipRole) error
+	RemoveMember(ctx context.Context, teamID, userID string) error
+	ListMembers(ctx context.Context, teamID string) ([]*domain.TeamMember, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.UserTeam, error)
+	ListByUsers(ctx context.Context, userIDs []string) (map[string][]*domain.UserTeam, error)
 }
 
 type PullRequest interface {
@@ -28,11 +55,158 @@ type PullRequest interface {
 
 	GetByID(ctx context.Context, id string) (*domain.PullRequest, error)
 
-	UpdateStatusMerged(ctx context.Context, id string) error
+	// UpdateStatusMerged переводит открытый PR в MERGED; false — PR уже смержен.
+	UpdateStatusMerged(ctx context.Context, id string) (bool, error)
 
 	AddReviewer(ctx context.Context, prID, reviewerID string) error
 	RemoveReviewer(ctx context.Context, prID, reviewerID string) error
 	ListReviewers(ctx context.Context, prID string) ([]string, error)
+	ListReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error)
+	ListOpenReviewsByTeam(ctx context.Context, teamID string) ([]*domain.ReviewAssignment, error)
+	CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error)
+
+	AddEvent(ctx context.Context, event *domain.PullRequestEvent) error
+	ListEvents(ctx context.Context, prID string) ([]*domain.PullRequestEvent, error)
+
+	ListByReviewer(ctx context.Context, filter *domain.ReviewerPRFilter) ([]*domain.PullRequest, error)
+	List(ctx context.Context, filter *domain.PullRequestFilter) ([]*domain.PullRequest, error)
+
+	SaveReviewSync(ctx context.Context, sync *domain.ReviewSync) error
+	GetReviewSync(ctx context.Context, prID string) (*domain.ReviewSync, error)
+}
+
+// Identity хранит учётные записи пользователей у внешних провайдеров; логин
+// передаётся уже нормализованным (domain.NormalizeLogin).
+type Identity interface {
+	Create(ctx context.Context, identity *domain.Identity) error
+
+	GetByLogin(ctx context.Context, provider domain.IdentityProvider, login string) (*domain.Identity, error)
+	GetByExternalID(ctx context.Context, provider domain.IdentityProvider, externalID int64) (*domain.Identity, error)
+	ListByUser(ctx context.Context, userID string) ([]*domain.Identity, error)
+
+	UpdateExternalID(ctx context.Context, provider domain.IdentityProvider, login string, externalID int64) error
+
+	Delete(ctx context.Context, provider domain.IdentityProvider, login string) error
+}
+
+// Subscription хранит подписки на события PR и журнал их доставок; доставки
+// удаляются вместе с подпиской.
+type Subscription interface {
+	Create(ctx context.Context, sub *domain.Subscription) error
+
+	GetByID(ctx context.Context, id string) (*domain.Subscription, error)
+	List(ctx context.Context) ([]*domain.Subscription, error)
+
+	Delete(ctx context.Context, id string) error
+
+	CreateDelivery(ctx context.Context, d *domain.Delivery) error
+	// UpdateDelivery сохраняет статус, число попыток, результат последней попытки
+	// и время следующей.
+	UpdateDelivery(ctx context.Context, d *domain.Delivery) error
+	GetDelivery(ctx context.Context, id string) (*domain.Delivery, error)
+	ListDeliveries(ctx context.Context, filter *domain.DeliveryFilter) ([]*domain.Delivery, error)
+	// ClaimDeliveries забирает до limit самых давних доставок PENDING, чья попытка
+	// наступила к now, и переносит их следующую попытку на until, чтобы их не
+	// забрал другой инстанс. Доставка, которую не успели сохранить
+	// до until, снова станет доступной.
+	ClaimDeliveries(ctx context.Context, now, until time.Time, limit int) ([]*domain.Delivery, error)
+}
+
+// Notification хранит каналы оповещений пользователей, настройки ежедневной
+// сводки и отметки об уже отправленных предупреждениях SLA.
+type Notification interface {
+	// SetPreference добавляет канал пользователя или меняет его адрес.
+	SetPreference(ctx context.Context, pref *domain.NotificationPreference) error
+	DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error
+	// ListPreferences возвращает каналы пользователя по имени канала.
+	ListPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error)
+
+	// MarkSLAWarned отмечает, что ревьювер предупреждён о PR; false — уже был.
+	MarkSLAWarned(ctx context.Context, prID, reviewerID string) (bool, error)
+	// UnmarkSLAWarned снимает отметку, чтобы предупреждение отправилось снова.
+	UnmarkSLAWarned(ctx context.Context, prID, reviewerID string) error
+
+	// SetDigest сохраняет настройки сводки, не трогая LastSentAt.
+	SetDigest(ctx context.Context, settings *domain.DigestSettings) error
+	GetDigest(ctx context.Context, userID string) (*domain.DigestSettings, error)
+	// ListEnabledDigests возвращает включённые сводки по id пользователя.
+	ListEnabledDigests(ctx context.Context) ([]*domain.DigestSettings, error)
+	// MarkDigestSent меняет LastSentAt с prev на sentAt; false — другой
+	// инстанс уже отметил отправку.
+	MarkDigestSent(ctx context.Context, userID string, prev *time.Time, sentAt time.Time) (bool, error)
+}
+
+// Audit — журнал аудита: записи только добавляются и удаляются по сроку хранения.
+type Audit interface {
+	Append(ctx context.Context, record *domain.AuditRecord) error
+	List(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditRecord, error)
+	// DeleteBefore удаляет записи, сделанные раньше before, и возвращает их число.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Outbox хранит события PR до публикации. Add вызывается в транзакции вместе с
+// изменением, которое породило событие.
+type Outbox interface {
+	Add(ctx context.Context, msg *domain.OutboxMessage) error
+
+	// ListPending возвращает до limit неопубликованных и не снятых с публикации
+	// событий по возрастанию Id.
+	ListPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error)
+
+	// MarkPublished помечает событие опубликованным и присваивает ему следующий
+	// PublishedSeq.
+	MarkPublished(ctx context.Context, id int64) error
+	// MarkFailed увеличивает число попыток, запоминает ошибку последней и
+	// получателей, уже принявших событие: при повторе оно уходит только остальным.
+	MarkFailed(ctx context.Context, id int64, acceptedBy []string, lastError string) error
+	// MarkDead снимает событие с публикации, когда попытки кончились.
+	MarkDead(ctx context.Context, id int64) error
+
+	// ListPublished возвращает до limit опубликованных событий с PublishedSeq
+	// больше afterSeq по его возрастанию.
+	ListPublished(ctx context.Context, afterSeq int64, limit int) ([]*domain.OutboxMessage, error)
+	// LastPublishedSeq возвращает PublishedSeq последнего опубликованного события, 0 — таких нет.
+	LastPublishedSeq(ctx context.Context) (int64, error)
+}
+
+// OutboxListener реализуют хранилища, общие для нескольких инстансов: канал
+// получает сигнал, когда любой инстанс опубликовал событие из outbox. Канал
+// закрывается при отмене ctx.
+type OutboxListener interface {
+	ListenOutbox(ctx context.Context) (<-chan struct{}, error)
+}
+
+// OutboxLocker реализуют хранилища, общие для нескольких инстансов: relay
+// публикует события, только захватив блокировку, поэтому проходы разных
+// инстансов не пересекаются и события одного PR не обгоняют друг друга.
+// ok == false — блокировку держит другой инстанс; unlock её отпускает.
+type OutboxLocker interface {
+	LockOutbox(ctx context.Context) (unlock func(), ok bool, err error)
+}
+
+// Transactor выполняет fn в транзакции хранилища: репозитории, вызванные с ctx
+// из fn, работают в ней. Ошибка fn откатывает транзакцию.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Stats interface {
+	UserStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.UserStats, error)
+	TeamStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.TeamStats, error)
+	MergeTime(ctx context.Context, filter *domain.StatsFilter) (*domain.MergeTimeStats, error)
+}
 
-	ListByReviewer(ctx context.Context, reviewerID string) ([]*domain.PullRequest, error)
+// Factory отдаёт репозитории одного хранилища; по нему usecase.Setup собирает
+// сценарии независимо от выбранного бэкенда.
+type Factory interface {
+	User() User
+	Team() Team
+	PullRequest() PullRequest
+	Identity() Identity
+	Subscription() Subscription
+	Notification() Notification
+	Audit() Audit
+	Outbox() Outbox
+	Stats() Stats
+	Transactor() Transactor
 }
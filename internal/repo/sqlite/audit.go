@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type AuditRepo struct {
+	db *sql.DB
+}
+
+func NewAuditRepo(db *sql.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+func (r *AuditRepo) Append(ctx context.Context, record *domain.AuditRecord) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO audit_log(id, actor, claimed_actor, action, target_type, target_id, snapshot_before, snapshot_after, request_id, created_at)
+         VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), NULLIF(?, ''), ?, ?)`,
+		record.Id,
+		record.Actor,
+		record.ClaimedActor,
+		record.Action,
+		record.TargetType,
+		record.TargetId,
+		string(record.Before),
+		string(record.After),
+		record.RequestId,
+		ts.UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert audit_log: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert audit_log: %w", err)
+	}
+
+	record.CreatedAt = ts
+	return nil
+}
+
+func (r *AuditRepo) List(ctx context.Context, filter *domain.AuditFilter) ([]*domain.AuditRecord, error) {
+	builder := sq.
+		Select(
+			"id", "actor", "claimed_actor", "action", "target_type", "target_id",
+			"COALESCE(snapshot_before, '')", "COALESCE(snapshot_after, '')",
+			"request_id", "created_at",
+		).
+		From("audit_log")
+
+	if filter.Actor != "" {
+		builder = builder.Where(sq.Eq{"actor": filter.Actor})
+	}
+	if filter.ClaimedActor != "" {
+		builder = builder.Where(sq.Eq{"claimed_actor": filter.ClaimedActor})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(sq.Eq{"action": filter.Action})
+	}
+	if filter.TargetType != "" {
+		builder = builder.Where(sq.Eq{"target_type": filter.TargetType})
+	}
+	if filter.TargetId != "" {
+		builder = builder.Where(sq.Eq{"target_id": filter.TargetId})
+	}
+	if filter.RequestId != "" {
+		builder = builder.Where(sq.Eq{"request_id": filter.RequestId})
+	}
+	if !filter.From.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.From.UnixMicro()})
+	}
+	if !filter.To.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.To.UnixMicro()})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listAudit: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listAudit: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.AuditRecord
+	for rows.Next() {
+		record, err := scanAuditRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan audit_log: %w", err)
+		}
+		res = append(res, record)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *AuditRepo) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM audit_log WHERE created_at < ?`,
+		before.UnixMicro(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete audit_log: %w", err)
+	}
+
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+func scanAuditRecord(row scanner) (*domain.AuditRecord, error) {
+	var (
+		record        domain.AuditRecord
+		before, after string
+		created       int64
+	)
+	err := row.Scan(
+		&record.Id, &record.Actor, &record.ClaimedActor, &record.Action, &record.TargetType, &record.TargetId,
+		&before, &after, &record.RequestId, &created,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if before != "" {
+		record.Before = []byte(before)
+	}
+	if after != "" {
+		record.After = []byte(after)
+	}
+	record.CreatedAt = fromMicro(created)
+
+	return &record, nil
+}
@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+type IdentityRepo struct {
+	db *sql.DB
+}
+
+func NewIdentityRepo(db *sql.DB) *IdentityRepo {
+	return &IdentityRepo{db: db}
+}
+
+func (r *IdentityRepo) Create(ctx context.Context, identity *domain.Identity) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO user_identity(provider, login, user_id, external_id, created_at)
+         VALUES (?, ?, ?, NULLIF(?, 0), ?)`,
+		identity.Provider,
+		identity.Login,
+		identity.UserId,
+		identity.ExternalId,
+		ts.UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert user_identity: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert user_identity: %w", err)
+	}
+
+	identity.CreatedAt = ts
+	return nil
+}
+
+func (r *IdentityRepo) GetByLogin(ctx context.Context, provider domain.IdentityProvider, login string) (*domain.Identity, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE provider = ? AND login = ?`,
+		provider,
+		login,
+	)
+
+	return getIdentity(row)
+}
+
+func (r *IdentityRepo) GetByExternalID(ctx context.Context, provider domain.IdentityProvider, externalID int64) (*domain.Identity, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE provider = ? AND external_id = ?`,
+		provider,
+		externalID,
+	)
+
+	return getIdentity(row)
+}
+
+func (r *IdentityRepo) ListByUser(ctx context.Context, userID string) ([]*domain.Identity, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT provider, login, user_id, COALESCE(external_id, 0), created_at
+         FROM user_identity
+         WHERE user_id = ?
+         ORDER BY created_at, provider, login`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user_identity: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Identity
+	for rows.Next() {
+		i, err := scanIdentity(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user_identity: %w", err)
+		}
+		res = append(res, i)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *IdentityRepo) UpdateExternalID(ctx context.Context, provider domain.IdentityProvider, login string, externalID int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE user_identity
+         SET external_id = NULLIF(?, 0)
+         WHERE provider = ? AND login = ?`,
+		externalID,
+		provider,
+		login,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("update user_identity: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("update user_identity: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *IdentityRepo) Delete(ctx context.Context, provider domain.IdentityProvider, login string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM user_identity
+         WHERE provider = ? AND login = ?`,
+		provider,
+		login,
+	)
+	if err != nil {
+		return fmt.Errorf("delete user_identity: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func getIdentity(row *sql.Row) (*domain.Identity, error) {
+	i, err := scanIdentity(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select user_identity: %w", err)
+	}
+	return i, nil
+}
+
+func scanIdentity(row scanner) (*domain.Identity, error) {
+	var (
+		i       domain.Identity
+		created int64
+	)
+	if err := row.Scan(&i.Provider, &i.Login, &i.UserId, &i.ExternalId, &created); err != nil {
+		return nil, err
+	}
+	i.CreatedAt = fromMicro(created)
+
+	return &i, nil
+}
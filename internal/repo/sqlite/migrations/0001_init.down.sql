@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS pull_request_history;
+DROP TABLE IF EXISTS pull_request_reviewer;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS team_membership;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS team;
@@ -0,0 +1,122 @@
+-- схема соответствует миграциям Postgres 0001–0007; время хранится в микросекундах Unix UTC
+
+CREATE TABLE team
+(
+    id        TEXT PRIMARY KEY,
+    name      TEXT UNIQUE NOT NULL,
+    parent_id TEXT,
+
+    -- при удалении родителя подкоманды становятся корневыми
+    CONSTRAINT fk_team_parent
+        FOREIGN KEY (parent_id)
+            REFERENCES team (id)
+            ON DELETE SET NULL,
+
+    CONSTRAINT chk_team_parent_self CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_team_parent ON team (parent_id);
+
+CREATE TABLE users
+(
+    id         TEXT PRIMARY KEY,
+    username   TEXT    NOT NULL,
+    team_id    TEXT,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    CONSTRAINT fk_users_team
+        FOREIGN KEY (team_id)
+            REFERENCES team (id)
+            ON DELETE SET NULL
+);
+
+CREATE INDEX idx_users_team ON users (team_id);
+CREATE INDEX idx_users_created_id ON users (created_at, id);
+
+CREATE TABLE team_membership
+(
+    team_id    TEXT    NOT NULL,
+    user_id    TEXT    NOT NULL,
+    role       TEXT    NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead')),
+    created_at INTEGER NOT NULL,
+
+    PRIMARY KEY (team_id, user_id),
+
+    CONSTRAINT fk_team_membership_team
+        FOREIGN KEY (team_id)
+            REFERENCES team (id)
+            ON DELETE CASCADE,
+
+    CONSTRAINT fk_team_membership_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_membership_user ON team_membership (user_id);
+
+CREATE TABLE pull_requests
+(
+    id         TEXT PRIMARY KEY,
+    name       TEXT    NOT NULL,
+    author_id  TEXT    NOT NULL,
+    team_id    TEXT,
+    status     TEXT    NOT NULL CHECK (status IN ('OPEN', 'MERGED')),
+    created_at INTEGER NOT NULL,
+    merged_at  INTEGER,
+
+    CONSTRAINT fk_pr_author
+        FOREIGN KEY (author_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE,
+
+    CONSTRAINT fk_pr_team
+        FOREIGN KEY (team_id)
+            REFERENCES team (id)
+            ON DELETE SET NULL
+);
+
+CREATE INDEX idx_pr_author ON pull_requests (author_id);
+CREATE INDEX idx_pr_status ON pull_requests (status);
+CREATE INDEX idx_pr_created_id ON pull_requests (created_at, id);
+CREATE INDEX idx_pr_team_created ON pull_requests (team_id, created_at);
+
+CREATE TABLE pull_request_reviewer
+(
+    pull_request_id TEXT NOT NULL,
+    reviewer_id     TEXT NOT NULL,
+
+    PRIMARY KEY (pull_request_id, reviewer_id),
+
+    CONSTRAINT fk_pr_reviewers_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE,
+
+    CONSTRAINT fk_pr_reviewers_user
+        FOREIGN KEY (reviewer_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_reviewer ON pull_request_reviewer (reviewer_id);
+
+CREATE TABLE pull_request_history
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT    NOT NULL,
+    type            TEXT    NOT NULL CHECK (type IN ('CREATED', 'REVIEWER_ASSIGNED', 'REVIEWER_REMOVED', 'MERGED')),
+    reviewer_id     TEXT,
+    actor           TEXT    NOT NULL,
+    reason          TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL,
+
+    CONSTRAINT fk_pr_history_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_history_pr_type ON pull_request_history (pull_request_id, type, reviewer_id);
@@ -0,0 +1,14 @@
+-- соответствует миграции Postgres 0008
+CREATE TABLE pull_request_sync
+(
+    pull_request_id TEXT PRIMARY KEY,
+    status          TEXT    NOT NULL CHECK (status IN ('PENDING', 'SYNCED', 'FAILED')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT    NOT NULL DEFAULT '',
+    updated_at      INTEGER NOT NULL,
+
+    CONSTRAINT fk_pr_sync_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- соответствует миграции Postgres 0009
+CREATE TABLE user_identity
+(
+    provider    TEXT    NOT NULL CHECK (provider IN ('github', 'gitlab', 'email')),
+    login       TEXT    NOT NULL,
+    user_id     TEXT    NOT NULL,
+    external_id INTEGER,
+    created_at  INTEGER NOT NULL,
+
+    PRIMARY KEY (provider, login),
+
+    CONSTRAINT uq_user_identity_external UNIQUE (provider, external_id),
+
+    CONSTRAINT fk_user_identity_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_identity_user ON user_identity (user_id, created_at);
@@ -0,0 +1,31 @@
+-- соответствует миграции Postgres 0010; events — типы событий через запятую
+CREATE TABLE webhook_subscription
+(
+    id         TEXT PRIMARY KEY,
+    url        TEXT    NOT NULL,
+    events     TEXT    NOT NULL DEFAULT '',
+    secret     TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+
+CREATE TABLE webhook_delivery
+(
+    id              TEXT PRIMARY KEY,
+    subscription_id TEXT    NOT NULL,
+    event_id        TEXT    NOT NULL,
+    event_type      TEXT    NOT NULL,
+    payload         TEXT    NOT NULL,
+    status          TEXT    NOT NULL CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD')),
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    response_code   INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL,
+    updated_at      INTEGER NOT NULL,
+
+    CONSTRAINT fk_webhook_delivery_subscription
+        FOREIGN KEY (subscription_id)
+            REFERENCES webhook_subscription (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_delivery_subscription ON webhook_delivery (subscription_id, created_at, id);
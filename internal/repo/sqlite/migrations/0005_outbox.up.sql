@@ -0,0 +1,14 @@
+-- соответствует миграции Postgres 0011
+CREATE TABLE outbox
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT    NOT NULL,
+    event_type      TEXT    NOT NULL,
+    payload         TEXT    NOT NULL,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    last_error      TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL,
+    published_at    INTEGER
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE published_at IS NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_published_seq;
+ALTER TABLE outbox DROP COLUMN published_seq;
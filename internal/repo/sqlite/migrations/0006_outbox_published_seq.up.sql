@@ -0,0 +1,4 @@
+-- соответствует миграции Postgres 0012; оповещений нет, база одна на процесс
+ALTER TABLE outbox ADD COLUMN published_seq INTEGER;
+
+CREATE UNIQUE INDEX idx_outbox_published_seq ON outbox (published_seq);
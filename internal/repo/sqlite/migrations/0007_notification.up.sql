@@ -0,0 +1,30 @@
+-- соответствует миграции Postgres 0013
+CREATE TABLE notification_preference
+(
+    user_id    TEXT    NOT NULL,
+    channel    TEXT    NOT NULL CHECK (channel IN ('slack', 'email', 'http')),
+    address    TEXT    NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL,
+
+    PRIMARY KEY (user_id, channel),
+
+    CONSTRAINT fk_notification_preference_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE notification_sla_warning
+(
+    pull_request_id TEXT    NOT NULL,
+    reviewer_id     TEXT    NOT NULL,
+    warned_at       INTEGER NOT NULL,
+
+    PRIMARY KEY (pull_request_id, reviewer_id),
+
+    CONSTRAINT fk_notification_sla_warning_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- соответствует миграции Postgres 0014
+CREATE TABLE notification_digest
+(
+    user_id      TEXT    NOT NULL PRIMARY KEY,
+    enabled      BOOLEAN NOT NULL DEFAULT FALSE,
+    timezone     TEXT    NOT NULL DEFAULT 'UTC',
+    hour         INTEGER NOT NULL DEFAULT 9 CHECK (hour BETWEEN 0 AND 23),
+    quiet_start  INTEGER CHECK (quiet_start BETWEEN 0 AND 23),
+    quiet_end    INTEGER CHECK (quiet_end BETWEEN 0 AND 23),
+    last_sent_at INTEGER,
+    updated_at   INTEGER NOT NULL,
+
+    CONSTRAINT fk_notification_digest_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
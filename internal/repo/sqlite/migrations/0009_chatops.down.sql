@@ -0,0 +1,29 @@
+DROP TABLE IF EXISTS user_away;
+
+CREATE TABLE user_identity_old
+(
+    provider    TEXT    NOT NULL CHECK (provider IN ('github', 'gitlab', 'email')),
+    login       TEXT    NOT NULL,
+    user_id     TEXT    NOT NULL,
+    external_id INTEGER,
+    created_at  INTEGER NOT NULL,
+
+    PRIMARY KEY (provider, login),
+
+    CONSTRAINT uq_user_identity_external UNIQUE (provider, external_id),
+
+    CONSTRAINT fk_user_identity_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+INSERT INTO user_identity_old(provider, login, user_id, external_id, created_at)
+SELECT provider, login, user_id, external_id, created_at
+FROM user_identity
+WHERE provider <> 'slack';
+
+DROP TABLE user_identity;
+ALTER TABLE user_identity_old RENAME TO user_identity;
+
+CREATE INDEX idx_user_identity_user ON user_identity (user_id, created_at);
@@ -0,0 +1,41 @@
+-- соответствует миграции Postgres 0015; CHECK в SQLite не меняется, таблица пересоздаётся
+CREATE TABLE user_identity_new
+(
+    provider    TEXT    NOT NULL CHECK (provider IN ('github', 'gitlab', 'email', 'slack')),
+    login       TEXT    NOT NULL,
+    user_id     TEXT    NOT NULL,
+    external_id INTEGER,
+    created_at  INTEGER NOT NULL,
+
+    PRIMARY KEY (provider, login),
+
+    CONSTRAINT uq_user_identity_external UNIQUE (provider, external_id),
+
+    CONSTRAINT fk_user_identity_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+INSERT INTO user_identity_new(provider, login, user_id, external_id, created_at)
+SELECT provider, login, user_id, external_id, created_at
+FROM user_identity;
+
+DROP TABLE user_identity;
+ALTER TABLE user_identity_new RENAME TO user_identity;
+
+CREATE INDEX idx_user_identity_user ON user_identity (user_id, created_at);
+
+CREATE TABLE user_away
+(
+    user_id    TEXT    NOT NULL PRIMARY KEY,
+    until      INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+
+    CONSTRAINT fk_user_away_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_away_until ON user_away (until);
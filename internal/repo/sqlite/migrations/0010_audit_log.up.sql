@@ -0,0 +1,24 @@
+-- соответствует миграции Postgres 0016
+CREATE TABLE audit_log
+(
+    id              TEXT    NOT NULL PRIMARY KEY,
+    actor           TEXT    NOT NULL,
+    action          TEXT    NOT NULL,
+    target_type     TEXT    NOT NULL,
+    target_id       TEXT    NOT NULL,
+    snapshot_before TEXT,
+    snapshot_after  TEXT,
+    request_id      TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL
+);
+
+CREATE INDEX idx_audit_log_created ON audit_log (created_at, id);
+CREATE INDEX idx_audit_log_target ON audit_log (target_type, target_id, created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor, created_at);
+
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE
+    ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log is append-only');
+END;
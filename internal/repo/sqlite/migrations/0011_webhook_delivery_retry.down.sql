@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_webhook_delivery_due;
+ALTER TABLE webhook_delivery DROP COLUMN next_attempt_at;
@@ -0,0 +1,6 @@
+-- соответствует миграции Postgres 0017
+ALTER TABLE webhook_delivery ADD COLUMN next_attempt_at INTEGER;
+
+UPDATE webhook_delivery SET next_attempt_at = updated_at WHERE status = 'PENDING';
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery (next_attempt_at) WHERE status = 'PENDING';
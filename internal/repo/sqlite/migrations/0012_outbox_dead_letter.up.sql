@@ -0,0 +1,6 @@
+-- соответствует миграции Postgres 0018
+ALTER TABLE outbox ADD COLUMN accepted_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE outbox ADD COLUMN dead_at INTEGER;
+
+DROP INDEX idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE published_at IS NULL AND dead_at IS NULL;
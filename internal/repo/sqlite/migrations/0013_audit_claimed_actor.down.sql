@@ -0,0 +1,3 @@
+DROP INDEX idx_audit_log_claimed_actor;
+
+ALTER TABLE audit_log DROP COLUMN claimed_actor;
@@ -0,0 +1,4 @@
+-- соответствует миграции Postgres 0019
+ALTER TABLE audit_log ADD COLUMN claimed_actor TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_audit_log_claimed_actor ON audit_log (claimed_actor, created_at);
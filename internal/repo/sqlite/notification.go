@@ -0,0 +1,233 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+)
+
+type NotificationRepo struct {
+	db *sql.DB
+}
+
+func NewNotificationRepo(db *sql.DB) *NotificationRepo {
+	return &NotificationRepo{db: db}
+}
+
+func (r *NotificationRepo) SetPreference(ctx context.Context, pref *domain.NotificationPreference) error {
+	var created, updated int64
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO notification_preference(user_id, channel, address, created_at, updated_at)
+         VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT (user_id, channel) DO UPDATE SET address = excluded.address, updated_at = excluded.updated_at
+         RETURNING created_at, updated_at`,
+		pref.UserId,
+		pref.Channel,
+		pref.Address,
+		now().UnixMicro(),
+		now().UnixMicro(),
+	).Scan(&created, &updated)
+	if err != nil {
+		return fmt.Errorf("upsert notification_preference: %w", err)
+	}
+
+	pref.CreatedAt, pref.UpdatedAt = fromMicro(created), fromMicro(updated)
+	return nil
+}
+
+func (r *NotificationRepo) DeletePreference(ctx context.Context, userID string, channel domain.NotificationChannel) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM notification_preference WHERE user_id = ? AND channel = ?`,
+		userID,
+		channel,
+	)
+	if err != nil {
+		return fmt.Errorf("delete notification_preference: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *NotificationRepo) ListPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT user_id, channel, address, created_at, updated_at
+         FROM notification_preference
+         WHERE user_id = ?
+         ORDER BY channel`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_preference: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.NotificationPreference
+	for rows.Next() {
+		var (
+			p                domain.NotificationPreference
+			created, updated int64
+		)
+		if err := rows.Scan(&p.UserId, &p.Channel, &p.Address, &created, &updated); err != nil {
+			return nil, fmt.Errorf("scan notification_preference: %w", err)
+		}
+		p.CreatedAt, p.UpdatedAt = fromMicro(created), fromMicro(updated)
+		res = append(res, &p)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *NotificationRepo) MarkSLAWarned(ctx context.Context, prID, reviewerID string) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO notification_sla_warning(pull_request_id, reviewer_id, warned_at)
+         VALUES (?, ?, ?)
+         ON CONFLICT DO NOTHING`,
+		prID,
+		reviewerID,
+		now().UnixMicro(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert notification_sla_warning: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *NotificationRepo) UnmarkSLAWarned(ctx context.Context, prID, reviewerID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM notification_sla_warning
+         WHERE pull_request_id = ? AND reviewer_id = ?`,
+		prID,
+		reviewerID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete notification_sla_warning: %w", err)
+	}
+	return nil
+}
+
+const digestColumns = `user_id, enabled, timezone, hour, quiet_start, quiet_end, last_sent_at, updated_at`
+
+func (r *NotificationRepo) SetDigest(ctx context.Context, settings *domain.DigestSettings) error {
+	var (
+		sent    sql.NullInt64
+		updated int64
+	)
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO notification_digest(user_id, enabled, timezone, hour, quiet_start, quiet_end, updated_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?)
+         ON CONFLICT (user_id) DO UPDATE SET enabled     = excluded.enabled,
+                                             timezone    = excluded.timezone,
+                                             hour        = excluded.hour,
+                                             quiet_start = excluded.quiet_start,
+                                             quiet_end   = excluded.quiet_end,
+                                             updated_at  = excluded.updated_at
+         RETURNING last_sent_at, updated_at`,
+		settings.UserId,
+		settings.Enabled,
+		settings.Timezone,
+		settings.Hour,
+		settings.QuietStart,
+		settings.QuietEnd,
+		now().UnixMicro(),
+	).Scan(&sent, &updated)
+	if err != nil {
+		return fmt.Errorf("upsert notification_digest: %w", err)
+	}
+
+	settings.LastSentAt, settings.UpdatedAt = fromNullMicro(sent), fromMicro(updated)
+	return nil
+}
+
+func (r *NotificationRepo) GetDigest(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT `+digestColumns+` FROM notification_digest WHERE user_id = ?`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_digest: %w", err)
+	}
+
+	settings, err := collectDigests(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return nil, repo.ErrNotFound
+	}
+	return settings[0], nil
+}
+
+func (r *NotificationRepo) ListEnabledDigests(ctx context.Context) ([]*domain.DigestSettings, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT `+digestColumns+` FROM notification_digest WHERE enabled ORDER BY user_id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query notification_digest: %w", err)
+	}
+
+	return collectDigests(rows)
+}
+
+func (r *NotificationRepo) MarkDigestSent(ctx context.Context, userID string, prev *time.Time, sentAt time.Time) (bool, error) {
+	var prevMicro *int64
+	if prev != nil {
+		v := prev.UnixMicro()
+		prevMicro = &v
+	}
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE notification_digest
+         SET last_sent_at = ?
+         WHERE user_id = ? AND last_sent_at IS ?`,
+		sentAt.UnixMicro(),
+		userID,
+		prevMicro,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update notification_digest: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func collectDigests(rows *sql.Rows) ([]*domain.DigestSettings, error) {
+	defer rows.Close()
+
+	var res []*domain.DigestSettings
+	for rows.Next() {
+		var (
+			s                    domain.DigestSettings
+			quietStart, quietEnd sql.NullInt64
+			sent                 sql.NullInt64
+			updated              int64
+		)
+		if err := rows.Scan(&s.UserId, &s.Enabled, &s.Timezone, &s.Hour, &quietStart, &quietEnd, &sent, &updated); err != nil {
+			return nil, fmt.Errorf("scan notification_digest: %w", err)
+		}
+		s.QuietStart, s.QuietEnd = nullInt(quietStart), nullInt(quietEnd)
+		s.LastSentAt, s.UpdatedAt = fromNullMicro(sent), fromMicro(updated)
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"strings"
+)
+
+type OutboxRepo struct {
+	db *sql.DB
+}
+
+func NewOutboxRepo(db *sql.DB) *OutboxRepo {
+	return &OutboxRepo{db: db}
+}
+
+func (r *OutboxRepo) Add(ctx context.Context, msg *domain.OutboxMessage) error {
+	ts := now()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO outbox(pull_request_id, event_type, payload, created_at)
+         VALUES (?, ?, ?, ?)`,
+		msg.PullRequestId,
+		msg.EventType,
+		string(msg.Payload),
+		ts.UnixMicro(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert outbox: %w", err)
+	}
+
+	msg.Id, msg.CreatedAt = id, ts
+	return nil
+}
+
+func (r *OutboxRepo) ListPending(ctx context.Context, limit int) ([]*domain.OutboxMessage, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, pull_request_id, event_type, payload, attempts, last_error, accepted_by, created_at, published_at, published_seq, dead_at
+         FROM outbox
+         WHERE published_at IS NULL AND dead_at IS NULL
+         ORDER BY id
+         LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.OutboxMessage
+	for rows.Next() {
+		msg, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox: %w", err)
+		}
+		res = append(res, msg)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox
+         SET published_at  = COALESCE(published_at, ?),
+             published_seq = COALESCE(published_seq, (SELECT COALESCE(MAX(published_seq), 0) + 1 FROM outbox))
+         WHERE id = ?`,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkFailed(ctx context.Context, id int64, acceptedBy []string, lastError string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox SET attempts = attempts + 1, last_error = ?, accepted_by = ? WHERE id = ?`,
+		lastError,
+		strings.Join(acceptedBy, ","),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) MarkDead(ctx context.Context, id int64) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE outbox SET dead_at = COALESCE(dead_at, ?) WHERE id = ?`,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update outbox: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *OutboxRepo) ListPublished(ctx context.Context, afterSeq int64, limit int) ([]*domain.OutboxMessage, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, pull_request_id, event_type, payload, attempts, last_error, accepted_by, created_at, published_at, published_seq, dead_at
+         FROM outbox
+         WHERE published_seq > ?
+         ORDER BY published_seq
+         LIMIT ?`,
+		afterSeq,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.OutboxMessage
+	for rows.Next() {
+		msg, err := scanOutboxMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox: %w", err)
+		}
+		res = append(res, msg)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *OutboxRepo) LastPublishedSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(published_seq), 0) FROM outbox`,
+	).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("select outbox: %w", err)
+	}
+	return seq, nil
+}
+
+func scanOutboxMessage(row scanner) (*domain.OutboxMessage, error) {
+	var (
+		msg       domain.OutboxMessage
+		payload   string
+		accepted  string
+		created   int64
+		published sql.NullInt64
+		seq       sql.NullInt64
+		dead      sql.NullInt64
+	)
+	err := row.Scan(
+		&msg.Id, &msg.PullRequestId, &msg.EventType, &payload,
+		&msg.Attempts, &msg.LastError, &accepted, &created, &published, &seq, &dead,
+	)
+	if err != nil {
+		return nil, err
+	}
+	msg.Payload = []byte(payload)
+	msg.CreatedAt = fromMicro(created)
+	msg.PublishedAt = fromNullMicro(published)
+	msg.PublishedSeq = seq.Int64
+	msg.DeadAt = fromNullMicro(dead)
+	if accepted != "" {
+		msg.AcceptedBy = strings.Split(accepted, ",")
+	}
+
+	return &msg, nil
+}
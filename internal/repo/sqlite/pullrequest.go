@@ -0,0 +1,529 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type PullRequestRepo struct {
+	db *sql.DB
+}
+
+func NewPullRequestRepo(db *sql.DB) *PullRequestRepo {
+	return &PullRequestRepo{db: db}
+}
+
+func (r *PullRequestRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO pull_requests(id, author_id, name, team_id, status, created_at)
+         VALUES (?, ?, ?, NULLIF(?, ''), ?, ?)`,
+		pr.Id,
+		pr.AuthorId,
+		pr.Name,
+		pr.TeamId,
+		pr.Status,
+		now().UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert pull_request: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert pull_request: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (*domain.PullRequest, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT pr.id, pr.author_id, pr.name, COALESCE(pr.team_id, ''), COALESCE(rt.name, ''),
+                pr.status, pr.created_at, pr.merged_at
+         FROM pull_requests AS pr
+         LEFT JOIN team AS rt ON rt.id = pr.team_id
+         WHERE pr.id = ?`,
+		id,
+	)
+
+	pr, err := scanPullRequest(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request: %w", err)
+	}
+
+	return pr, nil
+}
+
+func (r *PullRequestRepo) UpdateStatusMerged(ctx context.Context, id string) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE pull_requests
+         SET status = 'MERGED',
+             merged_at = COALESCE(merged_at, ?)
+         WHERE id = ? AND status = 'OPEN'`,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update merged: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		return true, nil
+	}
+
+	var exists bool
+	if err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM pull_requests WHERE id = ?)`,
+		id,
+	).Scan(&exists); err != nil {
+		return false, fmt.Errorf("select pull_request: %w", err)
+	}
+	if !exists {
+		return false, repo.ErrNotFound
+	}
+
+	return false, nil
+}
+
+func (r *PullRequestRepo) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO pull_request_reviewer(pull_request_id, reviewer_id)
+         VALUES (?, ?)`,
+		prID, reviewerID,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert reviewer: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert reviewer: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM pull_request_reviewer
+         WHERE pull_request_id = ? AND reviewer_id = ?`,
+		prID, reviewerID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete reviewer: %w", err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT reviewer_id
+         FROM pull_request_reviewer
+         WHERE pull_request_id = ?`,
+		prID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var rid string
+		if err := rows.Scan(&rid); err != nil {
+			return nil, fmt.Errorf("scan reviewer: %w", err)
+		}
+		ids = append(ids, rid)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return ids, nil
+}
+
+func (r *PullRequestRepo) ListReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
+	res := make(map[string][]string, len(prIDs))
+	if len(prIDs) == 0 {
+		return res, nil
+	}
+
+	query, args, err := sq.
+		Select("pull_request_id", "reviewer_id").
+		From("pull_request_reviewer").
+		Where(sq.Eq{"pull_request_id": prIDs}).
+		OrderBy("pull_request_id", "reviewer_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listReviewersByPRs: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listReviewersByPRs: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var prID, reviewerID string
+		if err := rows.Scan(&prID, &reviewerID); err != nil {
+			return nil, fmt.Errorf("scan listReviewersByPRs: %w", err)
+		}
+		res[prID] = append(res[prID], reviewerID)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) ListOpenReviewsByTeam(ctx context.Context, teamID string) ([]*domain.ReviewAssignment, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT rr.pull_request_id, rr.reviewer_id
+         FROM pull_request_reviewer AS rr
+         JOIN pull_requests AS pr ON pr.id = rr.pull_request_id
+         WHERE pr.team_id = ? AND pr.status = 'OPEN'
+         ORDER BY rr.pull_request_id, rr.reviewer_id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query open reviews by team: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.ReviewAssignment
+	for rows.Next() {
+		var a domain.ReviewAssignment
+		if err := rows.Scan(&a.PullRequestId, &a.ReviewerId); err != nil {
+			return nil, fmt.Errorf("scan open review: %w", err)
+		}
+		res = append(res, &a)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	res := make(map[string]int, len(reviewerIDs))
+	if len(reviewerIDs) == 0 {
+		return res, nil
+	}
+
+	query, args, err := sq.
+		Select("rr.reviewer_id", "COUNT(*)").
+		From("pull_request_reviewer AS rr").
+		Join("pull_requests AS pr ON pr.id = rr.pull_request_id").
+		Where(sq.Eq{"rr.reviewer_id": reviewerIDs, "pr.status": domain.PullRequestStatusOpen}).
+		GroupBy("rr.reviewer_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql countOpenReviews: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query countOpenReviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id string
+			n  int
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, fmt.Errorf("scan countOpenReviews: %w", err)
+		}
+		res[id] = n
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) AddEvent(ctx context.Context, event *domain.PullRequestEvent) error {
+	ts := now()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO pull_request_history(pull_request_id, type, reviewer_id, actor, reason, created_at)
+         VALUES (?, ?, NULLIF(?, ''), ?, ?, ?)`,
+		event.PullRequestId,
+		event.Type,
+		event.ReviewerId,
+		event.Actor,
+		event.Reason,
+		ts.UnixMicro(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert pull_request_history: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert pull_request_history: %w", err)
+	}
+
+	event.Id = id
+	event.CreatedAt = ts
+	return nil
+}
+
+func (r *PullRequestRepo) ListEvents(ctx context.Context, prID string) ([]*domain.PullRequestEvent, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, pull_request_id, type, COALESCE(reviewer_id, ''), actor, reason, created_at
+         FROM pull_request_history
+         WHERE pull_request_id = ?
+         ORDER BY id`,
+		prID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query pull_request_history: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.PullRequestEvent
+	for rows.Next() {
+		var (
+			ev      domain.PullRequestEvent
+			created int64
+		)
+		if err := rows.Scan(
+			&ev.Id,
+			&ev.PullRequestId,
+			&ev.Type,
+			&ev.ReviewerId,
+			&ev.Actor,
+			&ev.Reason,
+			&created,
+		); err != nil {
+			return nil, fmt.Errorf("scan pull_request_history: %w", err)
+		}
+		ev.CreatedAt = fromMicro(created)
+		res = append(res, &ev)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *PullRequestRepo) SaveReviewSync(ctx context.Context, sync *domain.ReviewSync) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO pull_request_sync(pull_request_id, status, attempts, last_error, updated_at)
+         VALUES (?, ?, ?, ?, ?)
+         ON CONFLICT (pull_request_id) DO UPDATE
+             SET status = excluded.status,
+                 attempts = excluded.attempts,
+                 last_error = excluded.last_error,
+                 updated_at = excluded.updated_at`,
+		sync.PullRequestId,
+		sync.Status,
+		sync.Attempts,
+		sync.LastError,
+		ts.UnixMicro(),
+	)
+	if err != nil {
+		return fmt.Errorf("upsert pull_request_sync: %w", err)
+	}
+
+	sync.UpdatedAt = ts
+	return nil
+}
+
+func (r *PullRequestRepo) GetReviewSync(ctx context.Context, prID string) (*domain.ReviewSync, error) {
+	var (
+		s         domain.ReviewSync
+		updatedAt int64
+	)
+
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT pull_request_id, status, attempts, last_error, updated_at
+         FROM pull_request_sync
+         WHERE pull_request_id = ?`,
+		prID,
+	).Scan(&s.PullRequestId, &s.Status, &s.Attempts, &s.LastError, &updatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select pull_request_sync: %w", err)
+	}
+
+	s.UpdatedAt = fromMicro(updatedAt)
+	return &s, nil
+}
+
+func (r *PullRequestRepo) ListByReviewer(ctx context.Context, filter *domain.ReviewerPRFilter) ([]*domain.PullRequest, error) {
+	builder := sq.
+		Select(prColumns...).
+		From("pull_requests AS pr").
+		LeftJoin("team AS rt ON rt.id = pr.team_id").
+		Join("pull_request_reviewer AS rr ON rr.pull_request_id = pr.id").
+		Where(sq.Eq{"rr.reviewer_id": filter.ReviewerId})
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"pr.status": filter.Status})
+	}
+	if filter.TeamId != "" {
+		builder = builder.Where(sq.Eq{"pr.team_id": filter.TeamId})
+	}
+	if !filter.CreatedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.created_at": filter.CreatedFrom.UnixMicro()})
+	}
+	if !filter.CreatedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.created_at": filter.CreatedTo.UnixMicro()})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "pr.created_at", "pr.id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listByReviewer: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listByReviewer: %w", err)
+	}
+
+	return collectPullRequests(rows)
+}
+
+func (r *PullRequestRepo) List(ctx context.Context, filter *domain.PullRequestFilter) ([]*domain.PullRequest, error) {
+	builder := sq.
+		Select(prColumns...).
+		From("pull_requests AS pr").
+		LeftJoin("team AS rt ON rt.id = pr.team_id")
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"pr.status": filter.Status})
+	}
+	if filter.AuthorId != "" {
+		builder = builder.Where(sq.Eq{"pr.author_id": filter.AuthorId})
+	}
+	if filter.TeamName != "" {
+		builder = builder.Where(sq.Eq{"rt.name": filter.TeamName})
+	}
+	if filter.ReviewerId != "" {
+		builder = builder.Where(
+			"EXISTS (SELECT 1 FROM pull_request_reviewer rr WHERE rr.pull_request_id = pr.id AND rr.reviewer_id = ?)",
+			filter.ReviewerId,
+		)
+	}
+	if filter.NameContains != "" {
+		builder = builder.Where(likeCI("pr.name", "%"+escapeLike(filter.NameContains)+"%"))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.created_at": filter.CreatedFrom.UnixMicro()})
+	}
+	if !filter.CreatedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.created_at": filter.CreatedTo.UnixMicro()})
+	}
+	if !filter.MergedFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"pr.merged_at": filter.MergedFrom.UnixMicro()})
+	}
+	if !filter.MergedTo.IsZero() {
+		builder = builder.Where(sq.Lt{"pr.merged_at": filter.MergedTo.UnixMicro()})
+	}
+	if filter.FewerReviewersThan > 0 {
+		builder = builder.Where(
+			"(SELECT COUNT(*) FROM pull_request_reviewer rc WHERE rc.pull_request_id = pr.id) < ?",
+			filter.FewerReviewersThan,
+		)
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "pr.created_at", "pr.id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listPullRequests: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listPullRequests: %w", err)
+	}
+
+	return collectPullRequests(rows)
+}
+
+// prColumns — колонки для scanPullRequest; rt — LEFT JOIN команды ревью.
+var prColumns = []string{
+	"pr.id",
+	"pr.author_id",
+	"pr.name",
+	"COALESCE(pr.team_id, '')",
+	"COALESCE(rt.name, '')",
+	"pr.status",
+	"pr.created_at",
+	"pr.merged_at",
+}
+
+func scanPullRequest(row scanner) (*domain.PullRequest, error) {
+	var (
+		pr      domain.PullRequest
+		created int64
+		merged  sql.NullInt64
+	)
+	err := row.Scan(
+		&pr.Id,
+		&pr.AuthorId,
+		&pr.Name,
+		&pr.TeamId,
+		&pr.TeamName,
+		&pr.Status,
+		&created,
+		&merged,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pr.CreatedAt = fromMicro(created)
+	pr.MergedAt = fromNullMicro(merged)
+
+	return &pr, nil
+}
+
+func collectPullRequests(rows *sql.Rows) ([]*domain.PullRequest, error) {
+	defer rows.Close()
+
+	var res []*domain.PullRequest
+
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan pull_request: %w", err)
+		}
+		res = append(res, pr)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,169 @@
+// Package sqlite — хранилище в одном файле SQLite для локального запуска и
+// небольших установок. Схема повторяет Postgres, время хранится в микросекундах Unix.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/golang-migrate/migrate/v4"
+	migratesqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var (
+	_ repo.User         = &UserRepo{}
+	_ repo.Team         = &TeamRepo{}
+	_ repo.PullRequest  = &PullRequestRepo{}
+	_ repo.Identity     = &IdentityRepo{}
+	_ repo.Subscription = &SubscriptionRepo{}
+	_ repo.Notification = &NotificationRepo{}
+	_ repo.Outbox       = &OutboxRepo{}
+	_ repo.Stats        = &StatsRepo{}
+	_ repo.Audit        = &AuditRepo{}
+	_ repo.Factory      = &Factory{}
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Open открывает базу по пути path и применяет миграции. Соединение одно:
+// SQLite сериализует запись, а база ":memory:" живёт только в своём соединении.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping sqlite: %w", err)
+	}
+
+	if err := migrateUp(db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateUp(db *sql.DB) error {
+	src, err := iofs.New(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("open migrations: %w", err)
+	}
+
+	driver, err := migratesqlite.WithInstance(db, &migratesqlite.Config{})
+	if err != nil {
+		return fmt.Errorf("migrate driver: %w", err)
+	}
+
+	// m.Close не вызываем: драйвер закрыл бы и переданное соединение
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		return fmt.Errorf("migrate init: %w", err)
+	}
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("migrate up: %w", err)
+	}
+
+	return nil
+}
+
+// Factory создаёт репозитории поверх одного соединения.
+type Factory struct {
+	db *sql.DB
+}
+
+func NewFactory(db *sql.DB) *Factory {
+	return &Factory{db: db}
+}
+
+func (f *Factory) User() repo.User                 { return NewUserRepo(f.db) }
+func (f *Factory) Team() repo.Team                 { return NewTeamRepo(f.db) }
+func (f *Factory) PullRequest() repo.PullRequest   { return NewPullRequestRepo(f.db) }
+func (f *Factory) Identity() repo.Identity         { return NewIdentityRepo(f.db) }
+func (f *Factory) Subscription() repo.Subscription { return NewSubscriptionRepo(f.db) }
+func (f *Factory) Notification() repo.Notification { return NewNotificationRepo(f.db) }
+func (f *Factory) Audit() repo.Audit               { return NewAuditRepo(f.db) }
+func (f *Factory) Outbox() repo.Outbox             { return NewOutboxRepo(f.db) }
+func (f *Factory) Stats() repo.Stats               { return NewStatsRepo(f.db) }
+func (f *Factory) Transactor() repo.Transactor     { return NewTransactor(f.db) }
+
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// now — текущее время с точностью хранения; значение в базе и в ответе совпадают.
+func now() time.Time {
+	return time.UnixMicro(time.Now().UnixMicro())
+}
+
+func fromMicro(v int64) time.Time {
+	return time.UnixMicro(v)
+}
+
+func fromNullMicro(v sql.NullInt64) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t := fromMicro(v.Int64)
+	return &t
+}
+
+func toNullMicro(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	v := t.UnixMicro()
+	return &v
+}
+
+// keyset добавляет к запросу сортировку по (createdCol, idCol) и условие
+// «строго после курсора» в выбранном направлении.
+func keyset(b sq.SelectBuilder, order domain.SortOrder, after *domain.Cursor, createdCol, idCol string) sq.SelectBuilder {
+	dir, cmp := "ASC", ">"
+	if order == domain.SortNewest {
+		dir, cmp = "DESC", "<"
+	}
+
+	if after != nil {
+		b = b.Where(
+			"("+createdCol+", "+idCol+") "+cmp+" (?, ?)",
+			after.CreatedAt.UnixMicro(), after.Id,
+		)
+	}
+
+	return b.OrderBy(createdCol+" "+dir, idCol+" "+dir)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// likeCI — регистронезависимый LIKE с экранированием, аналог ILIKE в Postgres.
+func likeCI(col, pattern string) sq.Sqlizer {
+	return sq.Expr("lower("+col+`) LIKE ? ESCAPE '\'`, strings.ToLower(pattern))
+}
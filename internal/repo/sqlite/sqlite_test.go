@@ -0,0 +1,78 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/repo/sqlite"
+	"gopr/internal/repo/testhelpers"
+)
+
+func TestConformance(t *testing.T) {
+	testhelpers.RunConformance(t, func(t *testing.T) testhelpers.Repos {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "gopr.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		return testhelpers.Repos{
+			User:         sqlite.NewUserRepo(db),
+			Team:         sqlite.NewTeamRepo(db),
+			PullRequest:  sqlite.NewPullRequestRepo(db),
+			Identity:     sqlite.NewIdentityRepo(db),
+			Subscription: sqlite.NewSubscriptionRepo(db),
+			Notification: sqlite.NewNotificationRepo(db),
+			Outbox:       sqlite.NewOutboxRepo(db),
+			Audit:        sqlite.NewAuditRepo(db),
+			Transactor:   sqlite.NewTransactor(db),
+		}
+	})
+}
+
+func TestTransactor_Rollback(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "gopr.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	teams, outbox := sqlite.NewTeamRepo(db), sqlite.NewOutboxRepo(db)
+	errAbort := errors.New("abort")
+
+	err = sqlite.NewTransactor(db).InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, teams.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+		require.NoError(t, outbox.Add(ctx, &domain.OutboxMessage{PullRequestId: "pr-1", EventType: domain.EventPullRequestMerged, Payload: []byte(`{}`)}))
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+
+	_, err = teams.GetByID(ctx, "t1")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+}
+
+func TestAudit_AppendOnly(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "gopr.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	audit := sqlite.NewAuditRepo(db)
+	require.NoError(t, audit.Append(ctx, &domain.AuditRecord{
+		Id: "a1", Actor: "alice", Action: domain.AuditUserSetActive, TargetType: domain.AuditTargetUser, TargetId: "u1",
+	}))
+
+	_, err = db.ExecContext(ctx, `UPDATE audit_log SET actor = 'mallory' WHERE id = 'a1'`)
+	require.ErrorContains(t, err, "audit_log is append-only")
+
+	list, err := audit.List(ctx, &domain.AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "alice", list[0].Actor)
+}
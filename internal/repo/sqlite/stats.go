@@ -0,0 +1,215 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gopr/internal/domain"
+	"sort"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// StatsRepo считает те же агрегаты, что и Postgres; перцентили времени до
+// мержа SQLite не умеет, их досчитываем в Go по выборке длительностей.
+type StatsRepo struct {
+	db *sql.DB
+}
+
+func NewStatsRepo(db *sql.DB) *StatsRepo {
+	return &StatsRepo{db: db}
+}
+
+func (r *StatsRepo) UserStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.UserStats, error) {
+	prs, args, err := statsPRs(filter, "id", "status").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql userStats: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`WITH prs AS (`+prs+`),
+         cur AS (
+             SELECT rr.reviewer_id AS user_id,
+                    COUNT(*) FILTER (WHERE prs.status = 'OPEN')   AS open,
+                    COUNT(*) FILTER (WHERE prs.status = 'MERGED') AS merged
+             FROM prs
+             JOIN pull_request_reviewer AS rr ON rr.pull_request_id = prs.id
+             GROUP BY rr.reviewer_id
+         ),
+         hist AS (
+             SELECT h.reviewer_id AS user_id,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_ASSIGNED') AS assigned,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_REMOVED')  AS removed
+             FROM prs
+             JOIN pull_request_history AS h ON h.pull_request_id = prs.id
+             WHERE h.reviewer_id IS NOT NULL
+             GROUP BY h.reviewer_id
+         )
+         SELECT u.id, u.username,
+                COALESCE(hist.assigned, 0), COALESCE(cur.open, 0),
+                COALESCE(cur.merged, 0), COALESCE(hist.removed, 0)
+         FROM users AS u
+         LEFT JOIN cur ON cur.user_id = u.id
+         LEFT JOIN hist ON hist.user_id = u.id
+         WHERE cur.user_id IS NOT NULL OR hist.user_id IS NOT NULL
+         ORDER BY 3 DESC, u.id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query userStats: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.UserStats, 0)
+	for rows.Next() {
+		var s domain.UserStats
+		if err := rows.Scan(&s.UserId, &s.Username, &s.Assigned, &s.Open, &s.Merged, &s.ReassignedAway); err != nil {
+			return nil, fmt.Errorf("scan userStats: %w", err)
+		}
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *StatsRepo) TeamStats(ctx context.Context, filter *domain.StatsFilter) ([]*domain.TeamStats, error) {
+	prs, args, err := statsPRs(filter, "id", "team_id", "status").
+		Where("team_id IS NOT NULL").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql teamStats: %w", err)
+	}
+
+	query := `WITH prs AS (` + prs + `),
+         agg AS (
+             SELECT team_id,
+                    COUNT(*)                                 AS total,
+                    COUNT(*) FILTER (WHERE status = 'OPEN')   AS open,
+                    COUNT(*) FILTER (WHERE status = 'MERGED') AS merged
+             FROM prs
+             GROUP BY team_id
+         ),
+         hist AS (
+             SELECT prs.team_id,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_ASSIGNED') AS assigned,
+                    COUNT(*) FILTER (WHERE h.type = 'REVIEWER_REMOVED')  AS removed
+             FROM prs
+             JOIN pull_request_history AS h ON h.pull_request_id = prs.id
+             GROUP BY prs.team_id
+         )
+         SELECT t.id, t.name, COALESCE(t.parent_id, ''),
+                COALESCE(agg.total, 0), COALESCE(agg.open, 0), COALESCE(agg.merged, 0),
+                COALESCE(hist.assigned, 0), COALESCE(hist.removed, 0)
+         FROM team AS t
+         LEFT JOIN agg ON agg.team_id = t.id
+         LEFT JOIN hist ON hist.team_id = t.id`
+
+	if filter.TeamId != "" {
+		query += ` WHERE t.id = ?`
+		args = append(args, filter.TeamId)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query+` ORDER BY t.name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query teamStats: %w", err)
+	}
+	defer rows.Close()
+
+	res := make([]*domain.TeamStats, 0)
+	for rows.Next() {
+		var (
+			t domain.Team
+			s domain.TeamStats
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &s.PullRequests, &s.Open, &s.Merged, &s.Assignments, &s.Reassignments); err != nil {
+			return nil, fmt.Errorf("scan teamStats: %w", err)
+		}
+		s.Team = &t
+		res = append(res, &s)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *StatsRepo) MergeTime(ctx context.Context, filter *domain.StatsFilter) (*domain.MergeTimeStats, error) {
+	query, args, err := statsPRs(filter, "merged_at - created_at").
+		Where("merged_at IS NOT NULL").
+		OrderBy("1").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql mergeTime: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query mergeTime: %w", err)
+	}
+	defer rows.Close()
+
+	var secs []float64
+	for rows.Next() {
+		var micros int64
+		if err := rows.Scan(&micros); err != nil {
+			return nil, fmt.Errorf("scan mergeTime: %w", err)
+		}
+		secs = append(secs, float64(micros)/1e6)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	res := &domain.MergeTimeStats{Merged: len(secs)}
+	if len(secs) == 0 {
+		return res, nil
+	}
+
+	sort.Float64s(secs)
+
+	sum := 0.0
+	for _, s := range secs {
+		sum += s
+	}
+
+	res.Avg = seconds(sum / float64(len(secs)))
+	res.P50 = seconds(percentile(secs, 0.5))
+	res.P90 = seconds(percentile(secs, 0.9))
+	res.P95 = seconds(percentile(secs, 0.95))
+	res.P99 = seconds(percentile(secs, 0.99))
+
+	return res, nil
+}
+
+// statsPRs — выборка PR фильтра, встраиваемая в CTE остального запроса.
+func statsPRs(filter *domain.StatsFilter, columns ...string) sq.SelectBuilder {
+	builder := sq.Select(columns...).
+		From("pull_requests").
+		Where(sq.GtOrEq{"created_at": filter.From.UnixMicro()}).
+		Where(sq.Lt{"created_at": filter.To.UnixMicro()})
+
+	if filter.TeamId != "" {
+		builder = builder.Where(sq.Eq{"team_id": filter.TeamId})
+	}
+
+	return builder
+}
+
+// percentile — линейная интерполяция по отсортированной выборке, как percentile_cont.
+func percentile(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	lo := int(pos)
+	if lo+1 >= len(sorted) {
+		return sorted[lo]
+	}
+	return sorted[lo] + (pos-float64(lo))*(sorted[lo+1]-sorted[lo])
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
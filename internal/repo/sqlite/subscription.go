@@ -0,0 +1,317 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type SubscriptionRepo struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepo(db *sql.DB) *SubscriptionRepo {
+	return &SubscriptionRepo{db: db}
+}
+
+func (r *SubscriptionRepo) Create(ctx context.Context, sub *domain.Subscription) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO webhook_subscription(id, url, events, secret, created_at)
+         VALUES (?, ?, ?, ?, ?)`,
+		sub.Id,
+		sub.URL,
+		joinEvents(sub.Events),
+		sub.Secret,
+		ts.UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert webhook_subscription: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert webhook_subscription: %w", err)
+	}
+
+	sub.CreatedAt = ts
+	return nil
+}
+
+func (r *SubscriptionRepo) GetByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, url, events, secret, created_at
+         FROM webhook_subscription
+         WHERE id = ?`,
+		id,
+	)
+
+	sub, err := scanSubscription(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepo) List(ctx context.Context) ([]*domain.Subscription, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT id, url, events, secret, created_at
+         FROM webhook_subscription
+         ORDER BY created_at, id`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query webhook_subscription: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_subscription: %w", err)
+		}
+		res = append(res, sub)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *SubscriptionRepo) Delete(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM webhook_subscription WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete webhook_subscription: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *SubscriptionRepo) CreateDelivery(ctx context.Context, d *domain.Delivery) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO webhook_delivery(id, subscription_id, event_id, event_type, payload, status,
+                                      attempts, response_code, last_error, next_attempt_at, created_at, updated_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		d.Id,
+		d.SubscriptionId,
+		d.EventId,
+		d.EventType,
+		string(d.Payload),
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		toNullMicro(d.NextAttemptAt),
+		ts.UnixMicro(),
+		ts.UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert webhook_delivery: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert webhook_delivery: %w", err)
+	}
+
+	d.CreatedAt, d.UpdatedAt = ts, ts
+	return nil
+}
+
+func (r *SubscriptionRepo) UpdateDelivery(ctx context.Context, d *domain.Delivery) error {
+	ts := now()
+
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE webhook_delivery
+         SET status = ?, attempts = ?, response_code = ?, last_error = ?, next_attempt_at = ?, updated_at = ?
+         WHERE id = ?`,
+		d.Status,
+		d.Attempts,
+		d.ResponseCode,
+		d.LastError,
+		toNullMicro(d.NextAttemptAt),
+		ts.UnixMicro(),
+		d.Id,
+	)
+	if err != nil {
+		return fmt.Errorf("update webhook_delivery: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+
+	d.UpdatedAt = ts
+	return nil
+}
+
+func (r *SubscriptionRepo) GetDelivery(ctx context.Context, id string) (*domain.Delivery, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, subscription_id, event_id, event_type, payload, status,
+                attempts, response_code, last_error, next_attempt_at, created_at, updated_at
+         FROM webhook_delivery
+         WHERE id = ?`,
+		id,
+	)
+
+	d, err := scanDelivery(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select webhook_delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *SubscriptionRepo) ListDeliveries(ctx context.Context, filter *domain.DeliveryFilter) ([]*domain.Delivery, error) {
+	builder := sq.
+		Select(
+			"id", "subscription_id", "event_id", "event_type", "payload", "status",
+			"attempts", "response_code", "last_error", "next_attempt_at", "created_at", "updated_at",
+		).
+		From("webhook_delivery").
+		Where(sq.Eq{"subscription_id": filter.SubscriptionId})
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"status": filter.Status})
+	}
+
+	builder = keyset(builder, filter.Order, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listDeliveries: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query listDeliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_delivery: %w", err)
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+// ClaimDeliveries выполняется одним запросом: запись в SQLite и так идёт по одной.
+func (r *SubscriptionRepo) ClaimDeliveries(ctx context.Context, now, until time.Time, limit int) ([]*domain.Delivery, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`UPDATE webhook_delivery
+         SET next_attempt_at = ?
+         WHERE id IN (SELECT id
+                      FROM webhook_delivery
+                      WHERE status = 'PENDING' AND next_attempt_at <= ?
+                      ORDER BY next_attempt_at, id
+                      LIMIT ?)
+         RETURNING id, subscription_id, event_id, event_type, payload, status,
+                   attempts, response_code, last_error, next_attempt_at, created_at, updated_at`,
+		until.UnixMicro(),
+		now.UnixMicro(),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook_delivery: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook_delivery: %w", err)
+		}
+		res = append(res, d)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func scanSubscription(row scanner) (*domain.Subscription, error) {
+	var (
+		sub     domain.Subscription
+		events  string
+		created int64
+	)
+	if err := row.Scan(&sub.Id, &sub.URL, &events, &sub.Secret, &created); err != nil {
+		return nil, err
+	}
+	sub.Events = splitEvents(events)
+	sub.CreatedAt = fromMicro(created)
+
+	return &sub, nil
+}
+
+func scanDelivery(row scanner) (*domain.Delivery, error) {
+	var (
+		d                domain.Delivery
+		payload          string
+		next             sql.NullInt64
+		created, updated int64
+	)
+	err := row.Scan(
+		&d.Id, &d.SubscriptionId, &d.EventId, &d.EventType, &payload, &d.Status,
+		&d.Attempts, &d.ResponseCode, &d.LastError, &next, &created, &updated,
+	)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = []byte(payload)
+	d.NextAttemptAt = fromNullMicro(next)
+	d.CreatedAt = fromMicro(created)
+	d.UpdatedAt = fromMicro(updated)
+
+	return &d, nil
+}
+
+func joinEvents(events []domain.EventType) string {
+	parts := make([]string, 0, len(events))
+	for _, e := range events {
+		parts = append(parts, string(e))
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitEvents(s string) []domain.EventType {
+	if s == "" {
+		return nil
+	}
+
+	var res []domain.EventType
+	for _, e := range strings.Split(s, ",") {
+		res = append(res, domain.EventType(e))
+	}
+	return res
+}
@@ -0,0 +1,400 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type TeamRepo struct {
+	db *sql.DB
+}
+
+func NewTeamRepo(db *sql.DB) *TeamRepo {
+	return &TeamRepo{db: db}
+}
+
+func (r *TeamRepo) Create(ctx context.Context, team *domain.Team) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO team(id, name, parent_id)
+         VALUES (?, ?, NULLIF(?, ''))`,
+		team.Id,
+		team.Name,
+		team.ParentId,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert team: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert team: %w", err)
+	}
+	return nil
+}
+
+func (r *TeamRepo) GetByID(ctx context.Context, id string) (*domain.Team, error) {
+	var t domain.Team
+
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, name, COALESCE(parent_id, '')
+         FROM team
+         WHERE id = ?`,
+		id,
+	).Scan(&t.Id, &t.Name, &t.ParentId)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select team by id: %w", err)
+	}
+
+	return &t, nil
+}
+
+func (r *TeamRepo) GetByName(ctx context.Context, name string) (*domain.Team, error) {
+	var t domain.Team
+
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, name, COALESCE(parent_id, '')
+         FROM team
+         WHERE name = ?`,
+		name,
+	).Scan(&t.Id, &t.Name, &t.ParentId)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select team by name: %w", err)
+	}
+
+	return &t, nil
+}
+
+func (r *TeamRepo) List(ctx context.Context) ([]*domain.TeamSummary, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.id, t.name, COALESCE(t.parent_id, ''), COALESCE(p.name, ''),
+                COUNT(u.id),
+                COUNT(u.id) FILTER (WHERE u.is_active)
+         FROM team AS t
+         LEFT JOIN team AS p ON p.id = t.parent_id
+         LEFT JOIN team_membership AS m ON m.team_id = t.id
+         LEFT JOIN users AS u ON u.id = m.user_id
+         GROUP BY t.id, t.name, p.name
+         ORDER BY t.name`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.TeamSummary
+	for rows.Next() {
+		var (
+			t       domain.Team
+			summary domain.TeamSummary
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &summary.ParentName, &summary.MembersCount, &summary.ActiveCount); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		summary.Team = &t
+		res = append(res, &summary)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) Rename(ctx context.Context, id, name string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE team
+         SET name = ?
+         WHERE id = ?`,
+		name,
+		id,
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("rename team: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("rename team: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) SetParent(ctx context.Context, id, parentID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE team
+         SET parent_id = NULLIF(?, '')
+         WHERE id = ?`,
+		parentID,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update team parent: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+// ListAncestors возвращает предков команды, начиная с непосредственного родителя.
+func (r *TeamRepo) ListAncestors(ctx context.Context, id string) ([]*domain.Team, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`WITH RECURSIVE anc AS (
+             SELECT p.id, p.name, COALESCE(p.parent_id, '') AS parent_id, 1 AS depth
+             FROM team AS c
+             JOIN team AS p ON p.id = c.parent_id
+             WHERE c.id = ?
+             UNION ALL
+             SELECT p.id, p.name, COALESCE(p.parent_id, ''), anc.depth + 1
+             FROM team AS p
+             JOIN anc ON p.id = anc.parent_id
+         )
+         SELECT id, name, parent_id
+         FROM anc
+         ORDER BY depth`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team ancestors: %w", err)
+	}
+
+	return collectTeams(rows)
+}
+
+// ListSubtree возвращает команду и всех её потомков по уровням, корень первым.
+func (r *TeamRepo) ListSubtree(ctx context.Context, id string) ([]*domain.Team, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`WITH RECURSIVE sub AS (
+             SELECT id, name, COALESCE(parent_id, '') AS parent_id, 0 AS depth
+             FROM team
+             WHERE id = ?
+             UNION ALL
+             SELECT t.id, t.name, t.parent_id, sub.depth + 1
+             FROM team AS t
+             JOIN sub ON t.parent_id = sub.id
+         )
+         SELECT id, name, parent_id
+         FROM sub
+         ORDER BY depth, name`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team subtree: %w", err)
+	}
+
+	return collectTeams(rows)
+}
+
+func (r *TeamRepo) Delete(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM team
+         WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete team: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) AddMember(ctx context.Context, m *domain.Membership) error {
+	ts := now()
+
+	_, err := conn(ctx, r.db).ExecContext(ctx,
+		`INSERT INTO team_membership(team_id, user_id, role, created_at)
+         VALUES (?, ?, ?, ?)`,
+		m.TeamId,
+		m.UserId,
+		m.Role,
+		ts.UnixMicro(),
+	)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("insert team_membership: %w", repo.ErrAlreadyExists)
+	}
+	if err != nil {
+		return fmt.Errorf("insert team_membership: %w", err)
+	}
+
+	m.CreatedAt = ts
+	return nil
+}
+
+func (r *TeamRepo) UpdateMemberRole(ctx context.Context, teamID, userID string, role domain.MembershipRole) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE team_membership
+         SET role = ?
+         WHERE team_id = ? AND user_id = ?`,
+		role,
+		teamID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("update team_membership: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) RemoveMember(ctx context.Context, teamID, userID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`DELETE FROM team_membership
+         WHERE team_id = ? AND user_id = ?`,
+		teamID,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete team_membership: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TeamRepo) ListMembers(ctx context.Context, teamID string) ([]*domain.TeamMember, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT u.id, u.username, COALESCE(u.team_id, ''), u.is_active, u.created_at, u.updated_at, m.role, m.created_at
+         FROM team_membership AS m
+         JOIN users AS u ON u.id = m.user_id
+         WHERE m.team_id = ?
+         ORDER BY m.created_at, u.id`,
+		teamID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query team members: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.TeamMember
+	for rows.Next() {
+		var (
+			u                          domain.User
+			m                          domain.TeamMember
+			created, updatedAt, joined int64
+		)
+		if err := rows.Scan(&u.Id, &u.Username, &u.TeamId, &u.IsActive, &created, &updatedAt, &m.Role, &joined); err != nil {
+			return nil, fmt.Errorf("scan team member: %w", err)
+		}
+		u.CreatedAt = fromMicro(created)
+		u.UpdatedAt = fromMicro(updatedAt)
+		m.JoinedAt = fromMicro(joined)
+		m.User = &u
+		res = append(res, &m)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUser(ctx context.Context, userID string) ([]*domain.UserTeam, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT t.id, t.name, COALESCE(t.parent_id, ''), m.role
+         FROM team_membership AS m
+         JOIN team AS t ON t.id = m.team_id
+         WHERE m.user_id = ?
+         ORDER BY m.created_at, t.name`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user teams: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.UserTeam
+	for rows.Next() {
+		var (
+			t  domain.Team
+			ut domain.UserTeam
+		)
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId, &ut.Role); err != nil {
+			return nil, fmt.Errorf("scan user team: %w", err)
+		}
+		ut.Team = &t
+		res = append(res, &ut)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func (r *TeamRepo) ListByUsers(ctx context.Context, userIDs []string) (map[string][]*domain.UserTeam, error) {
+	res := make(map[string][]*domain.UserTeam, len(userIDs))
+	if len(userIDs) == 0 {
+		return res, nil
+	}
+
+	query, args, err := sq.
+		Select("m.user_id", "t.id", "t.name", "COALESCE(t.parent_id, '')", "m.role").
+		From("team_membership AS m").
+		Join("team AS t ON t.id = m.team_id").
+		Where(sq.Eq{"m.user_id": userIDs}).
+		OrderBy("m.user_id", "m.created_at", "t.name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql listByUsers: %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users teams: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID string
+			t      domain.Team
+			ut     domain.UserTeam
+		)
+		if err := rows.Scan(&userID, &t.Id, &t.Name, &t.ParentId, &ut.Role); err != nil {
+			return nil, fmt.Errorf("scan user team: %w", err)
+		}
+		ut.Team = &t
+		res[userID] = append(res[userID], &ut)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
+
+func collectTeams(rows *sql.Rows) ([]*domain.Team, error) {
+	defer rows.Close()
+
+	var res []*domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.Id, &t.Name, &t.ParentId); err != nil {
+			return nil, fmt.Errorf("scan team: %w", err)
+		}
+		res = append(res, &t)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
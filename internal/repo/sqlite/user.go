@@ -0,0 +1,292 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type UserRepo struct {
+	db *sql.DB
+}
+
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+// Create добавляет пользователя; если задана основная команда, он сразу
+// становится её участником с ролью member.
+func (r *UserRepo) Create(ctx context.Context, user *domain.User) error {
+	return NewTransactor(r.db).InTx(ctx, func(ctx context.Context) error {
+		ts := now().UnixMicro()
+
+		_, err := conn(ctx, r.db).ExecContext(ctx,
+			`INSERT INTO users(id, username, team_id, is_active, created_at, updated_at)
+             VALUES (?, ?, NULLIF(?, ''), ?, ?, ?)`,
+			user.Id,
+			user.Username,
+			user.TeamId,
+			user.IsActive,
+			ts,
+			ts,
+		)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("insert user: %w", repo.ErrAlreadyExists)
+		}
+		if err != nil {
+			return fmt.Errorf("insert user: %w", err)
+		}
+
+		if user.TeamId != "" {
+			_, err = conn(ctx, r.db).ExecContext(ctx,
+				`INSERT INTO team_membership(team_id, user_id, role, created_at)
+                 VALUES (?, ?, 'member', ?)`,
+				user.TeamId,
+				user.Id,
+				ts,
+			)
+			if err != nil {
+				return fmt.Errorf("insert team_membership: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *UserRepo) GetByID(ctx context.Context, id string) (*domain.User, error) {
+	row := conn(ctx, r.db).QueryRowContext(ctx,
+		`SELECT id, username, COALESCE(team_id, ''), is_active, created_at, updated_at
+         FROM users
+         WHERE id = ?`,
+		id,
+	)
+
+	u, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, repo.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("select user: %w", err)
+	}
+
+	return u, nil
+}
+
+func (r *UserRepo) UpdateIsActive(ctx context.Context, id string, isActive bool) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE users
+         SET is_active = ?, updated_at = ?
+         WHERE id = ?`,
+		isActive,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdateTeam(ctx context.Context, id, teamID string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE users
+         SET team_id = NULLIF(?, ''), updated_at = ?
+         WHERE id = ?`,
+		teamID,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update user team: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdateUsername(ctx context.Context, id, username string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx,
+		`UPDATE users
+         SET username = ?, updated_at = ?
+         WHERE id = ?`,
+		username,
+		now().UnixMicro(),
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("update username: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) ListByTeam(ctx context.Context, teamID string, onlyActive bool) ([]*domain.User, error) {
+	builder := sq.
+		Select("u.id", "u.username", "COALESCE(u.team_id, '')", "u.is_active", "u.created_at", "u.updated_at").
+		From("users AS u").
+		Join("team_membership AS m ON m.user_id = u.id").
+		Where(sq.Eq{"m.team_id": teamID}).
+		OrderBy("m.created_at", "u.id")
+
+	if onlyActive {
+		builder = builder.Where(sq.Eq{"u.is_active": true})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql (list users by team): %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users by team: %w", err)
+	}
+
+	return collectUsers(rows)
+}
+
+func (r *UserRepo) List(ctx context.Context, filter *domain.UserFilter) ([]*domain.User, error) {
+	builder := sq.
+		Select("id", "username", "COALESCE(team_id, '')", "is_active", "created_at", "updated_at").
+		From("users")
+
+	if filter.UsernamePrefix != "" {
+		builder = builder.Where(likeCI("username", escapeLike(filter.UsernamePrefix)+"%"))
+	}
+	if filter.TeamId != "" {
+		builder = builder.Where(
+			"EXISTS (SELECT 1 FROM team_membership m WHERE m.user_id = users.id AND m.team_id = ?)",
+			filter.TeamId,
+		)
+	}
+
+	builder = keyset(builder, domain.SortOldest, filter.After, "created_at", "id")
+
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("build sql (list users): %w", err)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query users: %w", err)
+	}
+
+	return collectUsers(rows)
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var (
+		u                  domain.User
+		created, updatedAt int64
+	)
+	if err := row.Scan(&u.Id, &u.Username, &u.TeamId, &u.IsActive, &created, &updatedAt); err != nil {
+		return nil, err
+	}
+	u.CreatedAt = fromMicro(created)
+	u.UpdatedAt = fromMicro(updatedAt)
+
+	return &u, nil
+}
+
+func collectUsers(rows *sql.Rows) ([]*domain.User, error) {
+	defer rows.Close()
+
+	var result []*domain.User
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		result = append(result, u)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return result, nil
+}
+
+func (r *UserRepo) SetAway(ctx context.Context, away *domain.Away) error {
+	var created int64
+	err := conn(ctx, r.db).QueryRowContext(ctx,
+		`INSERT INTO user_away(user_id, until, created_at)
+         VALUES (?, ?, ?)
+         ON CONFLICT (user_id) DO UPDATE SET until = excluded.until
+         RETURNING created_at`,
+		away.UserId,
+		away.Until.UnixMicro(),
+		now().UnixMicro(),
+	).Scan(&created)
+	if err != nil {
+		return fmt.Errorf("upsert user_away: %w", err)
+	}
+
+	away.CreatedAt = fromMicro(created)
+	return nil
+}
+
+func (r *UserRepo) DeleteAway(ctx context.Context, id string) error {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM user_away WHERE user_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete user_away: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return repo.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) ListReturning(ctx context.Context, now time.Time) ([]*domain.Away, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx,
+		`SELECT user_id, until, created_at
+         FROM user_away
+         WHERE until <= ?
+         ORDER BY until, user_id`,
+		now.UnixMicro(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query user_away: %w", err)
+	}
+	defer rows.Close()
+
+	var res []*domain.Away
+	for rows.Next() {
+		var (
+			a              domain.Away
+			until, created int64
+		)
+		if err := rows.Scan(&a.UserId, &until, &created); err != nil {
+			return nil, fmt.Errorf("scan user_away: %w", err)
+		}
+		a.Until, a.CreatedAt = fromMicro(until), fromMicro(created)
+		res = append(res, &a)
+	}
+	if rows.Err() != nil {
+		return nil, fmt.Errorf("rows error: %w", rows.Err())
+	}
+
+	return res, nil
+}
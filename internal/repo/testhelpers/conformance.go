@@ -0,0 +1,1118 @@
+package testhelpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+// Repos — репозитории одного бэкенда.
+type Repos struct {
+	User         repo.User
+	Team         repo.Team
+	PullRequest  repo.PullRequest
+	Identity     repo.Identity
+	Subscription repo.Subscription
+	Notification repo.Notification
+	Outbox       repo.Outbox
+	Audit        repo.Audit
+	Transactor   repo.Transactor
+}
+
+// RunConformance проверяет, что бэкенд ведёт себя как repo/pg: ошибки
+// ErrNotFound и ErrAlreadyExists, фильтры, порядок выдачи и пагинация.
+// newRepos вызывается в каждом подтесте и должен возвращать пустое хранилище.
+func RunConformance(t *testing.T, newRepos func(t *testing.T) Repos) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, newRepos(t)) })
+	t.Run("Away", func(t *testing.T) { testAway(t, newRepos(t)) })
+	t.Run("Teams", func(t *testing.T) { testTeams(t, newRepos(t)) })
+	t.Run("TeamHierarchy", func(t *testing.T) { testTeamHierarchy(t, newRepos(t)) })
+	t.Run("TeamDelete", func(t *testing.T) { testTeamDelete(t, newRepos(t)) })
+	t.Run("PullRequests", func(t *testing.T) { testPullRequests(t, newRepos(t)) })
+	t.Run("PullRequestList", func(t *testing.T) { testPullRequestList(t, newRepos(t)) })
+	t.Run("Identities", func(t *testing.T) { testIdentities(t, newRepos(t)) })
+	t.Run("Subscriptions", func(t *testing.T) { testSubscriptions(t, newRepos(t)) })
+	t.Run("DeliveryClaims", func(t *testing.T) { testDeliveryClaims(t, newRepos(t)) })
+	t.Run("Notifications", func(t *testing.T) { testNotifications(t, newRepos(t)) })
+	t.Run("Digests", func(t *testing.T) { testDigests(t, newRepos(t)) })
+	t.Run("Outbox", func(t *testing.T) { testOutbox(t, newRepos(t)) })
+	t.Run("Audit", func(t *testing.T) { testAudit(t, newRepos(t)) })
+	t.Run("Transactions", func(t *testing.T) { testTransactions(t, newRepos(t)) })
+	t.Run("Concurrent", func(t *testing.T) { testConcurrent(t, newRepos(t)) })
+}
+
+func testUsers(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	team := &domain.Team{Id: "t1", Name: "backend"}
+	require.NoError(t, r.Team.Create(ctx, team))
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "Alice", TeamId: team.Id, IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "al_bert", TeamId: team.Id, IsActive: false}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u3", Username: "bob", IsActive: true}))
+
+	err := r.User.Create(ctx, &domain.User{Id: "u1", Username: "dup"})
+	require.ErrorIs(t, err, repo.ErrAlreadyExists)
+
+	_, err = r.User.GetByID(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	require.ErrorIs(t, r.User.UpdateIsActive(ctx, "missing", true), repo.ErrNotFound)
+	require.ErrorIs(t, r.User.UpdateTeam(ctx, "missing", ""), repo.ErrNotFound)
+	require.ErrorIs(t, r.User.UpdateUsername(ctx, "missing", "x"), repo.ErrNotFound)
+
+	u, err := r.User.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	require.Equal(t, "Alice", u.Username)
+	require.Equal(t, team.Id, u.TeamId)
+	require.True(t, u.IsActive)
+	require.False(t, u.CreatedAt.IsZero())
+
+	members, err := r.Team.ListMembers(ctx, team.Id)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	require.Equal(t, domain.RoleMember, members[0].Role)
+
+	active, err := r.User.ListByTeam(ctx, team.Id, true)
+	require.NoError(t, err)
+	require.Equal(t, []string{"u1"}, userIDs(active))
+
+	all, err := r.User.ListByTeam(ctx, team.Id, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u1", "u2"}, userIDs(all))
+
+	require.NoError(t, r.User.UpdateIsActive(ctx, "u2", true))
+	require.NoError(t, r.User.UpdateUsername(ctx, "u2", "albert"))
+	require.NoError(t, r.User.UpdateTeam(ctx, "u3", team.Id))
+
+	u, err = r.User.GetByID(ctx, "u2")
+	require.NoError(t, err)
+	require.True(t, u.IsActive)
+	require.Equal(t, "albert", u.Username)
+
+	// UpdateTeam меняет только основную команду, членство не появляется
+	moved, err := r.User.List(ctx, &domain.UserFilter{TeamId: team.Id})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u1", "u2"}, userIDs(moved))
+
+	byPrefix, err := r.User.List(ctx, &domain.UserFilter{UsernamePrefix: "AL"})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u1", "u2"}, userIDs(byPrefix))
+
+	// спецсимволы LIKE экранируются
+	none, err := r.User.List(ctx, &domain.UserFilter{UsernamePrefix: "a_"})
+	require.NoError(t, err)
+	require.Empty(t, none)
+
+	first, err := r.User.List(ctx, &domain.UserFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+
+	last := first[len(first)-1]
+	rest, err := r.User.List(ctx, &domain.UserFilter{After: &domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.NotContains(t, userIDs(first), rest[0].Id)
+}
+
+func testAway(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u3", Username: "carol"}))
+
+	day := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	require.Error(t, r.User.SetAway(ctx, &domain.Away{UserId: "missing", Until: day}))
+
+	alice := &domain.Away{UserId: "u1", Until: day.AddDate(0, 0, 7)}
+	require.NoError(t, r.User.SetAway(ctx, alice))
+	require.False(t, alice.CreatedAt.IsZero())
+	require.NoError(t, r.User.SetAway(ctx, &domain.Away{UserId: "u2", Until: day.AddDate(0, 0, 1)}))
+	require.NoError(t, r.User.SetAway(ctx, &domain.Away{UserId: "u3", Until: day.AddDate(0, 0, 30)}))
+	// перенос возвращения не меняет created_at
+	moved := &domain.Away{UserId: "u1", Until: day}
+	require.NoError(t, r.User.SetAway(ctx, moved))
+	require.True(t, alice.CreatedAt.Equal(moved.CreatedAt))
+
+	returning, err := r.User.ListReturning(ctx, day.Add(-time.Second))
+	require.NoError(t, err)
+	require.Empty(t, returning)
+
+	returning, err = r.User.ListReturning(ctx, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, returning, 2)
+	require.Equal(t, "u1", returning[0].UserId)
+	require.True(t, day.Equal(returning[0].Until))
+	require.Equal(t, "u2", returning[1].UserId)
+
+	require.NoError(t, r.User.DeleteAway(ctx, "u1"))
+	require.ErrorIs(t, r.User.DeleteAway(ctx, "u1"), repo.ErrNotFound)
+
+	returning, err = r.User.ListReturning(ctx, day.AddDate(1, 0, 0))
+	require.NoError(t, err)
+	require.Len(t, returning, 2)
+	require.Equal(t, "u2", returning[0].UserId)
+}
+
+func testTeams(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t2", Name: "api", ParentId: "t1"}))
+
+	require.ErrorIs(t, r.Team.Create(ctx, &domain.Team{Id: "t3", Name: "backend"}), repo.ErrAlreadyExists)
+	require.ErrorIs(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "other"}), repo.ErrAlreadyExists)
+
+	_, err := r.Team.GetByID(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	_, err = r.Team.GetByName(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	api, err := r.Team.GetByName(ctx, "api")
+	require.NoError(t, err)
+	require.Equal(t, "t1", api.ParentId)
+
+	require.ErrorIs(t, r.Team.Rename(ctx, "t2", "backend"), repo.ErrAlreadyExists)
+	require.ErrorIs(t, r.Team.Rename(ctx, "missing", "x"), repo.ErrNotFound)
+	require.NoError(t, r.Team.Rename(ctx, "t2", "gateway"))
+	require.NoError(t, r.Team.Rename(ctx, "t2", "gateway"))
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", TeamId: "t1", IsActive: false}))
+
+	m := &domain.Membership{TeamId: "t2", UserId: "u1", Role: domain.RoleLead}
+	require.NoError(t, r.Team.AddMember(ctx, m))
+	require.False(t, m.CreatedAt.IsZero())
+	require.ErrorIs(t, r.Team.AddMember(ctx, &domain.Membership{TeamId: "t2", UserId: "u1", Role: domain.RoleMember}), repo.ErrAlreadyExists)
+
+	teams, err := r.Team.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, teams, 2)
+	require.Equal(t, "backend", teams[0].Team.Name)
+	require.Equal(t, 2, teams[0].MembersCount)
+	require.Equal(t, 1, teams[0].ActiveCount)
+	require.Equal(t, "gateway", teams[1].Team.Name)
+	require.Equal(t, "backend", teams[1].ParentName)
+
+	userTeams, err := r.Team.ListByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, userTeams, 2)
+	require.Equal(t, "backend", userTeams[0].Team.Name)
+	require.Equal(t, domain.RoleLead, userTeams[1].Role)
+
+	byUsers, err := r.Team.ListByUsers(ctx, []string{"u1", "u2", "missing"})
+	require.NoError(t, err)
+	require.Len(t, byUsers, 2)
+	require.Equal(t, userTeams, byUsers["u1"])
+	require.Len(t, byUsers["u2"], 1)
+	require.Equal(t, "backend", byUsers["u2"][0].Team.Name)
+
+	byUsers, err = r.Team.ListByUsers(ctx, nil)
+	require.NoError(t, err)
+	require.Empty(t, byUsers)
+
+	require.NoError(t, r.Team.UpdateMemberRole(ctx, "t1", "u2", domain.RoleLead))
+	require.ErrorIs(t, r.Team.UpdateMemberRole(ctx, "t2", "u2", domain.RoleLead), repo.ErrNotFound)
+
+	members, err := r.Team.ListMembers(ctx, "t1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u1", "u2"}, memberIDs(members))
+	require.Equal(t, domain.RoleLead, members[1].Role)
+
+	require.NoError(t, r.Team.RemoveMember(ctx, "t2", "u1"))
+	require.ErrorIs(t, r.Team.RemoveMember(ctx, "t2", "u1"), repo.ErrNotFound)
+
+	members, err = r.Team.ListMembers(ctx, "t2")
+	require.NoError(t, err)
+	require.Empty(t, members)
+}
+
+func testTeamHierarchy(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "root", Name: "engineering"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "b", Name: "backend", ParentId: "root"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "a", Name: "apps", ParentId: "root"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "p", Name: "payments", ParentId: "b"}))
+
+	anc, err := r.Team.ListAncestors(ctx, "p")
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "root"}, teamIDs(anc))
+
+	anc, err = r.Team.ListAncestors(ctx, "root")
+	require.NoError(t, err)
+	require.Empty(t, anc)
+
+	sub, err := r.Team.ListSubtree(ctx, "root")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root", "a", "b", "p"}, teamIDs(sub))
+
+	require.NoError(t, r.Team.SetParent(ctx, "p", ""))
+	require.ErrorIs(t, r.Team.SetParent(ctx, "missing", ""), repo.ErrNotFound)
+	require.Error(t, r.Team.SetParent(ctx, "a", "a"))
+
+	sub, err = r.Team.ListSubtree(ctx, "root")
+	require.NoError(t, err)
+	require.Equal(t, []string{"root", "a", "b"}, teamIDs(sub))
+}
+
+func testTeamDelete(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t2", Name: "api", ParentId: "t1"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr1", AuthorId: "u1", Name: "x", TeamId: "t1", Status: string(domain.PullRequestStatusOpen)}))
+
+	require.NoError(t, r.Team.Delete(ctx, "t1"))
+	require.ErrorIs(t, r.Team.Delete(ctx, "t1"), repo.ErrNotFound)
+
+	u, err := r.User.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	require.Empty(t, u.TeamId)
+
+	teams, err := r.Team.ListByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Empty(t, teams)
+
+	child, err := r.Team.GetByID(ctx, "t2")
+	require.NoError(t, err)
+	require.Empty(t, child.ParentId)
+
+	pr, err := r.PullRequest.GetByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Empty(t, pr.TeamId)
+	require.Empty(t, pr.TeamName)
+}
+
+func testPullRequests(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	for _, id := range []string{"u1", "u2", "u3"} {
+		require.NoError(t, r.User.Create(ctx, &domain.User{Id: id, Username: id, TeamId: "t1", IsActive: true}))
+	}
+
+	open := string(domain.PullRequestStatusOpen)
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr1", AuthorId: "u1", Name: "First", TeamId: "t1", Status: open}))
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr2", AuthorId: "u1", Name: "Second", TeamId: "t1", Status: open}))
+	require.ErrorIs(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr1", AuthorId: "u1", Name: "dup", Status: open}), repo.ErrAlreadyExists)
+
+	_, err := r.PullRequest.GetByID(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	pr, err := r.PullRequest.GetByID(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, "backend", pr.TeamName)
+	require.Equal(t, open, pr.Status)
+	require.Nil(t, pr.MergedAt)
+
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr1", "u2"))
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr1", "u3"))
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr2", "u2"))
+	require.ErrorIs(t, r.PullRequest.AddReviewer(ctx, "pr1", "u2"), repo.ErrAlreadyExists)
+
+	revs, err := r.PullRequest.ListReviewers(ctx, "pr1")
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, revs)
+
+	byPRs, err := r.PullRequest.ListReviewersByPRs(ctx, []string{"pr1", "pr2", "missing"})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{"pr1": {"u2", "u3"}, "pr2": {"u2"}}, byPRs)
+
+	assignments, err := r.PullRequest.ListOpenReviewsByTeam(ctx, "t1")
+	require.NoError(t, err)
+	require.Len(t, assignments, 3)
+	require.Equal(t, domain.ReviewAssignment{PullRequestId: "pr1", ReviewerId: "u2"}, *assignments[0])
+
+	ok, err := r.PullRequest.UpdateStatusMerged(ctx, "pr2")
+	require.NoError(t, err)
+	require.True(t, ok)
+	merged, err := r.PullRequest.GetByID(ctx, "pr2")
+	require.NoError(t, err)
+	require.Equal(t, string(domain.PullRequestStatusClosed), merged.Status)
+	require.NotNil(t, merged.MergedAt)
+
+	// повторный merge ничего не меняет и не сдвигает merged_at
+	ok, err = r.PullRequest.UpdateStatusMerged(ctx, "pr2")
+	require.NoError(t, err)
+	require.False(t, ok)
+	again, err := r.PullRequest.GetByID(ctx, "pr2")
+	require.NoError(t, err)
+	require.True(t, merged.MergedAt.Equal(*again.MergedAt))
+	_, err = r.PullRequest.UpdateStatusMerged(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	load, err := r.PullRequest.CountOpenReviews(ctx, []string{"u2", "u3", "u1"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"u2": 1, "u3": 1}, load)
+
+	require.NoError(t, r.PullRequest.RemoveReviewer(ctx, "pr1", "u3"))
+	require.ErrorIs(t, r.PullRequest.RemoveReviewer(ctx, "pr1", "u3"), repo.ErrNotFound)
+
+	for _, typ := range []domain.PullRequestEventType{domain.PullRequestEventCreated, domain.PullRequestEventReviewerAssigned} {
+		ev := &domain.PullRequestEvent{PullRequestId: "pr1", Type: typ, Actor: domain.ActorSystem}
+		require.NoError(t, r.PullRequest.AddEvent(ctx, ev))
+		require.NotZero(t, ev.Id)
+		require.False(t, ev.CreatedAt.IsZero())
+	}
+
+	events, err := r.PullRequest.ListEvents(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, domain.PullRequestEventCreated, events[0].Type)
+	require.Less(t, events[0].Id, events[1].Id)
+
+	events, err = r.PullRequest.ListEvents(ctx, "pr2")
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	_, err = r.PullRequest.GetReviewSync(ctx, "pr1")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	sync := &domain.ReviewSync{PullRequestId: "pr1", Status: domain.ReviewSyncPending}
+	require.NoError(t, r.PullRequest.SaveReviewSync(ctx, sync))
+	require.False(t, sync.UpdatedAt.IsZero())
+
+	// повторное сохранение перезаписывает состояние
+	sync = &domain.ReviewSync{PullRequestId: "pr1", Status: domain.ReviewSyncFailed, Attempts: 3, LastError: "boom"}
+	require.NoError(t, r.PullRequest.SaveReviewSync(ctx, sync))
+
+	got, err := r.PullRequest.GetReviewSync(ctx, "pr1")
+	require.NoError(t, err)
+	require.Equal(t, domain.ReviewSyncFailed, got.Status)
+	require.Equal(t, 3, got.Attempts)
+	require.Equal(t, "boom", got.LastError)
+	require.True(t, sync.UpdatedAt.Equal(got.UpdatedAt))
+
+	require.Error(t, r.PullRequest.SaveReviewSync(ctx, &domain.ReviewSync{PullRequestId: "missing", Status: domain.ReviewSyncPending}))
+}
+
+func testPullRequestList(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t2", Name: "frontend"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", TeamId: "t2", IsActive: true}))
+
+	open := string(domain.PullRequestStatusOpen)
+	specs := []struct{ id, author, team, name string }{
+		{"pr1", "u1", "t1", "Add search"},
+		{"pr2", "u1", "t2", "Fix 100% CPU"},
+		{"pr3", "u2", "t2", "Refactor SEARCH"},
+		{"pr4", "u2", "t1", "Docs"},
+	}
+	for _, s := range specs {
+		require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: s.id, AuthorId: s.author, Name: s.name, TeamId: s.team, Status: open}))
+		// created_at берётся из NOW(), разносим PR по времени
+		time.Sleep(2 * time.Millisecond)
+	}
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr1", "u2"))
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr3", "u1"))
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr4", "u1"))
+	_, err := r.PullRequest.UpdateStatusMerged(ctx, "pr4")
+	require.NoError(t, err)
+
+	list := func(f *domain.PullRequestFilter) []string {
+		prs, err := r.PullRequest.List(ctx, f)
+		require.NoError(t, err)
+		return prIDs(prs)
+	}
+
+	require.Equal(t, []string{"pr1", "pr2", "pr3", "pr4"}, list(&domain.PullRequestFilter{}))
+	require.Equal(t, []string{"pr4", "pr3", "pr2", "pr1"}, list(&domain.PullRequestFilter{Order: domain.SortNewest}))
+	require.Equal(t, []string{"pr1", "pr4"}, list(&domain.PullRequestFilter{TeamName: "backend"}))
+	require.Equal(t, []string{"pr4"}, list(&domain.PullRequestFilter{Status: domain.PullRequestStatusClosed}))
+	require.Equal(t, []string{"pr3", "pr4"}, list(&domain.PullRequestFilter{AuthorId: "u2"}))
+	require.Equal(t, []string{"pr3", "pr4"}, list(&domain.PullRequestFilter{ReviewerId: "u1"}))
+	require.Equal(t, []string{"pr1", "pr3"}, list(&domain.PullRequestFilter{NameContains: "search"}))
+	require.Equal(t, []string{"pr2"}, list(&domain.PullRequestFilter{NameContains: "100%"}))
+	require.Equal(t, []string{"pr2"}, list(&domain.PullRequestFilter{FewerReviewersThan: 1}))
+
+	merged, err := r.PullRequest.GetByID(ctx, "pr4")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr4"}, list(&domain.PullRequestFilter{MergedFrom: *merged.MergedAt}))
+	require.Empty(t, list(&domain.PullRequestFilter{MergedTo: *merged.MergedAt}))
+
+	second, err := r.PullRequest.GetByID(ctx, "pr2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr2", "pr3", "pr4"}, list(&domain.PullRequestFilter{CreatedFrom: second.CreatedAt}))
+	require.Equal(t, []string{"pr1"}, list(&domain.PullRequestFilter{CreatedTo: second.CreatedAt}))
+
+	after := &domain.Cursor{CreatedAt: second.CreatedAt, Id: second.Id}
+	require.Equal(t, []string{"pr3"}, list(&domain.PullRequestFilter{After: after, Limit: 1}))
+	require.Equal(t, []string{"pr1"}, list(&domain.PullRequestFilter{After: after, Order: domain.SortNewest}))
+
+	reviews, err := r.PullRequest.ListByReviewer(ctx, &domain.ReviewerPRFilter{ReviewerId: "u1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr3", "pr4"}, prIDs(reviews))
+	require.Equal(t, "frontend", reviews[0].TeamName)
+
+	reviews, err = r.PullRequest.ListByReviewer(ctx, &domain.ReviewerPRFilter{ReviewerId: "u1", TeamId: "t1"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr4"}, prIDs(reviews))
+
+	reviews, err = r.PullRequest.ListByReviewer(ctx, &domain.ReviewerPRFilter{ReviewerId: "u1", Status: domain.PullRequestStatusOpen, Order: domain.SortNewest, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"pr3"}, prIDs(reviews))
+}
+
+func testIdentities(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", IsActive: true}))
+
+	gh := &domain.Identity{UserId: "u1", Provider: domain.IdentityGitHub, Login: "alice", ExternalId: 101}
+	require.NoError(t, r.Identity.Create(ctx, gh))
+	require.False(t, gh.CreatedAt.IsZero())
+	require.NoError(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u1", Provider: domain.IdentityGitLab, Login: "alice"}))
+	require.NoError(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u1", Provider: domain.IdentityEmail, Login: "alice@example.com"}))
+	// без числового id учётные записи не конфликтуют
+	require.NoError(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentityGitLab, Login: "bob"}))
+	require.NoError(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentitySlack, Login: "u024be7lh"}))
+
+	require.ErrorIs(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentityGitHub, Login: "alice"}), repo.ErrAlreadyExists)
+	require.ErrorIs(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentityGitHub, Login: "bob", ExternalId: 101}), repo.ErrAlreadyExists)
+	// тот же числовой id у другого провайдера — другая учётная запись
+	require.NoError(t, r.Identity.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentityGitHub, Login: "bob", ExternalId: 202}))
+
+	got, err := r.Identity.GetByLogin(ctx, domain.IdentityGitHub, "alice")
+	require.NoError(t, err)
+	require.Equal(t, "u1", got.UserId)
+	require.EqualValues(t, 101, got.ExternalId)
+
+	got, err = r.Identity.GetByExternalID(ctx, domain.IdentityGitHub, 202)
+	require.NoError(t, err)
+	require.Equal(t, "bob", got.Login)
+	got, err = r.Identity.GetByLogin(ctx, domain.IdentitySlack, "u024be7lh")
+	require.NoError(t, err)
+	require.Equal(t, "u2", got.UserId)
+
+	_, err = r.Identity.GetByLogin(ctx, domain.IdentityGitLab, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	_, err = r.Identity.GetByExternalID(ctx, domain.IdentityGitLab, 101)
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	list, err := r.Identity.ListByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	require.Equal(t, domain.IdentityGitHub, list[0].Provider)
+
+	require.ErrorIs(t, r.Identity.UpdateExternalID(ctx, domain.IdentityGitHub, "bob", 101), repo.ErrAlreadyExists)
+	require.ErrorIs(t, r.Identity.UpdateExternalID(ctx, domain.IdentityGitHub, "missing", 1), repo.ErrNotFound)
+	require.NoError(t, r.Identity.UpdateExternalID(ctx, domain.IdentityGitLab, "alice", 101))
+	require.NoError(t, r.Identity.UpdateExternalID(ctx, domain.IdentityGitHub, "alice", 0))
+
+	got, err = r.Identity.GetByLogin(ctx, domain.IdentityGitHub, "alice")
+	require.NoError(t, err)
+	require.Zero(t, got.ExternalId)
+
+	require.NoError(t, r.Identity.Delete(ctx, domain.IdentityGitHub, "alice"))
+	require.ErrorIs(t, r.Identity.Delete(ctx, domain.IdentityGitHub, "alice"), repo.ErrNotFound)
+
+	list, err = r.Identity.ListByUser(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+}
+
+func testSubscriptions(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	all := &domain.Subscription{Id: "s1", URL: "https://example.com/all", Secret: "secret-one-0123456"}
+	require.NoError(t, r.Subscription.Create(ctx, all))
+	require.False(t, all.CreatedAt.IsZero())
+	merged := &domain.Subscription{
+		Id:     "s2",
+		URL:    "https://example.com/merged",
+		Events: []domain.EventType{domain.EventPullRequestMerged, domain.EventReviewerReassigned},
+		Secret: "secret-two-0123456",
+	}
+	require.NoError(t, r.Subscription.Create(ctx, merged))
+	require.ErrorIs(t, r.Subscription.Create(ctx, &domain.Subscription{Id: "s1", URL: "https://example.com"}), repo.ErrAlreadyExists)
+
+	got, err := r.Subscription.GetByID(ctx, "s2")
+	require.NoError(t, err)
+	require.Equal(t, merged.Events, got.Events)
+	require.Equal(t, "secret-two-0123456", got.Secret)
+	_, err = r.Subscription.GetByID(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	subs, err := r.Subscription.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	require.Equal(t, "s1", subs[0].Id)
+	require.Empty(t, subs[0].Events)
+
+	for i := range 3 {
+		require.NoError(t, r.Subscription.CreateDelivery(ctx, &domain.Delivery{
+			Id:             fmt.Sprintf("d%d", i),
+			SubscriptionId: "s1",
+			EventId:        fmt.Sprintf("e%d", i),
+			EventType:      domain.EventReviewersAssigned,
+			Payload:        []byte(`{"id":"e` + fmt.Sprint(i) + `"}`),
+			Status:         domain.DeliveryPending,
+		}))
+	}
+	require.NoError(t, r.Subscription.CreateDelivery(ctx, &domain.Delivery{
+		Id: "d-s2", SubscriptionId: "s2", EventId: "e0", EventType: domain.EventPullRequestMerged,
+		Payload: []byte(`{}`), Status: domain.DeliveryPending,
+	}))
+
+	d := &domain.Delivery{Id: "d1", Status: domain.DeliveryDead, Attempts: 5, ResponseCode: 502, LastError: "bad gateway"}
+	require.NoError(t, r.Subscription.UpdateDelivery(ctx, d))
+	require.False(t, d.UpdatedAt.IsZero())
+	require.ErrorIs(t, r.Subscription.UpdateDelivery(ctx, &domain.Delivery{Id: "missing"}), repo.ErrNotFound)
+
+	gotDelivery, err := r.Subscription.GetDelivery(ctx, "d1")
+	require.NoError(t, err)
+	require.Equal(t, domain.DeliveryDead, gotDelivery.Status)
+	require.Equal(t, 5, gotDelivery.Attempts)
+	require.Equal(t, 502, gotDelivery.ResponseCode)
+	require.Equal(t, "e1", gotDelivery.EventId)
+	require.JSONEq(t, `{"id":"e1"}`, string(gotDelivery.Payload))
+	_, err = r.Subscription.GetDelivery(ctx, "missing")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+
+	list, err := r.Subscription.ListDeliveries(ctx, &domain.DeliveryFilter{SubscriptionId: "s1", Order: domain.SortNewest, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "d2", list[0].Id)
+
+	list, err = r.Subscription.ListDeliveries(ctx, &domain.DeliveryFilter{
+		SubscriptionId: "s1",
+		Order:          domain.SortNewest,
+		After:          &domain.Cursor{CreatedAt: list[1].CreatedAt, Id: list[1].Id},
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "d0", list[0].Id)
+
+	list, err = r.Subscription.ListDeliveries(ctx, &domain.DeliveryFilter{SubscriptionId: "s1", Status: domain.DeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	// доставки удаляются вместе с подпиской
+	require.NoError(t, r.Subscription.Delete(ctx, "s1"))
+	require.ErrorIs(t, r.Subscription.Delete(ctx, "s1"), repo.ErrNotFound)
+	_, err = r.Subscription.GetDelivery(ctx, "d0")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	_, err = r.Subscription.GetDelivery(ctx, "d-s2")
+	require.NoError(t, err)
+}
+
+func testDeliveryClaims(t *testing.T, r Repos) {
+	ctx := context.Background()
+	base := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		v := base.Add(d)
+		return &v
+	}
+
+	require.NoError(t, r.Subscription.Create(ctx, &domain.Subscription{Id: "s1", URL: "https://example.com", Secret: "secret-one-0123456"}))
+	for _, d := range []*domain.Delivery{
+		{Id: "late", Status: domain.DeliveryPending, NextAttemptAt: at(time.Minute)},
+		{Id: "due-2", Status: domain.DeliveryPending, NextAttemptAt: at(-time.Second)},
+		{Id: "due-1", Status: domain.DeliveryPending, NextAttemptAt: at(-time.Minute)},
+		{Id: "due-3", Status: domain.DeliveryPending, NextAttemptAt: at(0)},
+		{Id: "dead", Status: domain.DeliveryDead},
+	} {
+		d.SubscriptionId, d.EventId, d.EventType, d.Payload = "s1", d.Id, domain.EventReviewersAssigned, []byte(`{}`)
+		require.NoError(t, r.Subscription.CreateDelivery(ctx, d))
+	}
+
+	until := base.Add(30 * time.Second)
+	claimed, err := r.Subscription.ClaimDeliveries(ctx, base, until, 2)
+	require.NoError(t, err)
+	ids := make([]string, 0, len(claimed))
+	for _, d := range claimed {
+		require.True(t, until.Equal(*d.NextAttemptAt))
+		ids = append(ids, d.Id)
+	}
+	require.ElementsMatch(t, []string{"due-1", "due-2"}, ids)
+
+	// забранные доставки не выдаются повторно, пока не истечёт until
+	claimed, err = r.Subscription.ClaimDeliveries(ctx, base, until, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, "due-3", claimed[0].Id)
+
+	claimed, err = r.Subscription.ClaimDeliveries(ctx, base.Add(45*time.Second), until, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 3)
+
+	d, err := r.Subscription.GetDelivery(ctx, "due-1")
+	require.NoError(t, err)
+	d.Status, d.NextAttemptAt = domain.DeliveryDelivered, nil
+	require.NoError(t, r.Subscription.UpdateDelivery(ctx, d))
+	d, err = r.Subscription.GetDelivery(ctx, "due-1")
+	require.NoError(t, err)
+	require.Nil(t, d.NextAttemptAt)
+
+	claimed, err = r.Subscription.ClaimDeliveries(ctx, base.Add(2*time.Minute), until, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 3)
+}
+
+func testNotifications(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", IsActive: true}))
+
+	slack := &domain.NotificationPreference{UserId: "u1", Channel: domain.NotificationSlack}
+	require.NoError(t, r.Notification.SetPreference(ctx, slack))
+	require.False(t, slack.CreatedAt.IsZero())
+	require.NoError(t, r.Notification.SetPreference(ctx, &domain.NotificationPreference{UserId: "u1", Channel: domain.NotificationEmail, Address: "alice@example.com"}))
+	require.Error(t, r.Notification.SetPreference(ctx, &domain.NotificationPreference{UserId: "missing", Channel: domain.NotificationEmail}))
+
+	// повторная запись меняет адрес, а не добавляет канал
+	updated := &domain.NotificationPreference{UserId: "u1", Channel: domain.NotificationEmail, Address: "a@example.com"}
+	require.NoError(t, r.Notification.SetPreference(ctx, updated))
+	require.False(t, updated.UpdatedAt.Before(updated.CreatedAt))
+
+	list, err := r.Notification.ListPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, domain.NotificationEmail, list[0].Channel)
+	require.Equal(t, "a@example.com", list[0].Address)
+	require.Equal(t, domain.NotificationSlack, list[1].Channel)
+	require.Empty(t, list[1].Address)
+
+	list, err = r.Notification.ListPreferences(ctx, "u2")
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	require.NoError(t, r.Notification.DeletePreference(ctx, "u1", domain.NotificationSlack))
+	require.ErrorIs(t, r.Notification.DeletePreference(ctx, "u1", domain.NotificationSlack), repo.ErrNotFound)
+
+	list, err = r.Notification.ListPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	open := string(domain.PullRequestStatusOpen)
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr1", AuthorId: "u1", Name: "First", Status: open}))
+
+	warned, err := r.Notification.MarkSLAWarned(ctx, "pr1", "u2")
+	require.NoError(t, err)
+	require.True(t, warned)
+	warned, err = r.Notification.MarkSLAWarned(ctx, "pr1", "u2")
+	require.NoError(t, err)
+	require.False(t, warned)
+	warned, err = r.Notification.MarkSLAWarned(ctx, "pr1", "u1")
+	require.NoError(t, err)
+	require.True(t, warned)
+
+	// снятая отметка ставится заново
+	require.NoError(t, r.Notification.UnmarkSLAWarned(ctx, "pr1", "u2"))
+	require.NoError(t, r.Notification.UnmarkSLAWarned(ctx, "pr1", "u2"))
+	warned, err = r.Notification.MarkSLAWarned(ctx, "pr1", "u2")
+	require.NoError(t, err)
+	require.True(t, warned)
+}
+
+func testDigests(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", IsActive: true}))
+
+	_, err := r.Notification.GetDigest(ctx, "u1")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	require.Error(t, r.Notification.SetDigest(ctx, &domain.DigestSettings{UserId: "missing", Timezone: "UTC"}))
+	require.Error(t, r.Notification.SetDigest(ctx, &domain.DigestSettings{UserId: "u1", Timezone: "UTC", Hour: 24}))
+
+	start, end := 22, 7
+	alice := &domain.DigestSettings{UserId: "u1", Enabled: true, Timezone: "Europe/Moscow", Hour: 9, QuietStart: &start, QuietEnd: &end}
+	require.NoError(t, r.Notification.SetDigest(ctx, alice))
+	require.False(t, alice.UpdatedAt.IsZero())
+	require.Nil(t, alice.LastSentAt)
+	require.NoError(t, r.Notification.SetDigest(ctx, &domain.DigestSettings{UserId: "u2", Timezone: "UTC", Hour: 8}))
+
+	got, err := r.Notification.GetDigest(ctx, "u1")
+	require.NoError(t, err)
+	require.True(t, got.Enabled)
+	require.Equal(t, "Europe/Moscow", got.Timezone)
+	require.Equal(t, 22, *got.QuietStart)
+	require.Equal(t, 7, *got.QuietEnd)
+
+	enabled, err := r.Notification.ListEnabledDigests(ctx)
+	require.NoError(t, err)
+	require.Len(t, enabled, 1)
+	require.Equal(t, "u1", enabled[0].UserId)
+
+	// отправку отмечает только тот, кто видел прежнее значение
+	sent := time.Date(2026, 3, 2, 6, 0, 0, 0, time.UTC)
+	ok, err := r.Notification.MarkDigestSent(ctx, "u1", nil, sent)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = r.Notification.MarkDigestSent(ctx, "u1", nil, sent.Add(time.Minute))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	got, err = r.Notification.GetDigest(ctx, "u1")
+	require.NoError(t, err)
+	require.True(t, sent.Equal(*got.LastSentAt))
+
+	next := sent.Add(24 * time.Hour)
+	ok, err = r.Notification.MarkDigestSent(ctx, "u1", got.LastSentAt, next)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// изменение настроек не сбрасывает время последней отправки
+	alice.QuietStart, alice.QuietEnd = nil, nil
+	require.NoError(t, r.Notification.SetDigest(ctx, alice))
+	require.NotNil(t, alice.LastSentAt)
+	require.True(t, next.Equal(*alice.LastSentAt))
+
+	got, err = r.Notification.GetDigest(ctx, "u1")
+	require.NoError(t, err)
+	require.Nil(t, got.QuietStart)
+}
+
+func testAudit(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	records := []*domain.AuditRecord{
+		{
+			Id: "a1", Actor: "alice", Action: domain.AuditUserSetActive, TargetType: domain.AuditTargetUser, TargetId: "u1",
+			Before: []byte(`{"is_active":true}`), After: []byte(`{"is_active":false}`), RequestId: "req-1",
+		},
+		{
+			Id: "a2", Actor: "alice", ClaimedActor: "carol", Action: domain.AuditTeamAdd, TargetType: domain.AuditTargetTeam, TargetId: "backend",
+			After: []byte(`{"team_name":"backend"}`), RequestId: "req-1",
+		},
+		{
+			Id: "a3", Actor: "system", Action: domain.AuditPullRequestMerge, TargetType: domain.AuditTargetPullRequest, TargetId: "pr-1",
+			Before: []byte(`{"status":"OPEN"}`), After: []byte(`{"status":"MERGED"}`),
+		},
+	}
+	for _, record := range records {
+		require.NoError(t, r.Audit.Append(ctx, record))
+		require.False(t, record.CreatedAt.IsZero())
+	}
+	require.ErrorIs(t, r.Audit.Append(ctx, &domain.AuditRecord{Id: "a1", Actor: "bob"}), repo.ErrAlreadyExists)
+
+	list, err := r.Audit.List(ctx, &domain.AuditFilter{TargetType: domain.AuditTargetUser, TargetId: "u1"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "alice", list[0].Actor)
+	require.Equal(t, domain.AuditUserSetActive, list[0].Action)
+	require.Equal(t, "req-1", list[0].RequestId)
+	require.JSONEq(t, `{"is_active":true}`, string(list[0].Before))
+	require.JSONEq(t, `{"is_active":false}`, string(list[0].After))
+
+	// пустой снимок читается как nil, а не как "null"
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{Action: domain.AuditTeamAdd})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Nil(t, list[0].Before)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{Actor: "system"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Empty(t, list[0].RequestId)
+	require.Empty(t, list[0].ClaimedActor)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{ClaimedActor: "carol"})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "a2", list[0].Id)
+	require.Equal(t, "alice", list[0].Actor)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{RequestId: "req-1", Order: domain.SortNewest, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "a2", list[0].Id)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{
+		RequestId: "req-1",
+		Order:     domain.SortNewest,
+		After:     &domain.Cursor{CreatedAt: list[0].CreatedAt, Id: list[0].Id},
+	})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "a1", list[0].Id)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{From: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Empty(t, list)
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	n, err := r.Audit.DeleteBefore(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Zero(t, n)
+	n, err = r.Audit.DeleteBefore(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, n)
+
+	list, err = r.Audit.List(ctx, &domain.AuditFilter{})
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func testOutbox(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	// события пишутся в транзакции вместе с изменением и видны после неё
+	var msgs []*domain.OutboxMessage
+	err := r.Transactor.InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+		for i, prID := range []string{"pr-1", "pr-2", "pr-1"} {
+			msg := &domain.OutboxMessage{
+				PullRequestId: prID,
+				EventType:     domain.EventReviewersAssigned,
+				Payload:       []byte(fmt.Sprintf(`{"n":%d}`, i)),
+			}
+			if err := r.Outbox.Add(ctx, msg); err != nil {
+				return err
+			}
+			msgs = append(msgs, msg)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = r.Team.GetByID(ctx, "t1")
+	require.NoError(t, err)
+	require.Less(t, msgs[0].Id, msgs[1].Id)
+	require.Less(t, msgs[1].Id, msgs[2].Id)
+	require.False(t, msgs[0].CreatedAt.IsZero())
+
+	pending, err := r.Outbox.ListPending(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, msgs[0].Id, pending[0].Id)
+	require.Equal(t, "pr-1", pending[0].PullRequestId)
+	require.Equal(t, domain.EventReviewersAssigned, pending[0].EventType)
+	require.JSONEq(t, `{"n":0}`, string(pending[0].Payload))
+	require.Nil(t, pending[0].PublishedAt)
+	require.Nil(t, pending[0].AcceptedBy)
+
+	require.NoError(t, r.Outbox.MarkFailed(ctx, msgs[0].Id, nil, "sink is down"))
+	require.NoError(t, r.Outbox.MarkFailed(ctx, msgs[0].Id, []string{"subscriptions", "notifications"}, "sink is still down"))
+	require.NoError(t, r.Outbox.MarkPublished(ctx, msgs[1].Id))
+	require.ErrorIs(t, r.Outbox.MarkPublished(ctx, 1<<40), repo.ErrNotFound)
+	require.ErrorIs(t, r.Outbox.MarkFailed(ctx, 1<<40, nil, "x"), repo.ErrNotFound)
+	require.ErrorIs(t, r.Outbox.MarkDead(ctx, 1<<40), repo.ErrNotFound)
+
+	pending, err = r.Outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, msgs[0].Id, pending[0].Id)
+	require.Equal(t, 2, pending[0].Attempts)
+	require.Equal(t, "sink is still down", pending[0].LastError)
+	require.Equal(t, []string{"subscriptions", "notifications"}, pending[0].AcceptedBy)
+	require.Equal(t, msgs[2].Id, pending[1].Id)
+
+	// порядок публикации может отличаться от порядка записи
+	require.NoError(t, r.Outbox.MarkPublished(ctx, msgs[2].Id))
+	require.NoError(t, r.Outbox.MarkPublished(ctx, msgs[0].Id))
+	// повторная пометка ничего не меняет
+	require.NoError(t, r.Outbox.MarkPublished(ctx, msgs[1].Id))
+
+	published, err := r.Outbox.ListPublished(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, published, 3)
+	require.Equal(t, []int64{msgs[1].Id, msgs[2].Id, msgs[0].Id},
+		[]int64{published[0].Id, published[1].Id, published[2].Id})
+	require.Less(t, published[0].PublishedSeq, published[1].PublishedSeq)
+	require.Less(t, published[1].PublishedSeq, published[2].PublishedSeq)
+	require.NotNil(t, published[0].PublishedAt)
+
+	published, err = r.Outbox.ListPublished(ctx, published[0].PublishedSeq, 1)
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	require.Equal(t, msgs[2].Id, published[0].Id)
+
+	last, err := r.Outbox.LastPublishedSeq(ctx)
+	require.NoError(t, err)
+	pending, err = r.Outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	published, err = r.Outbox.ListPublished(ctx, last, 10)
+	require.NoError(t, err)
+	require.Empty(t, published)
+	require.Positive(t, last)
+
+	// снятое с публикации событие больше не ожидает её
+	dead := &domain.OutboxMessage{PullRequestId: "pr-3", EventType: domain.EventPullRequestMerged, Payload: []byte(`{}`)}
+	require.NoError(t, r.Outbox.Add(ctx, dead))
+	require.NoError(t, r.Outbox.MarkDead(ctx, dead.Id))
+	pending, err = r.Outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	published, err = r.Outbox.ListPublished(ctx, last, 10)
+	require.NoError(t, err)
+	require.Empty(t, published)
+}
+
+func testTransactions(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u1", Username: "alice", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "u2", Username: "bob", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr-1", Name: "Fix", AuthorId: "u1", TeamId: "t1", Status: string(domain.PullRequestStatusOpen)}))
+	require.NoError(t, r.PullRequest.AddReviewer(ctx, "pr-1", "u2"))
+
+	// ошибка fn откатывает все изменения, в том числе сделанные во вложенной транзакции
+	errBoom := errors.New("boom")
+	err := r.Transactor.InTx(ctx, func(ctx context.Context) error {
+		require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t2", Name: "frontend"}))
+		require.NoError(t, r.User.UpdateIsActive(ctx, "u1", false))
+		require.NoError(t, r.PullRequest.RemoveReviewer(ctx, "pr-1", "u2"))
+		merged, err := r.PullRequest.UpdateStatusMerged(ctx, "pr-1")
+		require.NoError(t, err)
+		require.True(t, merged)
+
+		return r.Transactor.InTx(ctx, func(ctx context.Context) error {
+			require.NoError(t, r.Outbox.Add(ctx, &domain.OutboxMessage{PullRequestId: "pr-1", EventType: domain.EventPullRequestMerged, Payload: []byte(`{}`)}))
+			require.NoError(t, r.Audit.Append(ctx, &domain.AuditRecord{Id: "a1", Actor: "alice", Action: domain.AuditPullRequestMerge, TargetType: domain.AuditTargetPullRequest, TargetId: "pr-1"}))
+			return errBoom
+		})
+	})
+	require.ErrorIs(t, err, errBoom)
+
+	_, err = r.Team.GetByID(ctx, "t2")
+	require.ErrorIs(t, err, repo.ErrNotFound)
+	u1, err := r.User.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	require.True(t, u1.IsActive)
+	pr, err := r.PullRequest.GetByID(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, string(domain.PullRequestStatusOpen), pr.Status)
+	require.Nil(t, pr.MergedAt)
+	reviewers, err := r.PullRequest.ListReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2"}, reviewers)
+	pending, err := r.Outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+	records, err := r.Audit.List(ctx, &domain.AuditFilter{})
+	require.NoError(t, err)
+	require.Empty(t, records)
+
+	// без ошибки изменения сохраняются
+	err = r.Transactor.InTx(ctx, func(ctx context.Context) error {
+		if err := r.Team.Create(ctx, &domain.Team{Id: "t2", Name: "frontend"}); err != nil {
+			return err
+		}
+		_, err := r.PullRequest.UpdateStatusMerged(ctx, "pr-1")
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = r.Team.GetByID(ctx, "t2")
+	require.NoError(t, err)
+	pr, err = r.PullRequest.GetByID(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Equal(t, string(domain.PullRequestStatusClosed), pr.Status)
+}
+
+func testConcurrent(t *testing.T, r Repos) {
+	ctx := context.Background()
+
+	require.NoError(t, r.Team.Create(ctx, &domain.Team{Id: "t1", Name: "backend"}))
+	require.NoError(t, r.User.Create(ctx, &domain.User{Id: "author", Username: "author", TeamId: "t1", IsActive: true}))
+	require.NoError(t, r.PullRequest.Create(ctx, &domain.PullRequest{Id: "pr", AuthorId: "author", Name: "x", TeamId: "t1", Status: string(domain.PullRequestStatusOpen)}))
+
+	const n = 20
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		created int
+		merged  int
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			id := fmt.Sprintf("u%02d", i)
+			if err := r.User.Create(ctx, &domain.User{Id: id, Username: id, TeamId: "t1", IsActive: true}); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := r.PullRequest.AddReviewer(ctx, "pr", id); err != nil {
+				t.Error(err)
+			}
+
+			// одновременная вставка одного ключа: проходит ровно одна
+			err := r.Team.Create(ctx, &domain.Team{Id: "race", Name: "race"})
+			if err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			} else if !errors.Is(err, repo.ErrAlreadyExists) {
+				t.Error(err)
+			}
+
+			// одновременный merge: статус меняет ровно один
+			ok, err := r.PullRequest.UpdateStatusMerged(ctx, "pr")
+			if err != nil {
+				t.Error(err)
+			}
+			if ok {
+				mu.Lock()
+				merged++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, created)
+	require.Equal(t, 1, merged)
+
+	revs, err := r.PullRequest.ListReviewers(ctx, "pr")
+	require.NoError(t, err)
+	require.Len(t, revs, n)
+
+	members, err := r.Team.ListMembers(ctx, "t1")
+	require.NoError(t, err)
+	require.Len(t, members, n+1)
+}
+
+func userIDs(users []*domain.User) []string {
+	res := make([]string, 0, len(users))
+	for _, u := range users {
+		res = append(res, u.Id)
+	}
+	return res
+}
+
+func memberIDs(members []*domain.TeamMember) []string {
+	res := make([]string, 0, len(members))
+	for _, m := range members {
+		res = append(res, m.User.Id)
+	}
+	return res
+}
+
+func teamIDs(teams []*domain.Team) []string {
+	res := make([]string, 0, len(teams))
+	for _, t := range teams {
+		res = append(res, t.Id)
+	}
+	return res
+}
+
+func prIDs(prs []*domain.PullRequest) []string {
+	res := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		res = append(res, pr.Id)
+	}
+	return res
+}
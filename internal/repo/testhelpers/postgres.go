@@ -0,0 +1,60 @@
+package testhelpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	tc "github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// StartPostgres поднимает Postgres в контейнере и применяет миграции.
+func StartPostgres(t *testing.T) (*pgxpool.Pool, func()) {
+	t.Helper()
+
+	dsn, stop := StartPostgresContainer(t)
+
+	dbpool, err := pgxpool.New(context.Background(), dsn)
+	require.NoError(t, err)
+
+	err = RunMigrations(dsn)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		dbpool.Close()
+		stop()
+	}
+
+	return dbpool, cleanup
+}
+
+// StartPostgresContainer поднимает пустой Postgres и возвращает его DSN.
+// Без доступного Docker тест пропускается.
+func StartPostgresContainer(t *testing.T) (string, func()) {
+	t.Helper()
+	tc.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+
+	req := tc.ContainerRequest{
+		Image:        "postgres:16.7",
+		Env:          map[string]string{"POSTGRES_PASSWORD": "root", "POSTGRES_USER": "root", "POSTGRES_DB": "testdb"},
+		ExposedPorts: []string{"5432/tcp"},
+		WaitingFor:   wait.ForListeningPort("5432/tcp"),
+	}
+
+	container, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err)
+
+	host, _ := container.Host(ctx)
+	port, _ := container.MappedPort(ctx, "5432")
+
+	dsn := "postgres://root:root@" + host + ":" + port.Port() + "/testdb?sslmode=disable"
+
+	return dsn, func() { container.Terminate(ctx) }
+}
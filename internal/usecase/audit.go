@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+// Audit ведёт журнал изменений: кто, что и над чем сделал, со снимками цели
+// до и после. Записи старше срока хранения удаляются.
+type Audit struct {
+	auditRepo repo.Audit
+	tx        repo.Transactor
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewAudit создаёт usecase журнала аудита; tx объединяет изменение и его
+// запись в журнал, retention <= 0 — записи хранятся всегда.
+func NewAudit(auditRepo repo.Audit, tx repo.Transactor, retention time.Duration) *Audit {
+	return &Audit{
+		auditRepo: auditRepo,
+		tx:        tx,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// SetClock подменяет текущее время для срока хранения.
+func (a *Audit) SetClock(now func() time.Time) {
+	a.now = now
+}
+
+// List возвращает страницу журнала, по умолчанию новые записи первыми.
+func (a *Audit) List(ctx context.Context, q *domain.AuditListQuery) (*domain.AuditPage, error) {
+	order := q.Order
+	if order == "" {
+		order = domain.SortNewest
+	}
+
+	page, err := newPageParams(order, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := a.auditRepo.List(ctx, &domain.AuditFilter{
+		Actor:        q.Actor,
+		ClaimedActor: q.ClaimedActor,
+		Action:       q.Action,
+		TargetType:   q.TargetType,
+		TargetId:     q.TargetId,
+		RequestId:    q.RequestId,
+		From:         q.From,
+		To:           q.To,
+		Order:        page.order,
+		After:        page.after,
+		Limit:        page.fetchLimit(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit records: %w", err)
+	}
+
+	var next string
+	if len(records) > page.limit {
+		records = records[:page.limit]
+		last := records[len(records)-1]
+		next = domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+
+	return &domain.AuditPage{
+		Records:    records,
+		NextCursor: next,
+	}, nil
+}
+
+// Prune удаляет записи старше срока хранения и возвращает их число.
+func (a *Audit) Prune(ctx context.Context) (int64, error) {
+	if a.retention <= 0 {
+		return 0, nil
+	}
+
+	n, err := a.auditRepo.DeleteBefore(ctx, a.now().Add(-a.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit records: %w", err)
+	}
+	return n, nil
+}
+
+// RunRetention удаляет устаревшие записи раз в interval, пока не отменён ctx.
+func (a *Audit) RunRetention(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func(ctx context.Context) error {
+		n, err := a.Prune(ctx)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			slogx.Info(ctx, "audit log pruned", "deleted", n)
+		}
+		return nil
+	})
+}
+
+// record записывает изменение в журнал; повторное действие, которое ничего не
+// поменяло (снимки совпали), не записывается.
+func (a *Audit) record(ctx context.Context, action domain.AuditAction, targetType domain.AuditTargetType, targetID string, before, after any) error {
+	rec := &domain.AuditRecord{
+		Id:           uuid.NewString(),
+		Actor:        domain.VerifiedActorFromCtx(ctx),
+		ClaimedActor: domain.ClaimedActorFromCtx(ctx),
+		Action:       action,
+		TargetType:   targetType,
+		TargetId:     targetID,
+		RequestId:    domain.RequestIDFromCtx(ctx),
+	}
+
+	var err error
+	if rec.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if rec.After, err = snapshot(after); err != nil {
+		return err
+	}
+	if rec.Before != nil && bytes.Equal(rec.Before, rec.After) {
+		return nil
+	}
+
+	if err := a.auditRepo.Append(ctx, rec); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+func snapshot(v any) (json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal audit snapshot: %w", err)
+	}
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	return data, nil
+}
+
+// auditSpec описывает, как записать изменение: before читает цель до него,
+// after достаёт её снимок из результата. target, если задан, достаёт id цели
+// из результата вместо targetID — для целей, которые ищутся не по id. skip,
+// если задан, говорит, что change ничего не изменил и записывать нечего.
+type auditSpec[T any] struct {
+	action     domain.AuditAction
+	targetType domain.AuditTargetType
+	targetID   string
+	target     func(res T) string
+	skip       func(res T) bool
+	before     func(ctx context.Context) (any, error)
+	after      func(res T) any
+}
+
+// audited — декоратор изменяющих сценариев: в одной транзакции снимает цель до
+// изменения, выполняет change и пишет запись в журнал, так что изменение без
+// записи не сохраняется. Цель, которой ещё нет, даёт пустой снимок до. Без
+// журнала change выполняется как есть.
+func audited[T any](ctx context.Context, a *Audit, spec auditSpec[T], change func(ctx context.Context) (T, error)) (T, error) {
+	if a == nil {
+		return change(ctx)
+	}
+
+	var res T
+	err := a.tx.InTx(ctx, func(ctx context.Context) error {
+		var before any
+		if spec.before != nil {
+			var err error
+			before, err = spec.before(ctx)
+			if err != nil && !errors.Is(err, repo.ErrNotFound) {
+				return err
+			}
+		}
+
+		var err error
+		res, err = change(ctx)
+		if err != nil {
+			return err
+		}
+		if spec.skip != nil && spec.skip(res) {
+			return nil
+		}
+
+		var after any
+		if spec.after != nil {
+			after = spec.after(res)
+		}
+		targetID := spec.targetID
+		if spec.target != nil {
+			targetID = spec.target(res)
+		}
+		return a.record(ctx, spec.action, spec.targetType, targetID, before, after)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,258 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/repo/memory"
+	"gopr/internal/repo/sqlite"
+	"gopr/internal/usecase"
+)
+
+type auditEnv struct {
+	audit  *usecase.Audit
+	users  *usecase.User
+	teams  *usecase.Team
+	prCase *usecase.PullRequest
+	now    time.Time
+}
+
+// setupAudit включает журнал для пользователей, команд и PR. Часы базы идут
+// на секунду за каждое обращение, чтобы записи не совпадали по времени.
+func setupAudit(t *testing.T, retention time.Duration) *auditEnv {
+	t.Helper()
+
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	e := &auditEnv{
+		audit:  usecase.NewAudit(memory.NewAuditRepo(db), memory.NewTransactor(db), retention),
+		users:  usecase.NewUser(userRepo, teamRepo, prRepo, nil),
+		teams:  usecase.NewTeam(teamRepo, userRepo, prRepo),
+		prCase: usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1),
+		now:    time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+	}
+	db.SetClock(func() time.Time {
+		e.now = e.now.Add(time.Second)
+		return e.now
+	})
+	e.users.SetAudit(e.audit)
+	e.teams.SetAudit(e.audit)
+	e.prCase.SetAudit(e.audit)
+
+	return e
+}
+
+func (e *auditEnv) list(t *testing.T, q *domain.AuditListQuery) []*domain.AuditRecord {
+	t.Helper()
+
+	page, err := e.audit.List(context.Background(), q)
+	require.NoError(t, err)
+	return page.Records
+}
+
+func snapshotField[T any](t *testing.T, raw json.RawMessage, path ...string) T {
+	t.Helper()
+
+	var v any
+	require.NoError(t, json.Unmarshal(raw, &v))
+	for _, key := range path {
+		v = v.(map[string]any)[key]
+	}
+	res, ok := v.(T)
+	require.True(t, ok, "%v", v)
+	return res
+}
+
+func TestAudit_RecordsChanges(t *testing.T) {
+	e := setupAudit(t, 0)
+	ctx := domain.WithRequestID(domain.WithActor(context.Background(), "admin"), "req-1")
+
+	team, err := e.teams.AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	records := e.list(t, &domain.AuditListQuery{})
+	require.Len(t, records, 1)
+	require.Equal(t, "admin", records[0].Actor)
+	require.Equal(t, domain.AuditTeamAdd, records[0].Action)
+	require.Equal(t, domain.AuditTargetTeam, records[0].TargetType)
+	require.Equal(t, team.Team.Id, records[0].TargetId)
+	require.Equal(t, "req-1", records[0].RequestId)
+	require.Nil(t, records[0].Before)
+	require.Len(t, snapshotField[[]any](t, records[0].After, "members"), 2)
+
+	// без актора и id запроса — действие системы
+	_, err = e.users.SetActive(context.Background(), "u2", false)
+	require.NoError(t, err)
+
+	records = e.list(t, &domain.AuditListQuery{TargetType: domain.AuditTargetUser, TargetId: "u2"})
+	require.Len(t, records, 1)
+	require.Equal(t, domain.ActorSystem, records[0].Actor)
+	require.Empty(t, records[0].RequestId)
+	require.True(t, snapshotField[bool](t, records[0].Before, "user", "is_active"))
+	require.False(t, snapshotField[bool](t, records[0].After, "user", "is_active"))
+
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+	_, err = e.prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+
+	records = e.list(t, &domain.AuditListQuery{TargetType: domain.AuditTargetPullRequest, Order: domain.SortOldest})
+	require.Len(t, records, 2)
+	require.Equal(t, domain.AuditPullRequestCreate, records[0].Action)
+	require.Nil(t, records[0].Before)
+	require.Equal(t, domain.AuditPullRequestMerge, records[1].Action)
+	require.Equal(t, "OPEN", snapshotField[string](t, records[1].Before, "pr", "status"))
+	require.Equal(t, "MERGED", snapshotField[string](t, records[1].After, "pr", "status"))
+
+	// повторный merge ничего не меняет и не записывается
+	_, err = e.prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+	require.Len(t, e.list(t, &domain.AuditListQuery{Action: domain.AuditPullRequestMerge}), 1)
+
+	// неудачное действие не записывается
+	_, err = e.teams.Rename(ctx, &domain.TeamRenameInput{TeamName: "missing", NewTeamName: "other"})
+	require.Error(t, err)
+
+	// цель записей команды — её id, а не имя, которое меняется
+	_, err = e.teams.Rename(ctx, &domain.TeamRenameInput{TeamName: "acme", NewTeamName: "acme-core"})
+	require.NoError(t, err)
+	_, err = e.teams.Delete(ctx, &domain.TeamDeleteInput{TeamName: "acme-core"})
+	require.NoError(t, err)
+
+	records = e.list(t, &domain.AuditListQuery{TargetType: domain.AuditTargetTeam, TargetId: team.Team.Id})
+	require.Len(t, records, 3)
+	require.Equal(t, domain.AuditTeamDelete, records[0].Action)
+	require.Equal(t, "acme-core", snapshotField[string](t, records[0].Before, "team", "name"))
+	require.Nil(t, records[0].After)
+	require.Equal(t, domain.AuditTeamRename, records[1].Action)
+	require.Equal(t, "acme", snapshotField[string](t, records[1].Before, "team", "name"))
+
+	require.Len(t, e.list(t, &domain.AuditListQuery{RequestId: "req-1"}), 5)
+	require.Len(t, e.list(t, &domain.AuditListQuery{Actor: "admin"}), 5)
+}
+
+func TestAudit_ReassignWithoutCandidate(t *testing.T) {
+	e := setupAudit(t, 0)
+	ctx := domain.WithActor(context.Background(), "u1")
+
+	_, err := e.teams.AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	// заменить bob некем, но с PR он всё равно снимается
+	_, newID, err := e.prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: "pr-1", OldReviewerId: "u2"})
+	require.ErrorIs(t, err, usecase.ErrNoCandidate)
+	require.Empty(t, newID)
+
+	records := e.list(t, &domain.AuditListQuery{Action: domain.AuditPullRequestReassign})
+	require.Len(t, records, 1)
+	require.Equal(t, "u1", records[0].Actor)
+	var before, after domain.PullRequestWithReviewers
+	require.NoError(t, json.Unmarshal(records[0].Before, &before))
+	require.NoError(t, json.Unmarshal(records[0].After, &after))
+	require.Equal(t, []string{"u2"}, before.Reviewers)
+	require.Empty(t, after.Reviewers)
+
+	_, _, err = e.prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: "pr-1", OldReviewerId: "u2"})
+	require.ErrorIs(t, err, usecase.ErrNotAssigned)
+	require.Len(t, e.list(t, &domain.AuditListQuery{Action: domain.AuditPullRequestReassign}), 1)
+}
+
+func TestAudit_PaginationAndRetention(t *testing.T) {
+	ctx := context.Background()
+	e := setupAudit(t, 24*time.Hour)
+
+	_, err := e.teams.AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members:  []domain.TeamAddMemberInput{{UserID: "u1", Username: "alice", IsActive: true}},
+	})
+	require.NoError(t, err)
+	for _, name := range []string{"alice-1", "alice-2"} {
+		_, err := e.users.UpdateUsername(ctx, &domain.UserUpdateInput{UserID: "u1", Username: name})
+		require.NoError(t, err)
+	}
+
+	page, err := e.audit.List(ctx, &domain.AuditListQuery{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 2)
+	require.Equal(t, domain.AuditUserUpdate, page.Records[0].Action)
+	require.NotEmpty(t, page.NextCursor)
+
+	page, err = e.audit.List(ctx, &domain.AuditListQuery{Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	require.Len(t, page.Records, 1)
+	require.Equal(t, domain.AuditTeamAdd, page.Records[0].Action)
+	require.Empty(t, page.NextCursor)
+
+	e.audit.SetClock(func() time.Time { return e.now.Add(time.Hour) })
+	deleted, err := e.audit.Prune(ctx)
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	e.audit.SetClock(func() time.Time { return e.now.Add(25 * time.Hour) })
+	deleted, err = e.audit.Prune(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, deleted)
+	require.Empty(t, e.list(t, &domain.AuditListQuery{}))
+}
+
+// failingAuditRepo не даёт записать журнал.
+type failingAuditRepo struct {
+	repo.Audit
+}
+
+func (failingAuditRepo) Append(context.Context, *domain.AuditRecord) error {
+	return errors.New("disk full")
+}
+
+func TestAudit_FailedRecordRollsBackChange(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "gopr.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userRepo := sqlite.NewUserRepo(db)
+	teamRepo := sqlite.NewTeamRepo(db)
+	prRepo := sqlite.NewPullRequestRepo(db)
+
+	_, err = usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members:  []domain.TeamAddMemberInput{{UserID: "u1", Username: "alice", IsActive: true}},
+	})
+	require.NoError(t, err)
+
+	users := usecase.NewUser(userRepo, teamRepo, prRepo, nil)
+	users.SetAudit(usecase.NewAudit(failingAuditRepo{sqlite.NewAuditRepo(db)}, sqlite.NewTransactor(db), 0))
+
+	_, err = users.SetActive(ctx, "u1", false)
+	require.ErrorContains(t, err, "disk full")
+
+	// изменение без записи в журнал не сохраняется
+	user, err := userRepo.GetByID(ctx, "u1")
+	require.NoError(t, err)
+	require.True(t, user.IsActive)
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gopr/pkg/slogx"
+)
+
+// runEvery вызывает fn раз в interval, пока не отменён ctx. Ошибка fn
+// логируется и не останавливает цикл.
+func runEvery(ctx context.Context, interval time.Duration, fn func(ctx context.Context) error) {
+	runOn(ctx, interval, nil, fn)
+}
+
+// runOn — runEvery, который вызывает fn ещё и по сигналу wake; wake может быть
+// nil, закрытый wake больше не ждётся.
+func runOn(ctx context.Context, interval time.Duration, wake <-chan struct{}, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case _, ok := <-wake:
+			if !ok {
+				wake = nil
+			}
+		}
+
+		runOnce(ctx, fn)
+	}
+}
+
+// runOnce вызывает fn и логирует её ошибку, если ctx не отменён.
+func runOnce(ctx context.Context, fn func(ctx context.Context) error) {
+	if err := fn(ctx); err != nil && ctx.Err() == nil {
+		slogx.WithErr(slogx.FromCtx(ctx), err).Warn("background task failed")
+	}
+}
+
+// detached запускает задачи, которые переживают запустивший их запрос или
+// проход: отмена ctx их не прерывает, а значения ctx (логгер, актор) сохраняются.
+type detached struct {
+	wg sync.WaitGroup
+}
+
+func (d *detached) start(ctx context.Context, fn func(ctx context.Context)) {
+	ctx = context.WithoutCancel(ctx)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		fn(ctx)
+	}()
+}
+
+// wait дожидается завершения запущенных задач.
+func (d *detached) wait() {
+	d.wg.Wait()
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"gopr/internal/domain"
+)
+
+var _ EventSink = &EventBus{}
+
+// EventBus раздаёт события из outbox подписчикам внутри процесса. Publish не
+// блокируется: подписчик, чей буфер переполнен, отключается — его канал
+// закрывается, и он сам решает, переподписаться ли и догнать пропущенное.
+type EventBus struct {
+	mu   sync.Mutex
+	subs map[*busSub]struct{}
+}
+
+type busSub struct {
+	ch chan *domain.Event
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[*busSub]struct{})}
+}
+
+func (b *EventBus) Name() string { return "bus" }
+
+// Subscribe возвращает канал событий с буфером buffer и функцию отписки,
+// которую можно вызывать повторно.
+func (b *EventBus) Subscribe(buffer int) (<-chan *domain.Event, func()) {
+	sub := &busSub{ch: make(chan *domain.Event, buffer)}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub.ch, func() { b.drop(sub) }
+}
+
+func (b *EventBus) Publish(_ context.Context, event *domain.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			delete(b.subs, sub)
+			close(sub.ch)
+		}
+	}
+	return nil
+}
+
+func (b *EventBus) drop(sub *busSub) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[sub]; ok {
+		delete(b.subs, sub)
+		close(sub.ch)
+	}
+}
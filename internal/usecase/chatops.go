@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+var ErrAwayDate = domain.NewError(domain.ErrCodeBadRequest, "return date must be a future date like 2026-11-01")
+
+const (
+	// chatReviews — сколько самых старых ревью показывает команда reviews.
+	chatReviews = 20
+	// chatDate — формат даты возвращения в команде away.
+	chatDate = "2006-01-02"
+	// chatUsersBatch — сколько пользователей читается за запрос при поиске по имени.
+	chatUsersBatch = 100
+)
+
+// chatEscape экранирует символы, которые Slack считает разметкой ссылок и упоминаний.
+var chatEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// ChatOps выполняет slash-команды чата от имени их автора: показывает ревью,
+// переназначает ревьюверов и отмечает отсутствие.
+type ChatOps struct {
+	users      *User
+	prCase     *PullRequest
+	identities *Identity
+	userRepo   repo.User
+	now        func() time.Time
+}
+
+// NewChatOps создаёт usecase slash-команд; автор команды и упомянутые
+// пользователи сопоставляются с пользователями gopr через identities.
+func NewChatOps(users *User, prCase *PullRequest, identities *Identity, userRepo repo.User) *ChatOps {
+	return &ChatOps{
+		users:      users,
+		prCase:     prCase,
+		identities: identities,
+		userRepo:   userRepo,
+		now:        time.Now,
+	}
+}
+
+// SetClock подменяет текущее время для возраста ревью и дат возвращения.
+func (c *ChatOps) SetClock(now func() time.Time) {
+	c.now = now
+}
+
+// Handle выполняет команду. Ошибки, понятные автору команды, — доменные:
+// их текст стоит показать ему в ответ.
+func (c *ChatOps) Handle(ctx context.Context, cmd *domain.SlashCommand) (*domain.SlashReply, error) {
+	args := strings.Fields(cmd.Text)
+	if len(args) == 0 || args[0] == "help" {
+		return &domain.SlashReply{Text: chatUsage(cmd.Command)}, nil
+	}
+
+	userID, err := c.identities.UserID(ctx, cmd.Provider, cmd.UserId)
+	var unknown *domain.UnknownIdentityError
+	if errors.As(err, &unknown) {
+		return &domain.SlashReply{
+			Text: fmt.Sprintf("Your %s account %s is not linked to a gopr user. Ask an admin to add it as a %s identity.", cmd.Provider, cmd.UserId, cmd.Provider),
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ctx = domain.WithActor(ctx, userID)
+
+	switch args[0] {
+	case "reviews":
+		return c.reviews(ctx, cmd.Provider, userID, args[1:])
+	case "reassign":
+		if len(args) != 3 {
+			return nil, domain.NewError(domain.ErrCodeBadRequest, "usage: "+cmd.Command+" reassign <pull request id> @reviewer")
+		}
+		return c.reassign(ctx, cmd.Provider, args[1], args[2])
+	case "away":
+		return c.away(ctx, cmd.Command, userID, args[1:])
+	case "back":
+		return c.back(ctx, userID)
+	default:
+		return &domain.SlashReply{Text: fmt.Sprintf("Unknown command %q.\n%s", args[0], chatUsage(cmd.Command))}, nil
+	}
+}
+
+func chatUsage(command string) string {
+	return "Usage:\n" +
+		"• `" + command + " reviews [@user]` — open reviews, oldest first\n" +
+		"• `" + command + " reassign <pull request id> @reviewer` — replace a reviewer\n" +
+		"• `" + command + " away [until YYYY-MM-DD]` — stop getting new reviews\n" +
+		"• `" + command + " back` — get new reviews again"
+}
+
+// reviews показывает открытые ревью автора команды или упомянутого пользователя.
+func (c *ChatOps) reviews(ctx context.Context, provider domain.IdentityProvider, userID string, args []string) (*domain.SlashReply, error) {
+	user, err := c.user(ctx, userID)
+	if len(args) > 0 {
+		user, err = c.mentioned(ctx, provider, args[0])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.users.GetReviews(ctx, &domain.UserReviewsQuery{
+		UserId: user.Id,
+		Status: domain.PullRequestStatusOpen,
+		Order:  domain.SortOldest,
+		Limit:  chatReviews,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.PRs) == 0 {
+		return &domain.SlashReply{Text: fmt.Sprintf("*%s* has no open reviews.", chatEscape.Replace(user.Username))}, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%s* has %d open review(s):", chatEscape.Replace(user.Username), len(res.PRs))
+
+	authors := make(map[string]string)
+	for _, pr := range res.PRs {
+		author, ok := authors[pr.PR.AuthorId]
+		if !ok {
+			author = c.username(ctx, pr.PR.AuthorId)
+			authors[pr.PR.AuthorId] = author
+		}
+
+		age := int64(c.now().Sub(pr.PR.CreatedAt).Seconds())
+		fmt.Fprintf(&b, "\n• *%s* (`%s`) by %s, waiting %s",
+			chatEscape.Replace(pr.PR.Name), pr.PR.Id, chatEscape.Replace(author), formatAge(age))
+	}
+	if res.NextCursor != "" {
+		fmt.Fprintf(&b, "\nOnly the oldest %d are shown.", chatReviews)
+	}
+
+	return &domain.SlashReply{Text: b.String()}, nil
+}
+
+// reassign снимает упомянутого ревьювера с PR; ответ виден всему каналу.
+func (c *ChatOps) reassign(ctx context.Context, provider domain.IdentityProvider, prID, mention string) (*domain.SlashReply, error) {
+	reviewer, err := c.mentioned(ctx, provider, mention)
+	if err != nil {
+		return nil, err
+	}
+
+	res, newID, err := c.prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: prID, OldReviewerId: reviewer.Id})
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("pull request %s not found", prID))
+	}
+	if err != nil && !errors.Is(err, ErrNoCandidate) {
+		return nil, err
+	}
+
+	name := chatEscape.Replace(res.PR.Name)
+	old := chatEscape.Replace(reviewer.Username)
+	if newID == "" {
+		return &domain.SlashReply{
+			Text:      fmt.Sprintf("*%s* is no longer a reviewer of *%s* (`%s`), no active replacement in the team.", old, name, res.PR.Id),
+			InChannel: true,
+		}, nil
+	}
+
+	return &domain.SlashReply{
+		Text:      fmt.Sprintf("*%s* (`%s`): *%s* is replaced by *%s*.", name, res.PR.Id, old, chatEscape.Replace(c.username(ctx, newID))),
+		InChannel: true,
+	}, nil
+}
+
+// away делает автора команды неактивным; с датой он снова станет активным
+// в начале этого дня по UTC.
+func (c *ChatOps) away(ctx context.Context, command, userID string, args []string) (*domain.SlashReply, error) {
+	if len(args) == 0 {
+		if err := c.clearAway(ctx, userID); err != nil {
+			return nil, err
+		}
+		if _, err := c.users.SetActive(ctx, userID, false); err != nil {
+			return nil, err
+		}
+		return &domain.SlashReply{Text: fmt.Sprintf("You are away and won't get new reviews until `%s back`.", command)}, nil
+	}
+
+	if len(args) != 2 || args[0] != "until" {
+		return nil, domain.NewError(domain.ErrCodeBadRequest, "usage: "+command+" away [until YYYY-MM-DD]")
+	}
+
+	until, err := time.Parse(chatDate, args[1])
+	if err != nil || !until.After(c.now()) {
+		return nil, ErrAwayDate
+	}
+
+	if err := c.userRepo.SetAway(ctx, &domain.Away{UserId: userID, Until: until}); err != nil {
+		return nil, fmt.Errorf("failed to save away: %w", err)
+	}
+	if _, err := c.users.SetActive(ctx, userID, false); err != nil {
+		return nil, err
+	}
+
+	return &domain.SlashReply{Text: fmt.Sprintf("You are away until %s (UTC) and won't get new reviews until then.", until.Format(chatDate))}, nil
+}
+
+func (c *ChatOps) back(ctx context.Context, userID string) (*domain.SlashReply, error) {
+	if err := c.clearAway(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := c.users.SetActive(ctx, userID, true); err != nil {
+		return nil, err
+	}
+
+	return &domain.SlashReply{Text: "Welcome back, you will get new reviews again."}, nil
+}
+
+func (c *ChatOps) clearAway(ctx context.Context, userID string) error {
+	err := c.userRepo.DeleteAway(ctx, userID)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return fmt.Errorf("failed to clear away: %w", err)
+	}
+	return nil
+}
+
+// ReturnAway снова делает активными пользователей, чьё отсутствие закончилось,
+// и возвращает их число.
+func (c *ChatOps) ReturnAway(ctx context.Context) (int, error) {
+	returning, err := c.userRepo.ListReturning(ctx, c.now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list returning users: %w", err)
+	}
+
+	for i, away := range returning {
+		_, err := c.users.SetActive(ctx, away.UserId, true)
+		if err != nil && !errors.Is(err, repo.ErrNotFound) {
+			return i, err
+		}
+		if err := c.clearAway(ctx, away.UserId); err != nil {
+			return i, err
+		}
+		slogx.Info(ctx, "user is back from away", "user_id", away.UserId)
+	}
+
+	return len(returning), nil
+}
+
+// RunReturns возвращает отсутствующих пользователей раз в interval, пока не отменён ctx.
+func (c *ChatOps) RunReturns(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func(ctx context.Context) error {
+		if _, err := c.ReturnAway(ctx); err != nil {
+			return fmt.Errorf("failed to return away users: %w", err)
+		}
+		return nil
+	})
+}
+
+// mentioned находит упомянутого пользователя: <@U024BE7LH|bob> — по учётной
+// записи в чате, @bob — по id или имени пользователя gopr.
+func (c *ChatOps) mentioned(ctx context.Context, provider domain.IdentityProvider, mention string) (*domain.User, error) {
+	if ref, ok := strings.CutPrefix(mention, "<@"); ok {
+		login, _, _ := strings.Cut(strings.TrimSuffix(ref, ">"), "|")
+		userID, err := c.identities.UserID(ctx, provider, login)
+		if err != nil {
+			return nil, err
+		}
+		return c.user(ctx, userID)
+	}
+
+	name := strings.TrimPrefix(mention, "@")
+	user, err := c.userRepo.GetByID(ctx, name)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	filter := &domain.UserFilter{UsernamePrefix: name, Limit: chatUsersBatch}
+	for {
+		users, err := c.userRepo.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		for _, u := range users {
+			if strings.EqualFold(u.Username, name) {
+				return u, nil
+			}
+		}
+		if len(users) < filter.Limit {
+			return nil, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", mention))
+		}
+
+		last := users[len(users)-1]
+		filter.After = &domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+}
+
+func (c *ChatOps) user(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return nil, domain.NewError(domain.ErrCodeNotFound, fmt.Sprintf("user %s not found", userID))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	return user, nil
+}
+
+// username возвращает имя пользователя или его id, если пользователь не загрузился.
+func (c *ChatOps) username(ctx context.Context, userID string) string {
+	user, err := c.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+type chatEnv struct {
+	db     *memory.DB
+	users  *usecase.User
+	prCase *usecase.PullRequest
+	chat   *usecase.ChatOps
+}
+
+// setupChatOps заводит команду alice, bob, carol и dave; в Slack bob — U01,
+// carol — U02 из конфига, у alice и dave учётных записей Slack нет. На PR
+// назначается reviewers ревьюверов.
+func setupChatOps(t *testing.T, reviewers int) *chatEnv {
+	t.Helper()
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+	identityRepo := memory.NewIdentityRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+			{UserID: "u3", Username: "carol", IsActive: true},
+			{UserID: "u4", Username: "dave", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	identities := usecase.NewIdentity(identityRepo, userRepo, map[domain.IdentityProvider]map[string]string{
+		domain.IdentitySlack: {"U02": "u3"},
+	})
+	_, err = identities.Add(ctx, &domain.IdentityAddInput{UserID: "u2", Provider: domain.IdentitySlack, Login: "U01"})
+	require.NoError(t, err)
+
+	users := usecase.NewUser(userRepo, teamRepo, prRepo, nil)
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, reviewers)
+
+	return &chatEnv{
+		db:     db,
+		users:  users,
+		prCase: prCase,
+		chat:   usecase.NewChatOps(users, prCase, identities, userRepo),
+	}
+}
+
+func (e *chatEnv) run(t *testing.T, slackID, text string) *domain.SlashReply {
+	t.Helper()
+
+	reply, err := e.chat.Handle(context.Background(), &domain.SlashCommand{
+		Provider: domain.IdentitySlack,
+		Command:  "/gopr",
+		UserId:   slackID,
+		Text:     text,
+	})
+	require.NoError(t, err)
+	return reply
+}
+
+func (e *chatEnv) fail(t *testing.T, slackID, text string) error {
+	t.Helper()
+
+	_, err := e.chat.Handle(context.Background(), &domain.SlashCommand{
+		Provider: domain.IdentitySlack,
+		Command:  "/gopr",
+		UserId:   slackID,
+		Text:     text,
+	})
+	require.Error(t, err)
+	return err
+}
+
+func TestChatOps_Usage(t *testing.T) {
+	e := setupChatOps(t, 2)
+
+	require.Contains(t, e.run(t, "U99", "").Text, "`/gopr reassign <pull request id> @reviewer`")
+	require.Contains(t, e.run(t, "U01", "dance").Text, `Unknown command "dance".`)
+	require.Equal(t, "Your slack account U99 is not linked to a gopr user. Ask an admin to add it as a slack identity.", e.run(t, "U99", "reviews").Text)
+}
+
+func TestChatOps_Reviews(t *testing.T) {
+	ctx := context.Background()
+	e := setupChatOps(t, 3)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	require.Equal(t, "*bob* has no open reviews.", e.run(t, "u01", "reviews").Text)
+
+	e.db.SetClock(func() time.Time { return start })
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix <script>"})
+	require.NoError(t, err)
+	e.db.SetClock(func() time.Time { return start.Add(20 * time.Hour) })
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-2", AuthorId: "u4", Name: "Feature"})
+	require.NoError(t, err)
+	e.chat.SetClock(func() time.Time { return start.Add(26*time.Hour + 30*time.Minute) })
+
+	// на PR назначаются все участники команды, кроме автора
+	reply := e.run(t, "U02", "reviews @alice")
+	require.Equal(t, "*alice* has 1 open review(s):\n• *Feature* (`pr-2`) by dave, waiting 6h 30m", reply.Text)
+	require.False(t, reply.InChannel)
+
+	reply = e.run(t, "U02", "reviews <@U01|bob>")
+	require.Contains(t, reply.Text, "• *Fix &lt;script&gt;* (`pr-1`) by alice, waiting 1d 2h")
+
+	require.ErrorContains(t, e.fail(t, "U01", "reviews @nobody"), "user @nobody not found")
+	var unknown *domain.UnknownIdentityError
+	require.ErrorAs(t, e.fail(t, "U01", "reviews <@U77>"), &unknown)
+}
+
+func TestChatOps_Reassign(t *testing.T) {
+	ctx := context.Background()
+	e := setupChatOps(t, 2)
+
+	pr, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "PR-123", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+	// из трёх кандидатов назначены двое, замена — оставшийся
+	reviewers := map[string]string{"u2": "bob", "u3": "carol", "u4": "dave"}
+	oldID := pr.Reviewers[0]
+	delete(reviewers, pr.Reviewers[0])
+	delete(reviewers, pr.Reviewers[1])
+	var newName string
+	for _, name := range reviewers {
+		newName = name
+	}
+	oldName := map[string]string{"u2": "bob", "u3": "carol", "u4": "dave"}[oldID]
+
+	reply := e.run(t, "U01", "reassign PR-123 @"+oldName)
+	require.True(t, reply.InChannel)
+	require.Equal(t, "*Fix* (`PR-123`): *"+oldName+"* is replaced by *"+newName+"*.", reply.Text)
+
+	got, err := e.prCase.Get(ctx, "PR-123")
+	require.NoError(t, err)
+	require.NotContains(t, got.Reviewers, oldID)
+
+	require.ErrorIs(t, e.fail(t, "U01", "reassign PR-123 @"+oldName), usecase.ErrNotAssigned)
+	require.ErrorContains(t, e.fail(t, "U01", "reassign PR-404 @bob"), "pull request PR-404 not found")
+	require.ErrorContains(t, e.fail(t, "U01", "reassign PR-123"), "usage: /gopr reassign")
+
+	// заменить некем: снятый ревьювер неактивен, остальные уже ревьюят PR
+	_, err = e.users.SetActive(ctx, oldID, false)
+	require.NoError(t, err)
+	current := got.Reviewers[0]
+	reply = e.run(t, "U01", "reassign PR-123 "+current)
+	require.Contains(t, reply.Text, "is no longer a reviewer of *Fix* (`PR-123`), no active replacement in the team.")
+}
+
+func TestChatOps_Away(t *testing.T) {
+	ctx := context.Background()
+	e := setupChatOps(t, 2)
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	e.chat.SetClock(func() time.Time { return now })
+
+	active := func(userID string) bool {
+		t.Helper()
+
+		user, err := e.users.Get(ctx, userID)
+		require.NoError(t, err)
+		return user.User.IsActive
+	}
+
+	require.ErrorIs(t, e.fail(t, "U01", "away until 2026-10-19"), usecase.ErrAwayDate)
+	require.ErrorIs(t, e.fail(t, "U01", "away until 11/01/2026"), usecase.ErrAwayDate)
+	require.ErrorContains(t, e.fail(t, "U01", "away for a while"), "usage: /gopr away [until YYYY-MM-DD]")
+
+	require.Equal(t, "You are away until 2026-11-01 (UTC) and won't get new reviews until then.", e.run(t, "U01", "away until 2026-11-01").Text)
+	require.False(t, active("u2"))
+	e.run(t, "U02", "away")
+	require.False(t, active("u3"))
+
+	returned, err := e.chat.ReturnAway(ctx)
+	require.NoError(t, err)
+	require.Zero(t, returned)
+
+	now = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	returned, err = e.chat.ReturnAway(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, returned)
+	require.True(t, active("u2"))
+	// без даты пользователь возвращается только сам
+	require.False(t, active("u3"))
+
+	returned, err = e.chat.ReturnAway(ctx)
+	require.NoError(t, err)
+	require.Zero(t, returned)
+
+	require.Equal(t, "Welcome back, you will get new reviews again.", e.run(t, "U02", "back").Text)
+	require.True(t, active("u3"))
+}
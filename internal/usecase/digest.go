@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+var ErrInvalidDigest = domain.NewError(domain.ErrCodeBadRequest, "invalid digest settings")
+
+const (
+	defaultDigestTimezone = "UTC"
+	defaultDigestHour     = 9
+	// digestBatch — сколько PR на ревью читается за один запрос.
+	digestBatch = 100
+)
+
+// GetDigest возвращает настройки сводки пользователя; без настроек сводка
+// выключена и уходила бы в 9 утра по UTC.
+func (n *Notification) GetDigest(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	if _, err := n.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	return n.digestSettings(ctx, userID)
+}
+
+func (n *Notification) digestSettings(ctx context.Context, userID string) (*domain.DigestSettings, error) {
+	settings, err := n.notificationRepo.GetDigest(ctx, userID)
+	if errors.Is(err, repo.ErrNotFound) {
+		return &domain.DigestSettings{UserId: userID, Timezone: defaultDigestTimezone, Hour: defaultDigestHour}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load digest settings: %w", err)
+	}
+	return settings, nil
+}
+
+func (n *Notification) SetDigest(ctx context.Context, input *domain.DigestSettingsInput) (*domain.DigestSettings, error) {
+	if _, err := n.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	settings := &domain.DigestSettings{
+		UserId:     input.UserID,
+		Enabled:    input.Enabled,
+		Timezone:   input.Timezone,
+		Hour:       defaultDigestHour,
+		QuietStart: input.QuietStart,
+		QuietEnd:   input.QuietEnd,
+	}
+	if settings.Timezone == "" {
+		settings.Timezone = defaultDigestTimezone
+	}
+	if input.Hour != nil {
+		settings.Hour = *input.Hour
+	}
+
+	if _, err := time.LoadLocation(settings.Timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidDigest, settings.Timezone)
+	}
+	if (settings.QuietStart == nil) != (settings.QuietEnd == nil) {
+		return nil, fmt.Errorf("%w: quiet hours need both start and end", ErrInvalidDigest)
+	}
+	if settings.QuietStart != nil && *settings.QuietStart == *settings.QuietEnd {
+		return nil, fmt.Errorf("%w: quiet hours start and end must differ", ErrInvalidDigest)
+	}
+	if settings.Quiet(settings.Hour) {
+		return nil, fmt.Errorf("%w: digest hour falls into quiet hours", ErrInvalidDigest)
+	}
+
+	if err := n.notificationRepo.SetDigest(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to save digest settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// PreviewDigest отрисовывает сводку пользователя на текущий момент, не
+// отправляя её и независимо от того, включена ли она.
+func (n *Notification) PreviewDigest(ctx context.Context, userID string) (*domain.Digest, error) {
+	user, err := n.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	settings, err := n.digestSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timezone: %w", err)
+	}
+
+	return n.buildDigest(ctx, user, loc, n.now())
+}
+
+// SendDigests отправляет сводки, которым пора уйти, и возвращает их число.
+// Пустая сводка не отправляется, но считается отправленной за этот день.
+func (n *Notification) SendDigests(ctx context.Context) (int, error) {
+	all, err := n.notificationRepo.ListEnabledDigests(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list digests: %w", err)
+	}
+
+	now := n.now()
+	sent := 0
+	var errs []error
+	for _, settings := range all {
+		ok, err := n.sendDigest(ctx, settings, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest for %s: %w", settings.UserId, err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+func (n *Notification) sendDigest(ctx context.Context, settings *domain.DigestSettings, now time.Time) (bool, error) {
+	loc, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return false, fmt.Errorf("failed to load timezone: %w", err)
+	}
+	if !settings.Due(now, loc) {
+		return false, nil
+	}
+
+	user, err := n.userRepo.GetByID(ctx, settings.UserId)
+	if err != nil {
+		return false, fmt.Errorf("failed to load user: %w", err)
+	}
+	digest, err := n.buildDigest(ctx, user, loc, now)
+	if err != nil {
+		return false, err
+	}
+
+	// отметка до отправки: из нескольких инстансов сводку отправит один
+	ok, err := n.notificationRepo.MarkDigestSent(ctx, settings.UserId, settings.LastSentAt, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	if !ok || len(digest.Items) == 0 {
+		return false, nil
+	}
+
+	prefs, err := n.notificationRepo.ListPreferences(ctx, settings.UserId)
+	if err != nil {
+		return false, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	n.tasks.start(ctx, func(ctx context.Context) {
+		log := slogx.FromCtx(ctx).With("user_id", user.Id, "kind", domain.NotificationDigest)
+		n.send(ctx, log, prefs, &domain.Notification{
+			Kind:     domain.NotificationDigest,
+			UserId:   user.Id,
+			Username: user.Username,
+			Subject:  digest.Subject,
+			Text:     digest.Text,
+		})
+	})
+
+	return true, nil
+}
+
+// buildDigest собирает открытые PR на ревью у пользователя, старые первыми, и
+// отмечает ждущие дольше SLA.
+func (n *Notification) buildDigest(ctx context.Context, user *domain.User, loc *time.Location, now time.Time) (*domain.Digest, error) {
+	digest := &domain.Digest{
+		UserId:      user.Id,
+		Username:    user.Username,
+		Timezone:    loc.String(),
+		GeneratedAt: now.In(loc),
+		Items:       []*domain.DigestItem{},
+	}
+
+	authors := make(map[string]string)
+	filter := &domain.ReviewerPRFilter{
+		ReviewerId: user.Id,
+		Status:     domain.PullRequestStatusOpen,
+		Order:      domain.SortOldest,
+		Limit:      digestBatch,
+	}
+	for {
+		prs, err := n.prRepo.ListByReviewer(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list reviews: %w", err)
+		}
+
+		for _, pr := range prs {
+			author, ok := authors[pr.AuthorId]
+			if !ok {
+				author = n.username(ctx, pr.AuthorId)
+				authors[pr.AuthorId] = author
+			}
+
+			age := now.Sub(pr.CreatedAt)
+			item := &domain.DigestItem{
+				PullRequestId: pr.Id,
+				Name:          pr.Name,
+				AuthorId:      pr.AuthorId,
+				Author:        author,
+				TeamName:      pr.TeamName,
+				CreatedAt:     pr.CreatedAt,
+				AgeSeconds:    int64(age / time.Second),
+				Overdue:       n.sla > 0 && age >= n.sla,
+			}
+			if item.Overdue {
+				digest.Overdue++
+			}
+			digest.Items = append(digest.Items, item)
+		}
+
+		if len(prs) < digestBatch {
+			break
+		}
+		last := prs[len(prs)-1]
+		filter.After = &domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+
+	var err error
+	if digest.Subject, digest.Text, err = render(domain.NotificationDigest, digest); err != nil {
+		return nil, fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	return digest, nil
+}
+
+// RunDigests проверяет каждые interval, кому пора отправить сводку, пока не
+// отменён ctx.
+func (n *Notification) RunDigests(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func(ctx context.Context) error {
+		if _, err := n.SendDigests(ctx); err != nil {
+			return fmt.Errorf("failed to send digests: %w", err)
+		}
+		return nil
+	})
+}
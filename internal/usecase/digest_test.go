@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestDigest_Settings(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 0)
+
+	got, err := e.notify.GetDigest(ctx, "u2")
+	require.NoError(t, err)
+	require.False(t, got.Enabled)
+	require.Equal(t, "UTC", got.Timezone)
+	require.Equal(t, 9, got.Hour)
+
+	for _, input := range []*domain.DigestSettingsInput{
+		{UserID: "u2", Timezone: "Mars/Olympus"},
+		{UserID: "u2", QuietStart: ptr(22)},
+		{UserID: "u2", QuietStart: ptr(8), QuietEnd: ptr(8)},
+		// сводка в тихие часы никогда бы не ушла
+		{UserID: "u2", Hour: ptr(23), QuietStart: ptr(22), QuietEnd: ptr(7)},
+	} {
+		_, err := e.notify.SetDigest(ctx, input)
+		require.ErrorIs(t, err, usecase.ErrInvalidDigest)
+	}
+
+	_, err = e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "missing"})
+	require.Error(t, err)
+
+	set, err := e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "u2", Enabled: true, Timezone: "Asia/Tokyo", Hour: ptr(7), QuietStart: ptr(22), QuietEnd: ptr(7)})
+	require.NoError(t, err)
+	require.Equal(t, 7, set.Hour)
+
+	got, err = e.notify.GetDigest(ctx, "u2")
+	require.NoError(t, err)
+	require.True(t, got.Enabled)
+	require.Equal(t, "Asia/Tokyo", got.Timezone)
+	require.Equal(t, 22, *got.QuietStart)
+}
+
+// setupDigest заводит bob ревьювером двух PR: "Old" старше суток и "New".
+func setupDigest(t *testing.T, start time.Time) *notificationEnv {
+	t.Helper()
+
+	ctx := context.Background()
+	e := setupNotification(t, 24*time.Hour)
+	e.db.SetClock(func() time.Time { return start })
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-old", AuthorId: "u1", Name: "Old"})
+	require.NoError(t, err)
+	e.db.SetClock(func() time.Time { return start.Add(20 * time.Hour) })
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-new", AuthorId: "u1", Name: "New"})
+	require.NoError(t, err)
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-merged", AuthorId: "u1", Name: "Merged"})
+	require.NoError(t, err)
+	_, err = e.prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-merged"})
+	require.NoError(t, err)
+
+	return e
+}
+
+func TestDigest_Preview(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e := setupDigest(t, start)
+	e.notify.SetClock(func() time.Time { return start.Add(26*time.Hour + 30*time.Minute) })
+
+	_, err := e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "u2", Timezone: "Europe/Moscow"})
+	require.NoError(t, err)
+
+	digest, err := e.notify.PreviewDigest(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, "Europe/Moscow", digest.Timezone)
+	require.Equal(t, 14, digest.GeneratedAt.Hour())
+	require.Len(t, digest.Items, 2)
+	require.Equal(t, "pr-old", digest.Items[0].PullRequestId)
+	require.True(t, digest.Items[0].Overdue)
+	require.Equal(t, "alice", digest.Items[0].Author)
+	require.Equal(t, "pr-new", digest.Items[1].PullRequestId)
+	require.False(t, digest.Items[1].Overdue)
+	require.Equal(t, 1, digest.Overdue)
+
+	require.Equal(t, "Pending reviews: 2, 1 overdue", digest.Subject)
+	require.Equal(t, `bob, 2 pull requests are waiting for your review:
+- [overdue] "Old" (pr-old) by alice, waiting 1d 2h
+- "New" (pr-new) by alice, waiting 6h 30m`, digest.Text)
+
+	// предпросмотр ничего не отправляет
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+	_, err = e.notify.PreviewDigest(ctx, "u2")
+	require.NoError(t, err)
+	e.notify.Wait()
+	require.Empty(t, e.slack.received())
+
+	digest, err = e.notify.PreviewDigest(ctx, "u1")
+	require.NoError(t, err)
+	require.Empty(t, digest.Items)
+	require.Equal(t, "alice, no pull requests are waiting for your review.", digest.Text)
+}
+
+func TestDigest_SendRespectsTimezoneAndQuietHours(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	e := setupDigest(t, start)
+
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+	e.prefer(t, "u3", domain.NotificationSlack, "")
+	// bob в Москве (UTC+3) хочет сводку в 8 утра, но с 7 до 10 у него тихие часы
+	_, err := e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "u2", Enabled: true, Timezone: "Europe/Moscow", Hour: ptr(8), QuietStart: ptr(7), QuietEnd: ptr(10)})
+	require.ErrorIs(t, err, usecase.ErrInvalidDigest)
+	_, err = e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "u2", Enabled: true, Timezone: "Europe/Moscow", Hour: ptr(8), QuietStart: ptr(12), QuietEnd: ptr(14)})
+	require.NoError(t, err)
+	// carol не включала сводку
+	_, err = e.notify.SetDigest(ctx, &domain.DigestSettingsInput{UserID: "u3", Timezone: "Europe/Moscow", Hour: ptr(8)})
+	require.NoError(t, err)
+
+	send := func(at time.Time) int {
+		t.Helper()
+
+		e.notify.SetClock(func() time.Time { return at })
+		sent, err := e.notify.SendDigests(ctx)
+		require.NoError(t, err)
+		e.notify.Wait()
+		return sent
+	}
+
+	day := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	require.Zero(t, send(day.Add(4*time.Hour)), "07:00 in Moscow")
+	require.Equal(t, 1, send(day.Add(5*time.Hour)), "08:00 in Moscow")
+	require.Zero(t, send(day.Add(6*time.Hour)), "already sent today")
+	require.Equal(t, []string{"u2 digest "}, e.slack.received())
+
+	// на следующий день сводка ждёт конца тихих часов
+	next := day.Add(24 * time.Hour)
+	require.Zero(t, send(next.Add(9*time.Hour)), "12:00 in Moscow is quiet")
+	require.Equal(t, 1, send(next.Add(11*time.Hour)), "14:00 in Moscow")
+
+	e.slack.mu.Lock()
+	defer e.slack.mu.Unlock()
+	require.Len(t, e.slack.sent, 2)
+	require.Equal(t, "Pending reviews: 2, 1 overdue", e.slack.sent[0].Subject)
+	require.Empty(t, e.slack.sent[0].PullRequestId)
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+const (
+	overloadFactor  = 1.5
+	underloadFactor = 0.5
+)
+
+type Fairness struct {
+	statsRepo  repo.Stats
+	teamRepo   repo.Team
+	prRepo     repo.PullRequest
+	strategies []Strategy
+	reviewers  int
+}
+
+// NewFairness создаёт usecase отчёта; reviewers — число ревьюверов на PR при
+// прогоне стратегий, <= 0 — два.
+func NewFairness(statsRepo repo.Stats, teamRepo repo.Team, prRepo repo.PullRequest, reviewers int) *Fairness {
+	if reviewers <= 0 {
+		reviewers = defaultReviewers
+	}
+
+	strategies := make([]Strategy, 0, len(StrategyNames))
+	for _, name := range StrategyNames {
+		s, _ := NewStrategy(name, nil)
+		strategies = append(strategies, s)
+	}
+
+	return &Fairness{
+		statsRepo:  statsRepo,
+		teamRepo:   teamRepo,
+		prRepo:     prRepo,
+		strategies: strategies,
+		reviewers:  reviewers,
+	}
+}
+
+// Report сравнивает фактическое распределение назначений между активными
+// участниками команды с тем, что дала бы каждая стратегия на тех же PR окна.
+func (f *Fairness) Report(ctx context.Context, q *domain.FairnessQuery) (*domain.FairnessReport, error) {
+	from, to, err := statsRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := f.teamRepo.GetByName(ctx, q.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team: %w", err)
+	}
+
+	all, err := f.teamRepo.ListMembers(ctx, team.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team users: %w", err)
+	}
+
+	members := make([]*domain.TeamMember, 0, len(all))
+	for _, m := range all {
+		if m.User.IsActive && m.JoinedAt.Before(to) {
+			members = append(members, m)
+		}
+	}
+
+	stats, err := f.statsRepo.UserStats(ctx, &domain.StatsFilter{From: from, To: to, TeamId: team.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user stats: %w", err)
+	}
+
+	actual := make(map[string]int, len(stats))
+	for _, s := range stats {
+		actual[s.UserId] = s.Assigned
+	}
+
+	prs, err := f.prRepo.List(ctx, &domain.PullRequestFilter{
+		TeamName:    team.Name,
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Order:       domain.SortOldest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+
+	report := &domain.FairnessReport{
+		Team:         team,
+		From:         from,
+		To:           to,
+		PullRequests: len(prs),
+		Actual:       fairnessResult("actual", members, actual, from, to),
+		Strategies:   make([]*domain.FairnessResult, 0, len(f.strategies)),
+	}
+
+	for _, s := range f.strategies {
+		counts := ReplayAssignments(s, members, prs, f.reviewers)
+		report.Strategies = append(report.Strategies, fairnessResult(s.Name(), members, counts, from, to))
+	}
+
+	return report, nil
+}
+
+// ReplayAssignments прогоняет PR в порядке создания через стратегию и
+// возвращает число назначений на каждого участника. Нагрузкой кандидата
+// считаются его назначения на PR, не смерженные к моменту создания очередного.
+func ReplayAssignments(s Strategy, members []*domain.TeamMember, prs []*domain.PullRequest, reviewers int) map[string]int {
+	type assignment struct {
+		reviewerID string
+		mergedAt   *time.Time
+	}
+
+	counts := make(map[string]int, len(members))
+	open := make([]assignment, 0)
+
+	for _, pr := range prs {
+		load := make(map[string]int, len(members))
+		kept := open[:0]
+		for _, a := range open {
+			if a.mergedAt != nil && !a.mergedAt.After(pr.CreatedAt) {
+				continue
+			}
+			kept = append(kept, a)
+			load[a.reviewerID]++
+		}
+		open = kept
+
+		candidates := make([]*domain.User, 0, len(members))
+		for _, m := range members {
+			if m.User.Id != pr.AuthorId && !m.JoinedAt.After(pr.CreatedAt) {
+				candidates = append(candidates, m.User)
+			}
+		}
+
+		for _, u := range s.Pick(candidates, load, reviewers) {
+			counts[u.Id]++
+			open = append(open, assignment{reviewerID: u.Id, mergedAt: pr.MergedAt})
+		}
+	}
+
+	return counts
+}
+
+func fairnessResult(strategy string, members []*domain.TeamMember, counts map[string]int, from, to time.Time) *domain.FairnessResult {
+	loads := MemberLoads(members, counts, from, to)
+
+	return &domain.FairnessResult{
+		Strategy: strategy,
+		Metrics:  EvaluateFairness(loads),
+		Members:  loads,
+	}
+}
+
+// MemberLoads переводит число назначений участников в назначения в день за
+// ту часть окна [from, to), что участник состоял в команде.
+func MemberLoads(members []*domain.TeamMember, counts map[string]int, from, to time.Time) []*domain.MemberLoad {
+	loads := make([]*domain.MemberLoad, 0, len(members))
+	for _, m := range members {
+		start := from
+		if m.JoinedAt.After(start) {
+			start = m.JoinedAt
+		}
+		// меньше суток в окне считаем за сутки, чтобы новичок не получал огромный rate
+		days := math.Max(to.Sub(start).Hours()/24, 1)
+
+		loads = append(loads, &domain.MemberLoad{
+			UserId:      m.User.Id,
+			Username:    m.User.Username,
+			Assignments: counts[m.User.Id],
+			DaysActive:  days,
+			Rate:        float64(counts[m.User.Id]) / days,
+		})
+	}
+
+	return loads
+}
+
+// EvaluateFairness считает неравномерность Rate участников и отмечает тех, у
+// кого он выше среднего в 1.5 раза или ниже в 2 раза.
+func EvaluateFairness(loads []*domain.MemberLoad) domain.FairnessMetrics {
+	var m domain.FairnessMetrics
+	if len(loads) == 0 {
+		return m
+	}
+
+	n := float64(len(loads))
+	rates := make([]float64, 0, len(loads))
+	sum := 0.0
+	for _, l := range loads {
+		rates = append(rates, l.Rate)
+		sum += l.Rate
+	}
+	sort.Float64s(rates)
+
+	m.Mean = sum / n
+
+	variance := 0.0
+	for _, r := range rates {
+		variance += (r - m.Mean) * (r - m.Mean)
+	}
+	m.StdDev = math.Sqrt(variance / n)
+
+	if sum > 0 {
+		// G = 2·Σ i·x(i) / (n·Σx) − (n+1)/n по возрастающей выборке
+		weighted := 0.0
+		for i, r := range rates {
+			weighted += float64(i+1) * r
+		}
+		m.Gini = 2*weighted/(n*sum) - (n+1)/n
+	}
+
+	lo, hi := rates[0], rates[len(rates)-1]
+	switch {
+	case hi == 0:
+		m.MaxMinRatio = 1
+	case lo == 0:
+		m.MaxMinRatio = math.Inf(1)
+	default:
+		m.MaxMinRatio = hi / lo
+	}
+
+	for _, l := range loads {
+		switch {
+		case m.Mean == 0:
+		case l.Rate > m.Mean*overloadFactor:
+			l.Flag = domain.FairnessOverloaded
+		case l.Rate < m.Mean*underloadFactor:
+			l.Flag = domain.FairnessUnderloaded
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,79 @@
+package usecase_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/usecase"
+)
+
+func TestEvaluateFairness(t *testing.T) {
+	even := usecase.EvaluateFairness([]*domain.MemberLoad{
+		{UserId: "a", Rate: 2},
+		{UserId: "b", Rate: 2},
+	})
+	require.InDelta(t, 0, even.Gini, 1e-9)
+	require.InDelta(t, 0, even.StdDev, 1e-9)
+	require.InDelta(t, 1, even.MaxMinRatio, 1e-9)
+
+	loads := []*domain.MemberLoad{
+		{UserId: "a", Rate: 0},
+		{UserId: "b", Rate: 1},
+		{UserId: "c", Rate: 1},
+		{UserId: "d", Rate: 2},
+	}
+	skewed := usecase.EvaluateFairness(loads)
+	require.InDelta(t, 1, skewed.Mean, 1e-9)
+	require.InDelta(t, math.Sqrt(0.5), skewed.StdDev, 1e-9)
+	require.InDelta(t, 0.375, skewed.Gini, 1e-9)
+	require.True(t, math.IsInf(skewed.MaxMinRatio, 1))
+	require.Equal(t, domain.FairnessUnderloaded, loads[0].Flag)
+	require.Empty(t, loads[1].Flag)
+	require.Equal(t, domain.FairnessOverloaded, loads[3].Flag)
+}
+
+func TestReplayAssignments(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	members := make([]*domain.TeamMember, 0, 4)
+	for _, id := range []string{"a", "b", "c", "d"} {
+		members = append(members, &domain.TeamMember{User: &domain.User{Id: id, IsActive: true}, JoinedAt: start})
+	}
+	late := &domain.TeamMember{User: &domain.User{Id: "late", IsActive: true}, JoinedAt: start.Add(1000 * time.Hour)}
+	members = append(members, late)
+
+	prs := make([]*domain.PullRequest, 0, 40)
+	for i := range 40 {
+		prs = append(prs, &domain.PullRequest{
+			Id:        string(rune('A' + i)),
+			AuthorId:  "a",
+			CreatedAt: start.Add(time.Duration(i) * time.Hour),
+		})
+	}
+
+	for _, name := range usecase.StrategyNames {
+		s, err := usecase.NewStrategy(name, rand.New(rand.NewSource(1)))
+		require.NoError(t, err)
+
+		counts := usecase.ReplayAssignments(s, members, prs, 2)
+		require.Zero(t, counts["a"], name)
+		require.Zero(t, counts["late"], name)
+		require.Equal(t, 80, counts["b"]+counts["c"]+counts["d"], name)
+	}
+
+	leastLoaded, err := usecase.NewStrategy(usecase.StrategyLeastLoaded, rand.New(rand.NewSource(1)))
+	require.NoError(t, err)
+
+	counts := usecase.ReplayAssignments(leastLoaded, members, prs, 2)
+	for _, id := range []string{"b", "c", "d"} {
+		require.InDelta(t, 80.0/3, counts[id], 1, id)
+	}
+
+	_, err = usecase.NewStrategy("round_robin", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+const (
+	// defaultFeedBatch — сколько опубликованных событий читается за один запрос.
+	defaultFeedBatch = 100
+	// watchBuffer — сколько событий может ждать читателя потока, прежде чем он
+	// будет отключён как отставший.
+	watchBuffer = 256
+)
+
+// EventFeed раздаёт события, опубликованные из outbox, потокам внутри процесса.
+// Источник — таблица outbox, а не relay: события видят все инстансы, даже если
+// relay работает на другом. Run подхватывает новые публикации по сигналу
+// хранилища или по таймеру.
+type EventFeed struct {
+	outbox repo.Outbox
+	bus    *EventBus
+	batch  int
+
+	mu   sync.Mutex
+	last int64
+}
+
+// NewEventFeed создаёт раздачу событий; batch <= 0 — сто событий за запрос.
+func NewEventFeed(outbox repo.Outbox, batch int) *EventFeed {
+	if batch <= 0 {
+		batch = defaultFeedBatch
+	}
+
+	return &EventFeed{
+		outbox: outbox,
+		bus:    NewEventBus(),
+		batch:  batch,
+	}
+}
+
+// Run раздаёт новые публикации каждые interval и по сигналу wake, пока не
+// отменён ctx; wake может быть nil. Раздача начинается с событий, опубликованных
+// после запуска.
+func (f *EventFeed) Run(ctx context.Context, interval time.Duration, wake <-chan struct{}) {
+	log := slogx.FromCtx(ctx)
+
+	last, err := f.outbox.LastPublishedSeq(ctx)
+	if err != nil && ctx.Err() == nil {
+		slogx.WithErr(log, err).Warn("failed to load last published event")
+	}
+	f.mu.Lock()
+	f.last = last
+	f.mu.Unlock()
+
+	runOn(ctx, interval, wake, func(ctx context.Context) error {
+		if _, err := f.Poll(ctx); err != nil {
+			return fmt.Errorf("failed to poll published events: %w", err)
+		}
+		return nil
+	})
+}
+
+// Poll раздаёт события, опубликованные после предыдущего вызова, и возвращает их число.
+func (f *EventFeed) Poll(ctx context.Context) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	total := 0
+	for {
+		events, err := f.since(ctx, f.last)
+		if err != nil {
+			return total, err
+		}
+
+		for _, event := range events {
+			_ = f.bus.Publish(ctx, event)
+			f.last = event.Seq
+		}
+		total += len(events)
+
+		if len(events) < f.batch {
+			return total, nil
+		}
+	}
+}
+
+// Watch возвращает события под filter: сначала пропущенные с Seq больше after,
+// если он задан, затем новые. Канал закрывается при отмене ctx, при ошибке
+// чтения и если читатель отстал; продолжить можно новым Watch с Seq последнего
+// полученного события.
+func (f *EventFeed) Watch(ctx context.Context, filter *domain.EventFilter, after *int64) <-chan *domain.Event {
+	// подписка до чтения пропущенного: то, что опубликуется во время чтения,
+	// придёт через шину, а повторы отбрасываются по Seq
+	live, unsubscribe := f.bus.Subscribe(watchBuffer)
+	out := make(chan *domain.Event)
+
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		var sent int64
+		send := func(event *domain.Event) bool {
+			if event.Seq <= sent {
+				return true
+			}
+			sent = event.Seq
+			if !filter.Match(event) {
+				return true
+			}
+
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		if after != nil {
+			sent = *after
+			if !f.replay(ctx, sent, send) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-live:
+				if !ok || !send(event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// replay отдаёт в send опубликованные события с Seq больше after.
+func (f *EventFeed) replay(ctx context.Context, after int64, send func(*domain.Event) bool) bool {
+	for {
+		events, err := f.since(ctx, after)
+		if err != nil {
+			if ctx.Err() == nil {
+				slogx.WithErr(slogx.FromCtx(ctx), err).Warn("failed to replay events")
+			}
+			return false
+		}
+
+		for _, event := range events {
+			if !send(event) {
+				return false
+			}
+			after = event.Seq
+		}
+
+		if len(events) < f.batch {
+			return true
+		}
+	}
+}
+
+func (f *EventFeed) since(ctx context.Context, after int64) ([]*domain.Event, error) {
+	msgs, err := f.outbox.ListPublished(ctx, after, f.batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list published events: %w", err)
+	}
+
+	events := make([]*domain.Event, 0, len(msgs))
+	for _, msg := range msgs {
+		event := &domain.Event{}
+		if err := json.Unmarshal(msg.Payload, event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", msg.Id, err)
+		}
+		event.Seq = msg.PublishedSeq
+		events = append(events, event)
+	}
+
+	return events, nil
+}
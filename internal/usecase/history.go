@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+func recordEvent(
+	ctx context.Context,
+	prRepo repo.PullRequest,
+	prID string,
+	typ domain.PullRequestEventType,
+	reviewerID, actor, reason string,
+) error {
+	err := prRepo.AddEvent(ctx, &domain.PullRequestEvent{
+		PullRequestId: prID,
+		Type:          typ,
+		ReviewerId:    reviewerID,
+		Actor:         actor,
+		Reason:        reason,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s event: %w", typ, err)
+	}
+	return nil
+}
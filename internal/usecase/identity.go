@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+var ErrIdentityExists = domain.NewError(domain.ErrCodeAlreadyExists, "identity is already linked")
+
+// Identity ведёт учётные записи пользователей у внешних провайдеров и
+// сопоставляет их с пользователями gopr для интеграций.
+type Identity struct {
+	identityRepo repo.Identity
+	userRepo     repo.User
+	static       map[domain.IdentityProvider]map[string]string
+}
+
+// NewIdentity создаёт usecase учётных записей; static — сопоставления логинов
+// с id пользователей из конфига, они проверяются после записей в хранилище.
+func NewIdentity(identityRepo repo.Identity, userRepo repo.User, static map[domain.IdentityProvider]map[string]string) *Identity {
+	normalized := make(map[domain.IdentityProvider]map[string]string, len(static))
+	for provider, logins := range static {
+		normalized[provider] = make(map[string]string, len(logins))
+		for login, userID := range logins {
+			normalized[provider][domain.NormalizeLogin(login)] = userID
+		}
+	}
+
+	return &Identity{
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		static:       normalized,
+	}
+}
+
+func (i *Identity) Add(ctx context.Context, input *domain.IdentityAddInput) (*domain.Identity, error) {
+	if _, err := i.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	identity := &domain.Identity{
+		UserId:     input.UserID,
+		Provider:   input.Provider,
+		Login:      domain.NormalizeLogin(input.Login),
+		ExternalId: input.ExternalID,
+	}
+
+	if err := i.identityRepo.Create(ctx, identity); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: %w", ErrIdentityExists, err)
+		}
+		return nil, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+func (i *Identity) List(ctx context.Context, userID string) ([]*domain.Identity, error) {
+	if _, err := i.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	identities, err := i.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	return identities, nil
+}
+
+func (i *Identity) Update(ctx context.Context, input *domain.IdentityUpdateInput) (*domain.Identity, error) {
+	login := domain.NormalizeLogin(input.Login)
+
+	if err := i.identityRepo.UpdateExternalID(ctx, input.Provider, login, input.ExternalID); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: %w", ErrIdentityExists, err)
+		}
+		return nil, fmt.Errorf("failed to update identity: %w", err)
+	}
+
+	identity, err := i.identityRepo.GetByLogin(ctx, input.Provider, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// Delete отвязывает учётную запись и возвращает её.
+func (i *Identity) Delete(ctx context.Context, input *domain.IdentityDeleteInput) (*domain.Identity, error) {
+	login := domain.NormalizeLogin(input.Login)
+
+	identity, err := i.identityRepo.GetByLogin(ctx, input.Provider, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	if err := i.identityRepo.Delete(ctx, input.Provider, login); err != nil {
+		return nil, fmt.Errorf("failed to unlink identity: %w", err)
+	}
+
+	return identity, nil
+}
+
+// Lookup возвращает учётную запись по логину или, если логин пуст, по числовому id.
+func (i *Identity) Lookup(ctx context.Context, q *domain.IdentityLookupQuery) (*domain.Identity, error) {
+	if q.Login == "" {
+		identity, err := i.identityRepo.GetByExternalID(ctx, q.Provider, q.ExternalID)
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, &domain.UnknownIdentityError{Provider: q.Provider, ExternalId: q.ExternalID}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load identity: %w", err)
+		}
+		return identity, nil
+	}
+
+	login := domain.NormalizeLogin(q.Login)
+
+	identity, err := i.identityRepo.GetByLogin(ctx, q.Provider, login)
+	if err == nil {
+		return identity, nil
+	}
+	if !errors.Is(err, repo.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	if userID, ok := i.static[q.Provider][login]; ok {
+		return &domain.Identity{UserId: userID, Provider: q.Provider, Login: login}, nil
+	}
+
+	return nil, &domain.UnknownIdentityError{Provider: q.Provider, Login: q.Login}
+}
+
+// UserID возвращает id пользователя gopr по логину у провайдера;
+// неизвестный логин — *domain.UnknownIdentityError.
+func (i *Identity) UserID(ctx context.Context, provider domain.IdentityProvider, login string) (string, error) {
+	identity, err := i.Lookup(ctx, &domain.IdentityLookupQuery{Provider: provider, Login: login})
+	if err != nil {
+		return "", err
+	}
+	return identity.UserId, nil
+}
+
+// Login возвращает логин пользователя у провайдера: самую раннюю из его учётных
+// записей или логин из конфига; если их нет — *domain.UnknownIdentityError.
+func (i *Identity) Login(ctx context.Context, provider domain.IdentityProvider, userID string) (string, error) {
+	identities, err := i.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list identities: %w", err)
+	}
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			return identity.Login, nil
+		}
+	}
+
+	for login, id := range i.static[provider] {
+		if id == userID {
+			return login, nil
+		}
+	}
+
+	return "", &domain.UnknownIdentityError{Provider: provider, UserId: userID}
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+func roleOrDefault(role domain.MembershipRole) domain.MembershipRole {
+	if role == "" {
+		return domain.RoleMember
+	}
+	return role
+}
+
+// resetPrimaryTeam вызывается после выхода пользователя из removedTeamID: если
+// это была его основная команда, основной становится самая старая из оставшихся.
+func resetPrimaryTeam(ctx context.Context, teamRepo repo.Team, userRepo repo.User, user *domain.User, removedTeamID string) error {
+	if user.TeamId != removedTeamID {
+		return nil
+	}
+
+	teams, err := teamRepo.ListByUser(ctx, user.Id)
+	if err != nil {
+		return fmt.Errorf("failed to load user teams: %w", err)
+	}
+
+	next := ""
+	for _, t := range teams {
+		if t.Team.Id != removedTeamID {
+			next = t.Team.Id
+			break
+		}
+	}
+
+	if err := userRepo.UpdateTeam(ctx, user.Id, next); err != nil {
+		return fmt.Errorf("failed to update user team: %w", err)
+	}
+	user.TeamId = next
+
+	return nil
+}
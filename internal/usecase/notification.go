@@ -0,0 +1,502 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+var ErrInvalidAddress = domain.NewError(domain.ErrCodeBadRequest, "address does not match the notification channel")
+
+const (
+	// slaBatch — сколько просроченных PR читается за один запрос.
+	slaBatch = 100
+	// seenEvents — сколько последних событий помнится, чтобы не оповещать о
+	// повторно опубликованном событии дважды.
+	seenEvents = 1024
+)
+
+// Notifier отправляет оповещения по одному каналу. Адрес получателя уже
+// подставлен в Notification.Address, если пользователь его задал.
+type Notifier interface {
+	Channel() domain.NotificationChannel
+	Notify(ctx context.Context, n *domain.Notification) error
+}
+
+type notificationTemplate struct {
+	subject *template.Template
+	text    *template.Template
+}
+
+func newNotificationTemplate(subject, text string) notificationTemplate {
+	funcs := template.FuncMap{"age": formatAge}
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Funcs(funcs).Parse(subject)),
+		text:    template.Must(template.New("text").Funcs(funcs).Parse(text)),
+	}
+}
+
+// notificationTemplates — шаблоны оповещений; данные — notificationData, для
+// сводки — domain.Digest.
+var notificationTemplates = map[domain.NotificationKind]notificationTemplate{
+	domain.NotificationAssigned: newNotificationTemplate(
+		`Review requested: {{.PullRequest.Name}}`,
+		`{{.Recipient.Username}}, you are assigned to review "{{.PullRequest.Name}}" ({{.PullRequest.Id}}) by {{.Author}}.`,
+	),
+	domain.NotificationReassigned: newNotificationTemplate(
+		`Reviewer changed: {{.PullRequest.Name}}`,
+		`{{if eq .Recipient.Id .OldReviewerId}}{{.Recipient.Username}}, you are no longer a reviewer of "{{.PullRequest.Name}}" ({{.PullRequest.Id}})`+
+			`{{if .NewReviewer}}, {{.NewReviewer}} takes over{{end}}.`+
+			`{{else}}{{.Recipient.Username}}, you are assigned to review "{{.PullRequest.Name}}" ({{.PullRequest.Id}}) by {{.Author}} instead of {{.OldReviewer}}.{{end}}`,
+	),
+	domain.NotificationSLAWarning: newNotificationTemplate(
+		`Review overdue: {{.PullRequest.Name}}`,
+		`{{.Recipient.Username}}, "{{.PullRequest.Name}}" ({{.PullRequest.Id}}) by {{.Author}} has been waiting for your review for {{.Age}}.`,
+	),
+	domain.NotificationMerged: newNotificationTemplate(
+		`Merged: {{.PullRequest.Name}}`,
+		`{{.Recipient.Username}}, "{{.PullRequest.Name}}" ({{.PullRequest.Id}}) by {{.Author}} has been merged{{if .Actor}} by {{.Actor}}{{end}}.`,
+	),
+	domain.NotificationDigest: newNotificationTemplate(
+		`Pending reviews: {{len .Items}}{{if .Overdue}}, {{.Overdue}} overdue{{end}}`,
+		`{{.Username}}, {{if .Items}}{{len .Items}} pull requests are waiting for your review:`+
+			`{{range .Items}}`+"\n"+`- {{if .Overdue}}[overdue] {{end}}"{{.Name}}" ({{.PullRequestId}}) by {{.Author}}, waiting {{age .AgeSeconds}}{{end}}`+
+			`{{else}}no pull requests are waiting for your review.{{end}}`,
+	),
+}
+
+// formatAge записывает длительность в секундах как "2d 3h", "5h 12m" или "7m".
+func formatAge(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	days, hours, minutes := int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour), int(d%time.Hour/time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+type notificationData struct {
+	Recipient     *domain.User
+	PullRequest   *domain.PullRequest
+	Author        string
+	Actor         string
+	OldReviewerId string
+	OldReviewer   string
+	NewReviewer   string
+	Age           time.Duration
+}
+
+type recipient struct {
+	userID string
+	kind   domain.NotificationKind
+}
+
+// Notification оповещает пользователей о событиях PR по выбранным ими каналам
+// и предупреждает ревьюверов о PR, ждущих ревью дольше SLA. Оповещения
+// отправляются в фоне: ни публикация события, ни проверка SLA не ждут каналов.
+type Notification struct {
+	notificationRepo repo.Notification
+	userRepo         repo.User
+	prRepo           repo.PullRequest
+	identityRepo     repo.Identity
+	notifiers        map[domain.NotificationChannel]Notifier
+	sla              time.Duration
+	now              func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]struct{}
+	// order — id запомненных событий в порядке получения
+	order []string
+
+	tasks detached
+}
+
+var _ EventSink = &Notification{}
+
+// NewNotification создаёт usecase оповещений. Канал без notifier пользователь
+// выбрать может, но оповещения по нему не отправляются. sla <= 0 отключает
+// предупреждения SLA.
+func NewNotification(notificationRepo repo.Notification, userRepo repo.User, prRepo repo.PullRequest, identityRepo repo.Identity, notifiers []Notifier, sla time.Duration) *Notification {
+	byChannel := make(map[domain.NotificationChannel]Notifier, len(notifiers))
+	for _, n := range notifiers {
+		byChannel[n.Channel()] = n
+	}
+
+	return &Notification{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		prRepo:           prRepo,
+		identityRepo:     identityRepo,
+		notifiers:        byChannel,
+		sla:              sla,
+		now:              time.Now,
+		seen:             make(map[string]struct{}),
+	}
+}
+
+// SetClock подменяет текущее время для проверки SLA.
+func (n *Notification) SetClock(now func() time.Time) {
+	n.now = now
+}
+
+func (n *Notification) SetPreference(ctx context.Context, input *domain.NotificationPreferenceSetInput) (*domain.NotificationPreference, error) {
+	if _, err := n.userRepo.GetByID(ctx, input.UserID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	address := strings.TrimSpace(input.Address)
+	if err := validateAddress(input.Channel, address); err != nil {
+		return nil, err
+	}
+
+	pref := &domain.NotificationPreference{
+		UserId:  input.UserID,
+		Channel: input.Channel,
+		Address: address,
+	}
+	if err := n.notificationRepo.SetPreference(ctx, pref); err != nil {
+		return nil, fmt.Errorf("failed to save notification preference: %w", err)
+	}
+
+	return pref, nil
+}
+
+// DeletePreference отключает канал пользователя и возвращает оставшиеся.
+func (n *Notification) DeletePreference(ctx context.Context, input *domain.NotificationPreferenceDeleteInput) ([]*domain.NotificationPreference, error) {
+	if err := n.notificationRepo.DeletePreference(ctx, input.UserID, input.Channel); err != nil {
+		return nil, fmt.Errorf("failed to delete notification preference: %w", err)
+	}
+	return n.ListPreferences(ctx, input.UserID)
+}
+
+func (n *Notification) ListPreferences(ctx context.Context, userID string) ([]*domain.NotificationPreference, error) {
+	if _, err := n.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	prefs, err := n.notificationRepo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification preferences: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func validateAddress(channel domain.NotificationChannel, address string) error {
+	if address == "" {
+		return nil
+	}
+
+	if channel == domain.NotificationEmail {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil || parsed.Address != address {
+			return fmt.Errorf("%w: invalid email", ErrInvalidAddress)
+		}
+		return nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: invalid URL", ErrInvalidAddress)
+	}
+	return nil
+}
+
+func (n *Notification) Name() string { return "notifications" }
+
+// Publish запускает оповещения о событии и сразу возвращается; ошибки каналов
+// только пишутся в лог. Повторно опубликованное событие пропускается.
+func (n *Notification) Publish(ctx context.Context, event *domain.Event) error {
+	if event.PullRequest == nil || !n.remember(event.Id) {
+		return nil
+	}
+
+	var recipients []recipient
+	switch event.Type {
+	case domain.EventReviewersAssigned:
+		for _, id := range event.Reviewers {
+			recipients = append(recipients, recipient{userID: id, kind: domain.NotificationAssigned})
+		}
+	case domain.EventReviewerReassigned:
+		recipients = append(recipients, recipient{userID: event.OldReviewerId, kind: domain.NotificationReassigned})
+		if event.NewReviewerId != "" {
+			recipients = append(recipients, recipient{userID: event.NewReviewerId, kind: domain.NotificationReassigned})
+		}
+	case domain.EventPullRequestMerged:
+		for _, id := range append([]string{event.PullRequest.AuthorId}, event.Reviewers...) {
+			if id != event.Actor {
+				recipients = append(recipients, recipient{userID: id, kind: domain.NotificationMerged})
+			}
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	// оповещения переживают публикацию, которая их запустила
+	n.tasks.start(ctx, func(ctx context.Context) {
+		data := notificationData{
+			PullRequest:   event.PullRequest,
+			Author:        n.username(ctx, event.PullRequest.AuthorId),
+			OldReviewerId: event.OldReviewerId,
+			OldReviewer:   n.username(ctx, event.OldReviewerId),
+			NewReviewer:   n.username(ctx, event.NewReviewerId),
+		}
+		if event.Actor != domain.ActorSystem {
+			data.Actor = n.username(ctx, event.Actor)
+		}
+
+		for _, r := range recipients {
+			n.notify(ctx, r, data)
+		}
+	})
+
+	return nil
+}
+
+// remember запоминает id события и возвращает false, если оно уже было.
+func (n *Notification) remember(id string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if _, ok := n.seen[id]; ok {
+		return false
+	}
+	if len(n.order) == seenEvents {
+		delete(n.seen, n.order[0])
+		n.order = n.order[1:]
+	}
+	n.seen[id] = struct{}{}
+	n.order = append(n.order, id)
+	return true
+}
+
+// CheckSLA предупреждает ревьюверов открытых PR, созданных раньше SLA; о
+// каждом PR ревьювер предупреждается один раз. Отметка ставится до отправки,
+// чтобы из нескольких инстансов предупредил один, и снимается, если
+// предупреждение не ушло ни по одному каналу. Возвращает число предупреждений.
+func (n *Notification) CheckSLA(ctx context.Context) (int, error) {
+	if n.sla <= 0 {
+		return 0, nil
+	}
+
+	now := n.now()
+	filter := &domain.PullRequestFilter{
+		Status:    domain.PullRequestStatusOpen,
+		CreatedTo: now.Add(-n.sla),
+		Order:     domain.SortOldest,
+		Limit:     slaBatch,
+	}
+
+	warned := 0
+	for {
+		prs, err := n.prRepo.List(ctx, filter)
+		if err != nil {
+			return warned, fmt.Errorf("failed to list overdue pull requests: %w", err)
+		}
+		if len(prs) == 0 {
+			return warned, nil
+		}
+
+		ids := make([]string, 0, len(prs))
+		for _, pr := range prs {
+			ids = append(ids, pr.Id)
+		}
+		reviewers, err := n.prRepo.ListReviewersByPRs(ctx, ids)
+		if err != nil {
+			return warned, fmt.Errorf("failed to list reviewers: %w", err)
+		}
+
+		for _, pr := range prs {
+			for _, reviewerID := range reviewers[pr.Id] {
+				ok, err := n.notificationRepo.MarkSLAWarned(ctx, pr.Id, reviewerID)
+				if err != nil {
+					return warned, fmt.Errorf("failed to mark SLA warning: %w", err)
+				}
+				if !ok {
+					continue
+				}
+				warned++
+				n.warn(ctx, pr, reviewerID, now.Sub(pr.CreatedAt).Truncate(time.Minute))
+			}
+		}
+
+		if len(prs) < slaBatch {
+			return warned, nil
+		}
+		last := prs[len(prs)-1]
+		filter.After = &domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}
+	}
+}
+
+func (n *Notification) warn(ctx context.Context, pr *domain.PullRequest, reviewerID string, age time.Duration) {
+	n.tasks.start(ctx, func(ctx context.Context) {
+		err := n.notify(ctx, recipient{userID: reviewerID, kind: domain.NotificationSLAWarning}, notificationData{
+			PullRequest: pr,
+			Author:      n.username(ctx, pr.AuthorId),
+			Age:         age,
+		})
+		if err == nil {
+			return
+		}
+
+		// следующая проверка SLA повторит предупреждение
+		if err := n.notificationRepo.UnmarkSLAWarned(ctx, pr.Id, reviewerID); err != nil {
+			slogx.WithErr(slogx.FromCtx(ctx), err).Warn("failed to unmark SLA warning",
+				"user_id", reviewerID, "pull_request_id", pr.Id)
+		}
+	})
+}
+
+// RunSLA проверяет SLA каждые interval, пока не отменён ctx.
+func (n *Notification) RunSLA(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func(ctx context.Context) error {
+		if _, err := n.CheckSLA(ctx); err != nil {
+			return fmt.Errorf("failed to check review SLA: %w", err)
+		}
+		return nil
+	})
+}
+
+// Wait дожидается отправки запущенных оповещений.
+func (n *Notification) Wait() {
+	n.tasks.wait()
+}
+
+// notify отправляет оповещение получателю по всем его каналам. Ошибка —
+// оповещение не ушло ни по одному каналу; она уже записана в лог.
+func (n *Notification) notify(ctx context.Context, r recipient, data notificationData) error {
+	log := slogx.FromCtx(ctx).With("user_id", r.userID, "kind", r.kind, "pull_request_id", data.PullRequest.Id)
+
+	user, err := n.userRepo.GetByID(ctx, r.userID)
+	if err != nil {
+		slogx.WithErr(log, err).Warn("failed to load notification recipient")
+		return err
+	}
+	data.Recipient = user
+
+	prefs, err := n.notificationRepo.ListPreferences(ctx, r.userID)
+	if err != nil {
+		slogx.WithErr(log, err).Warn("failed to load notification preferences")
+		return err
+	}
+	if len(prefs) == 0 {
+		return nil
+	}
+
+	subject, text, err := render(r.kind, &data)
+	if err != nil {
+		slogx.WithErr(log, err).Warn("failed to render notification")
+		return err
+	}
+
+	return n.send(ctx, log, prefs, &domain.Notification{
+		Kind:          r.kind,
+		UserId:        user.Id,
+		Username:      user.Username,
+		PullRequestId: data.PullRequest.Id,
+		Subject:       subject,
+		Text:          text,
+	})
+}
+
+// send отправляет отрисованное оповещение по каждому каналу пользователя,
+// подставляя адрес канала. Ошибка — ни один канал не принял оповещение.
+func (n *Notification) send(ctx context.Context, log *slog.Logger, prefs []*domain.NotificationPreference, msg *domain.Notification) error {
+	var (
+		delivered bool
+		errs      []error
+	)
+	for _, pref := range prefs {
+		notifier, ok := n.notifiers[pref.Channel]
+		if !ok {
+			continue
+		}
+
+		address := pref.Address
+		if address == "" && pref.Channel == domain.NotificationEmail {
+			var err error
+			if address, err = n.defaultEmail(ctx, msg.UserId); err != nil {
+				// без адреса повтор не поможет, канал просто пропускается
+				slogx.WithErr(log, err).Warn("no email address for notification")
+				continue
+			}
+		}
+
+		c := *msg
+		c.Channel, c.Address = pref.Channel, address
+		if err := notifier.Notify(ctx, &c); err != nil {
+			slogx.WithErr(log, err).Warn("failed to send notification", "channel", pref.Channel)
+			errs = append(errs, err)
+			continue
+		}
+		delivered = true
+	}
+
+	if delivered {
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
+func render(kind domain.NotificationKind, data any) (string, string, error) {
+	tmpl, ok := notificationTemplates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for %s", kind)
+	}
+
+	var subject, text bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("render subject: %w", err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return "", "", fmt.Errorf("render text: %w", err)
+	}
+
+	return subject.String(), text.String(), nil
+}
+
+// defaultEmail — адрес из учётной записи email пользователя.
+func (n *Notification) defaultEmail(ctx context.Context, userID string) (string, error) {
+	identities, err := n.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to list identities: %w", err)
+	}
+
+	for _, identity := range identities {
+		if identity.Provider == domain.IdentityEmail {
+			return identity.Login, nil
+		}
+	}
+	return "", errors.New("user has no email identity")
+}
+
+// username возвращает имя пользователя, а если его не загрузить — id.
+func (n *Notification) username(ctx context.Context, userID string) string {
+	if userID == "" {
+		return ""
+	}
+
+	user, err := n.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
@@ -0,0 +1,305 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+// stubNotifier запоминает оповещения; пока block не закрыт, отправка висит,
+// пока задан fail — отказывает.
+type stubNotifier struct {
+	channel domain.NotificationChannel
+	block   chan struct{}
+
+	mu   sync.Mutex
+	fail error
+	sent []*domain.Notification
+}
+
+func (s *stubNotifier) Channel() domain.NotificationChannel { return s.channel }
+
+func (s *stubNotifier) Notify(_ context.Context, n *domain.Notification) error {
+	if s.block != nil {
+		<-s.block
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return s.fail
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func (s *stubNotifier) setFail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = err
+}
+
+// received возвращает оповещения в виде "user kind address", отсортированные.
+func (s *stubNotifier) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]string, 0, len(s.sent))
+	for _, n := range s.sent {
+		res = append(res, n.UserId+" "+string(n.Kind)+" "+n.Address)
+	}
+	sort.Strings(res)
+	return res
+}
+
+type notificationEnv struct {
+	db     *memory.DB
+	prCase *usecase.PullRequest
+	relay  *usecase.Relay
+	notify *usecase.Notification
+	slack  *stubNotifier
+	email  *stubNotifier
+}
+
+func setupNotification(t *testing.T, sla time.Duration) *notificationEnv {
+	t.Helper()
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+	identityRepo := memory.NewIdentityRepo(db)
+	outbox := memory.NewOutboxRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+			{UserID: "u3", Username: "carol", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, identityRepo.Create(ctx, &domain.Identity{UserId: "u2", Provider: domain.IdentityEmail, Login: "bob@example.com"}))
+
+	slack := &stubNotifier{channel: domain.NotificationSlack}
+	email := &stubNotifier{channel: domain.NotificationEmail}
+	notify := usecase.NewNotification(memory.NewNotificationRepo(db), userRepo, prRepo, identityRepo, []usecase.Notifier{slack, email}, sla)
+
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 2)
+	prCase.SetOutbox(memory.NewTransactor(db), outbox)
+
+	return &notificationEnv{
+		db:     db,
+		prCase: prCase,
+		relay:  usecase.NewRelay(outbox, []usecase.EventSink{notify}, 0, 0),
+		notify: notify,
+		slack:  slack,
+		email:  email,
+	}
+}
+
+func (e *notificationEnv) prefer(t *testing.T, userID string, channel domain.NotificationChannel, address string) {
+	t.Helper()
+
+	_, err := e.notify.SetPreference(context.Background(), &domain.NotificationPreferenceSetInput{UserID: userID, Channel: channel, Address: address})
+	require.NoError(t, err)
+}
+
+func TestNotification_Preferences(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 0)
+
+	for _, input := range []*domain.NotificationPreferenceSetInput{
+		{UserID: "u1", Channel: domain.NotificationEmail, Address: "not an email"},
+		{UserID: "u1", Channel: domain.NotificationEmail, Address: "Alice <alice@example.com>"},
+		{UserID: "u1", Channel: domain.NotificationSlack, Address: "ftp://hooks.example.com"},
+		{UserID: "u1", Channel: domain.NotificationHTTP, Address: "/relative"},
+	} {
+		_, err := e.notify.SetPreference(ctx, input)
+		require.ErrorIs(t, err, usecase.ErrInvalidAddress, input.Address)
+	}
+
+	_, err := e.notify.SetPreference(ctx, &domain.NotificationPreferenceSetInput{UserID: "missing", Channel: domain.NotificationSlack})
+	require.Error(t, err)
+
+	e.prefer(t, "u1", domain.NotificationSlack, "https://hooks.example.com/a")
+	e.prefer(t, "u1", domain.NotificationEmail, " alice@example.com ")
+
+	prefs, err := e.notify.ListPreferences(ctx, "u1")
+	require.NoError(t, err)
+	require.Len(t, prefs, 2)
+	require.Equal(t, "alice@example.com", prefs[0].Address)
+
+	prefs, err = e.notify.DeletePreference(ctx, &domain.NotificationPreferenceDeleteInput{UserID: "u1", Channel: domain.NotificationSlack})
+	require.NoError(t, err)
+	require.Len(t, prefs, 1)
+	_, err = e.notify.DeletePreference(ctx, &domain.NotificationPreferenceDeleteInput{UserID: "u1", Channel: domain.NotificationSlack})
+	require.Error(t, err)
+}
+
+func TestNotification_PullRequestEvents(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 0)
+
+	e.prefer(t, "u1", domain.NotificationSlack, "")
+	e.prefer(t, "u2", domain.NotificationSlack, "https://hooks.example.com/bob")
+	e.prefer(t, "u2", domain.NotificationEmail, "")
+	// у carol нет каналов — оповещения ей не уходят
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+	_, err = e.prCase.Merge(domain.WithActor(ctx, "u2"), &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+
+	_, err = e.relay.Flush(ctx)
+	require.NoError(t, err)
+	e.notify.Wait()
+
+	// о слиянии не оповещается тот, кто слил
+	require.Equal(t, []string{
+		"u1 merged ",
+		"u2 assigned https://hooks.example.com/bob",
+	}, e.slack.received())
+	require.Equal(t, []string{"u2 assigned bob@example.com"}, e.email.received())
+
+	e.slack.mu.Lock()
+	var assigned, merged *domain.Notification
+	for _, n := range e.slack.sent {
+		switch n.Kind {
+		case domain.NotificationAssigned:
+			assigned = n
+		case domain.NotificationMerged:
+			merged = n
+		}
+	}
+	e.slack.mu.Unlock()
+	require.Equal(t, "Review requested: Fix", assigned.Subject)
+	require.Equal(t, `bob, you are assigned to review "Fix" (pr-1) by alice.`, assigned.Text)
+	require.Equal(t, `alice, "Fix" (pr-1) by alice has been merged by bob.`, merged.Text)
+}
+
+func TestNotification_Reassigned(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 0)
+
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+	e.prefer(t, "u3", domain.NotificationSlack, "")
+
+	event := &domain.Event{
+		Id:            "evt-1",
+		Type:          domain.EventReviewerReassigned,
+		PullRequest:   &domain.PullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"},
+		OldReviewerId: "u2",
+		NewReviewerId: "u3",
+		Actor:         domain.ActorSystem,
+	}
+	require.NoError(t, e.notify.Publish(ctx, event))
+	// повторная публикация того же события не оповещает снова
+	require.NoError(t, e.notify.Publish(ctx, event))
+	e.notify.Wait()
+
+	texts := map[string]string{}
+	for _, n := range e.slack.sent {
+		texts[n.UserId] = n.Text
+	}
+	require.Len(t, e.slack.sent, 2)
+	require.Equal(t, `bob, you are no longer a reviewer of "Fix" (pr-1), carol takes over.`, texts["u2"])
+	require.Equal(t, `carol, you are assigned to review "Fix" (pr-1) by alice instead of bob.`, texts["u3"])
+}
+
+func TestNotification_DoesNotBlockPublish(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 0)
+	e.slack.block = make(chan struct{})
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	// канал висит, а публикация уже завершилась
+	published, err := e.relay.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Empty(t, e.slack.received())
+
+	close(e.slack.block)
+	e.notify.Wait()
+	require.Equal(t, []string{"u2 assigned "}, e.slack.received())
+}
+
+func TestNotification_SLAWarning(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, 24*time.Hour)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e.db.SetClock(func() time.Time { return start })
+
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+	e.prefer(t, "u3", domain.NotificationSlack, "")
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-old", AuthorId: "u1", Name: "Old"})
+	require.NoError(t, err)
+	e.db.SetClock(func() time.Time { return start.Add(20 * time.Hour) })
+	_, err = e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-new", AuthorId: "u1", Name: "New"})
+	require.NoError(t, err)
+
+	e.notify.SetClock(func() time.Time { return start.Add(26 * time.Hour) })
+	warned, err := e.notify.CheckSLA(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, warned)
+	e.notify.Wait()
+
+	require.Equal(t, []string{"u2 sla_warning ", "u3 sla_warning "}, e.slack.received())
+	require.Contains(t, e.slack.sent[0].Text, `"Old" (pr-old) by alice has been waiting for your review for 26h0m0s.`)
+
+	// о том же PR повторно не предупреждает
+	warned, err = e.notify.CheckSLA(ctx)
+	require.NoError(t, err)
+	require.Zero(t, warned)
+}
+
+func TestNotification_SLAWarningRetriedAfterFailedSend(t *testing.T) {
+	ctx := context.Background()
+	e := setupNotification(t, time.Hour)
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	e.db.SetClock(func() time.Time { return start })
+
+	e.prefer(t, "u2", domain.NotificationSlack, "")
+	e.prefer(t, "u3", domain.NotificationSlack, "")
+	e.prefer(t, "u3", domain.NotificationEmail, "carol@example.com")
+
+	_, err := e.prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	e.slack.setFail(errors.New("slack is down"))
+	e.notify.SetClock(func() time.Time { return start.Add(2 * time.Hour) })
+	warned, err := e.notify.CheckSLA(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, warned)
+	e.notify.Wait()
+
+	// u3 получил предупреждение по email, u2 — ни по одному каналу
+	require.Empty(t, e.slack.received())
+	require.Equal(t, []string{"u3 sla_warning carol@example.com"}, e.email.received())
+
+	e.slack.setFail(nil)
+	warned, err = e.notify.CheckSLA(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, warned)
+	e.notify.Wait()
+	require.Equal(t, []string{"u2 sla_warning "}, e.slack.received())
+
+	warned, err = e.notify.CheckSLA(ctx)
+	require.NoError(t, err)
+	require.Zero(t, warned)
+}
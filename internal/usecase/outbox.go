@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+// defaultRelayBatch — сколько событий outbox публикуется за один проход.
+const defaultRelayBatch = 100
+
+// defaultRelayAttempts — после скольких неудачных проходов событие снимается с публикации.
+const defaultRelayAttempts = 100
+
+// EventSink получает события PR из outbox. Name различает получателей в
+// outbox, поэтому не меняется между запусками. Событие, которое получатель
+// принял, повторно ему не отдаётся, кроме случая, когда relay упал до записи
+// об этом: Id события не меняется, по нему получатель отбрасывает дубли.
+type EventSink interface {
+	Name() string
+	Publish(ctx context.Context, event *domain.Event) error
+}
+
+// eventOutbox пишет события в outbox в одной транзакции с изменением. Пока
+// транзакция и outbox не заданы, изменения выполняются как есть, без событий.
+type eventOutbox struct {
+	tx     repo.Transactor
+	outbox repo.Outbox
+}
+
+// inTx выполняет fn в транзакции, если она задана; иначе — как есть.
+func (o *eventOutbox) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if o.tx == nil {
+		return fn(ctx)
+	}
+	return o.tx.InTx(ctx, fn)
+}
+
+// emit записывает событие в outbox; вызывается внутри inTx.
+func (o *eventOutbox) emit(ctx context.Context, event *domain.Event) error {
+	if o.outbox == nil {
+		return nil
+	}
+	return addToOutbox(ctx, o.outbox, event)
+}
+
+// emitReassigned записывает reviewer.reassigned о замене oldID на newID в PR
+// prID с его текущими ревьюверами; пустой newID — ревьювер снят без замены.
+func (o *eventOutbox) emitReassigned(ctx context.Context, prRepo repo.PullRequest, prID, oldID, newID string) error {
+	if o.outbox == nil {
+		return nil
+	}
+
+	pr, err := prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("failed to get PR: %w", err)
+	}
+	revs, err := prRepo.ListReviewers(ctx, prID)
+	if err != nil {
+		return fmt.Errorf("failed to list reviewers: %w", err)
+	}
+
+	return o.emit(ctx, &domain.Event{
+		Type:          domain.EventReviewerReassigned,
+		PullRequest:   pr,
+		Reviewers:     revs,
+		OldReviewerId: oldID,
+		NewReviewerId: newID,
+		Actor:         domain.ActorFromCtx(ctx),
+	})
+}
+
+// addToOutbox присваивает событию id и время и записывает его в outbox.
+func addToOutbox(ctx context.Context, outbox repo.Outbox, event *domain.Event) error {
+	event.Id = uuid.NewString()
+	event.CreatedAt = time.Now()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	msg := &domain.OutboxMessage{
+		PullRequestId: event.PullRequest.Id,
+		EventType:     event.Type,
+		Payload:       payload,
+	}
+	if err := outbox.Add(ctx, msg); err != nil {
+		return fmt.Errorf("failed to add event to outbox: %w", err)
+	}
+
+	return nil
+}
+
+// Relay публикует события из outbox во все получатели. Событие помечается
+// опубликованным, когда его приняли все получатели; принявшие запоминаются, и на
+// следующем проходе событие уходит только остальным. После maxAttempts неудачных
+// проходов событие снимается с публикации. События одного PR уходят по порядку:
+// пока раннее не опубликовано или не снято, поздние ждут.
+type Relay struct {
+	outbox      repo.Outbox
+	sinks       []EventSink
+	batch       int
+	maxAttempts int
+	locker      repo.OutboxLocker
+}
+
+// NewRelay создаёт публикатор outbox; batch <= 0 — сто событий за проход,
+// maxAttempts <= 0 — сто попыток на событие.
+func NewRelay(outbox repo.Outbox, sinks []EventSink, batch, maxAttempts int) *Relay {
+	if batch <= 0 {
+		batch = defaultRelayBatch
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRelayAttempts
+	}
+
+	return &Relay{
+		outbox:      outbox,
+		sinks:       sinks,
+		batch:       batch,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// SetLocker включает блокировку проходов: пока её держит relay другого
+// инстанса, проход пропускается.
+func (r *Relay) SetLocker(locker repo.OutboxLocker) {
+	r.locker = locker
+}
+
+// Run публикует события сразу и затем каждые interval, пока не отменён ctx.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	flush := func(ctx context.Context) error {
+		if _, err := r.Flush(ctx); err != nil {
+			return fmt.Errorf("failed to relay outbox: %w", err)
+		}
+		return nil
+	}
+
+	runOnce(ctx, flush)
+	runEvery(ctx, interval, flush)
+}
+
+// Flush делает один проход по неопубликованным событиям и возвращает, сколько
+// из них опубликовано. Ошибки получателей записываются в событие и в лог.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	if r.locker != nil {
+		unlock, ok, err := r.locker.LockOutbox(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to lock outbox: %w", err)
+		}
+		if !ok {
+			return 0, nil
+		}
+		defer unlock()
+	}
+
+	msgs, err := r.outbox.ListPending(ctx, r.batch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list outbox: %w", err)
+	}
+
+	// PR, раннее событие которых не опубликовано в этом проходе
+	blocked := make(map[string]struct{})
+	published := 0
+
+	for _, msg := range msgs {
+		if _, ok := blocked[msg.PullRequestId]; ok {
+			continue
+		}
+
+		accepted, err := r.publish(ctx, msg)
+		if err != nil {
+			blocked[msg.PullRequestId] = struct{}{}
+
+			attempts := msg.Attempts + 1
+			log := slogx.WithErr(slogx.FromCtx(ctx), err).With(
+				"outbox_id", msg.Id, "pull_request_id", msg.PullRequestId, "attempts", attempts)
+			if err := r.outbox.MarkFailed(ctx, msg.Id, accepted, err.Error()); err != nil {
+				return published, fmt.Errorf("failed to mark outbox event failed: %w", err)
+			}
+			if attempts < r.maxAttempts {
+				log.Warn("failed to publish event")
+				continue
+			}
+
+			log.Error("event dropped from outbox after last attempt")
+			if err := r.outbox.MarkDead(ctx, msg.Id); err != nil {
+				return published, fmt.Errorf("failed to mark outbox event dead: %w", err)
+			}
+			continue
+		}
+
+		if err := r.outbox.MarkPublished(ctx, msg.Id); err != nil {
+			return published, fmt.Errorf("failed to mark outbox event published: %w", err)
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// publish отдаёт событие получателям, которые его ещё не приняли, и возвращает
+// имена всех принявших.
+func (r *Relay) publish(ctx context.Context, msg *domain.OutboxMessage) ([]string, error) {
+	event := &domain.Event{}
+	if err := json.Unmarshal(msg.Payload, event); err != nil {
+		return msg.AcceptedBy, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	accepted := slices.Clone(msg.AcceptedBy)
+	var errs []error
+	for _, sink := range r.sinks {
+		if slices.Contains(msg.AcceptedBy, sink.Name()) {
+			continue
+		}
+		if err := sink.Publish(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		accepted = append(accepted, sink.Name())
+	}
+
+	return accepted, errors.Join(errs...)
+}
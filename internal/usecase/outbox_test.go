@@ -0,0 +1,244 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo/memory"
+	"gopr/internal/usecase"
+)
+
+// recordingSink запоминает события и отказывает, пока fail не сброшен.
+type recordingSink struct {
+	mu     sync.Mutex
+	fail   map[string]bool
+	events []*domain.Event
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Publish(_ context.Context, event *domain.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.fail[event.PullRequest.Id] {
+		return errors.New("sink is down")
+	}
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) prEvents(prID string) []domain.EventType {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res []domain.EventType
+	for _, e := range s.events {
+		if e.PullRequest.Id == prID {
+			res = append(res, e.Type)
+		}
+	}
+	return res
+}
+
+func setupOutbox(t *testing.T) (*usecase.PullRequest, *memory.OutboxRepo) {
+	t.Helper()
+
+	ctx := context.Background()
+	db := memory.NewDB()
+	userRepo := memory.NewUserRepo(db)
+	teamRepo := memory.NewTeamRepo(db)
+	prRepo := memory.NewPullRequestRepo(db)
+
+	_, err := usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "author", IsActive: true},
+			{UserID: "u2", Username: "reviewer", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+
+	outbox := memory.NewOutboxRepo(db)
+	prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1)
+	prCase.SetOutbox(memory.NewTransactor(db), outbox)
+
+	return prCase, outbox
+}
+
+func TestRelay_PerPullRequestOrder(t *testing.T) {
+	ctx := context.Background()
+	prCase, outbox := setupOutbox(t)
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		_, err := prCase.Create(ctx, &domain.CreatePullRequest{Id: id, AuthorId: "u1", Name: id})
+		require.NoError(t, err)
+		_, err = prCase.Merge(ctx, &domain.MergePullRequest{Id: id})
+		require.NoError(t, err)
+	}
+
+	sink := &recordingSink{fail: map[string]bool{"pr-1": true}}
+	bus := usecase.NewEventBus()
+	ch, unsubscribe := bus.Subscribe(10)
+	defer unsubscribe()
+	relay := usecase.NewRelay(outbox, []usecase.EventSink{sink, bus}, 0, 0)
+
+	// pr-1 ждёт, пока получатель не поднимется; pr-2 не задерживается
+	published, err := relay.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, published)
+	require.Empty(t, sink.prEvents("pr-1"))
+	require.Equal(t, []domain.EventType{domain.EventReviewersAssigned, domain.EventPullRequestMerged}, sink.prEvents("pr-2"))
+
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, 1, pending[0].Attempts)
+	require.Contains(t, pending[0].LastError, "recording: sink is down")
+	require.Zero(t, pending[1].Attempts)
+
+	sink.mu.Lock()
+	sink.fail = nil
+	sink.mu.Unlock()
+
+	published, err = relay.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, published)
+	require.Equal(t, []domain.EventType{domain.EventReviewersAssigned, domain.EventPullRequestMerged}, sink.prEvents("pr-1"))
+
+	// шина приняла первое событие pr-1 с первого раза, и повтор ушёл только упавшему получателю
+	var got []string
+	for range 4 {
+		e := <-ch
+		got = append(got, e.PullRequest.Id+" "+string(e.Type))
+	}
+	require.Equal(t, []string{
+		"pr-1 reviewers.assigned",
+		"pr-2 reviewers.assigned",
+		"pr-2 pull_request.merged",
+		"pr-1 pull_request.merged",
+	}, got)
+	require.Empty(t, ch)
+}
+
+func TestRelay_DropsEventAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	prCase, outbox := setupOutbox(t)
+
+	_, err := prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	sink := &recordingSink{fail: map[string]bool{"pr-1": true}}
+	relay := usecase.NewRelay(outbox, []usecase.EventSink{sink}, 0, 2)
+
+	for range 2 {
+		published, err := relay.Flush(ctx)
+		require.NoError(t, err)
+		require.Zero(t, published)
+	}
+
+	// снятое событие не задерживает следующие события PR
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	sink.mu.Lock()
+	sink.fail = nil
+	sink.mu.Unlock()
+
+	_, err = prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"})
+	require.NoError(t, err)
+	published, err := relay.Flush(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, []domain.EventType{domain.EventPullRequestMerged}, sink.prEvents("pr-1"))
+}
+
+func TestMerge_ConcurrentEmitsOnce(t *testing.T) {
+	ctx := context.Background()
+	prCase, outbox := setupOutbox(t)
+
+	_, err := prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "pr-1"})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := prCase.Merge(ctx, &domain.MergePullRequest{Id: "pr-1"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+
+	var merged int
+	for _, m := range pending {
+		if m.EventType == domain.EventPullRequestMerged {
+			merged++
+		}
+	}
+	require.Equal(t, 1, merged)
+}
+
+// busyLocker — блокировку outbox держит другой инстанс.
+type busyLocker struct{}
+
+func (busyLocker) LockOutbox(context.Context) (func(), bool, error) {
+	return nil, false, nil
+}
+
+func TestRelay_SkipsPassWhenLocked(t *testing.T) {
+	ctx := context.Background()
+	prCase, outbox := setupOutbox(t)
+
+	_, err := prCase.Create(ctx, &domain.CreatePullRequest{Id: "pr-1", AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	sink := &recordingSink{}
+	relay := usecase.NewRelay(outbox, []usecase.EventSink{sink}, 0, 0)
+	relay.SetLocker(busyLocker{})
+
+	published, err := relay.Flush(ctx)
+	require.NoError(t, err)
+	require.Zero(t, published)
+	require.Empty(t, sink.prEvents("pr-1"))
+
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Zero(t, pending[0].Attempts)
+}
+
+func TestEventBus_DropsSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+	bus := usecase.NewEventBus()
+
+	slow, _ := bus.Subscribe(1)
+	fast, unsubscribe := bus.Subscribe(2)
+
+	event := &domain.Event{Type: domain.EventPullRequestMerged}
+	require.NoError(t, bus.Publish(ctx, event))
+	require.NoError(t, bus.Publish(ctx, event))
+
+	// переполненный подписчик отключён, но успевшее событие осталось в канале
+	require.Equal(t, event, <-slow)
+	_, ok := <-slow
+	require.False(t, ok)
+
+	require.Equal(t, event, <-fast)
+	require.Equal(t, event, <-fast)
+	unsubscribe()
+	unsubscribe()
+	_, ok = <-fast
+	require.False(t, ok)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+const (
+	defaultPageLimit = 20
+	defaultPageOrder = domain.SortOldest
+)
+
+type pageParams struct {
+	order domain.SortOrder
+	after *domain.Cursor
+	limit int
+}
+
+func newPageParams(order domain.SortOrder, cursor string, limit int) (*pageParams, error) {
+	p := &pageParams{
+		order: order,
+		limit: limit,
+	}
+
+	if p.order == "" {
+		p.order = defaultPageOrder
+	}
+	if p.limit == 0 {
+		p.limit = defaultPageLimit
+	}
+
+	if cursor != "" {
+		after, err := domain.DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.after = after
+	}
+
+	return p, nil
+}
+
+// fetchLimit — на одну запись больше страницы, чтобы понять, есть ли следующая.
+func (p *pageParams) fetchLimit() int {
+	return p.limit + 1
+}
+
+// buildPage обрезает выборку до размера страницы, считает курсор следующей
+// страницы и подгружает ревьюверов всех PR одним запросом.
+func buildPage(ctx context.Context, prRepo repo.PullRequest, prs []*domain.PullRequest, p *pageParams) (*domain.PullRequestPage, error) {
+	var next string
+	if len(prs) > p.limit {
+		prs = prs[:p.limit]
+		last := prs[len(prs)-1]
+		next = domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.Id)
+	}
+
+	reviewers, err := prRepo.ListReviewersByPRs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviewers: %w", err)
+	}
+
+	res := make([]*domain.PullRequestWithReviewers, 0, len(prs))
+	for _, pr := range prs {
+		res = append(res, &domain.PullRequestWithReviewers{
+			PR:        pr,
+			Reviewers: reviewers[pr.Id],
+		})
+	}
+
+	return &domain.PullRequestPage{
+		PRs:        res,
+		NextCursor: next,
+	}, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,26 +13,85 @@ import (
 )
 
 var (
-	ErrPRMerged    = errors.New("PR_MERGED")
-	ErrNotAssigned = errors.New("NOT_ASSIGNED")
-	ErrNoCandidate = errors.New("NO_CANDIDATE")
+	ErrPRExists    = domain.NewError(domain.ErrCodePRExists, "PR id already exists")
+	ErrPRMerged    = domain.NewError(domain.ErrCodePRMerged, "cannot reassign on merged PR")
+	ErrNotAssigned = domain.NewError(domain.ErrCodeNotAssigned, "reviewer is not assigned to this PR")
+	ErrNoCandidate = domain.NewError(domain.ErrCodeNoCandidate, "no active replacement candidate in team")
+	ErrNotMember   = domain.NewError(domain.ErrCodeNotMember, "author is not a member of the review team")
 )
 
+// defaultReviewers — сколько ревьюверов назначается на новый PR, если не задано иное.
+const defaultReviewers = 2
+
 type PullRequest struct {
-	prRepo   repo.PullRequest
-	userRepo repo.User
+	prRepo    repo.PullRequest
+	userRepo  repo.User
+	teamRepo  repo.Team
+	assigner  *assigner
+	reviewers int
+	sync      *ReviewSync
+	audit     *Audit
+	eventOutbox
 }
 
-func NewPullRequest(prRepo repo.PullRequest, userRepo repo.User) *PullRequest {
+// NewPullRequest создаёт usecase PR; strategy == nil — случайный выбор ревьюверов,
+// reviewers <= 0 — два ревьювера на PR.
+func NewPullRequest(prRepo repo.PullRequest, userRepo repo.User, teamRepo repo.Team, strategy Strategy, reviewers int) *PullRequest {
+	if reviewers <= 0 {
+		reviewers = defaultReviewers
+	}
+
 	return &PullRequest{
-		prRepo:   prRepo,
-		userRepo: userRepo,
+		prRepo:    prRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+		assigner:  newAssigner(prRepo, userRepo, teamRepo, strategy),
+		reviewers: reviewers,
 	}
 }
 
+// SetOutbox включает запись событий о назначениях, переназначениях и merge в
+// outbox в одной транзакции с изменением PR.
+func (p *PullRequest) SetOutbox(tx repo.Transactor, outbox repo.Outbox) {
+	p.tx = tx
+	p.outbox = outbox
+}
+
+// SetReviewSync включает выгрузку ревьюверов на код-хостинг после Create и Reassign.
+func (p *PullRequest) SetReviewSync(sync *ReviewSync) {
+	p.sync = sync
+}
+
+// SetAudit включает запись создания, merge и переназначений в журнал аудита.
+func (p *PullRequest) SetAudit(audit *Audit) {
+	p.audit = audit
+}
+
 func (p *PullRequest) Create(ctx context.Context, input *domain.CreatePullRequest) (*domain.PullRequestWithReviewers, error) {
+	id := input.Id
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	spec := p.auditSpec(domain.AuditPullRequestCreate, id)
+	spec.before = nil
+
+	res, err := audited(ctx, p.audit, spec, func(ctx context.Context) (*domain.PullRequestWithReviewers, error) {
+		return p.create(ctx, id, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// выгрузка начинается после фиксации транзакции
+	p.pushReviewers(ctx, res.PR.Id, res.Reviewers, nil)
+
+	return res, nil
+}
+
+func (p *PullRequest) create(ctx context.Context, id string, input *domain.CreatePullRequest) (*domain.PullRequestWithReviewers, error) {
 	pr := &domain.PullRequest{
-		Id:        uuid.NewString(),
+		Id:        id,
 		AuthorId:  input.AuthorId,
 		Name:      input.Name,
 		Status:    string(domain.PullRequestStatusOpen),
@@ -46,39 +104,60 @@ func (p *PullRequest) Create(ctx context.Context, input *domain.CreatePullReques
 		return nil, fmt.Errorf("failed to load author: %w", err)
 	}
 
-	if err := p.prRepo.Create(ctx, pr); err != nil {
-		return nil, fmt.Errorf("failed to create PR: %w", err)
+	team, err := p.reviewTeam(ctx, author, input.TeamName)
+	if err != nil {
+		return nil, err
+	}
+	if team != nil {
+		pr.TeamId = team.Id
+		pr.TeamName = team.Name
 	}
 
-	members, err := p.userRepo.ListByTeam(ctx, author.TeamId, true)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list team members: %w", err)
+	creator := domain.ActorFromCtx(ctx)
+	if creator == domain.ActorSystem {
+		creator = author.Id
 	}
 
-	candidates := make([]*domain.User, 0, len(members))
-	for _, m := range members {
-		if m.Id != author.Id {
-			candidates = append(candidates, m)
+	var reviewers []string
+
+	err = p.inTx(ctx, func(ctx context.Context) error {
+		if err := p.prRepo.Create(ctx, pr); err != nil {
+			if errors.Is(err, repo.ErrAlreadyExists) {
+				return fmt.Errorf("%w: %w", ErrPRExists, err)
+			}
+			return fmt.Errorf("failed to create PR: %w", err)
 		}
-	}
 
-	rand.Shuffle(len(candidates), func(i, j int) {
-		candidates[i], candidates[j] = candidates[j], candidates[i]
-	})
+		if err := recordEvent(ctx, p.prRepo, pr.Id, domain.PullRequestEventCreated, "", creator, ""); err != nil {
+			return err
+		}
 
-	limit := 2
-	if len(candidates) < 2 {
-		limit = len(candidates)
-	}
+		picked, err := p.assigner.pick(ctx, pr.TeamId, map[string]struct{}{author.Id: {}}, p.reviewers)
+		if err != nil {
+			return err
+		}
 
-	reviewers := make([]string, 0, limit)
+		reviewers = make([]string, 0, len(picked))
 
-	for i := 0; i < limit; i++ {
-		r := candidates[i]
-		if err := p.prRepo.AddReviewer(ctx, pr.Id, r.Id); err != nil {
-			return nil, fmt.Errorf("failed to add reviewer: %w", err)
+		for _, r := range picked {
+			if err := p.prRepo.AddReviewer(ctx, pr.Id, r.Id); err != nil {
+				return fmt.Errorf("failed to add reviewer: %w", err)
+			}
+			if err := recordEvent(ctx, p.prRepo, pr.Id, domain.PullRequestEventReviewerAssigned, r.Id, domain.ActorSystem, domain.ReasonAutoAssign); err != nil {
+				return err
+			}
+			reviewers = append(reviewers, r.Id)
 		}
-		reviewers = append(reviewers, r.Id)
+
+		return p.emit(ctx, &domain.Event{
+			Type:        domain.EventReviewersAssigned,
+			PullRequest: pr,
+			Reviewers:   reviewers,
+			Actor:       creator,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &domain.PullRequestWithReviewers{
@@ -87,10 +166,57 @@ func (p *PullRequest) Create(ctx context.Context, input *domain.CreatePullReques
 	}, nil
 }
 
+// reviewTeam возвращает команду ревью: указанную автором, в которой он должен
+// состоять, или его основную команду. nil — автор не состоит ни в одной команде.
+func (p *PullRequest) reviewTeam(ctx context.Context, author *domain.User, teamName string) (*domain.Team, error) {
+	if teamName == "" {
+		if author.TeamId == "" {
+			return nil, nil
+		}
+		team, err := p.teamRepo.GetByID(ctx, author.TeamId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load author team: %w", err)
+		}
+		return team, nil
+	}
+
+	team, err := p.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load review team: %w", err)
+	}
+
+	teams, err := p.teamRepo.ListByUser(ctx, author.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load author teams: %w", err)
+	}
+	for _, t := range teams {
+		if t.Team.Id == team.Id {
+			return team, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotMember, team.Name)
+}
+
+// Merge идемпотентен: если PR уже смержен, в том числе параллельным запросом,
+// событий и записи в журнале нет.
 func (p *PullRequest) Merge(ctx context.Context, input *domain.MergePullRequest) (*domain.PullRequestWithReviewers, error) {
+	merged := false
+	spec := p.auditSpec(domain.AuditPullRequestMerge, input.Id)
+	spec.skip = func(*domain.PullRequestWithReviewers) bool { return !merged }
+
+	return audited(ctx, p.audit, spec, func(ctx context.Context) (*domain.PullRequestWithReviewers, error) {
+		res, ok, err := p.merge(ctx, input)
+		merged = ok
+		return res, err
+	})
+}
+
+// merge возвращает false, если PR уже был смержен.
+func (p *PullRequest) merge(ctx context.Context, input *domain.MergePullRequest) (*domain.PullRequestWithReviewers, bool, error) {
 	pr, err := p.prRepo.GetByID(ctx, input.Id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get PR: %w", err)
+		return nil, false, fmt.Errorf("failed to get PR: %w", err)
 	}
 
 	if pr.Status == string(domain.PullRequestStatusClosed) {
@@ -98,19 +224,106 @@ func (p *PullRequest) Merge(ctx context.Context, input *domain.MergePullRequest)
 		return &domain.PullRequestWithReviewers{
 			PR:        pr,
 			Reviewers: revs,
-		}, nil
+		}, false, nil
+	}
+
+	var (
+		revs   []string
+		merged bool
+	)
+
+	err = p.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		merged, err = p.prRepo.UpdateStatusMerged(ctx, pr.Id)
+		if err != nil {
+			return fmt.Errorf("failed to merge: %w", err)
+		}
+
+		// статус успел поменять параллельный merge: он и отправил событие
+		if !merged {
+			if pr, err = p.prRepo.GetByID(ctx, pr.Id); err != nil {
+				return fmt.Errorf("failed to get PR: %w", err)
+			}
+			revs, _ = p.prRepo.ListReviewers(ctx, pr.Id)
+			return nil
+		}
+
+		if err := recordEvent(ctx, p.prRepo, pr.Id, domain.PullRequestEventMerged, "", domain.ActorFromCtx(ctx), ""); err != nil {
+			return err
+		}
+
+		pr.Status = string(domain.PullRequestStatusClosed)
+		now := time.Now()
+		pr.MergedAt = &now
+		pr.UpdatedAt = now
+
+		revs, _ = p.prRepo.ListReviewers(ctx, pr.Id)
+
+		return p.emit(ctx, &domain.Event{
+			Type:        domain.EventPullRequestMerged,
+			PullRequest: pr,
+			Reviewers:   revs,
+			Actor:       domain.ActorFromCtx(ctx),
+		})
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &domain.PullRequestWithReviewers{
+		PR:        pr,
+		Reviewers: revs,
+	}, merged, nil
+}
+
+// Unassign снимает всех ревьюверов с PR, закрытого на код-хостинге без merge.
+// PR остаётся OPEN, но больше не числится в нагрузке ревьюверов, SLA и дайджестах.
+func (p *PullRequest) Unassign(ctx context.Context, id string) (*domain.PullRequestWithReviewers, error) {
+	return audited(ctx, p.audit, p.auditSpec(domain.AuditPullRequestUnassign, id), func(ctx context.Context) (*domain.PullRequestWithReviewers, error) {
+		return p.unassign(ctx, id)
+	})
+}
+
+func (p *PullRequest) unassign(ctx context.Context, id string) (*domain.PullRequestWithReviewers, error) {
+	pr, err := p.prRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load PR: %w", err)
 	}
 
-	if err := p.prRepo.UpdateStatusMerged(ctx, pr.Id); err != nil {
-		return nil, fmt.Errorf("failed to merge: %w", err)
+	if pr.Status == string(domain.PullRequestStatusClosed) {
+		return nil, ErrPRMerged
 	}
 
-	pr.Status = string(domain.PullRequestStatusClosed)
-	now := time.Now()
-	pr.MergedAt = &now
-	pr.UpdatedAt = now
+	var revs []string
+
+	err = p.inTx(ctx, func(ctx context.Context) error {
+		current, err := p.prRepo.ListReviewers(ctx, pr.Id)
+		if err != nil {
+			return fmt.Errorf("failed to load reviewers: %w", err)
+		}
+
+		actor := domain.ActorFromCtx(ctx)
+		for _, r := range current {
+			if err := p.prRepo.RemoveReviewer(ctx, pr.Id, r); err != nil {
+				return fmt.Errorf("failed to remove reviewer: %w", err)
+			}
+			if err := recordEvent(ctx, p.prRepo, pr.Id, domain.PullRequestEventReviewerRemoved, r, actor, domain.ReasonClosed); err != nil {
+				return err
+			}
+			if err := p.emitReassigned(ctx, p.prRepo, pr.Id, r, ""); err != nil {
+				return err
+			}
+		}
 
-	revs, _ := p.prRepo.ListReviewers(ctx, pr.Id)
+		revs, err = p.prRepo.ListReviewers(ctx, pr.Id)
+		if err != nil {
+			return fmt.Errorf("failed to load reviewers: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &domain.PullRequestWithReviewers{
 		PR:        pr,
@@ -119,6 +332,32 @@ func (p *PullRequest) Merge(ctx context.Context, input *domain.MergePullRequest)
 }
 
 func (p *PullRequest) Reassign(ctx context.Context, input *domain.ReassignPullRequest) (*domain.PullRequestWithReviewers, string, error) {
+	// ревьювер снимается и без замены, поэтому ErrNoCandidate — тоже изменение
+	var newReviewerID string
+	var noCandidate error
+
+	res, err := audited(ctx, p.audit, p.auditSpec(domain.AuditPullRequestReassign, input.Id), func(ctx context.Context) (*domain.PullRequestWithReviewers, error) {
+		res, newID, err := p.reassign(ctx, input)
+		if errors.Is(err, ErrNoCandidate) {
+			noCandidate, err = err, nil
+		}
+		newReviewerID = newID
+		return res, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var added []string
+	if newReviewerID != "" {
+		added = []string{newReviewerID}
+	}
+	p.pushReviewers(ctx, input.Id, added, []string{input.OldReviewerId})
+
+	return res, newReviewerID, noCandidate
+}
+
+func (p *PullRequest) reassign(ctx context.Context, input *domain.ReassignPullRequest) (*domain.PullRequestWithReviewers, string, error) {
 	pr, err := p.prRepo.GetByID(ctx, input.Id)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to load PR: %w", err)
@@ -144,63 +383,124 @@ func (p *PullRequest) Reassign(ctx context.Context, input *domain.ReassignPullRe
 		return nil, "", ErrNotAssigned
 	}
 
-	oldUser, err := p.userRepo.GetByID(ctx, input.OldReviewerId)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to load old reviewer: %w", err)
-	}
+	var (
+		newReviewerID string
+		revs          []string
+		// без замены ревьювер всё равно снимается, поэтому ErrNoCandidate
+		// не откатывает транзакцию и возвращается после неё
+		noCandidate error
+	)
+
+	err = p.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		newReviewerID, err = p.assigner.replace(ctx, pr, current, input.OldReviewerId, domain.ReasonReassign)
+		if errors.Is(err, ErrNoCandidate) {
+			noCandidate = err
+		} else if err != nil {
+			return err
+		}
 
-	candidates, err := p.userRepo.ListByTeam(ctx, oldUser.TeamId, true)
+		revs, _ = p.prRepo.ListReviewers(ctx, pr.Id)
+
+		return p.emit(ctx, &domain.Event{
+			Type:          domain.EventReviewerReassigned,
+			PullRequest:   pr,
+			Reviewers:     revs,
+			OldReviewerId: input.OldReviewerId,
+			NewReviewerId: newReviewerID,
+			Actor:         domain.ActorFromCtx(ctx),
+		})
+	})
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to load candidates: %w", err)
+		return nil, "", err
 	}
 
-	// build set of current reviewers
-	currentSet := make(map[string]struct{})
-	for _, r := range current {
-		currentSet[r] = struct{}{}
+	return &domain.PullRequestWithReviewers{
+		PR:        pr,
+		Reviewers: revs,
+	}, newReviewerID, noCandidate
+}
+
+// auditSpec — запись изменения PR: снимки до и после — PR с ревьюверами.
+func (p *PullRequest) auditSpec(action domain.AuditAction, prID string) auditSpec[*domain.PullRequestWithReviewers] {
+	return auditSpec[*domain.PullRequestWithReviewers]{
+		action:     action,
+		targetType: domain.AuditTargetPullRequest,
+		targetID:   prID,
+		before: func(ctx context.Context) (any, error) {
+			pr, err := p.prRepo.GetByID(ctx, prID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load PR: %w", err)
+			}
+			revs, err := p.prRepo.ListReviewers(ctx, prID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load reviewers: %w", err)
+			}
+			return &domain.PullRequestWithReviewers{PR: pr, Reviewers: revs}, nil
+		},
+		after: func(res *domain.PullRequestWithReviewers) any { return res },
 	}
+}
 
-	filtered := make([]*domain.User, 0)
-	for _, u := range candidates {
-		if u.Id == input.OldReviewerId {
-			continue
-		}
-		if u.Id == pr.AuthorId {
-			continue
-		}
+func (p *PullRequest) pushReviewers(ctx context.Context, prID string, added, removed []string) {
+	if p.sync != nil {
+		p.sync.Push(ctx, prID, added, removed)
+	}
+}
 
-		if _, exists := currentSet[u.Id]; !exists {
-			filtered = append(filtered, u)
-		}
+func (p *PullRequest) Get(ctx context.Context, id string) (*domain.PullRequestDetails, error) {
+	pr, err := p.prRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get PR: %w", err)
 	}
 
-	// Если некого поставить вместо старого — просто удаляем
-	if len(filtered) == 0 {
-		if err := p.prRepo.RemoveReviewer(ctx, pr.Id, input.OldReviewerId); err != nil {
-			return nil, "", fmt.Errorf("failed to remove reviewer: %w", err)
-		}
-		revs, _ := p.prRepo.ListReviewers(ctx, pr.Id)
-		return &domain.PullRequestWithReviewers{
-			PR:        pr,
-			Reviewers: revs,
-		}, "", ErrNoCandidate
+	revs, err := p.prRepo.ListReviewers(ctx, pr.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reviewers: %w", err)
 	}
 
-	newReviewer := filtered[rand.Intn(len(filtered))]
-	newReviewerID := newReviewer.Id
+	timeline, err := p.prRepo.ListEvents(ctx, pr.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timeline: %w", err)
+	}
 
-	if err := p.prRepo.RemoveReviewer(ctx, pr.Id, input.OldReviewerId); err != nil {
-		return nil, "", fmt.Errorf("failed to remove old reviewer: %w", err)
+	sync, err := p.prRepo.GetReviewSync(ctx, pr.Id)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load review sync: %w", err)
 	}
 
-	if err := p.prRepo.AddReviewer(ctx, pr.Id, newReviewerID); err != nil {
-		return nil, "", fmt.Errorf("failed to add new reviewer: %w", err)
+	return &domain.PullRequestDetails{
+		PR:         pr,
+		Reviewers:  revs,
+		Timeline:   timeline,
+		ReviewSync: sync,
+	}, nil
+}
+
+func (p *PullRequest) List(ctx context.Context, q *domain.PullRequestListQuery) (*domain.PullRequestPage, error) {
+	page, err := newPageParams(q.Order, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
 	}
 
-	revs, _ := p.prRepo.ListReviewers(ctx, pr.Id)
+	prs, err := p.prRepo.List(ctx, &domain.PullRequestFilter{
+		Status:             q.Status,
+		AuthorId:           q.AuthorId,
+		TeamName:           q.TeamName,
+		ReviewerId:         q.ReviewerId,
+		NameContains:       q.Name,
+		CreatedFrom:        q.CreatedFrom,
+		CreatedTo:          q.CreatedTo,
+		MergedFrom:         q.MergedFrom,
+		MergedTo:           q.MergedTo,
+		FewerReviewersThan: q.FewerReviewersThan,
+		Order:              page.order,
+		After:              page.after,
+		Limit:              page.fetchLimit(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
 
-	return &domain.PullRequestWithReviewers{
-		PR:        pr,
-		Reviewers: revs,
-	}, newReviewerID, nil
+	return buildPage(ctx, p.prRepo, prs, page)
 }
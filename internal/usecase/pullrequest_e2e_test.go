@@ -3,296 +3,433 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 
 	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/repo/memory"
 	"gopr/internal/repo/pg"
+	"gopr/internal/repo/sqlite"
 	"gopr/internal/repo/testhelpers"
 	"gopr/internal/usecase"
-
-	"github.com/jackc/pgx/v5/pgxpool"
-	tc "github.com/testcontainers/testcontainers-go"
-	"github.com/testcontainers/testcontainers-go/wait"
 )
 
-func setupPostgres(t *testing.T) (*pgxpool.Pool, func()) {
-	ctx := context.Background()
+// forEachBackend прогоняет сценарий на каждом бэкенде хранилища с чистой базой.
+// Postgres пропускается, если Docker недоступен.
+func forEachBackend(t *testing.T, fn func(t *testing.T, f repo.Factory)) {
+	t.Run("memory", func(t *testing.T) {
+		fn(t, memory.NewFactory(memory.NewDB()))
+	})
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "gopr.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+		fn(t, sqlite.NewFactory(db))
+	})
+	t.Run("postgres", func(t *testing.T) {
+		db, cleanup := testhelpers.StartPostgres(t)
+		t.Cleanup(cleanup)
+		fn(t, pg.NewFactory(db))
+	})
+}
 
-	req := tc.ContainerRequest{
-		Image:        "postgres:16.7",
-		Env:          map[string]string{"POSTGRES_PASSWORD": "root", "POSTGRES_USER": "root", "POSTGRES_DB": "testdb"},
-		ExposedPorts: []string{"5432/tcp"},
-		WaitingFor:   wait.ForListeningPort("5432/tcp"),
-	}
+func TestPullRequestFlow_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
 
-	container, err := tc.GenericContainer(ctx, tc.GenericContainerRequest{
-		ContainerRequest: req,
-		Started:          true,
-	})
-	require.NoError(t, err)
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
 
-	host, _ := container.Host(ctx)
-	port, _ := container.MappedPort(ctx, "5432")
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
 
-	dsn := "postgres://root:root@" + host + ":" + port.Port() + "/testdb?sslmode=disable"
+		team := &domain.Team{
+			Id:   uuid.New().String(),
+			Name: "devs",
+		}
+		require.NoError(t, teamRepo.Create(ctx, team))
 
-	dbpool, err := pgxpool.New(ctx, dsn)
-	require.NoError(t, err)
+		u1 := &domain.User{Id: "u1", Username: "alice", TeamId: team.Id, IsActive: true}
+		u2 := &domain.User{Id: "u2", Username: "bob", TeamId: team.Id, IsActive: true}
+		u3 := &domain.User{Id: "u3", Username: "charlie", TeamId: team.Id, IsActive: true}
 
-	err = testhelpers.RunMigrations(dsn)
-	require.NoError(t, err)
+		require.NoError(t, userRepo.Create(ctx, u1))
+		require.NoError(t, userRepo.Create(ctx, u2))
+		require.NoError(t, userRepo.Create(ctx, u3))
 
-	cleanup := func() {
-		dbpool.Close()
-		container.Terminate(ctx)
-	}
+		input := &domain.CreatePullRequest{
+			AuthorId: u1.Id,
+			Name:     "Fix login bug",
+		}
 
-	return dbpool, cleanup
-}
+		pr, err := uc.Create(ctx, input)
+		require.NoError(t, err)
 
-func TestPullRequestFlow_E2E(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+		require.Equal(t, "Fix login bug", pr.PR.Name)
+		require.Equal(t, domain.PullRequestStatusOpen, domain.PullRequestStatus(pr.PR.Status))
+		require.Len(t, pr.Reviewers, 2)
 
-	ctx := context.Background()
+		oldReviewer := pr.Reviewers[0]
 
-	userRepo := pg.NewUserRepo(db)
-	teamRepo := pg.NewTeamRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+		reassign := &domain.ReassignPullRequest{
+			Id:            pr.PR.Id,
+			OldReviewerId: oldReviewer,
+		}
 
-	uc := usecase.NewPullRequest(prRepo, userRepo)
+		reassignedPR, newReviewer, err := uc.Reassign(ctx, reassign)
 
-	team := &domain.Team{
-		Id:   uuid.New().String(),
-		Name: "devs",
-	}
-	require.NoError(t, teamRepo.Create(ctx, team))
+		if errors.Is(err, usecase.ErrNoCandidate) {
+			require.Len(t, reassignedPR.Reviewers, 1)
+			require.Equal(t, pr.Reviewers[1], reassignedPR.Reviewers[0])
+		} else {
+			require.NoError(t, err)
+			require.NotEqual(t, oldReviewer, newReviewer)
+			require.Len(t, reassignedPR.Reviewers, 2)
+		}
 
-	u1 := &domain.User{Id: "u1", Username: "alice", TeamId: team.Id, IsActive: true}
-	u2 := &domain.User{Id: "u2", Username: "bob", TeamId: team.Id, IsActive: true}
-	u3 := &domain.User{Id: "u3", Username: "charlie", TeamId: team.Id, IsActive: true}
+		merged, err := uc.Merge(ctx, &domain.MergePullRequest{Id: pr.PR.Id})
+		require.NoError(t, err)
+		require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged.PR.Status))
+		require.NotNil(t, merged.PR.MergedAt)
 
-	require.NoError(t, userRepo.Create(ctx, u1))
-	require.NoError(t, userRepo.Create(ctx, u2))
-	require.NoError(t, userRepo.Create(ctx, u3))
+		merged2, err := uc.Merge(ctx, &domain.MergePullRequest{Id: pr.PR.Id})
+		require.NoError(t, err)
+		require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged2.PR.Status))
+	})
+}
 
-	input := &domain.CreatePullRequest{
-		AuthorId: u1.Id,
-		Name:     "Fix login bug",
-	}
+func TestPullRequest_ReassignOnMergedPR(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
 
-	pr, err := uc.Create(ctx, input)
-	require.NoError(t, err)
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
 
-	require.Equal(t, "Fix login bug", pr.PR.Name)
-	require.Equal(t, domain.PullRequestStatusOpen, domain.PullRequestStatus(pr.PR.Status))
-	require.Len(t, pr.Reviewers, 2)
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
 
-	oldReviewer := pr.Reviewers[0]
+		team := &domain.Team{
+			Id:   uuid.New().String(),
+			Name: "qa",
+		}
+		require.NoError(t, teamRepo.Create(ctx, team))
 
-	reassign := &domain.ReassignPullRequest{
-		Id:            pr.PR.Id,
-		OldReviewerId: oldReviewer,
-	}
+		u1 := &domain.User{Id: "a1", Username: "author", TeamId: team.Id, IsActive: true}
+		u2 := &domain.User{Id: "r1", Username: "rev1", TeamId: team.Id, IsActive: true}
+		u3 := &domain.User{Id: "r2", Username: "rev2", TeamId: team.Id, IsActive: true}
 
-	reassignedPR, newReviewer, err := uc.Reassign(ctx, reassign)
+		require.NoError(t, userRepo.Create(ctx, u1))
+		require.NoError(t, userRepo.Create(ctx, u2))
+		require.NoError(t, userRepo.Create(ctx, u3))
 
-	if errors.Is(err, usecase.ErrNoCandidate) {
-		require.Len(t, reassignedPR.Reviewers, 1)
-		require.Equal(t, pr.Reviewers[1], reassignedPR.Reviewers[0])
-	} else {
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{
+			AuthorId: u1.Id,
+			Name:     "Add logging",
+		})
 		require.NoError(t, err)
-		require.NotEqual(t, oldReviewer, newReviewer)
-		require.Len(t, reassignedPR.Reviewers, 2)
-	}
-
-	merged, err := uc.Merge(ctx, &domain.MergePullRequest{Id: pr.PR.Id})
-	require.NoError(t, err)
-	require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged.PR.Status))
-	require.NotNil(t, merged.PR.MergedAt)
-
-	merged2, err := uc.Merge(ctx, &domain.MergePullRequest{Id: pr.PR.Id})
-	require.NoError(t, err)
-	require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged2.PR.Status))
-}
+		require.Len(t, pr.Reviewers, 2)
 
-func TestPullRequest_ReassignOnMergedPR(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+		oldReviewer := pr.Reviewers[0]
 
-	ctx := context.Background()
+		merged, err := uc.Merge(ctx, &domain.MergePullRequest{
+			Id: pr.PR.Id,
+		})
+		require.NoError(t, err)
+		require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged.PR.Status))
 
-	userRepo := pg.NewUserRepo(db)
-	teamRepo := pg.NewTeamRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+		_, _, err = uc.Reassign(ctx, &domain.ReassignPullRequest{
+			Id:            pr.PR.Id,
+			OldReviewerId: oldReviewer,
+		})
 
-	uc := usecase.NewPullRequest(prRepo, userRepo)
+		require.Error(t, err)
+		require.True(t, errors.Is(err, usecase.ErrPRMerged), "expected PR_MERGED error")
+	})
+}
 
-	team := &domain.Team{
-		Id:   uuid.New().String(),
-		Name: "qa",
-	}
-	require.NoError(t, teamRepo.Create(ctx, team))
+func TestPullRequest_Unassign_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		teamCase := usecase.NewTeam(f.Team(), f.User(), f.PullRequest())
+		uc := usecase.NewPullRequest(f.PullRequest(), f.User(), f.Team(), nil, 0)
+		uc.SetOutbox(f.Transactor(), f.Outbox())
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "ops",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "o1", Username: "author", IsActive: true},
+				{UserID: "o2", Username: "rev1", IsActive: true},
+				{UserID: "o3", Username: "rev2", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
 
-	u1 := &domain.User{Id: "a1", Username: "author", TeamId: team.Id, IsActive: true}
-	u2 := &domain.User{Id: "r1", Username: "rev1", TeamId: team.Id, IsActive: true}
-	u3 := &domain.User{Id: "r2", Username: "rev2", TeamId: team.Id, IsActive: true}
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{AuthorId: "o1", Name: "Abandoned"})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
 
-	require.NoError(t, userRepo.Create(ctx, u1))
-	require.NoError(t, userRepo.Create(ctx, u2))
-	require.NoError(t, userRepo.Create(ctx, u3))
+		res, err := uc.Unassign(ctx, pr.PR.Id)
+		require.NoError(t, err)
+		require.Empty(t, res.Reviewers)
+		require.Equal(t, domain.PullRequestStatusOpen, domain.PullRequestStatus(res.PR.Status))
 
-	pr, err := uc.Create(ctx, &domain.CreatePullRequest{
-		AuthorId: u1.Id,
-		Name:     "Add logging",
-	})
-	require.NoError(t, err)
-	require.Len(t, pr.Reviewers, 2)
+		load, err := f.PullRequest().CountOpenReviews(ctx, pr.Reviewers)
+		require.NoError(t, err)
+		require.Zero(t, load["o2"]+load["o3"])
 
-	oldReviewer := pr.Reviewers[0]
+		// назначение и два снятия
+		pending, err := f.Outbox().ListPending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 3)
+		require.Equal(t, domain.EventReviewerReassigned, pending[2].EventType)
 
-	merged, err := uc.Merge(ctx, &domain.MergePullRequest{
-		Id: pr.PR.Id,
-	})
-	require.NoError(t, err)
-	require.Equal(t, domain.PullRequestStatusClosed, domain.PullRequestStatus(merged.PR.Status))
+		_, err = uc.Merge(ctx, &domain.MergePullRequest{Id: pr.PR.Id})
+		require.NoError(t, err)
 
-	_, _, err = uc.Reassign(ctx, &domain.ReassignPullRequest{
-		Id:            pr.PR.Id,
-		OldReviewerId: oldReviewer,
+		_, err = uc.Unassign(ctx, pr.PR.Id)
+		require.ErrorIs(t, err, usecase.ErrPRMerged)
 	})
-
-	require.Error(t, err)
-	require.True(t, errors.Is(err, usecase.ErrPRMerged), "expected PR_MERGED error")
 }
 
 func TestPullRequest_NoReviewers_E2E(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
 
-	ctx := context.Background()
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
 
-	userRepo := pg.NewUserRepo(db)
-	teamRepo := pg.NewTeamRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
 
-	uc := usecase.NewPullRequest(prRepo, userRepo)
+		team := &domain.Team{Id: uuid.NewString(), Name: "solo"}
+		require.NoError(t, teamRepo.Create(ctx, team))
 
-	team := &domain.Team{Id: uuid.NewString(), Name: "solo"}
-	require.NoError(t, teamRepo.Create(ctx, team))
+		u1 := &domain.User{
+			Id:       "solo1",
+			Username: "lonely",
+			TeamId:   team.Id,
+			IsActive: true,
+		}
+		require.NoError(t, userRepo.Create(ctx, u1))
 
-	u1 := &domain.User{
-		Id:       "solo1",
-		Username: "lonely",
-		TeamId:   team.Id,
-		IsActive: true,
-	}
-	require.NoError(t, userRepo.Create(ctx, u1))
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{
+			AuthorId: u1.Id,
+			Name:     "Solo update",
+		})
 
-	pr, err := uc.Create(ctx, &domain.CreatePullRequest{
-		AuthorId: u1.Id,
-		Name:     "Solo update",
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 0, "должно быть 0 ревьюверов, т.к. автор один в команде")
+		require.Equal(t, domain.PullRequestStatusOpen, domain.PullRequestStatus(pr.PR.Status))
 	})
-
-	require.NoError(t, err)
-	require.Len(t, pr.Reviewers, 0, "должно быть 0 ревьюверов, т.к. автор один в команде")
-	require.Equal(t, domain.PullRequestStatusOpen, domain.PullRequestStatus(pr.PR.Status))
 }
 
 func TestPullRequest_ReassignInactiveReviewer_E2E(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+		// Команда
+		team := &domain.Team{Id: uuid.NewString(), Name: "backend"}
+		require.NoError(t, teamRepo.Create(ctx, team))
+
+		// Автор + 3 ревьювера = всего 4 человека
+		u1 := &domain.User{Id: "auth1", Username: "author", TeamId: team.Id, IsActive: true}
+		u2 := &domain.User{Id: "rev1", Username: "rev1", TeamId: team.Id, IsActive: true}
+		u3 := &domain.User{Id: "rev2", Username: "rev2", TeamId: team.Id, IsActive: true}
+		u4 := &domain.User{Id: "rev3", Username: "rev3", TeamId: team.Id, IsActive: true}
+
+		require.NoError(t, userRepo.Create(ctx, u1))
+		require.NoError(t, userRepo.Create(ctx, u2))
+		require.NoError(t, userRepo.Create(ctx, u3))
+		require.NoError(t, userRepo.Create(ctx, u4))
+
+		// Создаём PR — назначится только 2 ревьювера из 3
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{
+			AuthorId: u1.Id,
+			Name:     "Add cache",
+		})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
 
-	ctx := context.Background()
+		old := pr.Reviewers[0]
 
-	userRepo := pg.NewUserRepo(db)
-	teamRepo := pg.NewTeamRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+		// Делаем старого ревьювера неактивным
+		require.NoError(t, userRepo.UpdateIsActive(ctx, old, false))
 
-	uc := usecase.NewPullRequest(prRepo, userRepo)
+		// Теперь должен быть кандидат (u4)
+		_, newRev, err := uc.Reassign(ctx, &domain.ReassignPullRequest{
+			Id:            pr.PR.Id,
+			OldReviewerId: old,
+		})
 
-	// Команда
-	team := &domain.Team{Id: uuid.NewString(), Name: "backend"}
-	require.NoError(t, teamRepo.Create(ctx, team))
+		require.NoError(t, err)
+		require.NotEqual(t, old, newRev)
+		require.NotEmpty(t, newRev)
+	})
+}
 
-	// Автор + 3 ревьювера = всего 4 человека
-	u1 := &domain.User{Id: "auth1", Username: "author", TeamId: team.Id, IsActive: true}
-	u2 := &domain.User{Id: "rev1", Username: "rev1", TeamId: team.Id, IsActive: true}
-	u3 := &domain.User{Id: "rev2", Username: "rev2", TeamId: team.Id, IsActive: true}
-	u4 := &domain.User{Id: "rev3", Username: "rev3", TeamId: team.Id, IsActive: true}
+func TestPullRequest_Reassign_NoCandidates_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
 
-	require.NoError(t, userRepo.Create(ctx, u1))
-	require.NoError(t, userRepo.Create(ctx, u2))
-	require.NoError(t, userRepo.Create(ctx, u3))
-	require.NoError(t, userRepo.Create(ctx, u4))
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
 
-	// Создаём PR — назначится только 2 ревьювера из 3
-	pr, err := uc.Create(ctx, &domain.CreatePullRequest{
-		AuthorId: u1.Id,
-		Name:     "Add cache",
-	})
-	require.NoError(t, err)
-	require.Len(t, pr.Reviewers, 2)
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
 
-	old := pr.Reviewers[0]
+		team := &domain.Team{Id: uuid.NewString(), Name: "tiny-team"}
+		require.NoError(t, teamRepo.Create(ctx, team))
 
-	// Делаем старого ревьювера неактивным
-	require.NoError(t, userRepo.UpdateIsActive(ctx, old, false))
+		u1 := &domain.User{Id: "auth2", Username: "author", TeamId: team.Id, IsActive: true}
+		u2 := &domain.User{Id: "revA", Username: "revA", TeamId: team.Id, IsActive: true}
 
-	// Теперь должен быть кандидат (u4)
-	_, newRev, err := uc.Reassign(ctx, &domain.ReassignPullRequest{
-		Id:            pr.PR.Id,
-		OldReviewerId: old,
-	})
+		require.NoError(t, userRepo.Create(ctx, u1))
+		require.NoError(t, userRepo.Create(ctx, u2))
+
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{
+			AuthorId: u1.Id,
+			Name:     "Small fix",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, pr.Reviewers, 1)
+
+		old := pr.Reviewers[0]
+
+		_, _, err = uc.Reassign(ctx, &domain.ReassignPullRequest{
+			Id:            pr.PR.Id,
+			OldReviewerId: old,
+		})
 
-	require.NoError(t, err)
-	require.NotEqual(t, old, newRev)
-	require.NotEmpty(t, newRev)
+		require.Error(t, err)
+		require.ErrorIs(t, err, usecase.ErrNoCandidate)
+
+		revs, err2 := prRepo.ListReviewers(ctx, pr.PR.Id)
+		require.NoError(t, err2)
+		require.Empty(t, revs, "после удаления без кандидатов не должно быть ревьюверов")
+	})
 }
 
-func TestPullRequest_Reassign_NoCandidates_E2E(t *testing.T) {
-	db, cleanup := setupPostgres(t)
-	defer cleanup()
+func TestPullRequest_List_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
 
-	ctx := context.Background()
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
 
-	userRepo := pg.NewUserRepo(db)
-	teamRepo := pg.NewTeamRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
 
-	uc := usecase.NewPullRequest(prRepo, userRepo)
+		team := &domain.Team{Id: uuid.NewString(), Name: "search"}
+		require.NoError(t, teamRepo.Create(ctx, team))
 
-	team := &domain.Team{Id: uuid.NewString(), Name: "tiny-team"}
-	require.NoError(t, teamRepo.Create(ctx, team))
+		u1 := &domain.User{Id: "s1", Username: "author", TeamId: team.Id, IsActive: true}
+		u2 := &domain.User{Id: "s2", Username: "rev", TeamId: team.Id, IsActive: true}
+		require.NoError(t, userRepo.Create(ctx, u1))
+		require.NoError(t, userRepo.Create(ctx, u2))
 
-	u1 := &domain.User{Id: "auth2", Username: "author", TeamId: team.Id, IsActive: true}
-	u2 := &domain.User{Id: "revA", Username: "revA", TeamId: team.Id, IsActive: true}
+		for _, name := range []string{"Add search", "Fix 100% CPU", "Search_v2"} {
+			_, err := uc.Create(ctx, &domain.CreatePullRequest{AuthorId: u1.Id, Name: name})
+			require.NoError(t, err)
+		}
 
-	require.NoError(t, userRepo.Create(ctx, u1))
-	require.NoError(t, userRepo.Create(ctx, u2))
+		page, err := uc.List(ctx, &domain.PullRequestListQuery{Name: "search", Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, page.PRs, 1)
+		require.Equal(t, "Add search", page.PRs[0].PR.Name)
+		require.Equal(t, []string{u2.Id}, page.PRs[0].Reviewers)
 
-	pr, err := uc.Create(ctx, &domain.CreatePullRequest{
-		AuthorId: u1.Id,
-		Name:     "Small fix",
-	})
-	require.NoError(t, err)
+		page, err = uc.List(ctx, &domain.PullRequestListQuery{Name: "search", Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, page.PRs, 1)
+		require.Equal(t, "Search_v2", page.PRs[0].PR.Name)
+		require.Empty(t, page.NextCursor)
 
-	require.Len(t, pr.Reviewers, 1)
+		page, err = uc.List(ctx, &domain.PullRequestListQuery{Name: "100%"})
+		require.NoError(t, err)
+		require.Len(t, page.PRs, 1)
 
-	old := pr.Reviewers[0]
+		page, err = uc.List(ctx, &domain.PullRequestListQuery{TeamName: "search", FewerReviewersThan: 2, Order: domain.SortNewest})
+		require.NoError(t, err)
+		require.Len(t, page.PRs, 3)
+		require.Equal(t, "Search_v2", page.PRs[0].PR.Name)
 
-	_, _, err = uc.Reassign(ctx, &domain.ReassignPullRequest{
-		Id:            pr.PR.Id,
-		OldReviewerId: old,
+		page, err = uc.List(ctx, &domain.PullRequestListQuery{TeamName: "unknown"})
+		require.NoError(t, err)
+		require.Empty(t, page.PRs)
 	})
+}
+
+func TestPullRequest_Timeline_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		uc := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+		team := &domain.Team{Id: uuid.NewString(), Name: "timeline"}
+		require.NoError(t, teamRepo.Create(ctx, team))
+
+		for _, id := range []string{"t1", "t2", "t3", "t4"} {
+			require.NoError(t, userRepo.Create(ctx, &domain.User{Id: id, Username: id, TeamId: team.Id, IsActive: true}))
+		}
+
+		pr, err := uc.Create(ctx, &domain.CreatePullRequest{AuthorId: "t1", Name: "Timeline"})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
 
-	require.Error(t, err)
-	require.ErrorIs(t, err, usecase.ErrNoCandidate)
+		lead := domain.WithActor(ctx, "lead")
 
-	revs, err2 := prRepo.ListReviewers(ctx, pr.PR.Id)
-	require.NoError(t, err2)
-	require.Empty(t, revs, "после удаления без кандидатов не должно быть ревьюверов")
+		_, newRev, err := uc.Reassign(lead, &domain.ReassignPullRequest{Id: pr.PR.Id, OldReviewerId: pr.Reviewers[0]})
+		require.NoError(t, err)
+
+		_, err = uc.Merge(lead, &domain.MergePullRequest{Id: pr.PR.Id})
+		require.NoError(t, err)
+
+		_, err = uc.Merge(lead, &domain.MergePullRequest{Id: pr.PR.Id})
+		require.NoError(t, err)
+
+		details, err := uc.Get(ctx, pr.PR.Id)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{pr.Reviewers[1], newRev}, details.Reviewers)
+
+		types := make([]domain.PullRequestEventType, 0, len(details.Timeline))
+		for _, ev := range details.Timeline {
+			types = append(types, ev.Type)
+		}
+		require.Equal(t, []domain.PullRequestEventType{
+			domain.PullRequestEventCreated,
+			domain.PullRequestEventReviewerAssigned,
+			domain.PullRequestEventReviewerAssigned,
+			domain.PullRequestEventReviewerRemoved,
+			domain.PullRequestEventReviewerAssigned,
+			domain.PullRequestEventMerged,
+		}, types)
+
+		require.Equal(t, "t1", details.Timeline[0].Actor)
+		require.Equal(t, domain.ActorSystem, details.Timeline[1].Actor)
+		require.Equal(t, domain.ReasonAutoAssign, details.Timeline[1].Reason)
+		require.Equal(t, pr.Reviewers[0], details.Timeline[3].ReviewerId)
+		require.Equal(t, "lead", details.Timeline[3].Actor)
+		require.Equal(t, domain.ReasonReassign, details.Timeline[3].Reason)
+		require.Equal(t, newRev, details.Timeline[4].ReviewerId)
+		require.Equal(t, "lead", details.Timeline[5].Actor)
+
+		_, err = uc.Get(ctx, "missing")
+		require.ErrorIs(t, err, repo.ErrNotFound)
+	})
 }
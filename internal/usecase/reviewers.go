@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+// assigner подбирает ревьюверов по стратегии с эскалацией в родительские команды.
+type assigner struct {
+	prRepo   repo.PullRequest
+	userRepo repo.User
+	teamRepo repo.Team
+	strategy Strategy
+}
+
+func newAssigner(prRepo repo.PullRequest, userRepo repo.User, teamRepo repo.Team, strategy Strategy) *assigner {
+	if strategy == nil {
+		strategy, _ = NewStrategy(StrategyRandom, nil)
+	}
+
+	return &assigner{
+		prRepo:   prRepo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		strategy: strategy,
+	}
+}
+
+// pick выбирает до n активных участников команды teamID, не входящих в
+// exclude. Если команда не набирает n кандидатов, недостающие берутся из
+// родительских команд, от ближайшей к корню.
+func (a *assigner) pick(ctx context.Context, teamID string, exclude map[string]struct{}, n int) ([]*domain.User, error) {
+	if teamID == "" || n <= 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(exclude))
+	for id := range exclude {
+		seen[id] = struct{}{}
+	}
+
+	res := make([]*domain.User, 0, n)
+	levels := []string{teamID}
+
+	for i := 0; i < len(levels) && len(res) < n; i++ {
+		members, err := a.userRepo.ListByTeam(ctx, levels[i], true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candidates: %w", err)
+		}
+
+		candidates := make([]*domain.User, 0, len(members))
+		ids := make([]string, 0, len(members))
+		for _, m := range members {
+			if _, ok := seen[m.Id]; !ok {
+				candidates = append(candidates, m)
+				ids = append(ids, m.Id)
+			}
+		}
+
+		load, err := a.prRepo.CountOpenReviews(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count open reviews: %w", err)
+		}
+
+		for _, c := range a.strategy.Pick(candidates, load, n-len(res)) {
+			seen[c.Id] = struct{}{}
+			res = append(res, c)
+		}
+
+		// предки нужны, только если своей команды не хватило
+		if i == 0 && len(res) < n {
+			ancestors, err := a.teamRepo.ListAncestors(ctx, teamID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load parent teams: %w", err)
+			}
+			for _, t := range ancestors {
+				levels = append(levels, t.Id)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// replace снимает oldReviewerID с PR и назначает вместо него участника команды
+// ревью PR (или её родителей), который ещё не ревьюит этот PR и не является
+// автором. Если замены нет, ревьювер просто снимается и возвращается ErrNoCandidate.
+func (a *assigner) replace(
+	ctx context.Context,
+	pr *domain.PullRequest,
+	current []string,
+	oldReviewerID, reason string,
+) (string, error) {
+	exclude := map[string]struct{}{
+		oldReviewerID: {},
+		pr.AuthorId:   {},
+	}
+	for _, r := range current {
+		exclude[r] = struct{}{}
+	}
+
+	picked, err := a.pick(ctx, pr.TeamId, exclude, 1)
+	if err != nil {
+		return "", err
+	}
+
+	actor := domain.ActorFromCtx(ctx)
+
+	// Если некого поставить вместо старого — просто удаляем
+	if len(picked) == 0 {
+		if err := a.prRepo.RemoveReviewer(ctx, pr.Id, oldReviewerID); err != nil {
+			return "", fmt.Errorf("failed to remove reviewer: %w", err)
+		}
+		if err := recordEvent(ctx, a.prRepo, pr.Id, domain.PullRequestEventReviewerRemoved, oldReviewerID, actor, domain.ReasonNoCandidate); err != nil {
+			return "", err
+		}
+		return "", ErrNoCandidate
+	}
+
+	newReviewerID := picked[0].Id
+
+	if err := a.prRepo.RemoveReviewer(ctx, pr.Id, oldReviewerID); err != nil {
+		return "", fmt.Errorf("failed to remove old reviewer: %w", err)
+	}
+	if err := recordEvent(ctx, a.prRepo, pr.Id, domain.PullRequestEventReviewerRemoved, oldReviewerID, actor, reason); err != nil {
+		return "", err
+	}
+
+	if err := a.prRepo.AddReviewer(ctx, pr.Id, newReviewerID); err != nil {
+		return "", fmt.Errorf("failed to add new reviewer: %w", err)
+	}
+	if err := recordEvent(ctx, a.prRepo, pr.Id, domain.PullRequestEventReviewerAssigned, newReviewerID, actor, reason); err != nil {
+		return "", err
+	}
+
+	return newReviewerID, nil
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cenkalti/backoff/v4"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+// CodeHost — клиент код-хостинга, на который выгружаются ревьюверы PR.
+// Ошибки, которые бессмысленно повторять, оборачиваются в backoff.Permanent.
+type CodeHost interface {
+	Provider() domain.IdentityProvider
+	RequestReviewers(ctx context.Context, ref domain.CodeHostRef, logins []string) error
+	RemoveReviewers(ctx context.Context, ref domain.CodeHostRef, logins []string) error
+}
+
+// ReviewSync выгружает изменения ревьюверов на код-хостинг в фоне, повторяя
+// неудачные попытки, и сохраняет состояние выгрузки у PR.
+type ReviewSync struct {
+	prRepo     repo.PullRequest
+	host       CodeHost
+	identities *Identity
+	newBackOff func() backoff.BackOff
+
+	mu sync.Mutex
+	// last — окончание последней запущенной выгрузки каждого PR; следующая
+	// выгрузка того же PR ждёт его, чтобы изменения не обгоняли друг друга.
+	last  map[string]chan struct{}
+	tasks detached
+
+	// stop отменяется при остановке сервиса и прерывает повторы выгрузок
+	stop   context.Context
+	cancel context.CancelFunc
+}
+
+// NewReviewSync создаёт выгрузку ревьюверов; логины на код-хостинге берутся из
+// identities, пользователь без учётной записи провайдера выгружается по id.
+// newBackOff отдаёт политику повторов для очередной выгрузки.
+func NewReviewSync(prRepo repo.PullRequest, host CodeHost, identities *Identity, newBackOff func() backoff.BackOff) *ReviewSync {
+	stop, cancel := context.WithCancel(context.Background())
+	return &ReviewSync{
+		prRepo:     prRepo,
+		host:       host,
+		identities: identities,
+		newBackOff: newBackOff,
+		last:       make(map[string]chan struct{}),
+		stop:       stop,
+		cancel:     cancel,
+	}
+}
+
+// Push запускает выгрузку: снимает removed и запрашивает ревью у added.
+// PR, заведённые не вебхуком этого код-хостинга, на нём не существуют и
+// пропускаются. Выгрузки одного PR выполняются по очереди.
+func (s *ReviewSync) Push(ctx context.Context, prID string, added, removed []string) {
+	ref, ok := domain.ParseCodeHostRef(prID)
+	if !ok || ref.Provider != s.host.Provider() || len(added)+len(removed) == 0 {
+		return
+	}
+
+	state := &domain.ReviewSync{PullRequestId: prID, Status: domain.ReviewSyncPending}
+	if err := s.prRepo.SaveReviewSync(ctx, state); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Warn("failed to save review sync", "pull_request_id", prID)
+	}
+
+	done := make(chan struct{})
+	s.mu.Lock()
+	prev := s.last[prID]
+	s.last[prID] = done
+	s.mu.Unlock()
+
+	// выгрузка переживает запрос, который её запустил
+	s.tasks.start(ctx, func(ctx context.Context) {
+		defer s.finish(prID, done)
+
+		if prev != nil {
+			<-prev
+		}
+		s.run(ctx, ref, state, added, removed)
+	})
+}
+
+// finish отмечает выгрузку PR завершённой и забывает её, если она последняя.
+func (s *ReviewSync) finish(prID string, done chan struct{}) {
+	s.mu.Lock()
+	if s.last[prID] == done {
+		delete(s.last, prID)
+	}
+	s.mu.Unlock()
+	close(done)
+}
+
+// Wait дожидается завершения запущенных выгрузок.
+func (s *ReviewSync) Wait() {
+	s.tasks.wait()
+}
+
+// Stop прерывает повторы запущенных выгрузок и дожидается их завершения.
+// Прерванные выгрузки остаются в статусе PENDING.
+func (s *ReviewSync) Stop() {
+	s.cancel()
+	s.tasks.wait()
+}
+
+func (s *ReviewSync) run(ctx context.Context, ref domain.CodeHostRef, state *domain.ReviewSync, added, removed []string) {
+	// запросы и повторы прерываются остановкой, сохранение состояния — нет
+	retryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(s.stop, cancel)()
+
+	op := func() error {
+		state.Attempts++
+
+		added, err := s.toLogins(retryCtx, added)
+		if err != nil {
+			return err
+		}
+		removed, err := s.toLogins(retryCtx, removed)
+		if err != nil {
+			return err
+		}
+
+		if len(removed) > 0 {
+			if err := s.host.RemoveReviewers(retryCtx, ref, removed); err != nil {
+				return err
+			}
+		}
+		if len(added) > 0 {
+			return s.host.RequestReviewers(retryCtx, ref, added)
+		}
+		return nil
+	}
+
+	state.Status, state.LastError = domain.ReviewSyncSynced, ""
+	if err := backoff.Retry(op, backoff.WithContext(s.newBackOff(), retryCtx)); err != nil {
+		state.Status, state.LastError = domain.ReviewSyncFailed, err.Error()
+		if s.stop.Err() != nil {
+			state.Status, state.LastError = domain.ReviewSyncPending, "interrupted by shutdown: "+err.Error()
+		}
+		slogx.FromCtxWithErr(ctx, err).Warn("failed to sync reviewers to code host",
+			"pull_request_id", state.PullRequestId, "attempts", state.Attempts, "status", state.Status)
+	}
+
+	if err := s.prRepo.SaveReviewSync(ctx, state); err != nil {
+		slogx.FromCtxWithErr(ctx, err).Warn("failed to save review sync", "pull_request_id", state.PullRequestId)
+	}
+}
+
+func (s *ReviewSync) toLogins(ctx context.Context, ids []string) ([]string, error) {
+	provider := s.host.Provider()
+
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		login, err := s.identities.Login(ctx, provider, id)
+
+		var unknown *domain.UnknownIdentityError
+		switch {
+		case errors.As(err, &unknown):
+			login = id
+		case err != nil:
+			return nil, fmt.Errorf("failed to resolve %s login of %s: %w", provider, id, err)
+		}
+
+		res = append(res, login)
+	}
+	return res, nil
+}
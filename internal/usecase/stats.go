@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+const defaultStatsRange = 30 * 24 * time.Hour
+
+type Stats struct {
+	statsRepo repo.Stats
+	teamRepo  repo.Team
+}
+
+func NewStats(statsRepo repo.Stats, teamRepo repo.Team) *Stats {
+	return &Stats{
+		statsRepo: statsRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+func (s *Stats) Users(ctx context.Context, q *domain.StatsQuery) (*domain.UserStatsReport, error) {
+	filter, err := s.filter(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.statsRepo.UserStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user stats: %w", err)
+	}
+
+	return &domain.UserStatsReport{
+		From:  filter.From,
+		To:    filter.To,
+		Users: users,
+	}, nil
+}
+
+func (s *Stats) Teams(ctx context.Context, q *domain.StatsQuery) (*domain.TeamStatsReport, error) {
+	filter, err := s.filter(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := s.statsRepo.TeamStats(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team stats: %w", err)
+	}
+
+	return &domain.TeamStatsReport{
+		From:  filter.From,
+		To:    filter.To,
+		Teams: teams,
+	}, nil
+}
+
+func (s *Stats) MergeTime(ctx context.Context, q *domain.StatsQuery) (*domain.MergeTimeReport, error) {
+	filter, err := s.filter(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.statsRepo.MergeTime(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load merge time stats: %w", err)
+	}
+
+	return &domain.MergeTimeReport{
+		From:  filter.From,
+		To:    filter.To,
+		Stats: stats,
+	}, nil
+}
+
+func (s *Stats) filter(ctx context.Context, q *domain.StatsQuery) (*domain.StatsFilter, error) {
+	from, to, err := statsRange(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.StatsFilter{
+		From: from,
+		To:   to,
+	}
+
+	if q.TeamName != "" {
+		team, err := s.teamRepo.GetByName(ctx, q.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load team: %w", err)
+		}
+		filter.TeamId = team.Id
+	}
+
+	return filter, nil
+}
+
+// statsRange подставляет окно по умолчанию: последние 30 дней до to или до текущего момента.
+func statsRange(from, to time.Time) (time.Time, time.Time, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultStatsRange)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, domain.ErrInvalidRange
+	}
+	return from, to, nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+)
+
+func TestStats_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+		statsCase := usecase.NewStats(f.Stats(), teamRepo)
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "stats",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "s1", Username: "author", IsActive: true},
+				{UserID: "s2", Username: "first", IsActive: true},
+				{UserID: "s3", Username: "second", IsActive: true},
+				{UserID: "s4", Username: "third", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		open, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "s1", Name: "Open"})
+		require.NoError(t, err)
+
+		merged, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "s1", Name: "Merged"})
+		require.NoError(t, err)
+		_, err = prCase.Merge(ctx, &domain.MergePullRequest{Id: merged.PR.Id})
+		require.NoError(t, err)
+
+		_, _, err = prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: open.PR.Id, OldReviewerId: open.Reviewers[0]})
+		require.NoError(t, err)
+
+		users, err := statsCase.Users(ctx, &domain.StatsQuery{TeamName: "stats"})
+		require.NoError(t, err)
+
+		var assigned, current, reassigned int
+		for _, u := range users.Users {
+			assigned += u.Assigned
+			current += u.Open + u.Merged
+			reassigned += u.ReassignedAway
+		}
+		require.Equal(t, 5, assigned)
+		require.Equal(t, 4, current)
+		require.Equal(t, 1, reassigned)
+
+		teams, err := statsCase.Teams(ctx, &domain.StatsQuery{})
+		require.NoError(t, err)
+		require.Len(t, teams.Teams, 1)
+		require.Equal(t, 2, teams.Teams[0].PullRequests)
+		require.Equal(t, 1, teams.Teams[0].Merged)
+		require.Equal(t, 1, teams.Teams[0].Reassignments)
+
+		mt, err := statsCase.MergeTime(ctx, &domain.StatsQuery{})
+		require.NoError(t, err)
+		require.Equal(t, 1, mt.Stats.Merged)
+		require.GreaterOrEqual(t, mt.Stats.P99, mt.Stats.P50)
+
+		_, err = statsCase.Users(ctx, &domain.StatsQuery{From: time.Now(), To: time.Now().Add(-time.Hour)})
+		require.ErrorIs(t, err, domain.ErrInvalidRange)
+	})
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"gopr/internal/domain"
+)
+
+const (
+	StrategyRandom         = "random"
+	StrategyLeastLoaded    = "least_loaded"
+	StrategyWeightedRandom = "weighted_random"
+)
+
+// StrategyNames перечисляет стратегии, которые можно выбрать в конфиге.
+var StrategyNames = []string{StrategyRandom, StrategyLeastLoaded, StrategyWeightedRandom}
+
+// Strategy выбирает до n ревьюверов среди кандидатов. load — число открытых
+// ревью у каждого кандидата.
+type Strategy interface {
+	Name() string
+	Pick(candidates []*domain.User, load map[string]int, n int) []*domain.User
+}
+
+// Rand — источник случайности стратегий; *rand.Rand подходит для
+// воспроизводимых прогонов.
+type Rand interface {
+	Intn(n int) int
+	Float64() float64
+	Shuffle(n int, swap func(i, j int))
+}
+
+type globalRand struct{}
+
+func (globalRand) Intn(n int) int                     { return rand.Intn(n) }
+func (globalRand) Float64() float64                   { return rand.Float64() }
+func (globalRand) Shuffle(n int, swap func(i, j int)) { rand.Shuffle(n, swap) }
+
+// NewStrategy возвращает стратегию по имени; rnd == nil — общий генератор math/rand.
+func NewStrategy(name string, rnd Rand) (Strategy, error) {
+	if rnd == nil {
+		rnd = globalRand{}
+	}
+
+	switch name {
+	case StrategyRandom, "":
+		return &randomStrategy{rnd: rnd}, nil
+	case StrategyLeastLoaded:
+		return &leastLoadedStrategy{rnd: rnd}, nil
+	case StrategyWeightedRandom:
+		return &weightedRandomStrategy{rnd: rnd}, nil
+	default:
+		return nil, fmt.Errorf("unknown assignment strategy %q", name)
+	}
+}
+
+// randomStrategy — равновероятный выбор без учёта нагрузки.
+type randomStrategy struct {
+	rnd Rand
+}
+
+func (s *randomStrategy) Name() string { return StrategyRandom }
+
+func (s *randomStrategy) Pick(candidates []*domain.User, _ map[string]int, n int) []*domain.User {
+	shuffled := shuffle(s.rnd, candidates)
+	return shuffled[:min(n, len(shuffled))]
+}
+
+// leastLoadedStrategy берёт кандидатов с наименьшей нагрузкой, при равенстве — случайно.
+type leastLoadedStrategy struct {
+	rnd Rand
+}
+
+func (s *leastLoadedStrategy) Name() string { return StrategyLeastLoaded }
+
+func (s *leastLoadedStrategy) Pick(candidates []*domain.User, load map[string]int, n int) []*domain.User {
+	shuffled := shuffle(s.rnd, candidates)
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return load[shuffled[i].Id] < load[shuffled[j].Id]
+	})
+	return shuffled[:min(n, len(shuffled))]
+}
+
+// weightedRandomStrategy выбирает случайно с весом 1/(load+1): загруженные
+// ревьюверы назначаются реже, но не исключаются полностью.
+type weightedRandomStrategy struct {
+	rnd Rand
+}
+
+func (s *weightedRandomStrategy) Name() string { return StrategyWeightedRandom }
+
+func (s *weightedRandomStrategy) Pick(candidates []*domain.User, load map[string]int, n int) []*domain.User {
+	pool := append([]*domain.User(nil), candidates...)
+	res := make([]*domain.User, 0, min(n, len(pool)))
+
+	for len(res) < n && len(pool) > 0 {
+		total := 0.0
+		for _, c := range pool {
+			total += 1 / float64(load[c.Id]+1)
+		}
+
+		x := s.rnd.Float64() * total
+		i := 0
+		for ; i < len(pool)-1; i++ {
+			x -= 1 / float64(load[pool[i].Id]+1)
+			if x < 0 {
+				break
+			}
+		}
+
+		res = append(res, pool[i])
+		pool = append(pool[:i], pool[i+1:]...)
+	}
+
+	return res
+}
+
+func shuffle(rnd Rand, users []*domain.User) []*domain.User {
+	res := append([]*domain.User(nil), users...)
+	rnd.Shuffle(len(res), func(i, j int) {
+		res[i], res[j] = res[j], res[i]
+	})
+	return res
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/google/uuid"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+)
+
+const (
+	// defaultMaxAttempts — сколько раз доставка пробуется, прежде чем стать DEAD.
+	defaultMaxAttempts = 5
+	// claimTimeout — на сколько доставка, взятая в работу, скрыта от других
+	// инстансов; за это время попытка должна завершиться и сохраниться.
+	claimTimeout = time.Minute
+	// deliveryBatch — сколько наступивших доставок берётся за один проход.
+	deliveryBatch = 100
+)
+
+// DeliverySender отправляет тело доставки на URL подписки и возвращает HTTP-код
+// ответа, 0 — ответа не было. Ошибка означает, что доставка не удалась; ошибки,
+// которые бессмысленно повторять, оборачиваются в backoff.Permanent.
+type DeliverySender interface {
+	Send(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) (int, error)
+}
+
+// Subscription ведёт подписки внешних систем на события PR и доставляет им
+// события. Первая попытка делается сразу в фоне, время следующей хранится у
+// доставки, и повторы выполняет DeliverDue — в том числе после перезапуска.
+type Subscription struct {
+	subRepo     repo.Subscription
+	sender      DeliverySender
+	maxAttempts int
+	newBackOff  func() backoff.BackOff
+	now         func() time.Time
+
+	tasks detached
+}
+
+var _ EventSink = &Subscription{}
+
+// NewSubscription создаёт usecase подписок. maxAttempts <= 0 — пять попыток;
+// newBackOff отдаёт задержки между попытками одной доставки: перед попыткой n+1
+// выжидается n-й шаг политики.
+func NewSubscription(subRepo repo.Subscription, sender DeliverySender, maxAttempts int, newBackOff func() backoff.BackOff) *Subscription {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	return &Subscription{
+		subRepo:     subRepo,
+		sender:      sender,
+		maxAttempts: maxAttempts,
+		newBackOff:  newBackOff,
+		now:         time.Now,
+	}
+}
+
+// SetClock подменяет текущее время для расписания повторов.
+func (s *Subscription) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+func (s *Subscription) Add(ctx context.Context, input *domain.SubscriptionAddInput) (*domain.Subscription, error) {
+	sub := &domain.Subscription{
+		Id:     uuid.NewString(),
+		URL:    input.URL,
+		Events: input.Events,
+		Secret: input.Secret,
+	}
+
+	if err := s.subRepo.Create(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+func (s *Subscription) List(ctx context.Context) ([]*domain.Subscription, error) {
+	subs, err := s.subRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// Delete удаляет подписку вместе с журналом доставок и возвращает её.
+func (s *Subscription) Delete(ctx context.Context, input *domain.SubscriptionDeleteInput) (*domain.Subscription, error) {
+	sub, err := s.subRepo.GetByID(ctx, input.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+
+	if err := s.subRepo.Delete(ctx, sub.Id); err != nil {
+		return nil, fmt.Errorf("failed to delete subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// Deliveries возвращает страницу журнала доставок подписки, по умолчанию новые первыми.
+func (s *Subscription) Deliveries(ctx context.Context, q *domain.DeliveryListQuery) (*domain.DeliveryPage, error) {
+	order := q.Order
+	if order == "" {
+		order = domain.SortNewest
+	}
+
+	page, err := newPageParams(order, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.subRepo.GetByID(ctx, q.SubscriptionId); err != nil {
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+
+	deliveries, err := s.subRepo.ListDeliveries(ctx, &domain.DeliveryFilter{
+		SubscriptionId: q.SubscriptionId,
+		Status:         q.Status,
+		Order:          page.order,
+		After:          page.after,
+		Limit:          page.fetchLimit(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	var next string
+	if len(deliveries) > page.limit {
+		deliveries = deliveries[:page.limit]
+		last := deliveries[len(deliveries)-1]
+		next = domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+
+	return &domain.DeliveryPage{
+		Deliveries: deliveries,
+		NextCursor: next,
+	}, nil
+}
+
+// Redeliver повторно отправляет событие доставки новой доставкой с тем же
+// event_id и телом, в том числе после DEAD.
+func (s *Subscription) Redeliver(ctx context.Context, input *domain.RedeliverInput) (*domain.Delivery, error) {
+	prev, err := s.subRepo.GetDelivery(ctx, input.DeliveryId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load delivery: %w", err)
+	}
+
+	sub, err := s.subRepo.GetByID(ctx, prev.SubscriptionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscription: %w", err)
+	}
+
+	return s.enqueue(ctx, sub, prev.EventId, prev.EventType, prev.Payload)
+}
+
+func (s *Subscription) Name() string { return "webhooks" }
+
+// Publish ставит событие в доставку всем подпискам, которые его принимают.
+// Ошибка возвращается, только если доставки не удалось записать; неудачные
+// доставки повторяет DeliverDue, и событие они не задерживают.
+func (s *Subscription) Publish(ctx context.Context, event *domain.Event) error {
+	subs, err := s.subRepo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	var errs []error
+	for _, sub := range subs {
+		if !sub.Accepts(event.Type) {
+			continue
+		}
+		if _, err := s.enqueue(ctx, sub, event.Id, event.Type, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeliverDue делает очередную попытку доставок, чьё время наступило, и
+// возвращает их число. Так продолжаются и доставки, прерванные перезапуском.
+// Начатые попытки доводятся до конца, даже если ctx отменён.
+func (s *Subscription) DeliverDue(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.subRepo.ClaimDeliveries(ctx, now, now.Add(claimTimeout), deliveryBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	subs := make(map[string]*domain.Subscription)
+
+	var tasks detached
+	defer tasks.wait()
+
+	for _, d := range due {
+		sub, ok := subs[d.SubscriptionId]
+		if !ok {
+			sub, err = s.subRepo.GetByID(ctx, d.SubscriptionId)
+			if err != nil {
+				return 0, fmt.Errorf("failed to load subscription: %w", err)
+			}
+			subs[sub.Id] = sub
+		}
+
+		tasks.start(ctx, func(ctx context.Context) {
+			s.attempt(ctx, sub, d)
+		})
+	}
+
+	return len(due), nil
+}
+
+// RunDeliveries повторяет наступившие доставки раз в interval, пока не отменён ctx.
+func (s *Subscription) RunDeliveries(ctx context.Context, interval time.Duration) {
+	runEvery(ctx, interval, func(ctx context.Context) error {
+		if _, err := s.DeliverDue(ctx); err != nil {
+			return fmt.Errorf("failed to retry deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// Wait дожидается завершения первых попыток запущенных доставок.
+func (s *Subscription) Wait() {
+	s.tasks.wait()
+}
+
+// enqueue записывает доставку и сразу делает первую попытку в фоне. До её
+// окончания доставка считается взятой в работу и DeliverDue её не трогает.
+func (s *Subscription) enqueue(ctx context.Context, sub *domain.Subscription, eventID string, eventType domain.EventType, payload []byte) (*domain.Delivery, error) {
+	claimed := s.now().Add(claimTimeout)
+	d := &domain.Delivery{
+		Id:             uuid.NewString(),
+		SubscriptionId: sub.Id,
+		EventId:        eventID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         domain.DeliveryPending,
+		NextAttemptAt:  &claimed,
+	}
+
+	if err := s.subRepo.CreateDelivery(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	// доставка переживает запрос, который её запустил, и меняет свою копию
+	state := *d
+
+	s.tasks.start(ctx, func(ctx context.Context) {
+		s.attempt(ctx, sub, &state)
+	})
+
+	return d, nil
+}
+
+// attempt делает одну попытку доставки и сохраняет результат: DELIVERED, DEAD
+// после maxAttempts попыток или ошибки, которую бессмысленно повторять, иначе
+// PENDING со временем следующей попытки.
+func (s *Subscription) attempt(ctx context.Context, sub *domain.Subscription, d *domain.Delivery) {
+	log := slogx.FromCtx(ctx).With("delivery_id", d.Id, "subscription_id", sub.Id)
+
+	d.Attempts++
+	code, err := s.sender.Send(ctx, sub, d)
+	d.ResponseCode = code
+
+	if err == nil {
+		d.Status, d.LastError, d.NextAttemptAt = domain.DeliveryDelivered, "", nil
+	} else {
+		d.LastError = err.Error()
+
+		var permanent *backoff.PermanentError
+		delay, retry := s.retryDelay(d.Attempts)
+		if errors.As(err, &permanent) || d.Attempts >= s.maxAttempts || !retry {
+			d.Status, d.NextAttemptAt = domain.DeliveryDead, nil
+			slogx.WithErr(log, err).Warn("delivery is dead", "attempts", d.Attempts)
+		} else {
+			next := s.now().Add(delay)
+			d.NextAttemptAt = &next
+		}
+	}
+
+	if err := s.subRepo.UpdateDelivery(ctx, d); err != nil {
+		slogx.WithErr(log, err).Warn("failed to save delivery")
+	}
+}
+
+// retryDelay возвращает задержку после attempts неудачных попыток;
+// retry == false — политика повторов исчерпана.
+func (s *Subscription) retryDelay(attempts int) (delay time.Duration, retry bool) {
+	b := s.newBackOff()
+	for range attempts {
+		if delay = b.NextBackOff(); delay == backoff.Stop {
+			return 0, false
+		}
+	}
+	return delay, true
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -10,49 +11,85 @@ import (
 	"gopr/internal/repo"
 )
 
+var (
+	ErrTeamExists      = domain.NewError(domain.ErrCodeTeamExists, "team_name already exists")
+	ErrTeamOpenReviews = domain.NewError(domain.ErrCodeOpenReviews, "team members have open reviews, use force to unassign them")
+	ErrUserInTeam      = domain.NewError(domain.ErrCodeUserInTeam, "user already belongs to the team")
+	ErrTeamCycle       = domain.NewError(domain.ErrCodeTeamCycle, "team cannot be nested into itself or its subteam")
+)
+
 type Team struct {
 	teamRepo repo.Team
 	userRepo repo.User
+	prRepo   repo.PullRequest
+	audit    *Audit
+	eventOutbox
 }
 
-func NewTeam(teamRepo repo.Team, userRepo repo.User) *Team {
+func NewTeam(teamRepo repo.Team, userRepo repo.User, prRepo repo.PullRequest) *Team {
 	return &Team{
 		teamRepo: teamRepo,
 		userRepo: userRepo,
+		prRepo:   prRepo,
 	}
 }
 
+// SetOutbox включает транзакции для изменений из нескольких шагов, например
+// удаления команды, и запись событий о снятых с PR ревьюверах в outbox.
+func (t *Team) SetOutbox(tx repo.Transactor, outbox repo.Outbox) {
+	t.tx = tx
+	t.outbox = outbox
+}
+
+// SetAudit включает запись изменений команд и их состава в журнал аудита.
+func (t *Team) SetAudit(audit *Audit) {
+	t.audit = audit
+}
+
 func (t *Team) AddTeam(ctx context.Context, input *domain.TeamAddInput) (*domain.TeamWithMembers, error) {
+	spec := t.auditSpec(domain.AuditTeamAdd, input.TeamName)
+	spec.before = nil
+
+	return audited(ctx, t.audit, spec, func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.addTeam(ctx, input)
+	})
+}
+
+func (t *Team) addTeam(ctx context.Context, input *domain.TeamAddInput) (*domain.TeamWithMembers, error) {
 	team := &domain.Team{
 		Id:   uuid.NewString(),
 		Name: input.TeamName,
 	}
 
+	if input.ParentName != "" {
+		parent, err := t.teamRepo.GetByName(ctx, input.ParentName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent team: %w", err)
+		}
+		team.ParentId = parent.Id
+	}
+
 	if err := t.teamRepo.Create(ctx, team); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: %w", ErrTeamExists, err)
+		}
 		return nil, fmt.Errorf("failed to create team: %w", err)
 	}
 
-	members := make([]*domain.User, 0, len(input.Members))
-
 	for _, m := range input.Members {
-		user := &domain.User{
-			Id:       m.UserID,
+		member := &domain.TeamMemberInput{
+			TeamName: team.Name,
+			UserID:   m.UserID,
 			Username: m.Username,
-			TeamId:   team.Id,
 			IsActive: m.IsActive,
+			Role:     m.Role,
 		}
-
-		if err := t.userRepo.Create(ctx, user); err != nil {
-			return nil, fmt.Errorf("failed to create user %s: %w", m.UserID, err)
+		if err := t.join(ctx, team, member); err != nil {
+			return nil, err
 		}
-
-		members = append(members, user)
 	}
 
-	return &domain.TeamWithMembers{
-		Team:    team,
-		Members: members,
-	}, nil
+	return t.GetTeam(ctx, team.Name)
 }
 
 func (t *Team) GetTeam(ctx context.Context, teamName string) (*domain.TeamWithMembers, error) {
@@ -61,13 +98,344 @@ func (t *Team) GetTeam(ctx context.Context, teamName string) (*domain.TeamWithMe
 		return nil, fmt.Errorf("failed to get team: %w", err)
 	}
 
-	users, err := t.userRepo.ListByTeam(ctx, team.Id, false)
+	members, err := t.teamRepo.ListMembers(ctx, team.Id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load team users: %w", err)
 	}
 
-	return &domain.TeamWithMembers{
+	res := &domain.TeamWithMembers{
 		Team:    team,
-		Members: users,
+		Members: members,
+	}
+
+	if team.ParentId != "" {
+		res.Parent, err = t.teamRepo.GetByID(ctx, team.ParentId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent team: %w", err)
+		}
+	}
+
+	return res, nil
+}
+
+// GetSubtree возвращает команду со всеми подкомандами и список участников
+// поддерева без повторов; роль берётся из ближайшей к корню команды.
+func (t *Team) GetSubtree(ctx context.Context, teamName string) (*domain.TeamWithMembers, error) {
+	root, err := t.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := t.teamRepo.ListSubtree(ctx, root.Team.Id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subtree: %w", err)
+	}
+
+	nodes := map[string]*domain.TeamWithMembers{root.Team.Id: root}
+	seen := make(map[string]struct{})
+	aggregate := make([]*domain.TeamMember, 0)
+
+	// ListSubtree отдаёт команды по уровням, поэтому родитель всегда уже в nodes
+	for _, team := range teams {
+		node, ok := nodes[team.Id]
+		if !ok {
+			members, err := t.teamRepo.ListMembers(ctx, team.Id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load team users: %w", err)
+			}
+
+			parent := nodes[team.ParentId]
+			node = &domain.TeamWithMembers{
+				Team:    team,
+				Parent:  parent.Team,
+				Members: members,
+			}
+			nodes[team.Id] = node
+			parent.Subteams = append(parent.Subteams, node)
+		}
+
+		for _, m := range node.Members {
+			if _, ok := seen[m.User.Id]; !ok {
+				seen[m.User.Id] = struct{}{}
+				aggregate = append(aggregate, m)
+			}
+		}
+	}
+
+	root.AggregateMembers = aggregate
+
+	return root, nil
+}
+
+func (t *Team) ListTeams(ctx context.Context) ([]*domain.TeamSummary, error) {
+	teams, err := t.teamRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	return teams, nil
+}
+
+func (t *Team) Rename(ctx context.Context, input *domain.TeamRenameInput) (*domain.TeamWithMembers, error) {
+	return audited(ctx, t.audit, t.auditSpec(domain.AuditTeamRename, input.TeamName), func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.rename(ctx, input)
+	})
+}
+
+func (t *Team) rename(ctx context.Context, input *domain.TeamRenameInput) (*domain.TeamWithMembers, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	if err := t.teamRepo.Rename(ctx, team.Id, input.NewTeamName); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return nil, fmt.Errorf("%w: %w", ErrTeamExists, err)
+		}
+		return nil, fmt.Errorf("failed to rename team: %w", err)
+	}
+
+	return t.GetTeam(ctx, input.NewTeamName)
+}
+
+// SetParent переносит команду под другую или делает её корневой. Команду нельзя
+// вложить в саму себя или в собственную подкоманду.
+func (t *Team) SetParent(ctx context.Context, input *domain.TeamSetParentInput) (*domain.TeamWithMembers, error) {
+	return audited(ctx, t.audit, t.auditSpec(domain.AuditTeamSetParent, input.TeamName), func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.setParent(ctx, input)
+	})
+}
+
+func (t *Team) setParent(ctx context.Context, input *domain.TeamSetParentInput) (*domain.TeamWithMembers, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	parentID := ""
+	if input.ParentName != "" {
+		parent, err := t.teamRepo.GetByName(ctx, input.ParentName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get parent team: %w", err)
+		}
+
+		subtree, err := t.teamRepo.ListSubtree(ctx, team.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load subtree: %w", err)
+		}
+		for _, sub := range subtree {
+			if sub.Id == parent.Id {
+				return nil, fmt.Errorf("%w: %s", ErrTeamCycle, parent.Name)
+			}
+		}
+
+		parentID = parent.Id
+	}
+
+	if err := t.teamRepo.SetParent(ctx, team.Id, parentID); err != nil {
+		return nil, fmt.Errorf("failed to update parent team: %w", err)
+	}
+
+	return t.GetTeam(ctx, team.Name)
+}
+
+// Delete удаляет команду вместе с членствами в ней, подкоманды становятся
+// корневыми. Если на PR, которые ревьюятся этой командой, есть назначенные
+// ревьюверы, удаление отклоняется, а с Force они снимаются с этих PR.
+func (t *Team) Delete(ctx context.Context, input *domain.TeamDeleteInput) (*domain.TeamDeleteResult, error) {
+	spec := auditSpec[*domain.TeamDeleteResult]{
+		action:     domain.AuditTeamDelete,
+		targetType: domain.AuditTargetTeam,
+		target:     func(res *domain.TeamDeleteResult) string { return res.Team.Id },
+		before:     func(ctx context.Context) (any, error) { return t.GetTeam(ctx, input.TeamName) },
+	}
+
+	return audited(ctx, t.audit, spec, func(ctx context.Context) (*domain.TeamDeleteResult, error) {
+		return t.delete(ctx, input)
+	})
+}
+
+func (t *Team) delete(ctx context.Context, input *domain.TeamDeleteInput) (*domain.TeamDeleteResult, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	var open []*domain.ReviewAssignment
+
+	// ревью снимаются только вместе с удалением команды
+	err = t.inTx(ctx, func(ctx context.Context) error {
+		var err error
+		open, err = t.prRepo.ListOpenReviewsByTeam(ctx, team.Id)
+		if err != nil {
+			return fmt.Errorf("failed to load open reviews: %w", err)
+		}
+
+		if len(open) > 0 && !input.Force {
+			return ErrTeamOpenReviews
+		}
+
+		members, err := t.teamRepo.ListMembers(ctx, team.Id)
+		if err != nil {
+			return fmt.Errorf("failed to load team users: %w", err)
+		}
+
+		actor := domain.ActorFromCtx(ctx)
+		for _, a := range open {
+			if err := t.prRepo.RemoveReviewer(ctx, a.PullRequestId, a.ReviewerId); err != nil {
+				return fmt.Errorf("failed to remove reviewer: %w", err)
+			}
+			err := recordEvent(ctx, t.prRepo, a.PullRequestId, domain.PullRequestEventReviewerRemoved, a.ReviewerId, actor, domain.ReasonTeamDeleted)
+			if err != nil {
+				return err
+			}
+			if err := t.emitReassigned(ctx, t.prRepo, a.PullRequestId, a.ReviewerId, ""); err != nil {
+				return err
+			}
+		}
+
+		if err := t.teamRepo.Delete(ctx, team.Id); err != nil {
+			return fmt.Errorf("failed to delete team: %w", err)
+		}
+
+		for _, m := range members {
+			if err := resetPrimaryTeam(ctx, t.teamRepo, t.userRepo, m.User, team.Id); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TeamDeleteResult{
+		Team:           team,
+		RemovedReviews: open,
 	}, nil
 }
+
+// AddMember добавляет пользователя в команду, создавая его при необходимости.
+// Пользователь может состоять в нескольких командах; первая из них становится основной.
+func (t *Team) AddMember(ctx context.Context, input *domain.TeamMemberInput) (*domain.TeamWithMembers, error) {
+	return audited(ctx, t.audit, t.auditSpec(domain.AuditTeamAddMember, input.TeamName), func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.addMember(ctx, input)
+	})
+}
+
+func (t *Team) addMember(ctx context.Context, input *domain.TeamMemberInput) (*domain.TeamWithMembers, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	if err := t.join(ctx, team, input); err != nil {
+		return nil, err
+	}
+
+	return t.GetTeam(ctx, team.Name)
+}
+
+func (t *Team) join(ctx context.Context, team *domain.Team, input *domain.TeamMemberInput) error {
+	user, err := t.userRepo.GetByID(ctx, input.UserID)
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		user = &domain.User{
+			Id:       input.UserID,
+			Username: input.Username,
+			TeamId:   team.Id,
+			IsActive: input.IsActive,
+		}
+		if err := t.userRepo.Create(ctx, user); err != nil {
+			return fmt.Errorf("failed to create user %s: %w", input.UserID, err)
+		}
+		if err := t.teamRepo.UpdateMemberRole(ctx, team.Id, user.Id, roleOrDefault(input.Role)); err != nil {
+			return fmt.Errorf("failed to update member role: %w", err)
+		}
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to load user: %w", err)
+	case user.TeamId == "":
+		if err := t.userRepo.UpdateTeam(ctx, user.Id, team.Id); err != nil {
+			return fmt.Errorf("failed to update user team: %w", err)
+		}
+		if err := t.userRepo.UpdateIsActive(ctx, user.Id, input.IsActive); err != nil {
+			return fmt.Errorf("failed to update activity: %w", err)
+		}
+	}
+
+	err = t.teamRepo.AddMember(ctx, &domain.Membership{
+		TeamId: team.Id,
+		UserId: user.Id,
+		Role:   roleOrDefault(input.Role),
+	})
+	if errors.Is(err, repo.ErrAlreadyExists) {
+		return fmt.Errorf("%w: %s", ErrUserInTeam, input.UserID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember выводит пользователя из команды. Назначенные ему ревью остаются.
+func (t *Team) RemoveMember(ctx context.Context, input *domain.TeamRemoveMemberInput) (*domain.TeamWithMembers, error) {
+	return audited(ctx, t.audit, t.auditSpec(domain.AuditTeamRemoveMember, input.TeamName), func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.removeMember(ctx, input)
+	})
+}
+
+func (t *Team) removeMember(ctx context.Context, input *domain.TeamRemoveMemberInput) (*domain.TeamWithMembers, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	user, err := t.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	if err := t.teamRepo.RemoveMember(ctx, team.Id, user.Id); err != nil {
+		return nil, fmt.Errorf("user %s is not a member of %s: %w", user.Id, team.Name, err)
+	}
+
+	if err := resetPrimaryTeam(ctx, t.teamRepo, t.userRepo, user, team.Id); err != nil {
+		return nil, err
+	}
+
+	return t.GetTeam(ctx, team.Name)
+}
+
+func (t *Team) SetMemberRole(ctx context.Context, input *domain.TeamMemberRoleInput) (*domain.TeamWithMembers, error) {
+	return audited(ctx, t.audit, t.auditSpec(domain.AuditTeamSetMemberRole, input.TeamName), func(ctx context.Context) (*domain.TeamWithMembers, error) {
+		return t.setMemberRole(ctx, input)
+	})
+}
+
+func (t *Team) setMemberRole(ctx context.Context, input *domain.TeamMemberRoleInput) (*domain.TeamWithMembers, error) {
+	team, err := t.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+
+	if err := t.teamRepo.UpdateMemberRole(ctx, team.Id, input.UserID, input.Role); err != nil {
+		return nil, fmt.Errorf("user %s is not a member of %s: %w", input.UserID, team.Name, err)
+	}
+
+	return t.GetTeam(ctx, team.Name)
+}
+
+// auditSpec — запись изменения команды: снимки до и после — команда с
+// участниками. Команда ищется по имени, а цель записи — её id, который не
+// меняется при переименовании; имя остаётся в снимках.
+func (t *Team) auditSpec(action domain.AuditAction, teamName string) auditSpec[*domain.TeamWithMembers] {
+	return auditSpec[*domain.TeamWithMembers]{
+		action:     action,
+		targetType: domain.AuditTargetTeam,
+		target:     func(res *domain.TeamWithMembers) string { return res.Team.Id },
+		before:     func(ctx context.Context) (any, error) { return t.GetTeam(ctx, teamName) },
+		after:      func(res *domain.TeamWithMembers) any { return res },
+	}
+}
@@ -0,0 +1,210 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+)
+
+func TestTeam_Management_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "platform",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "p1", Username: "alice", IsActive: true},
+				{UserID: "p2", Username: "bob", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = teamCase.AddTeam(ctx, &domain.TeamAddInput{TeamName: "infra"})
+		require.NoError(t, err)
+
+		_, err = teamCase.Rename(ctx, &domain.TeamRenameInput{TeamName: "infra", NewTeamName: "platform"})
+		require.ErrorIs(t, err, usecase.ErrTeamExists)
+
+		renamed, err := teamCase.Rename(ctx, &domain.TeamRenameInput{TeamName: "infra", NewTeamName: "sre"})
+		require.NoError(t, err)
+		require.Equal(t, "sre", renamed.Team.Name)
+
+		withMember, err := teamCase.AddMember(ctx, &domain.TeamMemberInput{TeamName: "sre", UserID: "s1", Username: "carol", IsActive: true})
+		require.NoError(t, err)
+		require.Len(t, withMember.Members, 1)
+
+		_, err = teamCase.AddMember(ctx, &domain.TeamMemberInput{TeamName: "sre", UserID: "s1", Username: "carol", IsActive: true})
+		require.ErrorIs(t, err, usecase.ErrUserInTeam)
+
+		teams, err := teamCase.ListTeams(ctx)
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		require.Equal(t, "platform", teams[0].Team.Name)
+		require.Equal(t, 2, teams[0].MembersCount)
+
+		pr, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "p1", Name: "Open review"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"p2"}, pr.Reviewers)
+
+		_, err = teamCase.Delete(ctx, &domain.TeamDeleteInput{TeamName: "platform"})
+		require.ErrorIs(t, err, usecase.ErrTeamOpenReviews)
+
+		deleted, err := teamCase.Delete(ctx, &domain.TeamDeleteInput{TeamName: "platform", Force: true})
+		require.NoError(t, err)
+		require.Len(t, deleted.RemovedReviews, 1)
+
+		revs, err := prRepo.ListReviewers(ctx, pr.PR.Id)
+		require.NoError(t, err)
+		require.Empty(t, revs)
+
+		orphan, err := userRepo.GetByID(ctx, "p1")
+		require.NoError(t, err)
+		require.Empty(t, orphan.TeamId)
+
+		_, err = teamCase.AddMember(ctx, &domain.TeamMemberInput{TeamName: "sre", UserID: "p1", Username: "alice", IsActive: true, Role: domain.RoleLead})
+		require.NoError(t, err)
+
+		left, err := teamCase.RemoveMember(ctx, &domain.TeamRemoveMemberInput{TeamName: "sre", UserID: "s1"})
+		require.NoError(t, err)
+		require.Len(t, left.Members, 1)
+		require.Equal(t, domain.RoleLead, left.Members[0].Role)
+
+		_, err = teamCase.RemoveMember(ctx, &domain.TeamRemoveMemberInput{TeamName: "sre", UserID: "s1"})
+		require.ErrorIs(t, err, repo.ErrNotFound)
+	})
+}
+
+func TestTeam_MultipleMemberships_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "squad",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "g1", Username: "alice", IsActive: true},
+				{UserID: "g2", Username: "bob", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		guild, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "guild",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "g1", Username: "alice", IsActive: true, Role: domain.RoleLead},
+				{UserID: "g3", Username: "carol", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, guild.Members, 2)
+
+		author, err := userRepo.GetByID(ctx, "g1")
+		require.NoError(t, err)
+
+		teams, err := teamRepo.ListByUser(ctx, author.Id)
+		require.NoError(t, err)
+		require.Len(t, teams, 2)
+		require.Equal(t, "squad", teams[0].Team.Name)
+
+		def, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "g1", Name: "Default team"})
+		require.NoError(t, err)
+		require.Equal(t, "squad", def.PR.TeamName)
+		require.Equal(t, []string{"g2"}, def.Reviewers)
+
+		inGuild, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "g1", Name: "Guild review", TeamName: "guild"})
+		require.NoError(t, err)
+		require.Equal(t, "guild", inGuild.PR.TeamName)
+		require.Equal(t, []string{"g3"}, inGuild.Reviewers)
+
+		_, err = prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "g2", Name: "Foreign team", TeamName: "guild"})
+		require.ErrorIs(t, err, usecase.ErrNotMember)
+
+		_, err = teamCase.RemoveMember(ctx, &domain.TeamRemoveMemberInput{TeamName: "squad", UserID: "g1"})
+		require.NoError(t, err)
+
+		author, err = userRepo.GetByID(ctx, "g1")
+		require.NoError(t, err)
+		require.Equal(t, guild.Team.Id, author.TeamId)
+	})
+}
+
+func TestTeam_Hierarchy_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "engineering",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "h1", Username: "head", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName:   "backend",
+			ParentName: "engineering",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "h2", Username: "lead", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		sub, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName:   "payments",
+			ParentName: "backend",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "h3", Username: "author", IsActive: true},
+				{UserID: "h2", Username: "lead", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "backend", sub.Parent.Name)
+
+		pr, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "h3", Name: "Escalate"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"h2", "h1"}, pr.Reviewers)
+
+		_, reassignedTo, err := prCase.Reassign(ctx, &domain.ReassignPullRequest{Id: pr.PR.Id, OldReviewerId: "h1"})
+		require.ErrorIs(t, err, usecase.ErrNoCandidate)
+		require.Empty(t, reassignedTo)
+
+		tree, err := teamCase.GetSubtree(ctx, "engineering")
+		require.NoError(t, err)
+		require.Len(t, tree.Subteams, 1)
+		require.Equal(t, "backend", tree.Subteams[0].Team.Name)
+		require.Len(t, tree.Subteams[0].Subteams, 1)
+		require.Len(t, tree.AggregateMembers, 3)
+
+		_, err = teamCase.SetParent(ctx, &domain.TeamSetParentInput{TeamName: "engineering", ParentName: "payments"})
+		require.ErrorIs(t, err, usecase.ErrTeamCycle)
+
+		root, err := teamCase.SetParent(ctx, &domain.TeamSetParentInput{TeamName: "payments"})
+		require.NoError(t, err)
+		require.Nil(t, root.Parent)
+	})
+}
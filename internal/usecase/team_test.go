@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/repo/sqlite"
+	"gopr/internal/usecase"
+)
+
+// failingDeleteTeamRepo не даёт удалить команду.
+type failingDeleteTeamRepo struct {
+	repo.Team
+}
+
+func (failingDeleteTeamRepo) Delete(context.Context, string) error {
+	return errors.New("disk full")
+}
+
+func TestTeam_DeleteRollsBack(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.Open(ctx, filepath.Join(t.TempDir(), "gopr.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	userRepo := sqlite.NewUserRepo(db)
+	teamRepo := sqlite.NewTeamRepo(db)
+	prRepo := sqlite.NewPullRequestRepo(db)
+
+	_, err = usecase.NewTeam(teamRepo, userRepo, prRepo).AddTeam(ctx, &domain.TeamAddInput{
+		TeamName: "acme",
+		Members: []domain.TeamAddMemberInput{
+			{UserID: "u1", Username: "alice", IsActive: true},
+			{UserID: "u2", Username: "bob", IsActive: true},
+		},
+	})
+	require.NoError(t, err)
+	pr, err := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 1).Create(ctx, &domain.CreatePullRequest{AuthorId: "u1", Name: "Fix"})
+	require.NoError(t, err)
+
+	teams := usecase.NewTeam(failingDeleteTeamRepo{teamRepo}, userRepo, prRepo)
+	outbox := sqlite.NewOutboxRepo(db)
+	teams.SetOutbox(sqlite.NewTransactor(db), outbox)
+	_, err = teams.Delete(ctx, &domain.TeamDeleteInput{TeamName: "acme", Force: true})
+	require.ErrorContains(t, err, "disk full")
+
+	// ревью не сняты, раз команда осталась
+	revs, err := prRepo.ListReviewers(ctx, pr.PR.Id)
+	require.NoError(t, err)
+	require.Equal(t, []string{"u2"}, revs)
+	_, err = teamRepo.GetByName(ctx, "acme")
+	require.NoError(t, err)
+	pending, err := outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, pending)
+
+	// после успешного удаления снятое ревью уходит в outbox
+	teams = usecase.NewTeam(teamRepo, userRepo, prRepo)
+	teams.SetOutbox(sqlite.NewTransactor(db), outbox)
+	_, err = teams.Delete(ctx, &domain.TeamDeleteInput{TeamName: "acme", Force: true})
+	require.NoError(t, err)
+	pending, err = outbox.ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, pr.PR.Id, pending[0].PullRequestId)
+	require.Equal(t, domain.EventReviewerReassigned, pending[0].EventType)
+}
@@ -3,25 +3,69 @@ package usecase
 import (
 	"context"
 	"gopr/cmd/config"
-	"gopr/internal/repo/pg"
-
-	"github.com/jackc/pgx/v5/pgxpool"
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/pkg/slogx"
+	"log/slog"
 )
 
 type Cases struct {
 	Team        *Team
 	User        *User
 	PullRequest *PullRequest
+	Stats       *Stats
+	Fairness    *Fairness
+	Identity    *Identity
+	Webhook     *Webhook
+	ChatOps     *ChatOps
+	Audit       *Audit
+	// Subscription собирается в cmd/server: ему нужен HTTP-клиент доставок.
+	Subscription *Subscription
+	// Notification собирается в cmd/server: ему нужны каналы оповещений.
+	Notification *Notification
+	// Events раздаёт опубликованные из outbox события потокам; запускается в cmd/server.
+	Events *EventFeed
 }
 
-func Setup(ctx context.Context, cfg *config.Config, db *pgxpool.Pool) Cases {
-	teamRepo := pg.NewTeamRepo(db)
-	userRepo := pg.NewUserRepo(db)
-	prRepo := pg.NewPullRequestRepo(db)
+// Setup собирает сценарии поверх репозиториев выбранного хранилища.
+func Setup(ctx context.Context, cfg *config.Config, repos repo.Factory) Cases {
+	teamRepo := repos.Team()
+	userRepo := repos.User()
+	prRepo := repos.PullRequest()
+	statsRepo := repos.Stats()
+
+	strategy, err := NewStrategy(cfg.Assign.Strategy, nil)
+	if err != nil {
+		slogx.Fatal(slog.Default(), "can't create assignment strategy", slogx.Err(err))
+	}
+
+	auditCase := NewAudit(repos.Audit(), repos.Transactor(), cfg.Audit.Retention)
+
+	prCase := NewPullRequest(prRepo, userRepo, teamRepo, strategy, cfg.Assign.Reviewers)
+	prCase.SetOutbox(repos.Transactor(), repos.Outbox())
+	prCase.SetAudit(auditCase)
+	identityCase := NewIdentity(repos.Identity(), userRepo, map[domain.IdentityProvider]map[string]string{
+		domain.IdentityGitHub: cfg.Webhooks.GitHub.Logins,
+		domain.IdentityGitLab: cfg.Webhooks.GitLab.Logins,
+		domain.IdentitySlack:  cfg.ChatOps.Slack.Logins,
+	})
+	userCase := NewUser(userRepo, teamRepo, prRepo, strategy)
+	userCase.SetOutbox(repos.Transactor(), repos.Outbox())
+	userCase.SetAudit(auditCase)
+	teamCase := NewTeam(teamRepo, userRepo, prRepo)
+	teamCase.SetOutbox(repos.Transactor(), repos.Outbox())
+	teamCase.SetAudit(auditCase)
 
 	return Cases{
-		Team:        NewTeam(teamRepo, userRepo),
-		User:        NewUser(userRepo, teamRepo),
-		PullRequest: NewPullRequest(prRepo, userRepo),
+		Team:        teamCase,
+		User:        userCase,
+		PullRequest: prCase,
+		Stats:       NewStats(statsRepo, teamRepo),
+		Fairness:    NewFairness(statsRepo, teamRepo, prRepo, cfg.Assign.Reviewers),
+		Identity:    identityCase,
+		Webhook:     NewWebhook(prCase, identityCase),
+		ChatOps:     NewChatOps(userCase, prCase, identityCase, userRepo),
+		Audit:       auditCase,
+		Events:      NewEventFeed(repos.Outbox(), 0),
 	}
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"gopr/internal/domain"
@@ -11,33 +12,324 @@ import (
 type User struct {
 	userRepo repo.User
 	teamRepo repo.Team
+	prRepo   repo.PullRequest
+	assigner *assigner
+	audit    *Audit
+	eventOutbox
 }
 
-func NewUser(userRepo repo.User, teamRepo repo.Team) *User {
+// NewUser создаёт usecase пользователей; strategy используется при передаче
+// ревью переведённого пользователя, nil — случайный выбор.
+func NewUser(userRepo repo.User, teamRepo repo.Team, prRepo repo.PullRequest, strategy Strategy) *User {
 	return &User{
 		userRepo: userRepo,
 		teamRepo: teamRepo,
+		prRepo:   prRepo,
+		assigner: newAssigner(prRepo, userRepo, teamRepo, strategy),
 	}
 }
 
-func (u *User) SetActive(ctx context.Context, userID string, active bool) (*domain.User, string, error) {
+// SetOutbox включает перевод пользователя в одной транзакции и запись событий о
+// передаче его ревью в outbox.
+func (u *User) SetOutbox(tx repo.Transactor, outbox repo.Outbox) {
+	u.tx = tx
+	u.outbox = outbox
+}
+
+// SetAudit включает запись изменений пользователей в журнал аудита.
+func (u *User) SetAudit(audit *Audit) {
+	u.audit = audit
+}
+
+func (u *User) SetActive(ctx context.Context, userID string, active bool) (*domain.UserDetails, error) {
+	return audited(ctx, u.audit, u.auditSpec(domain.AuditUserSetActive, userID), func(ctx context.Context) (*domain.UserDetails, error) {
+		return u.setActive(ctx, userID, active)
+	})
+}
+
+func (u *User) setActive(ctx context.Context, userID string, active bool) (*domain.UserDetails, error) {
 	if err := u.userRepo.UpdateIsActive(ctx, userID, active); err != nil {
-		return nil, "", fmt.Errorf("failed to update activity: %w", err)
+		return nil, fmt.Errorf("failed to update activity: %w", err)
 	}
 
+	return u.Get(ctx, userID)
+}
+
+func (u *User) Get(ctx context.Context, userID string) (*domain.UserDetails, error) {
 	user, err := u.userRepo.GetByID(ctx, userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to load user: %w", err)
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	res, err := u.details(ctx, []*domain.User{user})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[0], nil
+}
+
+func (u *User) List(ctx context.Context, q *domain.UserListQuery) (*domain.UserPage, error) {
+	page, err := newPageParams(domain.SortOldest, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.UserFilter{
+		UsernamePrefix: q.UsernamePrefix,
+		After:          page.after,
+		Limit:          page.fetchLimit(),
+	}
+
+	if q.TeamName != "" {
+		team, err := u.teamRepo.GetByName(ctx, q.TeamName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load team: %w", err)
+		}
+		filter.TeamId = team.Id
+	}
+
+	users, err := u.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var next string
+	if len(users) > page.limit {
+		users = users[:page.limit]
+		last := users[len(users)-1]
+		next = domain.Cursor{CreatedAt: last.CreatedAt, Id: last.Id}.Encode()
+	}
+
+	res, err := u.details(ctx, users)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserPage{
+		Users:      res,
+		NextCursor: next,
+	}, nil
+}
+
+func (u *User) UpdateUsername(ctx context.Context, input *domain.UserUpdateInput) (*domain.UserDetails, error) {
+	return audited(ctx, u.audit, u.auditSpec(domain.AuditUserUpdate, input.UserID), func(ctx context.Context) (*domain.UserDetails, error) {
+		return u.updateUsername(ctx, input)
+	})
+}
+
+func (u *User) updateUsername(ctx context.Context, input *domain.UserUpdateInput) (*domain.UserDetails, error) {
+	if err := u.userRepo.UpdateUsername(ctx, input.UserID, input.Username); err != nil {
+		return nil, fmt.Errorf("failed to update username: %w", err)
+	}
+
+	return u.Get(ctx, input.UserID)
+}
+
+// MoveToTeam переводит пользователя из основной команды в другую, которая
+// становится основной. Открытые ревью PR, которые ревьюит старая команда,
+// передаются другим её активным участникам, а при отсутствии кандидатов снимаются.
+// Членство в остальных командах не меняется.
+func (u *User) MoveToTeam(ctx context.Context, input *domain.UserMoveInput) (*domain.UserMoveResult, error) {
+	spec := auditSpec[*domain.UserMoveResult]{
+		action:     domain.AuditUserMove,
+		targetType: domain.AuditTargetUser,
+		targetID:   input.UserID,
+		before:     func(ctx context.Context) (any, error) { return u.Get(ctx, input.UserID) },
+		after:      func(res *domain.UserMoveResult) any { return res.User },
+	}
+
+	return audited(ctx, u.audit, spec, func(ctx context.Context) (*domain.UserMoveResult, error) {
+		return u.moveToTeam(ctx, input)
+	})
+}
+
+func (u *User) moveToTeam(ctx context.Context, input *domain.UserMoveInput) (*domain.UserMoveResult, error) {
+	user, err := u.userRepo.GetByID(ctx, input.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	team, err := u.teamRepo.GetByName(ctx, input.TeamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load team: %w", err)
+	}
+
+	reassigned := make([]*domain.Reassignment, 0)
+
+	if user.TeamId != team.Id {
+		// ревью передаются только вместе с переводом
+		err = u.inTx(ctx, func(ctx context.Context) error {
+			var err error
+			if user.TeamId != "" {
+				reassigned, err = u.releaseReviews(ctx, user)
+				if err != nil {
+					return err
+				}
+
+				err = u.teamRepo.RemoveMember(ctx, user.TeamId, user.Id)
+				if err != nil && !errors.Is(err, repo.ErrNotFound) {
+					return fmt.Errorf("failed to leave team: %w", err)
+				}
+			}
+
+			err = u.teamRepo.AddMember(ctx, &domain.Membership{TeamId: team.Id, UserId: user.Id, Role: domain.RoleMember})
+			if err != nil && !errors.Is(err, repo.ErrAlreadyExists) {
+				return fmt.Errorf("failed to join team: %w", err)
+			}
+
+			if err := u.userRepo.UpdateTeam(ctx, user.Id, team.Id); err != nil {
+				return fmt.Errorf("failed to update user team: %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	details, err := u.Get(ctx, user.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserMoveResult{
+		User:       details,
+		Reassigned: reassigned,
+	}, nil
+}
+
+// auditSpec — запись изменения пользователя: снимки до и после — его карточка.
+func (u *User) auditSpec(action domain.AuditAction, userID string) auditSpec[*domain.UserDetails] {
+	return auditSpec[*domain.UserDetails]{
+		action:     action,
+		targetType: domain.AuditTargetUser,
+		targetID:   userID,
+		before:     func(ctx context.Context) (any, error) { return u.Get(ctx, userID) },
+		after:      func(res *domain.UserDetails) any { return res },
+	}
+}
+
+func (u *User) releaseReviews(ctx context.Context, user *domain.User) ([]*domain.Reassignment, error) {
+	prs, err := u.prRepo.ListByReviewer(ctx, &domain.ReviewerPRFilter{
+		ReviewerId: user.Id,
+		Status:     domain.PullRequestStatusOpen,
+		TeamId:     user.TeamId,
+		Order:      domain.SortOldest,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open reviews: %w", err)
+	}
+
+	res := make([]*domain.Reassignment, 0, len(prs))
+	for _, pr := range prs {
+		current, err := u.prRepo.ListReviewers(ctx, pr.Id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load reviewers: %w", err)
+		}
+
+		newID, err := u.assigner.replace(ctx, pr, current, user.Id, domain.ReasonUserMoved)
+		if err != nil && !errors.Is(err, ErrNoCandidate) {
+			return nil, err
+		}
+
+		if err := u.emitReassigned(ctx, u.prRepo, pr.Id, user.Id, newID); err != nil {
+			return nil, err
+		}
+
+		res = append(res, &domain.Reassignment{
+			PullRequestId: pr.Id,
+			OldReviewerId: user.Id,
+			NewReviewerId: newID,
+		})
+	}
+
+	return res, nil
+}
+
+// details дополняет пользователей командами и числом открытых ревью.
+func (u *User) details(ctx context.Context, users []*domain.User) ([]*domain.UserDetails, error) {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.Id)
+	}
+
+	load, err := u.prRepo.CountOpenReviews(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count open reviews: %w", err)
+	}
+
+	teamsByUser, err := u.teamRepo.ListByUsers(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user teams: %w", err)
+	}
+
+	res := make([]*domain.UserDetails, 0, len(users))
+
+	for _, user := range users {
+		teams := teamsByUser[user.Id]
+
+		teamName := ""
+		for _, t := range teams {
+			if t.Team.Id == user.TeamId {
+				teamName = t.Team.Name
+			}
+		}
+
+		res = append(res, &domain.UserDetails{
+			User:        user,
+			TeamName:    teamName,
+			Teams:       teams,
+			ReviewLoad:  load[user.Id],
+			IsAvailable: user.IsActive,
+		})
 	}
 
-	var teamName string
-	if user.TeamId != "" {
-		team, err := u.teamRepo.GetByID(ctx, user.TeamId)
+	return res, nil
+}
+
+func (u *User) GetReviews(ctx context.Context, q *domain.UserReviewsQuery) (*domain.UserReviews, error) {
+	if _, err := u.userRepo.GetByID(ctx, q.UserId); err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+
+	page, err := newPageParams(q.Order, q.Cursor, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.ReviewerPRFilter{
+		ReviewerId:  q.UserId,
+		Status:      q.Status,
+		CreatedFrom: q.CreatedFrom,
+		CreatedTo:   q.CreatedTo,
+		Order:       page.order,
+		After:       page.after,
+		Limit:       page.fetchLimit(),
+	}
+
+	if q.TeamName != "" {
+		team, err := u.teamRepo.GetByName(ctx, q.TeamName)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to load team: %w", err)
+			return nil, fmt.Errorf("failed to load team: %w", err)
 		}
-		teamName = team.Name
+		filter.TeamId = team.Id
+	}
+
+	prs, err := u.prRepo.ListByReviewer(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	res, err := buildPage(ctx, u.prRepo, prs, page)
+	if err != nil {
+		return nil, err
 	}
 
-	return user, teamName, nil
+	return &domain.UserReviews{
+		UserID:     q.UserId,
+		PRs:        res.PRs,
+		NextCursor: res.NextCursor,
+	}, nil
 }
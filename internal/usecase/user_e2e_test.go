@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+	"gopr/internal/usecase"
+)
+
+func TestUser_GetReviews_Pagination_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+		userCase := usecase.NewUser(userRepo, teamRepo, prRepo, nil)
+
+		team := &domain.Team{Id: uuid.NewString(), Name: "reviews"}
+		require.NoError(t, teamRepo.Create(ctx, team))
+
+		author := &domain.User{Id: "ra", Username: "author", TeamId: team.Id, IsActive: true}
+		reviewer := &domain.User{Id: "rr", Username: "reviewer", TeamId: team.Id, IsActive: true}
+		require.NoError(t, userRepo.Create(ctx, author))
+		require.NoError(t, userRepo.Create(ctx, reviewer))
+
+		for _, name := range []string{"first", "second", "third"} {
+			_, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: author.Id, Name: name})
+			require.NoError(t, err)
+		}
+
+		_, err := prCase.Merge(ctx, &domain.MergePullRequest{Id: mustFirstReview(ctx, t, userCase, reviewer.Id)})
+		require.NoError(t, err)
+
+		page, err := userCase.GetReviews(ctx, &domain.UserReviewsQuery{UserId: reviewer.Id, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.PRs, 2)
+		require.NotEmpty(t, page.NextCursor)
+		require.Equal(t, []string{reviewer.Id}, page.PRs[0].Reviewers)
+
+		rest, err := userCase.GetReviews(ctx, &domain.UserReviewsQuery{UserId: reviewer.Id, Limit: 2, Cursor: page.NextCursor})
+		require.NoError(t, err)
+		require.Len(t, rest.PRs, 1)
+		require.Empty(t, rest.NextCursor)
+
+		open, err := userCase.GetReviews(ctx, &domain.UserReviewsQuery{UserId: reviewer.Id, Status: domain.PullRequestStatusOpen})
+		require.NoError(t, err)
+		require.Len(t, open.PRs, 2)
+	})
+}
+
+func mustFirstReview(ctx context.Context, t *testing.T, uc *usecase.User, userID string) string {
+	t.Helper()
+
+	res, err := uc.GetReviews(ctx, &domain.UserReviewsQuery{UserId: userID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, res.PRs, 1)
+
+	return res.PRs[0].PR.Id
+}
+
+func TestUser_MoveToTeam_E2E(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, f repo.Factory) {
+		ctx := context.Background()
+
+		userRepo := f.User()
+		teamRepo := f.Team()
+		prRepo := f.PullRequest()
+
+		teamCase := usecase.NewTeam(teamRepo, userRepo, prRepo)
+		prCase := usecase.NewPullRequest(prRepo, userRepo, teamRepo, nil, 0)
+		userCase := usecase.NewUser(userRepo, teamRepo, prRepo, nil)
+		userCase.SetOutbox(f.Transactor(), f.Outbox())
+
+		_, err := teamCase.AddTeam(ctx, &domain.TeamAddInput{
+			TeamName: "old",
+			Members: []domain.TeamAddMemberInput{
+				{UserID: "m1", Username: "alice", IsActive: true},
+				{UserID: "m2", Username: "bob", IsActive: true},
+				{UserID: "m3", Username: "carol", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = teamCase.AddTeam(ctx, &domain.TeamAddInput{TeamName: "new"})
+		require.NoError(t, err)
+
+		pr, err := prCase.Create(ctx, &domain.CreatePullRequest{AuthorId: "m1", Name: "Move me"})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviewers, 2)
+
+		moved := pr.Reviewers[0]
+		before, err := userCase.Get(ctx, moved)
+		require.NoError(t, err)
+		require.Equal(t, 1, before.ReviewLoad)
+
+		res, err := userCase.MoveToTeam(ctx, &domain.UserMoveInput{UserID: moved, TeamName: "new"})
+		require.NoError(t, err)
+		require.Equal(t, "new", res.User.TeamName)
+		require.Equal(t, 0, res.User.ReviewLoad)
+		require.Len(t, res.Reassigned, 1)
+		require.Empty(t, res.Reassigned[0].NewReviewerId)
+
+		// снятие ревью попадает в outbox вместе с переводом
+		pending, err := f.Outbox().ListPending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, domain.EventReviewerReassigned, pending[0].EventType)
+		var event domain.Event
+		require.NoError(t, json.Unmarshal(pending[0].Payload, &event))
+		require.Equal(t, moved, event.OldReviewerId)
+		require.Empty(t, event.NewReviewerId)
+		require.Equal(t, []string{pr.Reviewers[1]}, event.Reviewers)
+
+		page, err := userCase.List(ctx, &domain.UserListQuery{UsernamePrefix: "AL"})
+		require.NoError(t, err)
+		require.Len(t, page.Users, 1)
+		require.Equal(t, "m1", page.Users[0].User.Id)
+
+		renamed, err := userCase.UpdateUsername(ctx, &domain.UserUpdateInput{UserID: "m1", Username: "alicia"})
+		require.NoError(t, err)
+		require.Equal(t, "alicia", renamed.User.Username)
+	})
+}
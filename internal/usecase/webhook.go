@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gopr/internal/domain"
+	"gopr/internal/repo"
+)
+
+// Webhook применяет события PR с код-хостинга к PR в gopr.
+type Webhook struct {
+	prCase     *PullRequest
+	identities *Identity
+}
+
+// NewWebhook создаёт usecase вебхуков; логины провайдера сопоставляются с
+// пользователями через identities, событие с неизвестным логином отклоняется
+// ошибкой *domain.UnknownIdentityError.
+func NewWebhook(prCase *PullRequest, identities *Identity) *Webhook {
+	return &Webhook{
+		prCase:     prCase,
+		identities: identities,
+	}
+}
+
+// Handle создаёт PR при открытии, переоткрытии и выходе из черновика, мержит
+// его при merge на код-хостинге и снимает ревьюверов при закрытии без merge.
+// Черновики, повторные события по уже известным PR и merge или закрытие
+// неизвестных PR игнорируются.
+func (w *Webhook) Handle(ctx context.Context, hook *domain.PullRequestHook) (*domain.HookResult, error) {
+	id := hook.PullRequestId()
+
+	switch hook.Action {
+	case domain.HookActionOpened, domain.HookActionReady, domain.HookActionReopened:
+		if hook.Draft {
+			return ignoredHook(id, "draft pull request"), nil
+		}
+
+		authorID, err := w.userID(ctx, hook.Provider, hook.AuthorLogin)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = w.prCase.Create(ctx, &domain.CreatePullRequest{
+			Id:       id,
+			AuthorId: authorID,
+			Name:     hook.Title,
+		})
+		if errors.Is(err, ErrPRExists) {
+			return ignoredHook(id, "pull request is already tracked"), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create PR from %s webhook: %w", hook.Provider, err)
+		}
+
+		return &domain.HookResult{Outcome: domain.HookOutcomeCreated, PullRequestId: id}, nil
+
+	case domain.HookActionMerged:
+		actor, err := w.userID(ctx, hook.Provider, hook.SenderLogin)
+		if err != nil {
+			return nil, err
+		}
+		ctx = domain.WithActor(ctx, actor)
+
+		_, err = w.prCase.Merge(ctx, &domain.MergePullRequest{Id: id})
+		if errors.Is(err, repo.ErrNotFound) {
+			return ignoredHook(id, "pull request is not tracked"), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge PR from %s webhook: %w", hook.Provider, err)
+		}
+
+		return &domain.HookResult{Outcome: domain.HookOutcomeMerged, PullRequestId: id}, nil
+
+	case domain.HookActionClosed:
+		actor, err := w.userID(ctx, hook.Provider, hook.SenderLogin)
+		if err != nil {
+			return nil, err
+		}
+		ctx = domain.WithActor(ctx, actor)
+
+		_, err = w.prCase.Unassign(ctx, id)
+		if errors.Is(err, repo.ErrNotFound) {
+			return ignoredHook(id, "pull request is not tracked"), nil
+		}
+		if errors.Is(err, ErrPRMerged) {
+			return ignoredHook(id, "pull request is already merged"), nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to close PR from %s webhook: %w", hook.Provider, err)
+		}
+
+		return &domain.HookResult{Outcome: domain.HookOutcomeClosed, PullRequestId: id}, nil
+
+	default:
+		return ignoredHook(id, "unsupported action "+string(hook.Action)), nil
+	}
+}
+
+func (w *Webhook) userID(ctx context.Context, provider domain.HookProvider, login string) (string, error) {
+	id, err := w.identities.UserID(ctx, domain.IdentityProvider(provider), login)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s login %s: %w", provider, login, err)
+	}
+
+	return id, nil
+}
+
+func ignoredHook(id, reason string) *domain.HookResult {
+	return &domain.HookResult{
+		Outcome:       domain.HookOutcomeIgnored,
+		PullRequestId: id,
+		Reason:        reason,
+	}
+}
@@ -0,0 +1,7 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_pr_created_id ON pull_requests (created_at, id);
+CREATE INDEX idx_pr_status_created_id ON pull_requests (status, created_at, id);
+CREATE INDEX idx_pr_author_created_id ON pull_requests (author_id, created_at, id);
+CREATE INDEX idx_pr_merged_at ON pull_requests (merged_at) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pr_name_trgm ON pull_requests USING gin (name gin_trgm_ops);
@@ -0,0 +1,32 @@
+CREATE TABLE pull_request_history
+(
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL,
+    type            TEXT        NOT NULL CHECK (type IN ('CREATED', 'REVIEWER_ASSIGNED', 'REVIEWER_REMOVED', 'MERGED')),
+    reviewer_id     TEXT,
+    actor           TEXT        NOT NULL,
+    reason          TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_pr_history_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_history_pr ON pull_request_history (pull_request_id, id);
+
+-- история для уже существующих PR восстанавливается по текущему состоянию
+INSERT INTO pull_request_history (pull_request_id, type, actor, reason, created_at)
+SELECT id, 'CREATED', author_id, 'backfill', COALESCE(created_at, NOW())
+FROM pull_requests;
+
+INSERT INTO pull_request_history (pull_request_id, type, reviewer_id, actor, reason, created_at)
+SELECT rr.pull_request_id, 'REVIEWER_ASSIGNED', rr.reviewer_id, 'system', 'backfill', COALESCE(pr.created_at, NOW())
+FROM pull_request_reviewer rr
+         JOIN pull_requests pr ON pr.id = rr.pull_request_id;
+
+INSERT INTO pull_request_history (pull_request_id, type, actor, reason, created_at)
+SELECT id, 'MERGED', 'system', 'backfill', merged_at
+FROM pull_requests
+WHERE merged_at IS NOT NULL;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_users_username_prefix ON users (lower(username) text_pattern_ops);
+CREATE INDEX idx_users_created_id ON users (created_at, id);
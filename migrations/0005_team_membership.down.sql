@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pr_team;
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS fk_pr_team;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS team_id;
+DROP TABLE IF EXISTS team_membership;
@@ -0,0 +1,44 @@
+CREATE TABLE team_membership
+(
+    team_id    TEXT        NOT NULL,
+    user_id    TEXT        NOT NULL,
+    role       TEXT        NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'lead')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, user_id),
+
+    CONSTRAINT fk_team_membership_team
+        FOREIGN KEY (team_id)
+            REFERENCES team (id)
+            ON DELETE CASCADE,
+
+    CONSTRAINT fk_team_membership_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_team_membership_user ON team_membership (user_id);
+
+-- users.team_id остаётся основной командой пользователя и тоже становится членством
+INSERT INTO team_membership (team_id, user_id, created_at)
+SELECT team_id, id, created_at
+FROM users
+WHERE team_id IS NOT NULL;
+
+-- команда, в которой ревьюится PR; для существующих PR — основная команда автора
+ALTER TABLE pull_requests
+    ADD COLUMN team_id TEXT;
+
+ALTER TABLE pull_requests
+    ADD CONSTRAINT fk_pr_team
+        FOREIGN KEY (team_id)
+            REFERENCES team (id)
+            ON DELETE SET NULL;
+
+UPDATE pull_requests AS pr
+SET team_id = u.team_id
+FROM users AS u
+WHERE u.id = pr.author_id;
+
+CREATE INDEX idx_pr_team ON pull_requests (team_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_team_parent;
+ALTER TABLE team DROP CONSTRAINT IF EXISTS chk_team_parent_self;
+ALTER TABLE team DROP CONSTRAINT IF EXISTS fk_team_parent;
+ALTER TABLE team DROP COLUMN IF EXISTS parent_id;
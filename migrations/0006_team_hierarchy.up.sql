@@ -0,0 +1,14 @@
+ALTER TABLE team
+    ADD COLUMN parent_id TEXT;
+
+-- при удалении родителя подкоманды становятся корневыми
+ALTER TABLE team
+    ADD CONSTRAINT fk_team_parent
+        FOREIGN KEY (parent_id)
+            REFERENCES team (id)
+            ON DELETE SET NULL;
+
+ALTER TABLE team
+    ADD CONSTRAINT chk_team_parent_self CHECK (parent_id <> id);
+
+CREATE INDEX idx_team_parent ON team (parent_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_pr_history_pr_type;
+DROP INDEX IF EXISTS idx_pr_created_merged;
+DROP INDEX IF EXISTS idx_pr_team_created;
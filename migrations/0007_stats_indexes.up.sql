@@ -0,0 +1,5 @@
+-- индексы под /stats: выборка PR по диапазону created_at и команде ревью,
+-- percentiles по merged-PR и подсчёт событий ревьюверов без обращения к таблице
+CREATE INDEX idx_pr_team_created ON pull_requests (team_id, created_at);
+CREATE INDEX idx_pr_created_merged ON pull_requests (created_at) INCLUDE (merged_at, team_id) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pr_history_pr_type ON pull_request_history (pull_request_id, type, reviewer_id);
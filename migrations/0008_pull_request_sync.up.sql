@@ -0,0 +1,14 @@
+-- состояние выгрузки ревьюверов PR на код-хостинг, одна строка на PR
+CREATE TABLE pull_request_sync
+(
+    pull_request_id TEXT PRIMARY KEY,
+    status          TEXT        NOT NULL CHECK (status IN ('PENDING', 'SYNCED', 'FAILED')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_pr_sync_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
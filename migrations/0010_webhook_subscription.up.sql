@@ -0,0 +1,32 @@
+-- подписки внешних систем на события PR; пустой events — все события
+CREATE TABLE webhook_subscription
+(
+    id         TEXT PRIMARY KEY,
+    url        TEXT        NOT NULL,
+    events     TEXT[]      NOT NULL DEFAULT '{}',
+    secret     TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- журнал доставок; повторная доставка — новая строка с тем же event_id
+CREATE TABLE webhook_delivery
+(
+    id              TEXT PRIMARY KEY,
+    subscription_id TEXT        NOT NULL,
+    event_id        TEXT        NOT NULL,
+    event_type      TEXT        NOT NULL,
+    payload         JSON        NOT NULL,
+    status          TEXT        NOT NULL CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD')),
+    attempts        INT         NOT NULL DEFAULT 0,
+    response_code   INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_webhook_delivery_subscription
+        FOREIGN KEY (subscription_id)
+            REFERENCES webhook_subscription (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_webhook_delivery_subscription ON webhook_delivery (subscription_id, created_at, id);
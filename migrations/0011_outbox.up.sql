@@ -0,0 +1,14 @@
+-- события PR, записанные в одной транзакции с изменением; id задаёт порядок публикации
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT        NOT NULL,
+    event_type      TEXT        NOT NULL,
+    payload         JSON        NOT NULL,
+    attempts        INT         NOT NULL DEFAULT 0,
+    last_error      TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    published_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE published_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_outbox_published ON outbox;
+DROP FUNCTION IF EXISTS outbox_notify_published();
+ALTER TABLE outbox DROP COLUMN IF EXISTS published_seq;
+DROP SEQUENCE IF EXISTS outbox_published_seq;
@@ -0,0 +1,20 @@
+-- порядок публикации событий outbox: по нему поток событий возобновляется после обрыва
+CREATE SEQUENCE outbox_published_seq;
+
+ALTER TABLE outbox ADD COLUMN published_seq BIGINT UNIQUE;
+
+-- оповещает инстансы, слушающие канал gopr_outbox, о новом опубликованном событии
+CREATE FUNCTION outbox_notify_published() RETURNS trigger AS
+$$
+BEGIN
+    PERFORM pg_notify('gopr_outbox', NEW.published_seq::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_outbox_published
+    AFTER UPDATE OF published_seq
+    ON outbox
+    FOR EACH ROW
+    WHEN (NEW.published_seq IS NOT NULL AND OLD.published_seq IS NULL)
+EXECUTE FUNCTION outbox_notify_published();
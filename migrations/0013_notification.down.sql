@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS notification_sla_warning;
+DROP TABLE IF EXISTS notification_preference;
@@ -0,0 +1,31 @@
+-- каналы оповещений пользователей; пустой address — адрес по умолчанию
+CREATE TABLE notification_preference
+(
+    user_id    TEXT        NOT NULL,
+    channel    TEXT        NOT NULL CHECK (channel IN ('slack', 'email', 'http')),
+    address    TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, channel),
+
+    CONSTRAINT fk_notification_preference_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+-- отправленные предупреждения SLA: ревьювер предупреждается о PR один раз
+CREATE TABLE notification_sla_warning
+(
+    pull_request_id TEXT        NOT NULL,
+    reviewer_id     TEXT        NOT NULL,
+    warned_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (pull_request_id, reviewer_id),
+
+    CONSTRAINT fk_notification_sla_warning_pr
+        FOREIGN KEY (pull_request_id)
+            REFERENCES pull_requests (id)
+            ON DELETE CASCADE
+);
@@ -0,0 +1,19 @@
+-- ежедневная сводка ожидающих ревью; hour и тихие часы — по местному времени пользователя
+CREATE TABLE notification_digest
+(
+    user_id      TEXT        NOT NULL PRIMARY KEY,
+    enabled      BOOLEAN     NOT NULL DEFAULT FALSE,
+    timezone     TEXT        NOT NULL DEFAULT 'UTC',
+    hour         SMALLINT    NOT NULL DEFAULT 9 CHECK (hour BETWEEN 0 AND 23),
+    quiet_start  SMALLINT CHECK (quiet_start BETWEEN 0 AND 23),
+    quiet_end    SMALLINT CHECK (quiet_end BETWEEN 0 AND 23),
+    last_sent_at TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_notification_digest_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_notification_digest_enabled ON notification_digest (user_id) WHERE enabled;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS user_away;
+
+DELETE FROM user_identity WHERE provider = 'slack';
+ALTER TABLE user_identity DROP CONSTRAINT user_identity_provider_check;
+ALTER TABLE user_identity
+    ADD CONSTRAINT user_identity_provider_check CHECK (provider IN ('github', 'gitlab', 'email'));
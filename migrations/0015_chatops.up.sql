@@ -0,0 +1,19 @@
+-- учётные записи Slack для slash-команд: логин — id участника Slack
+ALTER TABLE user_identity DROP CONSTRAINT user_identity_provider_check;
+ALTER TABLE user_identity
+    ADD CONSTRAINT user_identity_provider_check CHECK (provider IN ('github', 'gitlab', 'email', 'slack'));
+
+-- отсутствие пользователя: до until он неактивен, затем снова становится активным
+CREATE TABLE user_away
+(
+    user_id    TEXT        NOT NULL PRIMARY KEY,
+    until      TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT fk_user_away_user
+        FOREIGN KEY (user_id)
+            REFERENCES users (id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX idx_user_away_until ON user_away (until);
@@ -0,0 +1,30 @@
+-- журнал аудита изменений; записи только добавляются и удаляются по сроку хранения
+CREATE TABLE audit_log
+(
+    id              TEXT        NOT NULL PRIMARY KEY,
+    actor           TEXT        NOT NULL,
+    action          TEXT        NOT NULL,
+    target_type     TEXT        NOT NULL,
+    target_id       TEXT        NOT NULL,
+    snapshot_before JSON,
+    snapshot_after  JSON,
+    request_id      TEXT        NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_created ON audit_log (created_at, id);
+CREATE INDEX idx_audit_log_target ON audit_log (target_type, target_id, created_at);
+CREATE INDEX idx_audit_log_actor ON audit_log (actor, created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE
+    ON audit_log
+    FOR EACH ROW
+EXECUTE FUNCTION audit_log_append_only();
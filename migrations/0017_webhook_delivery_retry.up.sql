@@ -0,0 +1,6 @@
+-- повторы доставок хранятся в базе и переживают перезапуск; NULL — доставка завершена
+ALTER TABLE webhook_delivery ADD COLUMN next_attempt_at TIMESTAMPTZ;
+
+UPDATE webhook_delivery SET next_attempt_at = NOW() WHERE status = 'PENDING';
+
+CREATE INDEX idx_webhook_delivery_due ON webhook_delivery (next_attempt_at) WHERE status = 'PENDING';
@@ -0,0 +1,7 @@
+-- получатели, уже принявшие событие: повтор уходит только остальным
+ALTER TABLE outbox ADD COLUMN accepted_by TEXT[] NOT NULL DEFAULT '{}';
+-- событие снято с публикации, когда попытки кончились
+ALTER TABLE outbox ADD COLUMN dead_at TIMESTAMPTZ;
+
+DROP INDEX idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE published_at IS NULL AND dead_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_log_claimed_actor;
+
+ALTER TABLE audit_log DROP COLUMN IF EXISTS claimed_actor;
@@ -0,0 +1,5 @@
+-- инициатор из X-Actor-Id, которого никто не проверял; actor — только подтверждённый.
+-- В записях до этой миграции actor мог быть взят из заголовка.
+ALTER TABLE audit_log ADD COLUMN claimed_actor TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_audit_log_claimed_actor ON audit_log (claimed_actor, created_at);